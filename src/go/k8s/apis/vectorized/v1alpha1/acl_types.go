@@ -0,0 +1,195 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package v1alpha1
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ACLResourceType is the type of resource an ACL rule applies to
+type ACLResourceType string
+
+const (
+	// ACLResourceTypeTopic is a topic
+	ACLResourceTypeTopic ACLResourceType = "Topic"
+	// ACLResourceTypeGroup is a consumer group
+	ACLResourceTypeGroup ACLResourceType = "Group"
+	// ACLResourceTypeCluster is the cluster
+	ACLResourceTypeCluster ACLResourceType = "Cluster"
+	// ACLResourceTypeTransactionalID is a transactional ID
+	ACLResourceTypeTransactionalID ACLResourceType = "TransactionalID"
+)
+
+// ACLPatternType defines how the resource name of an ACL rule is matched
+type ACLPatternType string
+
+const (
+	// ACLPatternTypeLiteral matches the resource name exactly
+	ACLPatternTypeLiteral ACLPatternType = "Literal"
+	// ACLPatternTypePrefixed matches resource names starting with the name
+	ACLPatternTypePrefixed ACLPatternType = "Prefixed"
+)
+
+// ACLPermission defines whether an ACL rule allows or denies the operations
+type ACLPermission string
+
+const (
+	// ACLPermissionAllow allows the operations
+	ACLPermissionAllow ACLPermission = "Allow"
+	// ACLPermissionDeny denies the operations
+	ACLPermissionDeny ACLPermission = "Deny"
+)
+
+// ACLOperation is an operation on a resource
+// +kubebuilder:validation:Enum=All;Read;Write;Create;Delete;Alter;Describe;ClusterAction;DescribeConfigs;AlterConfigs;IdempotentWrite
+type ACLOperation string
+
+const (
+	// ACLReadyCondition is True when all the ACL rules exist in the cluster
+	ACLReadyCondition = "Ready"
+)
+
+// ACLRule is a set of operations allowed or denied on a resource
+type ACLRule struct {
+	// ResourceType is the type of the resource.
+	// +kubebuilder:validation:Enum=Topic;Group;Cluster;TransactionalID
+	ResourceType ACLResourceType `json:"resourceType"`
+
+	// ResourceName is the name of the resource, or "*" for all resources of
+	// the type. It is ignored for the Cluster resource type.
+	// +optional
+	ResourceName string `json:"resourceName,omitempty"`
+
+	// PatternType defines how ResourceName is matched.
+	// +optional
+	// +kubebuilder:validation:Enum=Literal;Prefixed
+	// +kubebuilder:default=Literal
+	PatternType ACLPatternType `json:"patternType,omitempty"`
+
+	// Operations are the operations allowed or denied.
+	// +kubebuilder:validation:MinItems=1
+	Operations []ACLOperation `json:"operations"`
+
+	// Permission defines whether the operations are allowed or denied.
+	// +optional
+	// +kubebuilder:validation:Enum=Allow;Deny
+	// +kubebuilder:default=Allow
+	Permission ACLPermission `json:"permission,omitempty"`
+
+	// Host is the host the principal connects from, defaulting to "*" for
+	// any host.
+	// +optional
+	Host string `json:"host,omitempty"`
+}
+
+// ACLSpec defines the desired state of ACL
+type ACLSpec struct {
+	// The referenced Redpanda Cluster. It must be in the namespace of the
+	// ACL.
+	ClusterRef NamespaceNameRef `json:"clusterRef"`
+
+	// Principal the rules apply to, for example "User:my-app".
+	// +kubebuilder:validation:Pattern=`^User:.+$`
+	Principal string `json:"principal"`
+
+	// Rules are the ACL rules of the principal.
+	// +optional
+	Rules []ACLRule `json:"rules,omitempty"`
+}
+
+// ACLStatus defines the observed state of ACL
+type ACLStatus struct {
+	// ObservedGeneration is the last observed generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Principal is the principal of the applied rules.
+	// +optional
+	Principal string `json:"principal,omitempty"`
+
+	// AppliedRules are the rules created in the cluster. Only these are
+	// deleted when they are removed from the spec or the ACL is deleted.
+	// +optional
+	AppliedRules []ACLRule `json:"appliedRules,omitempty"`
+
+	// Conditions holds the conditions for the ACL.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Principal",type="string",JSONPath=".spec.principal"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message"
+
+// ACL is the Schema for the acls API
+type ACL struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ACLSpec   `json:"spec,omitempty"`
+	Status ACLStatus `json:"status,omitempty"`
+}
+
+// GetClusterRef returns the NamespacedName of referenced Cluster object
+func (a *ACL) GetClusterRef() types.NamespacedName {
+	return types.NamespacedName{Name: a.Spec.ClusterRef.Name, Namespace: a.Spec.ClusterRef.Namespace}
+}
+
+// GetCluster returns the referenced Cluster object
+func (a *ACL) GetCluster(
+	ctx context.Context, cl client.Client,
+) (*Cluster, error) {
+	if a.Spec.ClusterRef.Namespace != a.Namespace {
+		return nil, ErrClusterRefNamespace
+	}
+	cluster := &Cluster{}
+	if err := cl.Get(ctx, a.GetClusterRef(), cluster); err != nil {
+		return nil, err
+	}
+	if cc := cluster.Status.GetCondition(ClusterConfiguredConditionType); cc == nil || cc.Status != corev1.ConditionTrue {
+		return cluster, ErrClusterNotConfigured
+	}
+	return cluster, nil
+}
+
+// SetCondition sets a status condition of the ACL, tracking the generation it
+// was observed at
+func (a *ACL) SetCondition(
+	conditionType string, status metav1.ConditionStatus, reason, message string,
+) {
+	apimeta.SetStatusCondition(&a.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: a.GetGeneration(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+//+kubebuilder:object:root=true
+
+// ACLList contains a list of ACL
+type ACLList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ACL `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ACL{}, &ACLList{})
+}
@@ -124,6 +124,9 @@ type ClusterSpec struct {
 	// If specified, Redpanda Pod node selectors. For reference please visit
 	// https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Topology configures how the brokers are spread across zones and which
+	// node label defines their rack
+	Topology *TopologySpec `json:"topology,omitempty"`
 	// Storage spec for cluster
 	Storage StorageSpec `json:"storage,omitempty"`
 	// Cloud storage configuration for cluster
@@ -169,6 +172,30 @@ type ClusterSpec struct {
 	// RestartConfig allows to control the behavior of the cluster when restarting
 	RestartConfig *RestartConfig `json:"restartConfig,omitempty"`
 
+	// UpgradeStrategy controls how Redpanda version upgrades are rolled out
+	UpgradeStrategy *UpgradeStrategy `json:"upgradeStrategy,omitempty"`
+
+	// Autoscaling adjusts the number of replicas to the load of the brokers.
+	// When enabled, Replicas is the initial number of replicas and the number
+	// set by the autoscaling is kept in the status.
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+
+	// CertificateReloadPolicy controls how the brokers pick up renewed
+	// listener certificates. Auto lets Redpanda versions that reload their
+	// certificates from disk pick up renewed node certificates without a
+	// restart, and restarts the brokers one by one when a CA changes.
+	// RollingRestart restarts the brokers one by one on any change. Defaults
+	// to Auto.
+	// +kubebuilder:validation:Enum=Auto;RollingRestart
+	CertificateReloadPolicy CertificateReloadPolicy `json:"certificateReloadPolicy,omitempty"`
+
+	// ConfigurationDriftMode controls how the centralized configuration
+	// properties changed outside of the operator are handled. Correct resets
+	// them to the desired configuration. Report only reports them in the
+	// ConfigurationDrift condition and in events. Defaults to Correct.
+	// +kubebuilder:validation:Enum=Correct;Report
+	ConfigurationDriftMode ConfigurationDriftMode `json:"configurationDriftMode,omitempty"`
+
 	// If key is not provided in the SecretRef, Secret data should have key "license"
 	LicenseRef *SecretKeyRef `json:"licenseRef,omitempty"`
 
@@ -207,6 +234,111 @@ type RestartConfig struct {
 	UnderReplicatedPartitionThreshold int `json:"underReplicatedPartitionThreshold,omitempty"`
 }
 
+// UpgradeStrategy contains strategies to roll out Redpanda version upgrades.
+type UpgradeStrategy struct {
+	// Canary upgrades a single broker first and holds the upgrade of the other
+	// brokers for a soak period. During the soak period the cluster health, the
+	// under replicated partitions and the metric thresholds of the canary
+	// broker are checked, and the upgrade is paused or rolled back when a
+	// check fails.
+	//
+	// A paused upgrade is resumed by changing the version (for example back to
+	// the previous one), or by removing the canary strategy to upgrade the
+	// remaining brokers without checks.
+	Canary *CanaryUpgrade `json:"canary,omitempty"`
+}
+
+// CanaryUpgrade configures canary upgrades
+type CanaryUpgrade struct {
+	// SoakPeriod is how long the canary broker must pass all checks before
+	// the other brokers are upgraded. It is also how long the canary broker
+	// has to become ready and healthy once restarted. Defaults to 10 minutes.
+	SoakPeriod *metav1.Duration `json:"soakPeriod,omitempty"`
+
+	// MetricThresholds are checked against the metrics scraped from the
+	// canary broker during the soak period
+	MetricThresholds []MetricThreshold `json:"metricThresholds,omitempty"`
+
+	// FailurePolicy is the action taken when a check fails: Pause stops the
+	// upgrade and leaves the canary broker on the new version, Rollback
+	// restarts the canary broker on the previous version.
+	// +kubebuilder:validation:Enum=Pause;Rollback
+	// +kubebuilder:default=Pause
+	FailurePolicy CanaryFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// CanaryFailurePolicy is the action taken when a canary upgrade check fails
+type CanaryFailurePolicy string
+
+const (
+	// CanaryFailurePolicyPause pauses the upgrade
+	CanaryFailurePolicyPause CanaryFailurePolicy = "Pause"
+	// CanaryFailurePolicyRollback rolls the canary broker back to the previous version
+	CanaryFailurePolicyRollback CanaryFailurePolicy = "Rollback"
+)
+
+// MetricThreshold is the maximum value of a metric exposed by the Redpanda
+// metrics endpoint of the admin API
+type MetricThreshold struct {
+	// Name of the metric family, e.g. vectorized_kafka_rpc_produce_bad_create_time
+	Name string `json:"name"`
+	// Labels restricts the samples of the metric to the ones with the given
+	// label values
+	Labels map[string]string `json:"labels,omitempty"`
+	// Max is the maximum value of the sum of the samples of the metric
+	Max resource.Quantity `json:"max"`
+}
+
+// AutoscalingSpec configures the horizontal autoscaling of the cluster.
+//
+// The metrics of the brokers are averaged and compared with their targets.
+// For each target the desired number of replicas is
+// ceil(replicas * average / target), and the highest one, within MinReplicas
+// and MaxReplicas, is set to the replicas of the autoscaling status. A broker
+// is then added, or decommissioned and removed, like for a manual change of
+// Replicas. When autoscaling is disabled, the cluster is scaled back to
+// Replicas.
+type AutoscalingSpec struct {
+	// MinReplicas is the lower limit of the number of replicas
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas int32 `json:"minReplicas"`
+	// MaxReplicas is the upper limit of the number of replicas
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+	// Targets are the average values of the broker metrics to maintain
+	Targets AutoscalingTargets `json:"targets"`
+	// ScaleUpCooldown is the minimum time between the last scaling decision
+	// and a scale up. Defaults to 5 minutes.
+	ScaleUpCooldown *metav1.Duration `json:"scaleUpCooldown,omitempty"`
+	// ScaleDownCooldown is the minimum time between the last scaling decision
+	// and a scale down. Defaults to 30 minutes.
+	ScaleDownCooldown *metav1.Duration `json:"scaleDownCooldown,omitempty"`
+}
+
+// AutoscalingTargets are the average values of the broker metrics the
+// autoscaling maintains. Unset targets are not evaluated.
+type AutoscalingTargets struct {
+	// CPUUtilizationPercentage is the target reactor utilization of the
+	// brokers, from the vectorized_reactor_utilization metric
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	CPUUtilizationPercentage *int32 `json:"cpuUtilizationPercentage,omitempty"`
+	// DiskUsagePercentage is the target usage of the data volume of the
+	// brokers, from the redpanda_storage_disk_total_bytes and
+	// redpanda_storage_disk_free_bytes public metrics
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	DiskUsagePercentage *int32 `json:"diskUsagePercentage,omitempty"`
+	// PartitionsPerBroker is the target number of partitions of the cluster
+	// per broker, replicas not included, from the redpanda_cluster_partitions
+	// public metric
+	// +kubebuilder:validation:Minimum=1
+	PartitionsPerBroker *int32 `json:"partitionsPerBroker,omitempty"`
+	// ProduceBytesPerSecond is the target produce throughput of a broker,
+	// from the vectorized_cluster_partition_bytes_produced_total metric
+	ProduceBytesPerSecond *resource.Quantity `json:"produceBytesPerSecond,omitempty"`
+}
+
 // PDBConfig specifies how the PodDisruptionBudget should be created for the
 // redpanda cluster. PDB will be created for the deployed cluster if Enabled is
 // set to true.
@@ -308,7 +440,95 @@ type StorageSpec struct {
 	Capacity resource.Quantity `json:"capacity,omitempty"`
 	// Storage class name - https://kubernetes.io/docs/concepts/storage/storage-classes/
 	StorageClassName string `json:"storageClassName,omitempty"`
-}
+	// AutoExpansion grows the volumes when their usage crosses a threshold.
+	// It is only supported for the Redpanda data volume.
+	AutoExpansion *VolumeAutoExpansion `json:"autoExpansion,omitempty"`
+}
+
+// VolumeAutoExpansion configures the automatic expansion of the Redpanda data
+// volumes. The usage is read from the redpanda_storage_disk_total_bytes and
+// redpanda_storage_disk_free_bytes public metrics of the brokers, and all volumes
+// are expanded when the usage of a broker crosses the threshold.
+type VolumeAutoExpansion struct {
+	// UsageThresholdPercentage is the usage of a volume above which the
+	// volumes are expanded
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +kubebuilder:default=80
+	UsageThresholdPercentage int32 `json:"usageThresholdPercentage,omitempty"`
+	// IncrementPercentage is how much the capacity grows at each expansion
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=20
+	IncrementPercentage int32 `json:"incrementPercentage,omitempty"`
+	// MaxCapacity is the capacity above which the volumes are not expanded
+	MaxCapacity resource.Quantity `json:"maxCapacity"`
+}
+
+// TopologySpec configures the placement of the brokers across zones
+type TopologySpec struct {
+	// ZoneLabel is the node label that defines the zone of a node. Defaults
+	// to topology.kubernetes.io/zone.
+	ZoneLabel string `json:"zoneLabel,omitempty"`
+	// RackLabel is the node label whose value is the rack of the brokers
+	// running on the node, when rack awareness is enabled. Defaults to
+	// ZoneLabel when set, otherwise to topology.cloud.redpanda.com/zone-id
+	// falling back to topology.kubernetes.io/zone.
+	RackLabel string `json:"rackLabel,omitempty"`
+	// Zones restricts the brokers to the nodes of the given zones. The
+	// replication factors of the cluster are validated against the number
+	// of zones, or against the racks of the nodes when no zone is listed
+	// and rack awareness is in effect.
+	Zones []string `json:"zones,omitempty"`
+	// MaxSkew is the maximum difference of the number of brokers between
+	// two zones. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	MaxSkew *int32 `json:"maxSkew,omitempty"`
+	// WhenUnsatisfiable tells whether a broker is scheduled when the spread
+	// across zones cannot be satisfied. Defaults to ScheduleAnyway.
+	// +kubebuilder:validation:Enum=ScheduleAnyway;DoNotSchedule
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+	// ZoneAntiAffinity keeps the brokers in distinct zones: Preferred
+	// schedules a broker in a zone without broker when possible, Required
+	// runs at most one broker per zone.
+	// +kubebuilder:validation:Enum=None;Preferred;Required
+	ZoneAntiAffinity ZoneAntiAffinity `json:"zoneAntiAffinity,omitempty"`
+}
+
+// CertificateReloadPolicy is how the brokers pick up renewed certificates
+type CertificateReloadPolicy string
+
+const (
+	// CertificateReloadPolicyAuto only restarts the brokers when a CA
+	// changes, if the Redpanda version reloads its certificates from disk
+	CertificateReloadPolicyAuto CertificateReloadPolicy = "Auto"
+	// CertificateReloadPolicyRollingRestart restarts the brokers when any
+	// certificate changes
+	CertificateReloadPolicyRollingRestart CertificateReloadPolicy = "RollingRestart"
+)
+
+// ConfigurationDriftMode is how configuration drift is handled
+type ConfigurationDriftMode string
+
+const (
+	// ConfigurationDriftModeCorrect resets the drifted properties to the
+	// desired configuration
+	ConfigurationDriftModeCorrect ConfigurationDriftMode = "Correct"
+	// ConfigurationDriftModeReport reports the drifted properties without
+	// changing them
+	ConfigurationDriftModeReport ConfigurationDriftMode = "Report"
+)
+
+// ZoneAntiAffinity is the anti-affinity of the brokers across zones
+type ZoneAntiAffinity string
+
+const (
+	// ZoneAntiAffinityNone does not add a zone anti-affinity
+	ZoneAntiAffinityNone ZoneAntiAffinity = "None"
+	// ZoneAntiAffinityPreferred prefers zones without broker
+	ZoneAntiAffinityPreferred ZoneAntiAffinity = "Preferred"
+	// ZoneAntiAffinityRequired runs at most one broker per zone
+	ZoneAntiAffinityRequired ZoneAntiAffinity = "Required"
+)
 
 // ExternalConnectivityConfig adds listener that can be reached outside
 // of a kubernetes cluster. The Service type NodePort will be used
@@ -411,6 +631,175 @@ type ClusterStatus struct {
 	// Current state of the cluster.
 	// +optional
 	Conditions []ClusterCondition `json:"conditions,omitempty"`
+	// Upgrade is the state of the canary upgrade of the cluster
+	// +optional
+	Upgrade *UpgradeStatus `json:"upgrade,omitempty"`
+	// Autoscaling is the state of the horizontal autoscaling of the cluster
+	// +optional
+	Autoscaling *AutoscalingStatus `json:"autoscaling,omitempty"`
+	// Storage is the state of the expansion of the data volumes
+	// +optional
+	Storage *StorageStatus `json:"storage,omitempty"`
+	// Certificates are the certificates mounted by the brokers, with their
+	// expiry dates
+	// +optional
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
+}
+
+// UpgradePhase is the phase of a canary upgrade
+type UpgradePhase string
+
+const (
+	// UpgradePhaseCanary is set while the canary broker is restarted on the new version
+	UpgradePhaseCanary UpgradePhase = "Canary"
+	// UpgradePhaseSoaking is set while the canary broker is checked during the soak period
+	UpgradePhaseSoaking UpgradePhase = "Soaking"
+	// UpgradePhaseRollingOut is set while the remaining brokers are upgraded
+	UpgradePhaseRollingOut UpgradePhase = "RollingOut"
+	// UpgradePhasePaused is set when a check failed and the upgrade is paused
+	UpgradePhasePaused UpgradePhase = "Paused"
+	// UpgradePhaseRolledBack is set when a check failed and the canary broker is rolled back
+	UpgradePhaseRolledBack UpgradePhase = "RolledBack"
+)
+
+// UpgradeStatus is the state of a canary upgrade
+type UpgradeStatus struct {
+	// Phase of the upgrade
+	Phase UpgradePhase `json:"phase"`
+	// FromVersion is the version the cluster is upgraded from
+	FromVersion string `json:"fromVersion"`
+	// ToVersion is the version the cluster is upgraded to
+	ToVersion string `json:"toVersion"`
+	// CanaryPod is the name of the Pod of the canary broker
+	// +optional
+	CanaryPod string `json:"canaryPod,omitempty"`
+	// PhaseStartTime is the time the current phase started
+	// +optional
+	PhaseStartTime metav1.Time `json:"phaseStartTime,omitempty"`
+	// Message describes the failed check when the upgrade is paused or
+	// rolled back
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// AutoscalingStatus is the state of the horizontal autoscaling
+type AutoscalingStatus struct {
+	// Replicas is the number of replicas set by the autoscaling, which
+	// replaces spec.replicas
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// DesiredReplicas is the number of replicas computed at the last
+	// evaluation, before the cooldowns are applied
+	// +optional
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+	// LastEvaluationTime is the time the broker metrics were last evaluated
+	// +optional
+	LastEvaluationTime metav1.Time `json:"lastEvaluationTime,omitempty"`
+	// LastScaleTime is the time of the last scaling decision
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+	// CurrentMetrics are the averages of the broker metrics at the last
+	// evaluation
+	// +optional
+	CurrentMetrics []AutoscalingMetricStatus `json:"currentMetrics,omitempty"`
+	// ProducedBytes is the total number of bytes produced to the brokers at
+	// the last evaluation, used to compute the produce throughput
+	// +optional
+	ProducedBytes int64 `json:"producedBytes,omitempty"`
+	// History are the last scaling decisions, the most recent last
+	// +optional
+	History []ScalingDecision `json:"history,omitempty"`
+}
+
+// AutoscalingMetricStatus is the value of an autoscaling target metric
+type AutoscalingMetricStatus struct {
+	// Name of the target
+	Name string `json:"name"`
+	// Current is the average value of the metric across the brokers
+	Current string `json:"current"`
+	// Target is the target value of the metric
+	Target string `json:"target"`
+	// DesiredReplicas is the number of replicas computed for the target
+	DesiredReplicas int32 `json:"desiredReplicas"`
+}
+
+// ScalingDecision is a change of replicas made by the autoscaling
+type ScalingDecision struct {
+	// Time of the decision
+	Time metav1.Time `json:"time"`
+	// FromReplicas is the number of replicas before the decision
+	FromReplicas int32 `json:"fromReplicas"`
+	// ToReplicas is the number of replicas after the decision
+	ToReplicas int32 `json:"toReplicas"`
+	// Reason describes the metrics that led to the decision
+	Reason string `json:"reason"`
+}
+
+// StorageStatus is the state of the expansion of the data volumes
+type StorageStatus struct {
+	// Capacity is the capacity reached by the automatic expansion of the
+	// data volumes. The data volumes are expanded to the larger of this
+	// capacity and the capacity of the storage spec.
+	// +optional
+	Capacity resource.Quantity `json:"capacity,omitempty"`
+	// LastEvaluationTime is the time the usage of the volumes was last
+	// evaluated for the automatic expansion
+	// +optional
+	LastEvaluationTime *metav1.Time `json:"lastEvaluationTime,omitempty"`
+	// LastExpansionTime is the time of the last automatic expansion
+	// +optional
+	LastExpansionTime *metav1.Time `json:"lastExpansionTime,omitempty"`
+	// Volumes are the data volumes that are expanded
+	// +optional
+	Volumes []VolumeStatus `json:"volumes,omitempty"`
+}
+
+// VolumeExpansionPhase is the phase of the expansion of a volume
+type VolumeExpansionPhase string
+
+const (
+	// VolumeExpansionPhaseResizing is set while the volume is expanded
+	VolumeExpansionPhaseResizing VolumeExpansionPhase = "Resizing"
+	// VolumeExpansionPhaseFileSystemResizePending is set when the volume is
+	// expanded and its file system is resized once the Pod is restarted by
+	// a rolling update
+	VolumeExpansionPhaseFileSystemResizePending VolumeExpansionPhase = "FileSystemResizePending"
+	// VolumeExpansionPhaseResized is set once the file system is resized
+	VolumeExpansionPhaseResized VolumeExpansionPhase = "Resized"
+	// VolumeExpansionPhaseNotExpandable is set when the storage class does
+	// not allow volume expansion
+	VolumeExpansionPhaseNotExpandable VolumeExpansionPhase = "NotExpandable"
+)
+
+// VolumeStatus is the state of the expansion of a data volume
+type VolumeStatus struct {
+	// Name of the PersistentVolumeClaim
+	Name string `json:"name"`
+	// Phase of the expansion
+	Phase VolumeExpansionPhase `json:"phase"`
+	// Capacity is the current capacity of the volume
+	// +optional
+	Capacity resource.Quantity `json:"capacity,omitempty"`
+	// Message describes why the volume is not expanded
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// CertificateStatus is the validity of a certificate mounted by the brokers.
+// When the key holds a bundle, the certificate that expires first is
+// reported.
+type CertificateStatus struct {
+	// SecretName is the name of the Secret holding the certificate
+	SecretName string `json:"secretName"`
+	// Key of the certificate in the Secret
+	Key string `json:"key"`
+	// Subject of the certificate
+	// +optional
+	Subject string `json:"subject,omitempty"`
+	// NotBefore is the start of the validity of the certificate
+	NotBefore metav1.Time `json:"notBefore"`
+	// NotAfter is the expiry date of the certificate
+	NotAfter metav1.Time `json:"notAfter"`
 }
 
 // ClusterCondition contains details for the current conditions of the cluster
@@ -431,13 +820,15 @@ type ClusterCondition struct {
 }
 
 // ClusterConditionType is a valid value for ClusterCondition.Type
-// +kubebuilder:validation:Enum=ClusterConfigured
+// +kubebuilder:validation:Enum=ClusterConfigured;ConfigurationDrift
 type ClusterConditionType string
 
 // These are valid conditions of the cluster.
 const (
 	// ClusterConfiguredConditionType indicates whether the Redpanda cluster configuration is in sync with the desired one
 	ClusterConfiguredConditionType ClusterConditionType = "ClusterConfigured"
+	// ClusterConfigurationDriftConditionType indicates whether properties of the Redpanda cluster configuration were changed outside of the operator
+	ClusterConfigurationDriftConditionType ClusterConditionType = "ConfigurationDrift"
 )
 
 // GetCondition return the condition of the given type
@@ -515,6 +906,16 @@ const (
 	ClusterConfiguredReasonError = "Error"
 )
 
+// These are valid reasons for ConfigurationDrift
+const (
+	// ClusterConfigurationDriftReasonCorrected indicates that drifted properties were detected and are being reset to the desired configuration
+	ClusterConfigurationDriftReasonCorrected = "Corrected"
+	// ClusterConfigurationDriftReasonReported indicates that drifted properties were detected and left unchanged, as the cluster is in report only mode
+	ClusterConfigurationDriftReasonReported = "Reported"
+	// ClusterConfigurationDriftReasonInSync indicates that no drifted properties were detected in the last check
+	ClusterConfigurationDriftReasonInSync = "InSync"
+)
+
 // NodesList shows where client of Cluster custom resource can reach
 // various listeners of Redpanda cluster
 type NodesList struct {
@@ -828,12 +1229,81 @@ func init() {
 	SchemeBuilder.Register(&Cluster{}, &ClusterList{})
 }
 
-// FullImageName returns image name including version
+// FullImageName returns image name including version. When a canary
+// upgrade was rolled back, the version the cluster was upgraded from is used.
 func (r *Cluster) FullImageName() string {
 	if r == nil {
 		return ""
 	}
-	return fmt.Sprintf("%s:%s", r.Spec.Image, r.Spec.Version)
+	version := r.Spec.Version
+	if r.IsUpgradeRolledBack() {
+		// Keep the brokers on the version the canary upgrade was rolled back to
+		version = r.Status.Upgrade.FromVersion
+	}
+	return fmt.Sprintf("%s:%s", r.Spec.Image, version)
+}
+
+// IsUpgradeRolledBack tells if the canary upgrade to the desired version was rolled back
+func (r *Cluster) IsUpgradeRolledBack() bool {
+	u := r.Status.Upgrade
+	return u != nil && u.Phase == UpgradePhaseRolledBack && u.ToVersion == r.Spec.Version
+}
+
+// DataVolumeCapacity returns the capacity of the data volumes: the larger of
+// the capacity of the storage spec and the capacity reached by the automatic
+// expansion
+func (r *Cluster) DataVolumeCapacity() resource.Quantity {
+	c := r.Spec.Storage.Capacity
+	if s := r.Status.Storage; s != nil && s.Capacity.Cmp(c) > 0 {
+		return s.Capacity
+	}
+	return c
+}
+
+// ZoneLabel returns the node label that defines the zone of a node
+func (r *Cluster) ZoneLabel() string {
+	if r.Spec.Topology != nil && r.Spec.Topology.ZoneLabel != "" {
+		return r.Spec.Topology.ZoneLabel
+	}
+	return corev1.LabelTopologyZone
+}
+
+// RackLabel returns the node label that defines the rack of a broker, or an
+// empty string when the rack is read from the default zone labels
+func (r *Cluster) RackLabel() string {
+	if r.Spec.Topology == nil {
+		return ""
+	}
+	if r.Spec.Topology.RackLabel != "" {
+		return r.Spec.Topology.RackLabel
+	}
+	return r.Spec.Topology.ZoneLabel
+}
+
+// GetCertificateReloadPolicy returns the certificate reload policy, defaulting
+// to Auto
+func (r *Cluster) GetCertificateReloadPolicy() CertificateReloadPolicy {
+	if r.Spec.CertificateReloadPolicy == "" {
+		return CertificateReloadPolicyAuto
+	}
+	return r.Spec.CertificateReloadPolicy
+}
+
+// GetConfigurationDriftMode returns the configuration drift mode, defaulting
+// to Correct
+func (r *Cluster) GetConfigurationDriftMode() ConfigurationDriftMode {
+	if r.Spec.ConfigurationDriftMode == "" {
+		return ConfigurationDriftModeCorrect
+	}
+	return r.Spec.ConfigurationDriftMode
+}
+
+// CanaryUpgrade returns the canary upgrade strategy, or nil if not configured
+func (r *Cluster) CanaryUpgrade() *CanaryUpgrade {
+	if r.Spec.UpgradeStrategy == nil {
+		return nil
+	}
+	return r.Spec.UpgradeStrategy.Canary
 }
 
 // ExternalListener returns external listener if found in configuration. Returns
@@ -1123,6 +1593,18 @@ func (s *ClusterStatus) SetRestarting(restarting bool) {
 	s.DeprecatedUpgrading = restarting
 }
 
+// GetDesiredReplicas returns the number of replicas the cluster is scaled to,
+// which is set by the autoscaling when it is enabled
+func (r *Cluster) GetDesiredReplicas() int32 {
+	if r == nil || r.Spec.Replicas == nil {
+		return 0
+	}
+	if r.Spec.Autoscaling != nil && r.Status.Autoscaling != nil && r.Status.Autoscaling.Replicas > 0 {
+		return r.Status.Autoscaling.Replicas
+	}
+	return *r.Spec.Replicas
+}
+
 // GetCurrentReplicas returns the current number of replicas that the controller wants to run.
 // It returns 1 when not initialized (as fresh clusters start from 1 replica)
 func (r *Cluster) GetCurrentReplicas() int32 {
@@ -252,3 +252,40 @@ func TestInitialReplicas(t *testing.T) {
 	cluster.Status.Nodes.Internal = nil
 	assert.Equal(t, int32(3), cluster.GetCurrentReplicas())
 }
+
+func TestDesiredReplicas(t *testing.T) {
+	cluster := v1alpha1.Cluster{}
+	assert.Equal(t, int32(0), cluster.GetDesiredReplicas())
+	cluster.Spec.Replicas = pointer.Int32(3)
+	assert.Equal(t, int32(3), cluster.GetDesiredReplicas())
+
+	// The replicas set by the autoscaling replace the ones of the spec
+	cluster.Spec.Autoscaling = &v1alpha1.AutoscalingSpec{MinReplicas: 3, MaxReplicas: 6}
+	assert.Equal(t, int32(3), cluster.GetDesiredReplicas())
+	cluster.Status.Autoscaling = &v1alpha1.AutoscalingStatus{Replicas: 5}
+	assert.Equal(t, int32(5), cluster.GetDesiredReplicas())
+
+	// The cluster is scaled back to the spec when autoscaling is disabled
+	cluster.Spec.Autoscaling = nil
+	assert.Equal(t, int32(3), cluster.GetDesiredReplicas())
+}
+
+func TestFullImageNameAfterRollback(t *testing.T) {
+	cluster := v1alpha1.Cluster{
+		Spec: v1alpha1.ClusterSpec{Image: "redpanda", Version: "v23.1.2"},
+	}
+	assert.Equal(t, "redpanda:v23.1.2", cluster.FullImageName())
+
+	cluster.Status.Upgrade = &v1alpha1.UpgradeStatus{
+		Phase:       v1alpha1.UpgradePhaseRolledBack,
+		FromVersion: "v22.3.11",
+		ToVersion:   "v23.1.2",
+	}
+	assert.True(t, cluster.IsUpgradeRolledBack())
+	assert.Equal(t, "redpanda:v22.3.11", cluster.FullImageName())
+
+	// A new version starts a new upgrade
+	cluster.Spec.Version = "v23.1.3"
+	assert.False(t, cluster.IsUpgradeRolledBack())
+	assert.Equal(t, "redpanda:v23.1.3", cluster.FullImageName())
+}
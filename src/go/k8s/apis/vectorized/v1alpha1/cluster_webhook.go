@@ -60,6 +60,9 @@ const (
 	httpBasicAuthorizationMechanism    = "http_basic"
 
 	defaultSchemaRegistryPort = 8081
+
+	defaultVolumeUsageThresholdPercentage = 80
+	defaultVolumeIncrementPercentage      = 20
 )
 
 // validHostnameSegment matches valid DNS name segments.
@@ -132,6 +135,15 @@ func (r *Cluster) Default() {
 		r.Spec.CloudStorage.CacheStorage.Capacity = resource.MustParse("20G")
 	}
 
+	if ae := r.Spec.Storage.AutoExpansion; ae != nil {
+		if ae.UsageThresholdPercentage == 0 {
+			ae.UsageThresholdPercentage = defaultVolumeUsageThresholdPercentage
+		}
+		if ae.IncrementPercentage == 0 {
+			ae.IncrementPercentage = defaultVolumeIncrementPercentage
+		}
+	}
+
 	r.setDefaultAdditionalConfiguration()
 	if r.Spec.PodDisruptionBudget == nil {
 		defaultMaxUnavailable := intstr.FromInt(1)
@@ -231,6 +243,8 @@ func (r *Cluster) ValidateUpdate(old runtime.Object) error {
 
 	allErrs = append(allErrs, r.validateDownscaling(oldCluster)...)
 
+	allErrs = append(allErrs, r.validateStorageCapacity(oldCluster)...)
+
 	allErrs = append(allErrs, r.validateRedpandaCoreChanges(oldCluster)...)
 
 	allErrs = append(allErrs, r.validateLicense(oldCluster)...)
@@ -260,6 +274,10 @@ func (r *Cluster) validateCommon(log logr.Logger) field.ErrorList {
 	allErrs = append(allErrs, r.validateRedpandaResources(redpandaResourceFields(r))...)
 	allErrs = append(allErrs, r.validateArchivalStorage()...)
 	allErrs = append(allErrs, r.validatePodDisruptionBudget()...)
+	allErrs = append(allErrs, r.validateUpgradeStrategy()...)
+	allErrs = append(allErrs, r.validateAutoscaling()...)
+	allErrs = append(allErrs, r.validateStorageAutoExpansion()...)
+	allErrs = append(allErrs, r.validateTopology()...)
 	if featuregates.InternalTopicReplication(r.Spec.Version) {
 		allErrs = append(allErrs, r.validateAdditionalConfiguration()...)
 	}
@@ -1036,6 +1054,212 @@ func (r *Cluster) validatePodDisruptionBudget() field.ErrorList {
 	return allErrs
 }
 
+func (r *Cluster) validateUpgradeStrategy() field.ErrorList {
+	var allErrs field.ErrorList
+	canary := r.CanaryUpgrade()
+	if canary == nil {
+		return allErrs
+	}
+	if canary.SoakPeriod != nil && canary.SoakPeriod.Duration <= 0 {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("spec").Child("upgradeStrategy").Child("canary").Child("soakPeriod"),
+				canary.SoakPeriod.Duration.String(),
+				"soak period must be positive"))
+	}
+	names := make(map[string]bool, len(canary.MetricThresholds))
+	for i, t := range canary.MetricThresholds {
+		if names[t.Name] {
+			allErrs = append(allErrs,
+				field.Duplicate(
+					field.NewPath("spec").Child("upgradeStrategy").Child("canary").Child("metricThresholds").Index(i).Child("name"),
+					t.Name))
+		}
+		names[t.Name] = true
+	}
+	return allErrs
+}
+
+func (r *Cluster) validateAutoscaling() field.ErrorList {
+	var allErrs field.ErrorList
+	as := r.Spec.Autoscaling
+	if as == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("autoscaling")
+	if !AllowDownscalingInWebhook {
+		allErrs = append(allErrs,
+			field.Forbidden(path,
+				"autoscaling requires downscaling: unset --allow-downscaling=false in the controller parameters to enable it"))
+	}
+	if as.MinReplicas < 1 {
+		allErrs = append(allErrs,
+			field.Invalid(path.Child("minReplicas"), as.MinReplicas, "minimum replicas must be at least 1"))
+	}
+	if as.MaxReplicas < as.MinReplicas {
+		allErrs = append(allErrs,
+			field.Invalid(path.Child("maxReplicas"), as.MaxReplicas, "maximum replicas must not be lower than minimum replicas"))
+	}
+	t := as.Targets
+	if t.CPUUtilizationPercentage == nil && t.DiskUsagePercentage == nil && t.PartitionsPerBroker == nil && t.ProduceBytesPerSecond == nil {
+		allErrs = append(allErrs,
+			field.Required(path.Child("targets"), "at least one target must be set"))
+	}
+	if t.ProduceBytesPerSecond != nil && t.ProduceBytesPerSecond.Sign() <= 0 {
+		allErrs = append(allErrs,
+			field.Invalid(path.Child("targets").Child("produceBytesPerSecond"), t.ProduceBytesPerSecond.String(), "produce throughput target must be positive"))
+	}
+	if as.ScaleUpCooldown != nil && as.ScaleUpCooldown.Duration < 0 {
+		allErrs = append(allErrs,
+			field.Invalid(path.Child("scaleUpCooldown"), as.ScaleUpCooldown.Duration.String(), "cooldown must not be negative"))
+	}
+	if as.ScaleDownCooldown != nil && as.ScaleDownCooldown.Duration < 0 {
+		allErrs = append(allErrs,
+			field.Invalid(path.Child("scaleDownCooldown"), as.ScaleDownCooldown.Duration.String(), "cooldown must not be negative"))
+	}
+	return allErrs
+}
+
+func (r *Cluster) validateStorageAutoExpansion() field.ErrorList {
+	var allErrs field.ErrorList
+	if cache := r.Spec.CloudStorage.CacheStorage; cache != nil && cache.AutoExpansion != nil {
+		allErrs = append(allErrs,
+			field.Forbidden(field.NewPath("spec").Child("cloudStorage").Child("cacheStorage").Child("autoExpansion"),
+				"automatic expansion is only supported for the data volume"))
+	}
+	ae := r.Spec.Storage.AutoExpansion
+	if ae == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("storage").Child("autoExpansion")
+	if ae.UsageThresholdPercentage < 1 || ae.UsageThresholdPercentage > 99 {
+		allErrs = append(allErrs,
+			field.Invalid(path.Child("usageThresholdPercentage"), ae.UsageThresholdPercentage, "usage threshold must be between 1 and 99"))
+	}
+	if ae.IncrementPercentage < 1 {
+		allErrs = append(allErrs,
+			field.Invalid(path.Child("incrementPercentage"), ae.IncrementPercentage, "increment must be at least 1"))
+	}
+	if ae.MaxCapacity.Cmp(r.Spec.Storage.Capacity) <= 0 {
+		allErrs = append(allErrs,
+			field.Invalid(path.Child("maxCapacity"), ae.MaxCapacity.String(), "maximum capacity must be greater than the storage capacity"))
+	}
+	return allErrs
+}
+
+// validateTopology checks that the zones are unique and that the replicas of
+// the topics can be placed in distinct zones. When rack awareness is in
+// effect without a list of zones, the racks are counted from the labels of
+// the nodes.
+func (r *Cluster) validateTopology() field.ErrorList {
+	var allErrs field.ErrorList
+	topology := r.Spec.Topology
+	if topology == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("topology")
+	rackLabel := r.RackLabel()
+	if rackLabel != "" && !featuregates.RackAwareness(r.Spec.Version) {
+		rackPath := path.Child("rackLabel")
+		if topology.RackLabel == "" {
+			rackPath = path.Child("zoneLabel")
+		}
+		allErrs = append(allErrs,
+			field.Forbidden(rackPath,
+				fmt.Sprintf("rack awareness is not supported by redpanda version %s", r.Spec.Version)))
+	}
+
+	zones := make(map[string]bool, len(topology.Zones))
+	for i, zone := range topology.Zones {
+		if zone == "" {
+			allErrs = append(allErrs,
+				field.Invalid(path.Child("zones").Index(i), zone, "zone must not be empty"))
+		}
+		if zones[zone] {
+			allErrs = append(allErrs,
+				field.Duplicate(path.Child("zones").Index(i), zone))
+		}
+		zones[zone] = true
+	}
+
+	if len(topology.Zones) > 0 && topology.ZoneAntiAffinity == ZoneAntiAffinityRequired && r.Spec.Replicas != nil && int(*r.Spec.Replicas) > len(topology.Zones) {
+		allErrs = append(allErrs,
+			field.Invalid(path.Child("zoneAntiAffinity"), topology.ZoneAntiAffinity,
+				fmt.Sprintf("%d replicas cannot run in distinct zones out of %d zones", *r.Spec.Replicas, len(topology.Zones))))
+	}
+
+	racks, source := len(topology.Zones), "zones"
+	if racks == 0 && rackLabel != "" {
+		var err error
+		racks, err = countNodeRacks(rackLabel)
+		if err != nil {
+			allErrs = append(allErrs,
+				field.InternalError(path, fmt.Errorf("unable to count the racks of the nodes: %w", err)))
+			return allErrs
+		}
+		source = fmt.Sprintf("racks of the nodes labeled %s", rackLabel)
+	}
+	// Nodes may not be labeled yet, in which case there is nothing to check
+	if racks == 0 {
+		return allErrs
+	}
+
+	for _, k := range []string{
+		defaultTopicReplicationKey,
+		internalTopicReplicationFactorKey,
+		transactionCoordinatorReplicationKey,
+		idAllocatorReplicationKey,
+	} {
+		v, ok := r.Spec.AdditionalConfiguration[k]
+		if !ok {
+			continue
+		}
+		// invalid values are reported by validateAdditionalConfiguration
+		replication, err := strconv.Atoi(v)
+		if err != nil {
+			continue
+		}
+		if replication > racks {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec").Child("additionalConfiguration").Child(k), v,
+					fmt.Sprintf("replication factor cannot be satisfied across %d %s", racks, source)))
+		}
+	}
+	return allErrs
+}
+
+// countNodeRacks returns the number of distinct values of the rack label of
+// the nodes
+func countNodeRacks(rackLabel string) (int, error) {
+	if kclient == nil {
+		return 0, nil
+	}
+	var nodes corev1.NodeList
+	if err := kclient.List(context.TODO(), &nodes, client.HasLabels{rackLabel}); err != nil {
+		return 0, err
+	}
+	racks := make(map[string]bool)
+	for i := range nodes.Items {
+		if rack := nodes.Items[i].Labels[rackLabel]; rack != "" {
+			racks[rack] = true
+		}
+	}
+	return len(racks), nil
+}
+
+// validateStorageCapacity rejects the decrease of the storage capacity, as
+// volumes can only be expanded
+func (r *Cluster) validateStorageCapacity(old *Cluster) field.ErrorList {
+	var allErrs field.ErrorList
+	if !old.Spec.Storage.Capacity.IsZero() && !r.Spec.Storage.Capacity.IsZero() && r.Spec.Storage.Capacity.Cmp(old.Spec.Storage.Capacity) < 0 {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec").Child("storage").Child("capacity"),
+				r.Spec.Storage.Capacity.String(),
+				fmt.Sprintf("storage capacity must not be decreased below %s, volumes can only be expanded", old.Spec.Storage.Capacity.String())))
+	}
+	return allErrs
+}
+
 func (r *Cluster) validateAdditionalConfiguration() field.ErrorList {
 	var allErrs field.ErrorList
 	var idAllocatorReplication, transactionCoordinatorReplication, defaultTopicReplication int
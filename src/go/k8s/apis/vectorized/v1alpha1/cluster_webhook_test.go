@@ -15,6 +15,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 	"github.com/stretchr/testify/assert"
@@ -29,6 +30,7 @@ import (
 
 	"github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -1664,6 +1666,237 @@ func validRedpandaCluster() *v1alpha1.Cluster {
 	}
 }
 
+func TestUpgradeStrategy(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+
+	t.Run("canary with defaults is valid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.UpgradeStrategy = &v1alpha1.UpgradeStrategy{Canary: &v1alpha1.CanaryUpgrade{}}
+
+		err := rpc.ValidateCreate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("canary with negative soak period is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.UpgradeStrategy = &v1alpha1.UpgradeStrategy{Canary: &v1alpha1.CanaryUpgrade{
+			SoakPeriod: &metav1.Duration{Duration: -time.Minute},
+		}}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("canary with duplicate metric thresholds is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.UpgradeStrategy = &v1alpha1.UpgradeStrategy{Canary: &v1alpha1.CanaryUpgrade{
+			MetricThresholds: []v1alpha1.MetricThreshold{
+				{Name: "vectorized_reactor_utilization", Max: resource.MustParse("90")},
+				{Name: "vectorized_reactor_utilization", Max: resource.MustParse("95")},
+			},
+		}}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+}
+
+func TestAutoscaling(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+	v1alpha1.AllowDownscalingInWebhook = true
+	defer func() { v1alpha1.AllowDownscalingInWebhook = false }()
+
+	autoscaling := func() *v1alpha1.AutoscalingSpec {
+		return &v1alpha1.AutoscalingSpec{
+			MinReplicas: 3,
+			MaxReplicas: 9,
+			Targets: v1alpha1.AutoscalingTargets{
+				CPUUtilizationPercentage: pointer.Int32(70),
+			},
+		}
+	}
+
+	t.Run("autoscaling with a target is valid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Autoscaling = autoscaling()
+
+		err := rpc.ValidateCreate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("autoscaling without target is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Autoscaling = autoscaling()
+		rpc.Spec.Autoscaling.Targets = v1alpha1.AutoscalingTargets{}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("autoscaling with max replicas lower than min replicas is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Autoscaling = autoscaling()
+		rpc.Spec.Autoscaling.MaxReplicas = 2
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("autoscaling with negative cooldown is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Autoscaling = autoscaling()
+		rpc.Spec.Autoscaling.ScaleDownCooldown = &metav1.Duration{Duration: -time.Minute}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("autoscaling without downscaling is invalid", func(t *testing.T) {
+		v1alpha1.AllowDownscalingInWebhook = false
+		defer func() { v1alpha1.AllowDownscalingInWebhook = true }()
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Autoscaling = autoscaling()
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+}
+
+func TestStorageAutoExpansion(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+	rpCluster.Spec.Storage.Capacity = resource.MustParse("100Gi")
+
+	t.Run("auto expansion with defaults is valid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Storage.AutoExpansion = &v1alpha1.VolumeAutoExpansion{MaxCapacity: resource.MustParse("500Gi")}
+		rpc.Default()
+
+		assert.Equal(t, int32(80), rpc.Spec.Storage.AutoExpansion.UsageThresholdPercentage)
+		assert.Equal(t, int32(20), rpc.Spec.Storage.AutoExpansion.IncrementPercentage)
+		err := rpc.ValidateCreate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("auto expansion with max capacity below capacity is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Storage.AutoExpansion = &v1alpha1.VolumeAutoExpansion{MaxCapacity: resource.MustParse("50Gi")}
+		rpc.Default()
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("auto expansion of the cache storage is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.CloudStorage.CacheStorage = &v1alpha1.StorageSpec{
+			Capacity:      resource.MustParse("10Gi"),
+			AutoExpansion: &v1alpha1.VolumeAutoExpansion{MaxCapacity: resource.MustParse("50Gi")},
+		}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("capacity can be increased", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Storage.Capacity = resource.MustParse("200Gi")
+
+		err := rpc.ValidateUpdate(rpCluster)
+		assert.NoError(t, err)
+	})
+
+	t.Run("capacity cannot be decreased", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Storage.Capacity = resource.MustParse("50Gi")
+
+		err := rpc.ValidateUpdate(rpCluster)
+		assert.Error(t, err)
+	})
+}
+
+func TestTopology(t *testing.T) {
+	rpCluster := validRedpandaCluster()
+	rpCluster.Spec.Replicas = pointer.Int32(3)
+
+	t.Run("topology across three zones is valid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Topology = &v1alpha1.TopologySpec{
+			Zones:            []string{"a", "b", "c"},
+			ZoneAntiAffinity: v1alpha1.ZoneAntiAffinityRequired,
+		}
+		rpc.Spec.AdditionalConfiguration = map[string]string{"redpanda.default_topic_replications": "3"}
+
+		err := rpc.ValidateCreate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("duplicate zones are invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Topology = &v1alpha1.TopologySpec{Zones: []string{"a", "b", "a"}}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("replication factor above the number of zones is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Topology = &v1alpha1.TopologySpec{Zones: []string{"a", "b"}}
+		rpc.Spec.AdditionalConfiguration = map[string]string{"redpanda.default_topic_replications": "3"}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("required zone anti-affinity with more replicas than zones is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Topology = &v1alpha1.TopologySpec{
+			Zones:            []string{"a", "b"},
+			ZoneAntiAffinity: v1alpha1.ZoneAntiAffinityRequired,
+		}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("rack label with redpanda older than 22.1 is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Version = "v21.11.1"
+		rpc.Spec.Topology = &v1alpha1.TopologySpec{RackLabel: "example.com/rack"}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("zone label used as rack with redpanda older than 22.1 is invalid", func(t *testing.T) {
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Version = "v21.11.1"
+		rpc.Spec.Topology = &v1alpha1.TopologySpec{ZoneLabel: "example.com/zone"}
+
+		err := rpc.ValidateCreate()
+		assert.Error(t, err)
+	})
+
+	t.Run("replication factor is checked against the racks of the nodes", func(t *testing.T) {
+		var nodes []client.Object
+		for i, zone := range []string{"a", "b", "b"} {
+			nodes = append(nodes, &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name:   fmt.Sprintf("node-%d", i),
+				Labels: map[string]string{"example.com/zone": zone},
+			}})
+		}
+		v1alpha1.SetK8sClient(fake.NewClientBuilder().WithObjects(nodes...).Build())
+		defer v1alpha1.SetK8sClient(fakeK8sClient)
+
+		rpc := rpCluster.DeepCopy()
+		rpc.Spec.Topology = &v1alpha1.TopologySpec{ZoneLabel: "example.com/zone"}
+		rpc.Spec.AdditionalConfiguration = map[string]string{"redpanda.default_topic_replications": "3"}
+		assert.Error(t, rpc.ValidateCreate())
+
+		rpc.Spec.AdditionalConfiguration = map[string]string{"redpanda.default_topic_replications": "1"}
+		assert.NoError(t, rpc.ValidateCreate())
+	})
+}
+
 func TestPodDisruptionBudget(t *testing.T) {
 	rpCluster := validRedpandaCluster()
 	value := intstr.FromInt(1)
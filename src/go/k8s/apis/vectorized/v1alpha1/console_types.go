@@ -27,6 +27,10 @@ var (
 
 	// ErrClusterNotConfigured is error returned if referenced Cluster is not yet configured
 	ErrClusterNotConfigured = fmt.Errorf("cluster not configured")
+
+	// ErrClusterRefNamespace is error returned if referenced Cluster is not in
+	// the namespace of the referencing resource
+	ErrClusterRefNamespace = fmt.Errorf("cluster must be in the same namespace")
 )
 
 // ConsoleSpec defines the desired state of Console
@@ -46,7 +50,7 @@ type ConsoleSpec struct {
 	// +optional
 	Server Server `json:"server"`
 
-	SchemaRegistry Schema `json:"schema"`
+	SchemaRegistry ConsoleSchemaRegistry `json:"schema"`
 
 	// The referenced Redpanda Cluster
 	ClusterRef NamespaceNameRef `json:"clusterRef"`
@@ -131,8 +135,8 @@ type Server struct {
 	StripPrefix bool `json:"stripPrefix,omitempty"`
 }
 
-// Schema defines configurable fields for Schema Registry
-type Schema struct {
+// ConsoleSchemaRegistry defines configurable fields for Schema Registry
+type ConsoleSchemaRegistry struct {
 	Enabled bool `json:"enabled"`
 
 	// Indication on whether to use the schema registry CA as trust when connecting to the schema registry.
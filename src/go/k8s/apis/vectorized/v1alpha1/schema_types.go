@@ -0,0 +1,200 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package v1alpha1
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SchemaType is the format of a schema
+type SchemaType string
+
+const (
+	// SchemaTypeAvro is an Avro schema
+	SchemaTypeAvro SchemaType = "Avro"
+	// SchemaTypeProtobuf is a Protobuf schema
+	SchemaTypeProtobuf SchemaType = "Protobuf"
+	// SchemaTypeJSON is a JSON schema
+	SchemaTypeJSON SchemaType = "JSON"
+)
+
+const (
+	// SchemaReadyCondition is True when the schema is registered as the
+	// latest version of the subject
+	SchemaReadyCondition = "Ready"
+	// SchemaCompatibleCondition is False when the schema is not compatible
+	// with the latest version of the subject and was not registered
+	SchemaCompatibleCondition = "Compatible"
+)
+
+// SchemaReference is a reference to a schema registered under another subject
+type SchemaReference struct {
+	// Name of the reference, as used in the schema (for example the import
+	// path of a Protobuf file).
+	Name string `json:"name"`
+
+	// Subject of the referenced schema.
+	Subject string `json:"subject"`
+
+	// Version of the referenced schema.
+	Version int `json:"version"`
+}
+
+// SchemaSpec defines the desired state of Schema
+type SchemaSpec struct {
+	// The referenced Redpanda Cluster. It must be in the namespace of the
+	// Schema.
+	ClusterRef NamespaceNameRef `json:"clusterRef"`
+
+	// Subject the schema is registered under, if different from the name of
+	// the Schema resource. It cannot be changed once set.
+	// +optional
+	Subject string `json:"subject,omitempty"`
+
+	// SchemaType is the format of the schema.
+	// +optional
+	// +kubebuilder:validation:Enum=Avro;Protobuf;JSON
+	// +kubebuilder:default=Avro
+	SchemaType SchemaType `json:"schemaType,omitempty"`
+
+	// Schema is the schema definition. Either Schema or SchemaConfigMapRef
+	// must be set.
+	// +optional
+	Schema string `json:"schema,omitempty"`
+
+	// SchemaConfigMapRef selects the key of a ConfigMap in the namespace of
+	// the Schema that holds the schema definition.
+	// +optional
+	SchemaConfigMapRef *corev1.ConfigMapKeySelector `json:"schemaConfigMapRef,omitempty"`
+
+	// References are the schemas referenced by the schema.
+	// +optional
+	References []SchemaReference `json:"references,omitempty"`
+
+	// Compatibility is the compatibility level of the subject. When unset,
+	// the compatibility level of the subject is not managed.
+	// +optional
+	// +kubebuilder:validation:Enum=NONE;BACKWARD;BACKWARD_TRANSITIVE;FORWARD;FORWARD_TRANSITIVE;FULL;FULL_TRANSITIVE
+	Compatibility string `json:"compatibility,omitempty"`
+}
+
+// SchemaStatus defines the observed state of Schema
+type SchemaStatus struct {
+	// ObservedGeneration is the last observed generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Subject is the subject of the managed schema.
+	// +optional
+	Subject string `json:"subject,omitempty"`
+
+	// ID is the global ID of the registered schema.
+	// +optional
+	ID int `json:"id,omitempty"`
+
+	// Version is the version of the registered schema in the subject.
+	// +optional
+	Version int `json:"version,omitempty"`
+
+	// Conditions holds the conditions for the Schema.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Subject",type="string",JSONPath=".status.subject"
+//+kubebuilder:printcolumn:name="Version",type="integer",JSONPath=".status.version"
+//+kubebuilder:printcolumn:name="ID",type="integer",JSONPath=".status.id"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message"
+
+// Schema is the Schema for the schemas API
+type Schema struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SchemaSpec   `json:"spec,omitempty"`
+	Status SchemaStatus `json:"status,omitempty"`
+}
+
+// GetSubject returns the subject the schema is registered under
+func (s *Schema) GetSubject() string {
+	if s.Status.Subject != "" {
+		return s.Status.Subject
+	}
+	if s.Spec.Subject != "" {
+		return s.Spec.Subject
+	}
+	return s.GetName()
+}
+
+// GetSchemaType returns the schema type, defaulting to Avro
+func (s *Schema) GetSchemaType() SchemaType {
+	if s.Spec.SchemaType == "" {
+		return SchemaTypeAvro
+	}
+	return s.Spec.SchemaType
+}
+
+// GetClusterRef returns the NamespacedName of referenced Cluster object
+func (s *Schema) GetClusterRef() types.NamespacedName {
+	return types.NamespacedName{Name: s.Spec.ClusterRef.Name, Namespace: s.Spec.ClusterRef.Namespace}
+}
+
+// GetCluster returns the referenced Cluster object
+func (s *Schema) GetCluster(
+	ctx context.Context, cl client.Client,
+) (*Cluster, error) {
+	if s.Spec.ClusterRef.Namespace != s.Namespace {
+		return nil, ErrClusterRefNamespace
+	}
+	cluster := &Cluster{}
+	if err := cl.Get(ctx, s.GetClusterRef(), cluster); err != nil {
+		return nil, err
+	}
+	if cc := cluster.Status.GetCondition(ClusterConfiguredConditionType); cc == nil || cc.Status != corev1.ConditionTrue {
+		return cluster, ErrClusterNotConfigured
+	}
+	return cluster, nil
+}
+
+// SetCondition sets a status condition of the Schema, tracking the
+// generation it was observed at
+func (s *Schema) SetCondition(
+	conditionType string, status metav1.ConditionStatus, reason, message string,
+) {
+	apimeta.SetStatusCondition(&s.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: s.GetGeneration(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+//+kubebuilder:object:root=true
+
+// SchemaList contains a list of Schema
+type SchemaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Schema `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Schema{}, &SchemaList{})
+}
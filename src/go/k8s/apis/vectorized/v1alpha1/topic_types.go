@@ -0,0 +1,210 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package v1alpha1
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TopicDeletionPolicy defines what happens to the Kafka topic when the Topic
+// resource is deleted
+type TopicDeletionPolicy string
+
+const (
+	// TopicDeletionPolicyDelete deletes the Kafka topic with the Topic resource
+	TopicDeletionPolicyDelete TopicDeletionPolicy = "Delete"
+	// TopicDeletionPolicyRetain keeps the Kafka topic when the Topic resource
+	// is deleted
+	TopicDeletionPolicyRetain TopicDeletionPolicy = "Retain"
+)
+
+const (
+	// TopicReadyCondition is True when the Kafka topic exists and matches the
+	// spec
+	TopicReadyCondition = "Ready"
+	// TopicDriftedCondition is True when the Kafka topic differs from the spec
+	// in a way that the operator cannot correct, such as a different
+	// replication factor or more partitions than requested
+	TopicDriftedCondition = "Drifted"
+)
+
+// TopicSpec defines the desired state of Topic
+type TopicSpec struct {
+	// The referenced Redpanda Cluster. It must be in the namespace of the
+	// Topic.
+	ClusterRef NamespaceNameRef `json:"clusterRef"`
+
+	// TopicName is the name of the Kafka topic, if different from the name of
+	// the Topic resource (for example, names that are not valid Kubernetes
+	// object names). It cannot be changed once set.
+	// +optional
+	TopicName string `json:"topicName,omitempty"`
+
+	// Partitions is the number of partitions. When unset, the cluster default
+	// is used. Partitions can be added but not removed.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Partitions *int32 `json:"partitions,omitempty"`
+
+	// ReplicationFactor is the number of replicas of each partition. When
+	// unset, the cluster default is used. It cannot be changed once the topic
+	// is created.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ReplicationFactor *int32 `json:"replicationFactor,omitempty"`
+
+	// Configs are topic-level configuration properties, for example
+	// "cleanup.policy" or "retention.ms". Properties that are set on the
+	// topic but are not listed here are reset to the cluster default.
+	// +optional
+	Configs map[string]string `json:"configs,omitempty"`
+
+	// DeletionPolicy defines whether the Kafka topic is deleted with the Topic
+	// resource (Delete) or kept (Retain).
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +kubebuilder:default=Retain
+	DeletionPolicy TopicDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// AdoptExisting allows the operator to manage a Kafka topic that already
+	// exists, resetting the configs that are not in the spec. Adopted topics
+	// are never deleted by the operator, whatever the deletion policy.
+	// +optional
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+}
+
+// TopicStatus defines the observed state of Topic
+type TopicStatus struct {
+	// ObservedGeneration is the last observed generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// TopicName is the name of the managed Kafka topic.
+	// +optional
+	TopicName string `json:"topicName,omitempty"`
+
+	// Created is true if the Kafka topic was created by the operator, and
+	// can be deleted with the Topic.
+	// +optional
+	Created bool `json:"created,omitempty"`
+
+	// Partitions is the observed number of partitions.
+	// +optional
+	Partitions int32 `json:"partitions,omitempty"`
+
+	// ReplicationFactor is the observed replication factor.
+	// +optional
+	ReplicationFactor int32 `json:"replicationFactor,omitempty"`
+
+	// Conditions holds the conditions for the Topic.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Topic",type="string",JSONPath=".status.topicName"
+//+kubebuilder:printcolumn:name="Partitions",type="integer",JSONPath=".status.partitions"
+//+kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".status.replicationFactor"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message"
+
+// Topic is the Schema for the topics API
+type Topic struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TopicSpec   `json:"spec,omitempty"`
+	Status TopicStatus `json:"status,omitempty"`
+}
+
+// GetTopicName returns the name of the Kafka topic
+func (t *Topic) GetTopicName() string {
+	if t.Status.TopicName != "" {
+		return t.Status.TopicName
+	}
+	if t.Spec.TopicName != "" {
+		return t.Spec.TopicName
+	}
+	return t.GetName()
+}
+
+// GetDeletionPolicy returns the deletion policy, defaulting to Retain
+func (t *Topic) GetDeletionPolicy() TopicDeletionPolicy {
+	if t.Spec.DeletionPolicy == "" {
+		return TopicDeletionPolicyRetain
+	}
+	return t.Spec.DeletionPolicy
+}
+
+// GetClusterRef returns the NamespacedName of referenced Cluster object
+func (t *Topic) GetClusterRef() types.NamespacedName {
+	return types.NamespacedName{Name: t.Spec.ClusterRef.Name, Namespace: t.Spec.ClusterRef.Namespace}
+}
+
+// GetCluster returns the referenced Cluster object
+func (t *Topic) GetCluster(
+	ctx context.Context, cl client.Client,
+) (*Cluster, error) {
+	if t.Spec.ClusterRef.Namespace != t.Namespace {
+		return nil, ErrClusterRefNamespace
+	}
+	cluster := &Cluster{}
+	if err := cl.Get(ctx, t.GetClusterRef(), cluster); err != nil {
+		return nil, err
+	}
+	if cc := cluster.Status.GetCondition(ClusterConfiguredConditionType); cc == nil || cc.Status != corev1.ConditionTrue {
+		return cluster, ErrClusterNotConfigured
+	}
+	return cluster, nil
+}
+
+// GetConditions returns the status conditions of the object.
+func (t *Topic) GetConditions() *[]metav1.Condition {
+	return &t.Status.Conditions
+}
+
+// GetCondition returns the status condition of the given type, or nil
+func (t *Topic) GetCondition(conditionType string) *metav1.Condition {
+	return apimeta.FindStatusCondition(t.Status.Conditions, conditionType)
+}
+
+// SetCondition sets a status condition of the Topic, tracking the generation
+// it was observed at
+func (t *Topic) SetCondition(
+	conditionType string, status metav1.ConditionStatus, reason, message string,
+) {
+	apimeta.SetStatusCondition(t.GetConditions(), metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: t.GetGeneration(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+//+kubebuilder:object:root=true
+
+// TopicList contains a list of Topic
+type TopicList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Topic `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Topic{}, &TopicList{})
+}
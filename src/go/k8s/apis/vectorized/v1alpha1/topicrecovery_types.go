@@ -0,0 +1,183 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package v1alpha1
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TopicRecoveryPhase is the phase of a topic recovery
+type TopicRecoveryPhase string
+
+const (
+	// TopicRecoveryPhasePending waits for the cluster to be ready and for
+	// any other recovery of the cluster to finish
+	TopicRecoveryPhasePending TopicRecoveryPhase = "Pending"
+	// TopicRecoveryPhaseRunning recovers the topics from the cloud storage
+	TopicRecoveryPhaseRunning TopicRecoveryPhase = "Running"
+	// TopicRecoveryPhaseCompleted is set when all topics were recovered
+	TopicRecoveryPhaseCompleted TopicRecoveryPhase = "Completed"
+	// TopicRecoveryPhaseFailed is set when the recovery could not be
+	// started or some segments failed to be downloaded
+	TopicRecoveryPhaseFailed TopicRecoveryPhase = "Failed"
+)
+
+const (
+	// TopicRecoveryCompletedCondition is True when the topics were recovered
+	TopicRecoveryCompletedCondition = "Completed"
+
+	// DefaultTopicNamesPattern recovers all topics of the bucket
+	DefaultTopicNamesPattern = ".*"
+)
+
+// TopicRecoverySpec defines the desired state of TopicRecovery
+type TopicRecoverySpec struct {
+	// The referenced Redpanda Cluster. Its cloud storage must be enabled and
+	// configured with the bucket to recover the topics from. It must be in the
+	// namespace of the TopicRecovery.
+	ClusterRef NamespaceNameRef `json:"clusterRef"`
+
+	// TopicNamesPattern is a regular expression matching the names of the
+	// topics to recover from the bucket. Topics that already exist in the
+	// cluster are not recovered.
+	// +optional
+	// +kubebuilder:default=".*"
+	TopicNamesPattern string `json:"topicNamesPattern,omitempty"`
+}
+
+// TopicRecoveryProgress is the progress of the recovery of a topic
+type TopicRecoveryProgress struct {
+	// Topic is the namespaced name of the topic.
+	Topic string `json:"topic"`
+	// PendingDownloads is the number of partitions being downloaded.
+	PendingDownloads int `json:"pendingDownloads"`
+	// SuccessfulDownloads is the number of partitions downloaded.
+	SuccessfulDownloads int `json:"successfulDownloads"`
+	// FailedDownloads is the number of partitions that failed to download.
+	FailedDownloads int `json:"failedDownloads"`
+}
+
+// TopicRecoveryStatus defines the observed state of TopicRecovery
+type TopicRecoveryStatus struct {
+	// ObservedGeneration is the generation the recovery was run for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase of the recovery.
+	// +optional
+	Phase TopicRecoveryPhase `json:"phase,omitempty"`
+
+	// State is the state of the recovery reported by Redpanda.
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// StartTime is when the recovery was started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the recovery completed or failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Topics is the progress of the recovery of each topic.
+	// +optional
+	Topics []TopicRecoveryProgress `json:"topics,omitempty"`
+
+	// Conditions holds the conditions for the TopicRecovery.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Pattern",type="string",JSONPath=".spec.topicNamesPattern"
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Completed\")].message"
+
+// TopicRecovery is the Schema for the topicrecoveries API. It recovers the
+// topics of a cluster from its cloud storage bucket, once the cluster is
+// ready. The recovery runs once per generation of the resource.
+type TopicRecovery struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TopicRecoverySpec   `json:"spec,omitempty"`
+	Status TopicRecoveryStatus `json:"status,omitempty"`
+}
+
+// GetTopicNamesPattern returns the pattern of the topics to recover,
+// defaulting to all topics
+func (t *TopicRecovery) GetTopicNamesPattern() string {
+	if t.Spec.TopicNamesPattern == "" {
+		return DefaultTopicNamesPattern
+	}
+	return t.Spec.TopicNamesPattern
+}
+
+// IsFinished tells if the recovery of the observed generation completed or
+// failed
+func (t *TopicRecovery) IsFinished() bool {
+	return t.Status.Phase == TopicRecoveryPhaseCompleted || t.Status.Phase == TopicRecoveryPhaseFailed
+}
+
+// GetClusterRef returns the NamespacedName of referenced Cluster object
+func (t *TopicRecovery) GetClusterRef() types.NamespacedName {
+	return types.NamespacedName{Name: t.Spec.ClusterRef.Name, Namespace: t.Spec.ClusterRef.Namespace}
+}
+
+// GetCluster returns the referenced Cluster object
+func (t *TopicRecovery) GetCluster(
+	ctx context.Context, cl client.Client,
+) (*Cluster, error) {
+	if t.Spec.ClusterRef.Namespace != t.Namespace {
+		return nil, ErrClusterRefNamespace
+	}
+	cluster := &Cluster{}
+	if err := cl.Get(ctx, t.GetClusterRef(), cluster); err != nil {
+		return nil, err
+	}
+	if cc := cluster.Status.GetCondition(ClusterConfiguredConditionType); cc == nil || cc.Status != corev1.ConditionTrue {
+		return cluster, ErrClusterNotConfigured
+	}
+	return cluster, nil
+}
+
+// SetCondition sets a status condition of the TopicRecovery, tracking the
+// generation it was observed at
+func (t *TopicRecovery) SetCondition(
+	conditionType string, status metav1.ConditionStatus, reason, message string,
+) {
+	apimeta.SetStatusCondition(&t.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: t.GetGeneration(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+//+kubebuilder:object:root=true
+
+// TopicRecoveryList contains a list of TopicRecovery
+type TopicRecoveryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TopicRecovery `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TopicRecovery{}, &TopicRecoveryList{})
+}
@@ -0,0 +1,194 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package v1alpha1
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UserMechanism is the SCRAM mechanism of a User
+type UserMechanism string
+
+const (
+	// UserMechanismScramSha256 is the SCRAM-SHA-256 mechanism
+	UserMechanismScramSha256 UserMechanism = "SCRAM-SHA-256"
+	// UserMechanismScramSha512 is the SCRAM-SHA-512 mechanism
+	UserMechanismScramSha512 UserMechanism = "SCRAM-SHA-512"
+)
+
+const (
+	// UserReadyCondition is True when the SASL user exists with the
+	// credentials of the spec
+	UserReadyCondition = "Ready"
+)
+
+// UserSpec defines the desired state of User
+type UserSpec struct {
+	// The referenced Redpanda Cluster. It must be in the namespace of the
+	// User.
+	ClusterRef NamespaceNameRef `json:"clusterRef"`
+
+	// Username is the SASL username, if different from the name of the User
+	// resource. It cannot be changed once set.
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// Mechanism is the SCRAM mechanism of the credentials.
+	// +optional
+	// +kubebuilder:validation:Enum=SCRAM-SHA-256;SCRAM-SHA-512
+	// +kubebuilder:default=SCRAM-SHA-256
+	Mechanism UserMechanism `json:"mechanism,omitempty"`
+
+	// PasswordSecretRef selects the key of a Secret in the namespace of the
+	// User that holds the password. When unset, a password is generated and
+	// written to the Secret named by SecretName.
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// SecretName is the name of the Secret the generated credentials are
+	// written to, with the username, password and mechanism keys. It defaults
+	// to the name of the User resource and is not used when PasswordSecretRef
+	// is set. Deleting the Secret generates a new password.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// UserStatus defines the observed state of User
+type UserStatus struct {
+	// ObservedGeneration is the last observed generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Username is the name of the managed SASL user.
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// Mechanism is the SCRAM mechanism of the current credentials.
+	// +optional
+	Mechanism UserMechanism `json:"mechanism,omitempty"`
+
+	// Created is true when the SASL user was created for this User, which
+	// then deletes it with the User. Users that already existed are never
+	// updated or deleted.
+	// +optional
+	Created bool `json:"created,omitempty"`
+
+	// SecretResourceVersion is the resource version of the Secret the current
+	// password was read from. A different version updates the credentials.
+	// +optional
+	SecretResourceVersion string `json:"secretResourceVersion,omitempty"`
+
+	// Conditions holds the conditions for the User.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Username",type="string",JSONPath=".status.username"
+//+kubebuilder:printcolumn:name="Mechanism",type="string",JSONPath=".status.mechanism"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message"
+
+// User is the Schema for the users API
+type User struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UserSpec   `json:"spec,omitempty"`
+	Status UserStatus `json:"status,omitempty"`
+}
+
+// GetUsername returns the SASL username
+func (u *User) GetUsername() string {
+	if u.Status.Username != "" {
+		return u.Status.Username
+	}
+	if u.Spec.Username != "" {
+		return u.Spec.Username
+	}
+	return u.GetName()
+}
+
+// GetMechanism returns the SCRAM mechanism, defaulting to SCRAM-SHA-256
+func (u *User) GetMechanism() UserMechanism {
+	if u.Spec.Mechanism == "" {
+		return UserMechanismScramSha256
+	}
+	return u.Spec.Mechanism
+}
+
+// GetSecretName returns the name of the Secret with the generated credentials
+func (u *User) GetSecretName() string {
+	if u.Spec.SecretName != "" {
+		return u.Spec.SecretName
+	}
+	return u.GetName()
+}
+
+// GetClusterRef returns the NamespacedName of referenced Cluster object
+func (u *User) GetClusterRef() types.NamespacedName {
+	return types.NamespacedName{Name: u.Spec.ClusterRef.Name, Namespace: u.Spec.ClusterRef.Namespace}
+}
+
+// GetCluster returns the referenced Cluster object
+func (u *User) GetCluster(
+	ctx context.Context, cl client.Client,
+) (*Cluster, error) {
+	if u.Spec.ClusterRef.Namespace != u.Namespace {
+		return nil, ErrClusterRefNamespace
+	}
+	cluster := &Cluster{}
+	if err := cl.Get(ctx, u.GetClusterRef(), cluster); err != nil {
+		return nil, err
+	}
+	if cc := cluster.Status.GetCondition(ClusterConfiguredConditionType); cc == nil || cc.Status != corev1.ConditionTrue {
+		return cluster, ErrClusterNotConfigured
+	}
+	return cluster, nil
+}
+
+// GetCondition returns the status condition of the given type, if any
+func (u *User) GetCondition(conditionType string) *metav1.Condition {
+	return apimeta.FindStatusCondition(u.Status.Conditions, conditionType)
+}
+
+// SetCondition sets a status condition of the User, tracking the generation
+// it was observed at
+func (u *User) SetCondition(
+	conditionType string, status metav1.ConditionStatus, reason, message string,
+) {
+	apimeta.SetStatusCondition(&u.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: u.GetGeneration(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+//+kubebuilder:object:root=true
+
+// UserList contains a list of User
+type UserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []User `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&User{}, &UserList{})
+}
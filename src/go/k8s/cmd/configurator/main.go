@@ -47,6 +47,7 @@ const (
 	nodeNameEnvVar                                       = "NODE_NAME"
 	proxyHostPortEnvVar                                  = "PROXY_HOST_PORT"
 	rackAwarenessEnvVar                                  = "RACK_AWARENESS"
+	rackNodeLabelEnvVar                                  = "RACK_NODE_LABEL"
 	validateMountedVolumeEnvVar                          = "VALIDATE_MOUNTED_VOLUME"
 	redpandaRPCPortEnvVar                                = "REDPANDA_RPC_PORT"
 	svcFQDNEnvVar                                        = "SERVICE_FQDN"
@@ -67,6 +68,7 @@ type configuratorConfig struct {
 	nodeName                                       string
 	proxyHostPort                                  int
 	rackAwareness                                  bool
+	rackNodeLabel                                  string
 	validateMountedVolume                          bool
 	redpandaRPCPort                                int
 	subdomain                                      string
@@ -87,6 +89,7 @@ func (c *configuratorConfig) String() string {
 		"hostPort: %d\n"+
 		"proxyHostPort: %d\n"+
 		"rackAwareness: %t\n"+
+		"rackNodeLabel: %s\n"+
 		"validateMountedVolume: %t\n",
 		c.hostName,
 		c.svcFQDN,
@@ -100,6 +103,7 @@ func (c *configuratorConfig) String() string {
 		c.hostPort,
 		c.proxyHostPort,
 		c.rackAwareness,
+		c.rackNodeLabel,
 		c.validateMountedVolume)
 }
 
@@ -173,7 +177,7 @@ func main() {
 	}
 
 	if c.rackAwareness {
-		zone, zoneID, errZone := getZoneLabels(c.nodeName)
+		zone, zoneID, errZone := getZoneLabels(c.nodeName, c.rackNodeLabel)
 		if errZone != nil {
 			log.Fatalf("%s", fmt.Errorf("unable to retrieve zone labels: %w", errZone))
 		}
@@ -251,16 +255,24 @@ func validateMountedVolume(cfg *config.Config, validate bool) error {
 
 var errInternalPortMissing = errors.New("port configuration is missing internal port")
 
-func getZoneLabels(nodeName string) (zone, zoneID string, err error) {
+func getZoneLabels(nodeName, rackNodeLabel string) (zone, zoneID string, err error) {
 	node, err := getNode(nodeName)
 	if err != nil {
 		return "", "", fmt.Errorf("unable to retrieve node: %w", err)
 	}
-	zone = node.Labels["topology.kubernetes.io/zone"]
-	zoneID = node.Labels["topology.cloud.redpanda.com/zone-id"]
+	zone, zoneID = zoneLabels(node.Labels, rackNodeLabel)
 	return zone, zoneID, nil
 }
 
+// zoneLabels returns the zone and zone id of the node. When the rack is
+// defined by a custom node label, its value is returned as the zone.
+func zoneLabels(nodeLabels map[string]string, rackNodeLabel string) (zone, zoneID string) {
+	if rackNodeLabel != "" {
+		return nodeLabels[rackNodeLabel], ""
+	}
+	return nodeLabels["topology.kubernetes.io/zone"], nodeLabels["topology.cloud.redpanda.com/zone-id"]
+}
+
 func populateRack(cfg *config.Config, zone, zoneID string) {
 	cfg.Redpanda.Rack = zoneID
 	if zoneID == "" {
@@ -504,6 +516,9 @@ func checkEnvVars() (configuratorConfig, error) {
 		result = errors.Join(result, fmt.Errorf("unable to parse bool: %w", err))
 	}
 
+	// Providing the rack node label is optional.
+	c.rackNodeLabel = os.Getenv(rackNodeLabelEnvVar)
+
 	// Providing the address type is optional.
 	addressType, exists := os.LookupEnv(externalConnectivityAddressTypeEnvVar)
 	if exists {
@@ -33,3 +33,26 @@ func TestPopulateRack(t *testing.T) {
 		assert.Equal(t, tt.ExpectedRack, cfg.Redpanda.Rack)
 	}
 }
+
+func TestZoneLabels(t *testing.T) {
+	nodeLabels := map[string]string{
+		"topology.kubernetes.io/zone":         "zone",
+		"topology.cloud.redpanda.com/zone-id": "zoneid",
+		"example.com/rack":                    "rack",
+	}
+	tests := []struct {
+		RackNodeLabel  string
+		ExpectedZone   string
+		ExpectedZoneID string
+	}{
+		{RackNodeLabel: "", ExpectedZone: "zone", ExpectedZoneID: "zoneid"},
+		{RackNodeLabel: "example.com/rack", ExpectedZone: "rack", ExpectedZoneID: ""},
+		{RackNodeLabel: "topology.kubernetes.io/zone", ExpectedZone: "zone", ExpectedZoneID: ""},
+		{RackNodeLabel: "example.com/missing", ExpectedZone: "", ExpectedZoneID: ""},
+	}
+	for _, tt := range tests {
+		zone, zoneID := zoneLabels(nodeLabels, tt.RackNodeLabel)
+		assert.Equal(t, tt.ExpectedZone, zone)
+		assert.Equal(t, tt.ExpectedZoneID, zoneID)
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/acl"
+	consolepkg "github.com/redpanda-data/redpanda/src/go/k8s/pkg/console"
+)
+
+const (
+	defaultACLResyncPeriod = 1 * time.Minute
+
+	// ACLDriftCorrectedEvent is an event for an ACL whose Kafka ACLs were
+	// deleted outside of the operator and have been created again
+	ACLDriftCorrectedEvent = "ACLDriftCorrected"
+)
+
+// ACLReconciler reconciles an ACL object
+type ACLReconciler struct {
+	client.Client
+	Scheme                  *runtime.Scheme
+	Log                     logr.Logger
+	Store                   *consolepkg.Store
+	EventRecorder           record.EventRecorder
+	KafkaAdminClientFactory acl.ClientFactory
+	// ResyncPeriod is how often ACLs are checked for drift
+	ResyncPeriod *time.Duration
+}
+
+//+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=acls,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=acls/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=acls/finalizers,verbs=update
+
+// Reconcile handles ACL reconcile requests
+func (r *ACLReconciler) Reconcile(
+	ctx context.Context, req ctrl.Request,
+) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx).WithName("ACLReconciler.Reconcile")
+
+	// Kafka admin clients are closed when their context is done, and the
+	// reconcile context is never cancelled
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	a := &vectorizedv1alpha1.ACL{}
+	if err := r.Get(ctx, req.NamespacedName, a); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	deleting := !a.GetDeletionTimestamp().IsZero()
+
+	cluster, err := a.GetCluster(ctx, r.Client)
+	switch {
+	case err == nil:
+	case errors.Is(err, vectorizedv1alpha1.ErrClusterRefNamespace):
+		// ACLs are never reconciled against a Cluster in another namespace
+		if deleting {
+			controllerutil.RemoveFinalizer(a, acl.ACLFinalizer)
+			return ctrl.Result{}, r.Update(ctx, a)
+		}
+		return ctrl.Result{}, r.setNotReady(ctx, a, "ClusterRefNamespace", fmt.Sprintf("Cluster %s is not in namespace %s", a.GetClusterRef(), a.Namespace))
+	case apierrors.IsNotFound(err) || (cluster != nil && !cluster.GetDeletionTimestamp().IsZero()):
+		// Without the cluster there are no ACLs left to delete
+		if deleting {
+			controllerutil.RemoveFinalizer(a, acl.ACLFinalizer)
+			return ctrl.Result{}, r.Update(ctx, a)
+		}
+		r.EventRecorder.Eventf(
+			a,
+			corev1.EventTypeWarning, ClusterNotFoundEvent,
+			"Unable to reconcile ACL as the referenced Cluster %s is not found or is being deleted", a.GetClusterRef(),
+		)
+		return ctrl.Result{}, r.setNotReady(ctx, a, "ClusterNotFound", fmt.Sprintf("Cluster %s not found", a.GetClusterRef()))
+	case errors.Is(err, vectorizedv1alpha1.ErrClusterNotConfigured):
+		// When the Cluster is configured, the ACL will receive a notification trigger
+		return ctrl.Result{}, r.setNotReady(ctx, a, "ClusterNotConfigured", fmt.Sprintf("Cluster %s is not yet configured", a.GetClusterRef()))
+	default:
+		return ctrl.Result{}, err
+	}
+
+	applied, err := acl.Entries(a.Status.Principal, a.Status.AppliedRules)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("reading applied rules: %w", err)
+	}
+	if deleting {
+		return ctrl.Result{}, r.delete(ctx, a, cluster, applied, log)
+	}
+
+	want, err := acl.Entries(a.Spec.Principal, a.Spec.Rules)
+	if err != nil {
+		// Retrying does not help until the spec is changed
+		return ctrl.Result{}, r.setNotReady(ctx, a, "InvalidRules", err.Error())
+	}
+
+	if !controllerutil.ContainsFinalizer(a, acl.ACLFinalizer) {
+		controllerutil.AddFinalizer(a, acl.ACLFinalizer)
+		if err := r.Update(ctx, a); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	cl, err := r.kafkaClient(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	res, err := acl.Reconcile(ctx, cl, want, applied)
+	if err != nil {
+		if statusErr := r.setNotReady(ctx, a, "ReconcileFailed", err.Error()); statusErr != nil {
+			log.Error(statusErr, "unable to update ACL status")
+		}
+		return ctrl.Result{}, err
+	}
+	if len(res.Restored) > 0 {
+		r.EventRecorder.Eventf(a, corev1.EventTypeNormal, ACLDriftCorrectedEvent,
+			"ACLs were deleted outside of the operator and have been created again: %s", strings.Join(res.Restored, "; "))
+	}
+	if len(res.Created) > 0 || len(res.Restored) > 0 || len(res.Deleted) > 0 {
+		log.Info("reconciled ACLs", "principal", a.Spec.Principal, "created", res.Created, "restored", res.Restored, "deleted", res.Deleted)
+	}
+
+	a.Status.ObservedGeneration = a.GetGeneration()
+	a.Status.Principal = a.Spec.Principal
+	a.Status.AppliedRules = a.Spec.Rules
+	a.SetCondition(vectorizedv1alpha1.ACLReadyCondition, metav1.ConditionTrue, "Reconciled", fmt.Sprintf("%d ACLs reconciled", len(want)))
+	return ctrl.Result{RequeueAfter: r.getResyncPeriod()}, r.Status().Update(ctx, a)
+}
+
+func (r *ACLReconciler) delete(
+	ctx context.Context, a *vectorizedv1alpha1.ACL, cluster *vectorizedv1alpha1.Cluster, applied []acl.Entry, log logr.Logger,
+) error {
+	if !controllerutil.ContainsFinalizer(a, acl.ACLFinalizer) {
+		return nil
+	}
+	if exp, err := vectorizedv1alpha1.FinalizersExpired(a); err != nil {
+		log.Error(err, "invalid configuration for finalizers timeout")
+	} else if exp {
+		log.Info("finalizers timed out for acl: removing finalizer")
+		controllerutil.RemoveFinalizer(a, acl.ACLFinalizer)
+		return r.Update(ctx, a)
+	}
+
+	if len(applied) > 0 {
+		cl, err := r.kafkaClient(ctx, cluster)
+		if err != nil {
+			return err
+		}
+		if err := acl.Delete(ctx, cl, applied); err != nil {
+			return err
+		}
+		log.Info("deleted ACLs", "principal", a.Status.Principal)
+	}
+	controllerutil.RemoveFinalizer(a, acl.ACLFinalizer)
+	return r.Update(ctx, a)
+}
+
+func (r *ACLReconciler) kafkaClient(
+	ctx context.Context, cluster *vectorizedv1alpha1.Cluster,
+) (acl.Client, error) {
+	if err := r.Store.SyncKafka(ctx, cluster); err != nil {
+		return nil, fmt.Errorf("sync kafka certificates: %w", err)
+	}
+	cl, err := r.KafkaAdminClientFactory(ctx, r.Client, cluster, r.Store)
+	if err != nil {
+		return nil, fmt.Errorf("creating kafka admin client: %w", err)
+	}
+	return cl, nil
+}
+
+func (r *ACLReconciler) setNotReady(
+	ctx context.Context, a *vectorizedv1alpha1.ACL, reason, message string,
+) error {
+	if !a.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+	a.SetCondition(vectorizedv1alpha1.ACLReadyCondition, metav1.ConditionFalse, reason, message)
+	return r.Status().Update(ctx, a)
+}
+
+func (r *ACLReconciler) getResyncPeriod() time.Duration {
+	if r.ResyncPeriod != nil {
+		return *r.ResyncPeriod
+	}
+	return defaultACLResyncPeriod
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ACLReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&vectorizedv1alpha1.ACL{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&source.Kind{Type: &vectorizedv1alpha1.Cluster{}},
+			handler.EnqueueRequestsFromMapFunc(r.reconcileACLsForCluster),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Complete(r)
+}
+
+func (r *ACLReconciler) reconcileACLsForCluster(c client.Object) []reconcile.Request {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var acls vectorizedv1alpha1.ACLList
+	if err := r.Client.List(ctx, &acls); err != nil {
+		r.Log.Error(err, "unexpected: could not list acls for propagating reconcile events")
+		return nil
+	}
+
+	var res []reconcile.Request
+	for i := range acls.Items {
+		a := &acls.Items[i]
+		if a.GetClusterRef() == client.ObjectKeyFromObject(c) {
+			res = append(res, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: a.Namespace, Name: a.Name},
+			})
+		}
+	}
+	return res
+}
@@ -0,0 +1,124 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/acl"
+)
+
+var _ = Describe("ACL controller", func() {
+	const (
+		ClusterName = "acl-cluster"
+
+		timeout  = time.Second * 30
+		interval = time.Millisecond * 100
+	)
+
+	var (
+		key             types.NamespacedName
+		redpandaCluster *vectorizedv1alpha1.Cluster
+		namespace       *corev1.Namespace
+	)
+
+	BeforeEach(func() {
+		ctx := context.Background()
+		if redpandaCluster == nil {
+			key, _, redpandaCluster, namespace = getInitialTestCluster(ClusterName)
+		}
+		if err := k8sClient.Get(ctx, key, &vectorizedv1alpha1.Cluster{}); err != nil {
+			if !apierrors.IsNotFound(err) {
+				Expect(err).To(Equal(nil))
+			}
+			Expect(k8sClient.Create(ctx, namespace)).Should(Succeed())
+			Expect(k8sClient.Create(ctx, redpandaCluster)).Should(Succeed())
+			Eventually(clusterConfiguredConditionStatusGetter(key), timeout, interval).Should(BeTrue())
+		}
+	})
+
+	newACL := func(name, ns string) *vectorizedv1alpha1.ACL {
+		return &vectorizedv1alpha1.ACL{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+			},
+			Spec: vectorizedv1alpha1.ACLSpec{
+				ClusterRef: vectorizedv1alpha1.NamespaceNameRef{Namespace: key.Namespace, Name: key.Name},
+				Principal:  "User:" + name,
+				Rules: []vectorizedv1alpha1.ACLRule{{
+					ResourceType: vectorizedv1alpha1.ACLResourceTypeTopic,
+					ResourceName: "orders",
+					Operations:   []vectorizedv1alpha1.ACLOperation{"Read", "Describe"},
+				}},
+			},
+		}
+	}
+
+	aclReadyCondition := func(ak types.NamespacedName) func() *metav1.Condition {
+		return func() *metav1.Condition {
+			var a vectorizedv1alpha1.ACL
+			if err := k8sClient.Get(context.Background(), ak, &a); err != nil {
+				return nil
+			}
+			return apimeta.FindStatusCondition(a.Status.Conditions, vectorizedv1alpha1.ACLReadyCondition)
+		}
+	}
+
+	Context("When creating an ACL", func() {
+		ctx := context.Background()
+		It("Should create the Kafka ACLs and delete them with the ACL", func() {
+			a := newACL("orders-app", key.Namespace)
+			ak := client.ObjectKeyFromObject(a)
+			Expect(k8sClient.Create(ctx, a)).Should(Succeed())
+
+			By("Creating the Kafka ACLs")
+			want, err := acl.Entries(a.Spec.Principal, a.Spec.Rules)
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(aclReadyCondition(ak), timeout, interval).Should(HaveField("Status", metav1.ConditionTrue))
+			Expect(testACLClient.ACLs()).To(HaveLen(len(want)))
+			for _, e := range want {
+				Expect(testACLClient.ACLs()).To(HaveKey(e))
+			}
+
+			By("Deleting the Kafka ACLs with the ACL")
+			Expect(k8sClient.Delete(ctx, a)).Should(Succeed())
+			Eventually(func() bool {
+				return apierrors.IsNotFound(k8sClient.Get(ctx, ak, &vectorizedv1alpha1.ACL{}))
+			}, timeout, interval).Should(BeTrue())
+			Expect(testACLClient.ACLs()).To(BeEmpty())
+		})
+
+		It("Should refuse a Cluster in another namespace", func() {
+			other := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: key.Namespace + "-acls"}}
+			Expect(k8sClient.Create(ctx, other)).Should(Succeed())
+			a := newACL("foreign-app", other.Name)
+			ak := client.ObjectKeyFromObject(a)
+			Expect(k8sClient.Create(ctx, a)).Should(Succeed())
+
+			Eventually(aclReadyCondition(ak), timeout, interval).Should(And(
+				HaveField("Status", metav1.ConditionFalse),
+				HaveField("Reason", "ClusterRefNamespace"),
+			))
+			Expect(testACLClient.ACLs()).To(BeEmpty())
+		})
+	})
+})
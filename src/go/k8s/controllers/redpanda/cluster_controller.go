@@ -19,6 +19,7 @@ import (
 	"strings"
 	"time"
 
+	cmapiv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	"github.com/fluxcd/pkg/runtime/logger"
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
@@ -81,7 +82,7 @@ type ClusterReconciler struct {
 //+kubebuilder:rbac:groups=cert-manager.io,resources=clusterissuers,verbs=create;get;list;watch;patch;delete;update;
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
-//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;delete;
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;update;patch;delete;
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;update;delete
 //+kubebuilder:rbac:groups=core,resources=pods/finalizers,verbs=update
 //+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;
@@ -93,6 +94,7 @@ type ClusterReconciler struct {
 //+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=clusters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=clusters/finalizers,verbs=update
 //+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=clusters/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -275,6 +277,10 @@ func (r *ClusterReconciler) Reconcile(
 		return ctrl.Result{}, err
 	}
 
+	if vectorizedCluster.Spec.Autoscaling != nil || vectorizedCluster.Spec.Storage.AutoExpansion != nil {
+		// The broker metrics are evaluated periodically
+		return ctrl.Result{RequeueAfter: resources.AutoscalingInterval}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -298,6 +304,11 @@ func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			handler.EnqueueRequestsFromMapFunc(r.reconcileClusterForExternalCASecret),
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
 		).
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.reconcileClusterForCertificateSecret),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
 		Complete(r)
 }
 
@@ -697,6 +708,75 @@ func (r *ClusterReconciler) reconcileClusterForExternalCASecret(s client.Object)
 	}}
 }
 
+// reconcileClusterForCertificateSecret reconciles the clusters whose brokers
+// mount the certificates of the Secret, so renewed certificates are picked up.
+// The Secrets are either issued by cert-manager for the Certificates of a
+// cluster, or referenced by the TLS configuration of its listeners.
+func (r *ClusterReconciler) reconcileClusterForCertificateSecret(s client.Object) []reconcile.Request {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if certName, found := s.GetAnnotations()[cmapiv1.CertificateNameKey]; found {
+		var cert cmapiv1.Certificate
+		err := r.Get(ctx, types.NamespacedName{Namespace: s.GetNamespace(), Name: certName}, &cert)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				r.Log.Error(err, "unexpected: could not get certificate for propagating reconcile events")
+			}
+			return nil
+		}
+		if clusterName, found := cert.GetLabels()[labels.InstanceKey]; found {
+			return []reconcile.Request{{
+				NamespacedName: types.NamespacedName{Namespace: s.GetNamespace(), Name: clusterName},
+			}}
+		}
+	}
+
+	var clusters vectorizedv1alpha1.ClusterList
+	if err := r.List(ctx, &clusters); err != nil {
+		r.Log.Error(err, "unexpected: could not list clusters for propagating reconcile events")
+		return nil
+	}
+	var res []reconcile.Request
+	for i := range clusters.Items {
+		if clusterReferencesSecret(&clusters.Items[i], client.ObjectKeyFromObject(s)) {
+			res = append(res, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&clusters.Items[i]),
+			})
+		}
+	}
+	return res
+}
+
+// clusterReferencesSecret tells if the TLS configuration of a listener of the
+// cluster references the Secret
+func clusterReferencesSecret(
+	cluster *vectorizedv1alpha1.Cluster, secret types.NamespacedName,
+) bool {
+	var tlsConfigs []*vectorizedv1alpha1.TLSConfig
+	for _, l := range cluster.Spec.Configuration.KafkaAPI {
+		tlsConfigs = append(tlsConfigs, l.GetTLS())
+	}
+	for _, l := range cluster.Spec.Configuration.PandaproxyAPI {
+		tlsConfigs = append(tlsConfigs, l.GetTLS())
+	}
+	if sr := cluster.Spec.Configuration.SchemaRegistry; sr != nil {
+		tlsConfigs = append(tlsConfigs, sr.GetTLS())
+	}
+	for _, tls := range tlsConfigs {
+		if tls == nil {
+			continue
+		}
+		if ref := tls.NodeSecretRef; ref != nil && ref.Name == secret.Name && ref.Namespace == secret.Namespace {
+			return true
+		}
+		if ref := tls.ClientCACertRef; ref != nil && ref.Name == secret.Name && cluster.Namespace == secret.Namespace {
+			return true
+		}
+	}
+	return false
+}
+
 // WithConfiguratorSettings set the configurator image settings
 func (r *ClusterReconciler) WithConfiguratorSettings(
 	configuratorSettings resources.ConfiguratorSettings,
@@ -20,6 +20,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -35,6 +36,13 @@ import (
 
 const (
 	defaultDriftCheckPeriod = 1 * time.Minute
+
+	// ConfigurationDriftCorrectedEvent is an event for a Cluster whose
+	// configuration was changed outside of the operator and is being reset
+	ConfigurationDriftCorrectedEvent = "ConfigurationDriftCorrected"
+	// ConfigurationDriftDetectedEvent is a warning event for a Cluster in
+	// report only mode whose configuration was changed outside of the operator
+	ConfigurationDriftDetectedEvent = "ConfigurationDriftDetected"
 )
 
 // ClusterConfigurationDriftReconciler detects drifts in the cluster configuration and triggers a reconciliation.
@@ -43,6 +51,7 @@ type ClusterConfigurationDriftReconciler struct {
 	Log                       logr.Logger
 	clusterDomain             string
 	Scheme                    *runtime.Scheme
+	EventRecorder             record.EventRecorder
 	DriftCheckPeriod          *time.Duration
 	AdminAPIClientFactory     adminutils.AdminAPIClientFactory
 	RestrictToRedpandaVersion string
@@ -87,6 +96,7 @@ func (r *ClusterConfigurationDriftReconciler) Reconcile(
 		// configuration drift already signaled
 		return ctrl.Result{RequeueAfter: r.getDriftCheckPeriod()}, nil
 	}
+	reportOnly := redpandaCluster.GetConfigurationDriftMode() == vectorizedv1alpha1.ConfigurationDriftModeReport
 
 	// wait at least a driftCheckPeriod before checking drifts
 	now := time.Now()
@@ -153,10 +163,49 @@ func (r *ClusterConfigurationDriftReconciler) Reconcile(
 	patch := configuration.ThreeWayMerge(log, lastAppliedConfig, clusterConfig, lastAppliedConfig, nil, schema)
 	if patch.Empty() {
 		// Nothing to do, everything in sync
+		if redpandaCluster.Status.SetCondition(
+			vectorizedv1alpha1.ClusterConfigurationDriftConditionType,
+			corev1.ConditionFalse,
+			vectorizedv1alpha1.ClusterConfigurationDriftReasonInSync,
+			"No configuration drift detected by periodic check",
+		) {
+			if err := r.Status().Update(ctx, &redpandaCluster); err != nil {
+				return ctrl.Result{}, fmt.Errorf("could not update cluster status: %w", err)
+			}
+		}
+		return ctrl.Result{RequeueAfter: r.getDriftCheckPeriod()}, nil
+	}
+
+	drift := configuration.Drift(patch, clusterConfig, schema)
+	summary := configuration.DriftSummary(drift)
+	log.Info("Detected configuration drift in the cluster", "drift", summary, "reportOnly", reportOnly)
+
+	if reportOnly {
+		// Only report drift when it changes, the condition keeps it visible
+		if !redpandaCluster.Status.SetCondition(
+			vectorizedv1alpha1.ClusterConfigurationDriftConditionType,
+			corev1.ConditionTrue,
+			vectorizedv1alpha1.ClusterConfigurationDriftReasonReported,
+			summary,
+		) {
+			return ctrl.Result{RequeueAfter: r.getDriftCheckPeriod()}, nil
+		}
+		recordConfigurationDrift(&redpandaCluster, drift, vectorizedv1alpha1.ClusterConfigurationDriftReasonReported)
+		r.EventRecorder.Event(&redpandaCluster, corev1.EventTypeWarning, ConfigurationDriftDetectedEvent, summary)
+		if err := r.Status().Update(ctx, &redpandaCluster); err != nil {
+			return ctrl.Result{}, fmt.Errorf("could not update cluster status to report a configuration drift: %w", err)
+		}
 		return ctrl.Result{RequeueAfter: r.getDriftCheckPeriod()}, nil
 	}
 
-	log.Info("Detected configuration drift in the cluster")
+	recordConfigurationDrift(&redpandaCluster, drift, vectorizedv1alpha1.ClusterConfigurationDriftReasonCorrected)
+	r.EventRecorder.Eventf(&redpandaCluster, corev1.EventTypeNormal, ConfigurationDriftCorrectedEvent, "Resetting configuration: %s", summary)
+	redpandaCluster.Status.SetCondition(
+		vectorizedv1alpha1.ClusterConfigurationDriftConditionType,
+		corev1.ConditionTrue,
+		vectorizedv1alpha1.ClusterConfigurationDriftReasonCorrected,
+		summary,
+	)
 
 	// Signal drift by setting the condition to False
 	redpandaCluster.Status.SetCondition(
@@ -190,6 +239,17 @@ func (r *ClusterConfigurationDriftReconciler) WithClusterDomain(
 	return r
 }
 
+// recordConfigurationDrift counts the drifted properties of the cluster
+func recordConfigurationDrift(
+	redpandaCluster *vectorizedv1alpha1.Cluster,
+	drift []configuration.PropertyDrift,
+	action string,
+) {
+	for _, d := range drift {
+		configurationDrifts.WithLabelValues(redpandaCluster.Name, d.Name, action).Inc()
+	}
+}
+
 func (r *ClusterConfigurationDriftReconciler) getDriftCheckPeriod() time.Duration {
 	if r.DriftCheckPeriod != nil {
 		return *r.DriftCheckPeriod
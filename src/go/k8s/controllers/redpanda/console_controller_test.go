@@ -96,7 +96,7 @@ var _ = Describe("Console controller", func() {
 				},
 				Spec: vectorizedv1alpha1.ConsoleSpec{
 					ClusterRef:     vectorizedv1alpha1.NamespaceNameRef{Namespace: key.Namespace, Name: key.Name},
-					SchemaRegistry: vectorizedv1alpha1.Schema{Enabled: enableSchemaRegistry},
+					SchemaRegistry: vectorizedv1alpha1.ConsoleSchemaRegistry{Enabled: enableSchemaRegistry},
 					Deployment:     vectorizedv1alpha1.Deployment{Image: deploymentImage},
 					Connect:        vectorizedv1alpha1.Connect{Enabled: enableConnect},
 				},
@@ -221,7 +221,7 @@ var _ = Describe("Console controller", func() {
 				},
 				Spec: vectorizedv1alpha1.ConsoleSpec{
 					ClusterRef:     vectorizedv1alpha1.NamespaceNameRef{Namespace: brokenKey.Namespace, Name: brokenKey.Name},
-					SchemaRegistry: vectorizedv1alpha1.Schema{Enabled: enableSchemaRegistry},
+					SchemaRegistry: vectorizedv1alpha1.ConsoleSchemaRegistry{Enabled: enableSchemaRegistry},
 					Deployment:     vectorizedv1alpha1.Deployment{Image: deploymentImage},
 					Connect:        vectorizedv1alpha1.Connect{Enabled: enableConnect},
 				},
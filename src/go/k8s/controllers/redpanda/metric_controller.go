@@ -47,11 +47,17 @@ var (
 			Help: "Number of Redpanda clusters having configuration problems",
 		}, []string{"reason"},
 	)
+	configurationDrifts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "redpanda_configuration_drift_total",
+			Help: "Number of cluster configuration properties found changed outside of the operator",
+		}, []string{"cluster", "property", "action"},
+	)
 )
 
 func init() {
 	// Register custom metrics with the global prometheus registry
-	metrics.Registry.MustRegister(redpandaClusters, desireRedpandaNodes, actualRedpandaNodes, misconfiguredClusters)
+	metrics.Registry.MustRegister(redpandaClusters, desireRedpandaNodes, actualRedpandaNodes, misconfiguredClusters, configurationDrifts)
 }
 
 // ClusterMetricController provides metrics for nodes and cluster
@@ -89,7 +95,7 @@ func (r *ClusterMetricController) Reconcile(
 		if err != nil {
 			return ctrl.Result{}, err
 		}
-		g.Set(float64(*cl.Items[i].Spec.Replicas))
+		g.Set(float64(cl.Items[i].GetDesiredReplicas()))
 
 		g, err = actualRedpandaNodes.GetMetricWithLabelValues(cl.Items[i].Name)
 		if err != nil {
@@ -0,0 +1,241 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	consolepkg "github.com/redpanda-data/redpanda/src/go/k8s/pkg/console"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/schema"
+)
+
+const (
+	defaultSchemaResyncPeriod = 1 * time.Minute
+
+	// SchemaIncompatibleEvent is a warning event for a Schema that is not
+	// compatible with the latest version of its subject
+	SchemaIncompatibleEvent = "SchemaIncompatible"
+)
+
+// SchemaReconciler reconciles a Schema object
+type SchemaReconciler struct {
+	client.Client
+	Scheme          *runtime.Scheme
+	Log             logr.Logger
+	Store           *consolepkg.Store
+	EventRecorder   record.EventRecorder
+	RegistryFactory schema.RegistryFactory
+	// ResyncPeriod is how often schemas are checked against the registry
+	ResyncPeriod *time.Duration
+}
+
+//+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=schemas,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=schemas/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+
+// Reconcile handles Schema reconcile requests
+func (r *SchemaReconciler) Reconcile(
+	ctx context.Context, req ctrl.Request,
+) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx).WithName("SchemaReconciler.Reconcile")
+
+	s := &vectorizedv1alpha1.Schema{}
+	if err := r.Get(ctx, req.NamespacedName, s); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	// Deleting a Schema does not delete the subject
+	if !s.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	cluster, err := s.GetCluster(ctx, r.Client)
+	switch {
+	case err == nil:
+	case errors.Is(err, vectorizedv1alpha1.ErrClusterRefNamespace):
+		// Schemas are never registered on a Cluster in another namespace
+		return ctrl.Result{}, r.setNotReady(ctx, s, "ClusterRefNamespace", fmt.Sprintf("Cluster %s is not in namespace %s", s.GetClusterRef(), s.Namespace))
+	case apierrors.IsNotFound(err) || (cluster != nil && !cluster.GetDeletionTimestamp().IsZero()):
+		r.EventRecorder.Eventf(
+			s,
+			corev1.EventTypeWarning, ClusterNotFoundEvent,
+			"Unable to reconcile Schema as the referenced Cluster %s is not found or is being deleted", s.GetClusterRef(),
+		)
+		return ctrl.Result{}, r.setNotReady(ctx, s, "ClusterNotFound", fmt.Sprintf("Cluster %s not found", s.GetClusterRef()))
+	case errors.Is(err, vectorizedv1alpha1.ErrClusterNotConfigured):
+		// When the Cluster is configured, the Schema will receive a notification trigger
+		return ctrl.Result{}, r.setNotReady(ctx, s, "ClusterNotConfigured", fmt.Sprintf("Cluster %s is not yet configured", s.GetClusterRef()))
+	default:
+		return ctrl.Result{}, err
+	}
+	if cluster.Spec.Configuration.SchemaRegistry == nil {
+		return ctrl.Result{}, r.setNotReady(ctx, s, "SchemaRegistryNotEnabled", fmt.Sprintf("Cluster %s does not enable the Schema Registry", s.GetClusterRef()))
+	}
+
+	definition, err := r.definition(ctx, s)
+	var notReady *schemaNotReadyError
+	if errors.As(err, &notReady) {
+		result := ctrl.Result{}
+		if notReady.retry {
+			// The ConfigMap is not watched, so check it again later
+			result.RequeueAfter = r.getResyncPeriod()
+		}
+		return result, r.setNotReady(ctx, s, notReady.reason, notReady.Error())
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.Store.SyncSchemaRegistry(ctx, cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("sync schema registry certificates: %w", err)
+	}
+	reg, err := r.RegistryFactory(ctx, r.Client, cluster, r.Store)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("creating schema registry client: %w", err)
+	}
+	subject := s.GetSubject()
+	res, err := schema.Reconcile(ctx, reg, subject, schema.New(&s.Spec, definition), s.Spec.Compatibility)
+	if err != nil {
+		if statusErr := r.setNotReady(ctx, s, "ReconcileFailed", err.Error()); statusErr != nil {
+			log.Error(statusErr, "unable to update Schema status")
+		}
+		return ctrl.Result{}, err
+	}
+	if len(res.Changes) > 0 {
+		log.Info("reconciled schema", "subject", subject, "changes", res.Changes)
+	}
+
+	s.Status.ObservedGeneration = s.GetGeneration()
+	s.Status.Subject = subject
+	if res.Incompatible {
+		msg := fmt.Sprintf("Schema is not compatible with the latest version of subject %s", subject)
+		if !apimeta.IsStatusConditionFalse(s.Status.Conditions, vectorizedv1alpha1.SchemaCompatibleCondition) {
+			r.EventRecorder.Event(s, corev1.EventTypeWarning, SchemaIncompatibleEvent, msg)
+		}
+		s.SetCondition(vectorizedv1alpha1.SchemaCompatibleCondition, metav1.ConditionFalse, "Incompatible", msg)
+		s.SetCondition(vectorizedv1alpha1.SchemaReadyCondition, metav1.ConditionFalse, "Incompatible", msg)
+	} else {
+		if res.Registered != nil {
+			s.Status.ID = res.Registered.ID
+			s.Status.Version = res.Registered.Version
+		}
+		s.SetCondition(vectorizedv1alpha1.SchemaCompatibleCondition, metav1.ConditionTrue, "Compatible", "Schema is registered")
+		s.SetCondition(vectorizedv1alpha1.SchemaReadyCondition, metav1.ConditionTrue, "Reconciled", fmt.Sprintf("Schema registered as version %d", s.Status.Version))
+	}
+	// The compatibility level or the subject may change in the registry
+	return ctrl.Result{RequeueAfter: r.getResyncPeriod()}, r.Status().Update(ctx, s)
+}
+
+// schemaNotReadyError is returned when the definition of a Schema cannot be
+// read
+type schemaNotReadyError struct {
+	reason  string
+	message string
+	retry   bool
+}
+
+func (e *schemaNotReadyError) Error() string {
+	return e.message
+}
+
+// definition returns the schema definition from the spec or its ConfigMap
+func (r *SchemaReconciler) definition(
+	ctx context.Context, s *vectorizedv1alpha1.Schema,
+) (string, error) {
+	ref := s.Spec.SchemaConfigMapRef
+	if ref == nil {
+		if s.Spec.Schema == "" {
+			return "", &schemaNotReadyError{"SchemaNotSet", "Either schema or schemaConfigMapRef must be set", false}
+		}
+		return s.Spec.Schema, nil
+	}
+	var cm corev1.ConfigMap
+	if err := r.Get(ctx, types.NamespacedName{Namespace: s.Namespace, Name: ref.Name}, &cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", &schemaNotReadyError{"ConfigMapNotFound", fmt.Sprintf("ConfigMap %s not found", ref.Name), true}
+		}
+		return "", err
+	}
+	definition, ok := cm.Data[ref.Key]
+	if !ok || definition == "" {
+		return "", &schemaNotReadyError{"ConfigMapKeyNotFound", fmt.Sprintf("ConfigMap %s has no %s key", ref.Name, ref.Key), true}
+	}
+	return definition, nil
+}
+
+func (r *SchemaReconciler) setNotReady(
+	ctx context.Context, s *vectorizedv1alpha1.Schema, reason, message string,
+) error {
+	s.SetCondition(vectorizedv1alpha1.SchemaReadyCondition, metav1.ConditionFalse, reason, message)
+	return r.Status().Update(ctx, s)
+}
+
+func (r *SchemaReconciler) getResyncPeriod() time.Duration {
+	if r.ResyncPeriod != nil {
+		return *r.ResyncPeriod
+	}
+	return defaultSchemaResyncPeriod
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SchemaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&vectorizedv1alpha1.Schema{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&source.Kind{Type: &vectorizedv1alpha1.Cluster{}},
+			handler.EnqueueRequestsFromMapFunc(r.reconcileSchemasForCluster),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Complete(r)
+}
+
+func (r *SchemaReconciler) reconcileSchemasForCluster(c client.Object) []reconcile.Request {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var schemas vectorizedv1alpha1.SchemaList
+	if err := r.Client.List(ctx, &schemas); err != nil {
+		r.Log.Error(err, "unexpected: could not list schemas for propagating reconcile events")
+		return nil
+	}
+
+	var res []reconcile.Request
+	for i := range schemas.Items {
+		s := &schemas.Items[i]
+		if s.GetClusterRef() == client.ObjectKeyFromObject(c) {
+			res = append(res, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: s.Namespace, Name: s.Name},
+			})
+		}
+	}
+	return res
+}
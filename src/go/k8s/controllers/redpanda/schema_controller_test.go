@@ -0,0 +1,131 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+)
+
+var _ = Describe("Schema controller", func() {
+	const (
+		ClusterName = "schema-cluster"
+
+		timeout  = time.Second * 30
+		interval = time.Millisecond * 100
+	)
+
+	var (
+		key             types.NamespacedName
+		redpandaCluster *vectorizedv1alpha1.Cluster
+		namespace       *corev1.Namespace
+	)
+
+	BeforeEach(func() {
+		ctx := context.Background()
+		if redpandaCluster == nil {
+			key, _, redpandaCluster, namespace = getInitialTestCluster(ClusterName)
+			redpandaCluster.Spec.Configuration.SchemaRegistry = &vectorizedv1alpha1.SchemaRegistryAPI{Port: 8081}
+		}
+		if err := k8sClient.Get(ctx, key, &vectorizedv1alpha1.Cluster{}); err != nil {
+			if !apierrors.IsNotFound(err) {
+				Expect(err).To(Equal(nil))
+			}
+			Expect(k8sClient.Create(ctx, namespace)).Should(Succeed())
+			Expect(k8sClient.Create(ctx, redpandaCluster)).Should(Succeed())
+			Eventually(clusterConfiguredConditionStatusGetter(key), timeout, interval).Should(BeTrue())
+		}
+	})
+
+	schemaCondition := func(sk types.NamespacedName, conditionType string) func() *metav1.Condition {
+		return func() *metav1.Condition {
+			var s vectorizedv1alpha1.Schema
+			if err := k8sClient.Get(context.Background(), sk, &s); err != nil {
+				return nil
+			}
+			return apimeta.FindStatusCondition(s.Status.Conditions, conditionType)
+		}
+	}
+
+	Context("When creating a Schema", func() {
+		ctx := context.Background()
+		It("Should register new versions that are compatible", func() {
+			s := &vectorizedv1alpha1.Schema{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "orders-value",
+					Namespace: key.Namespace,
+				},
+				Spec: vectorizedv1alpha1.SchemaSpec{
+					ClusterRef:    vectorizedv1alpha1.NamespaceNameRef{Namespace: key.Namespace, Name: key.Name},
+					Schema:        `{"type":"string"}`,
+					Compatibility: "BACKWARD",
+				},
+			}
+			sk := client.ObjectKeyFromObject(s)
+			Expect(k8sClient.Create(ctx, s)).Should(Succeed())
+
+			By("Registering the schema and setting the compatibility level")
+			Eventually(schemaCondition(sk, vectorizedv1alpha1.SchemaReadyCondition), timeout, interval).Should(HaveField("Status", metav1.ConditionTrue))
+			Expect(testSchemaRegistry.Versions("orders-value")).To(Equal(1))
+			Expect(testSchemaRegistry.Compatibility(ctx, "orders-value")).To(Equal("BACKWARD"))
+			Expect(k8sClient.Get(ctx, sk, s)).Should(Succeed())
+			Expect(s.Status.Version).To(Equal(1))
+
+			By("Not registering an incompatible schema")
+			s.Spec.Schema = `"incompatible"`
+			Expect(k8sClient.Update(ctx, s)).Should(Succeed())
+			Eventually(schemaCondition(sk, vectorizedv1alpha1.SchemaCompatibleCondition), timeout, interval).Should(HaveField("Status", metav1.ConditionFalse))
+			Expect(testSchemaRegistry.Versions("orders-value")).To(Equal(1))
+
+			By("Registering a compatible schema as a new version")
+			Expect(k8sClient.Get(ctx, sk, s)).Should(Succeed())
+			s.Spec.Schema = `{"type":"bytes"}`
+			Expect(k8sClient.Update(ctx, s)).Should(Succeed())
+			Eventually(func() int {
+				return testSchemaRegistry.Versions("orders-value")
+			}, timeout, interval).Should(Equal(2))
+			Eventually(schemaCondition(sk, vectorizedv1alpha1.SchemaReadyCondition), timeout, interval).Should(HaveField("Status", metav1.ConditionTrue))
+		})
+
+		It("Should refuse a Cluster in another namespace", func() {
+			other := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: key.Namespace + "-schemas"}}
+			Expect(k8sClient.Create(ctx, other)).Should(Succeed())
+			s := &vectorizedv1alpha1.Schema{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foreign-value",
+					Namespace: other.Name,
+				},
+				Spec: vectorizedv1alpha1.SchemaSpec{
+					ClusterRef: vectorizedv1alpha1.NamespaceNameRef{Namespace: key.Namespace, Name: key.Name},
+					Schema:     `{"type":"string"}`,
+				},
+			}
+			sk := client.ObjectKeyFromObject(s)
+			Expect(k8sClient.Create(ctx, s)).Should(Succeed())
+
+			Eventually(schemaCondition(sk, vectorizedv1alpha1.SchemaReadyCondition), timeout, interval).Should(And(
+				HaveField("Status", metav1.ConditionFalse),
+				HaveField("Reason", "ClusterRefNamespace"),
+			))
+			Expect(testSchemaRegistry.Versions("foreign-value")).To(Equal(0))
+		})
+	})
+})
@@ -43,10 +43,13 @@ import (
 	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
 	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
 	redpandacontrollers "github.com/redpanda-data/redpanda/src/go/k8s/controllers/redpanda"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/acl"
 	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
 	consolepkg "github.com/redpanda-data/redpanda/src/go/k8s/pkg/console"
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/types"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/schema"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/topic"
 )
 
 // These tests use Ginkgo (BDD-style Go testing framework). Refer to
@@ -61,6 +64,9 @@ var (
 	testStore             *consolepkg.Store
 	testKafkaAdmin        *mockKafkaAdmin
 	testKafkaAdminFactory consolepkg.KafkaAdminClientFactory
+	testTopicAdmin        *topic.MockAdminClient
+	testACLClient         *acl.MockClient
+	testSchemaRegistry    *schema.MockRegistry
 	ts                    *httptest.Server
 
 	ctx              context.Context
@@ -173,6 +179,7 @@ var _ = BeforeSuite(func(done Done) {
 		Client:                k8sManager.GetClient(),
 		Log:                   ctrl.Log.WithName("controllers").WithName("core").WithName("RedpandaCluster"),
 		Scheme:                k8sManager.GetScheme(),
+		EventRecorder:         k8sManager.GetEventRecorderFor("ClusterConfigurationDrift"),
 		AdminAPIClientFactory: testAdminAPIFactory,
 		DriftCheckPeriod:      &driftCheckPeriod,
 	}).WithClusterDomain("cluster.local").SetupWithManager(k8sManager)
@@ -189,6 +196,73 @@ var _ = BeforeSuite(func(done Done) {
 	}).WithClusterDomain("cluster.local").SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
+	testTopicAdmin = &topic.MockAdminClient{}
+	topicResyncPeriod := 500 * time.Millisecond
+	err = (&redpandacontrollers.TopicReconciler{
+		Client:        k8sManager.GetClient(),
+		Scheme:        k8sManager.GetScheme(),
+		Log:           ctrl.Log.WithName("controllers").WithName("redpanda").WithName("Topic"),
+		Store:         testStore,
+		EventRecorder: k8sManager.GetEventRecorderFor("Topic"),
+		KafkaAdminClientFactory: func(context.Context, client.Client, *vectorizedv1alpha1.Cluster, *consolepkg.Store) (topic.AdminClient, error) {
+			return testTopicAdmin, nil
+		},
+		ResyncPeriod: &topicResyncPeriod,
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	userResyncPeriod := 500 * time.Millisecond
+	err = (&redpandacontrollers.UserReconciler{
+		Client:                k8sManager.GetClient(),
+		Scheme:                k8sManager.GetScheme(),
+		Log:                   ctrl.Log.WithName("controllers").WithName("redpanda").WithName("User"),
+		EventRecorder:         k8sManager.GetEventRecorderFor("User"),
+		AdminAPIClientFactory: testAdminAPIFactory,
+		ResyncPeriod:          &userResyncPeriod,
+	}).WithClusterDomain("cluster.local").SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	testACLClient = &acl.MockClient{}
+	aclResyncPeriod := 500 * time.Millisecond
+	err = (&redpandacontrollers.ACLReconciler{
+		Client:        k8sManager.GetClient(),
+		Scheme:        k8sManager.GetScheme(),
+		Log:           ctrl.Log.WithName("controllers").WithName("redpanda").WithName("ACL"),
+		Store:         testStore,
+		EventRecorder: k8sManager.GetEventRecorderFor("ACL"),
+		KafkaAdminClientFactory: func(context.Context, client.Client, *vectorizedv1alpha1.Cluster, *consolepkg.Store) (acl.Client, error) {
+			return testACLClient, nil
+		},
+		ResyncPeriod: &aclResyncPeriod,
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	testSchemaRegistry = &schema.MockRegistry{}
+	schemaResyncPeriod := 500 * time.Millisecond
+	err = (&redpandacontrollers.SchemaReconciler{
+		Client:        k8sManager.GetClient(),
+		Scheme:        k8sManager.GetScheme(),
+		Log:           ctrl.Log.WithName("controllers").WithName("redpanda").WithName("Schema"),
+		Store:         testStore,
+		EventRecorder: k8sManager.GetEventRecorderFor("Schema"),
+		RegistryFactory: func(context.Context, client.Client, *vectorizedv1alpha1.Cluster, *consolepkg.Store) (schema.Registry, error) {
+			return testSchemaRegistry, nil
+		},
+		ResyncPeriod: &schemaResyncPeriod,
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	recoveryPollPeriod := 500 * time.Millisecond
+	err = (&redpandacontrollers.TopicRecoveryReconciler{
+		Client:                k8sManager.GetClient(),
+		Scheme:                k8sManager.GetScheme(),
+		Log:                   ctrl.Log.WithName("controllers").WithName("redpanda").WithName("TopicRecovery"),
+		EventRecorder:         k8sManager.GetEventRecorderFor("TopicRecovery"),
+		AdminAPIClientFactory: testAdminAPIFactory,
+		PollPeriod:            &recoveryPollPeriod,
+	}).WithClusterDomain("cluster.local").SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
 	storageAddr := ":9090"
 	storageAdvAddr := redpandacontrollers.DetermineAdvStorageAddr(storageAddr, ctrl.Log.WithName("controllers").WithName("core").WithName("Redpanda"))
 	storage := redpandacontrollers.MustInitStorage("/tmp", storageAdvAddr, 60*time.Second, 2, ctrl.Log.WithName("controllers").WithName("core").WithName("Redpanda"))
@@ -269,6 +343,9 @@ var _ = BeforeSuite(func(done Done) {
 var _ = BeforeEach(func() {
 	By("Cleaning the admin API")
 	testAdminAPI.Clear()
+	testTopicAdmin.Clear()
+	testACLClient.Clear()
+	testSchemaRegistry.Clear()
 	// Register some known properties for all tests
 	testAdminAPI.RegisterPropertySchema("auto_create_topics_enabled", admin.ConfigPropertyMetadata{NeedsRestart: false})
 	testAdminAPI.RegisterPropertySchema("cloud_storage_segment_max_upload_interval_sec", admin.ConfigPropertyMetadata{NeedsRestart: true})
@@ -0,0 +1,326 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	consolepkg "github.com/redpanda-data/redpanda/src/go/k8s/pkg/console"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/topic"
+)
+
+const (
+	defaultTopicResyncPeriod = 1 * time.Minute
+
+	// TopicDriftCorrectedEvent is an event for a Topic whose Kafka topic was
+	// changed outside of the operator and has been brought back to the spec
+	TopicDriftCorrectedEvent = "TopicDriftCorrected"
+
+	// TopicDriftedEvent is a warning event for a Topic whose Kafka topic
+	// differs from the spec in a way that cannot be corrected
+	TopicDriftedEvent = "TopicDrifted"
+
+	// TopicExistsEvent is a warning event for a Topic whose Kafka topic
+	// already exists and is not adopted
+	TopicExistsEvent = "TopicExists"
+)
+
+// TopicReconciler reconciles a Topic object
+type TopicReconciler struct {
+	client.Client
+	Scheme                  *runtime.Scheme
+	Log                     logr.Logger
+	Store                   *consolepkg.Store
+	EventRecorder           record.EventRecorder
+	KafkaAdminClientFactory topic.AdminClientFactory
+	// ResyncPeriod is how often topics are checked for drift
+	ResyncPeriod *time.Duration
+}
+
+//+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=topics,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=topics/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=topics/finalizers,verbs=update
+
+// Reconcile handles Topic reconcile requests
+func (r *TopicReconciler) Reconcile(
+	ctx context.Context, req ctrl.Request,
+) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx).WithName("TopicReconciler.Reconcile")
+
+	// Kafka admin clients are closed when their context is done, and the
+	// reconcile context is never cancelled
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	t := &vectorizedv1alpha1.Topic{}
+	if err := r.Get(ctx, req.NamespacedName, t); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	deleting := !t.GetDeletionTimestamp().IsZero()
+
+	cluster, err := t.GetCluster(ctx, r.Client)
+	switch {
+	case err == nil:
+	case errors.Is(err, vectorizedv1alpha1.ErrClusterRefNamespace):
+		// Topics are never reconciled against a Cluster in another namespace
+		if deleting {
+			controllerutil.RemoveFinalizer(t, topic.TopicFinalizer)
+			return ctrl.Result{}, r.Update(ctx, t)
+		}
+		return ctrl.Result{}, r.setNotReady(ctx, t, "ClusterRefNamespace", fmt.Sprintf("Cluster %s is not in namespace %s", t.GetClusterRef(), t.Namespace))
+	case apierrors.IsNotFound(err) || (cluster != nil && !cluster.GetDeletionTimestamp().IsZero()):
+		// Without the cluster there is no topic left to delete
+		if deleting {
+			controllerutil.RemoveFinalizer(t, topic.TopicFinalizer)
+			return ctrl.Result{}, r.Update(ctx, t)
+		}
+		r.EventRecorder.Eventf(
+			t,
+			corev1.EventTypeWarning, ClusterNotFoundEvent,
+			"Unable to reconcile Topic as the referenced Cluster %s is not found or is being deleted", t.GetClusterRef(),
+		)
+		return ctrl.Result{}, r.setNotReady(ctx, t, "ClusterNotFound", fmt.Sprintf("Cluster %s not found", t.GetClusterRef()))
+	case errors.Is(err, vectorizedv1alpha1.ErrClusterNotConfigured):
+		// When the Cluster is configured, the Topic will receive a notification trigger
+		return ctrl.Result{}, r.setNotReady(ctx, t, "ClusterNotConfigured", fmt.Sprintf("Cluster %s is not yet configured", t.GetClusterRef()))
+	default:
+		return ctrl.Result{}, err
+	}
+
+	if deleting {
+		return ctrl.Result{}, r.delete(ctx, t, cluster, log)
+	}
+
+	if !controllerutil.ContainsFinalizer(t, topic.TopicFinalizer) {
+		controllerutil.AddFinalizer(t, topic.TopicFinalizer)
+		if err := r.Update(ctx, t); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	adm, err := r.adminClient(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	name := t.GetTopicName()
+	if t.Status.TopicName == "" {
+		exists, err := topic.Exists(ctx, adm, name)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if exists && !t.Spec.AdoptExisting {
+			return r.refuseExisting(ctx, t, name)
+		}
+		// Record the ownership before creating the topic, otherwise a
+		// failure to update the status would turn it into an existing topic
+		if err := r.setTopicName(ctx, t, name, !exists); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	res, err := topic.Reconcile(ctx, adm, name, &t.Spec)
+	if errors.Is(err, topic.ErrTopicExists) {
+		// The topic was created by someone else in the meantime
+		if err := r.setTopicName(ctx, t, "", false); err != nil {
+			return ctrl.Result{}, err
+		}
+		return r.refuseExisting(ctx, t, name)
+	}
+	if err != nil {
+		if statusErr := r.setNotReady(ctx, t, "ReconcileFailed", err.Error()); statusErr != nil {
+			log.Error(statusErr, "unable to update Topic status")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Changes while the spec did not change are out-of-band changes to the
+	// topic that we just reverted
+	if len(res.Changes) > 0 && !res.Created && t.Status.ObservedGeneration == t.GetGeneration() {
+		r.EventRecorder.Eventf(t, corev1.EventTypeNormal, TopicDriftCorrectedEvent,
+			"Topic %s was changed outside of the operator: %s", name, strings.Join(res.Changes, "; "))
+	}
+	if len(res.Drift) > 0 {
+		msg := strings.Join(res.Drift, "; ")
+		if cond := t.GetCondition(vectorizedv1alpha1.TopicDriftedCondition); cond == nil || cond.Message != msg {
+			r.EventRecorder.Event(t, corev1.EventTypeWarning, TopicDriftedEvent, msg)
+		}
+		t.SetCondition(vectorizedv1alpha1.TopicDriftedCondition, metav1.ConditionTrue, "Drifted", msg)
+	} else {
+		t.SetCondition(vectorizedv1alpha1.TopicDriftedCondition, metav1.ConditionFalse, "InSync", "Topic matches the spec")
+	}
+
+	t.Status.ObservedGeneration = t.GetGeneration()
+	t.Status.TopicName = name
+	result := ctrl.Result{RequeueAfter: r.getResyncPeriod()}
+	if res.Created {
+		t.SetCondition(vectorizedv1alpha1.TopicReadyCondition, metav1.ConditionUnknown, "Created", "Topic created")
+		// Observe the partitions and replicas of the new topic
+		result.RequeueAfter = time.Second
+	} else {
+		t.Status.Partitions = res.Partitions
+		t.Status.ReplicationFactor = res.ReplicationFactor
+		t.SetCondition(vectorizedv1alpha1.TopicReadyCondition, metav1.ConditionTrue, "Reconciled", "Topic reconciled")
+	}
+	if len(res.Changes) > 0 {
+		log.Info("reconciled topic", "topic", name, "changes", res.Changes)
+	}
+	return result, r.Status().Update(ctx, t)
+}
+
+func (r *TopicReconciler) delete(
+	ctx context.Context, t *vectorizedv1alpha1.Topic, cluster *vectorizedv1alpha1.Cluster, log logr.Logger,
+) error {
+	if !controllerutil.ContainsFinalizer(t, topic.TopicFinalizer) {
+		return nil
+	}
+	if exp, err := vectorizedv1alpha1.FinalizersExpired(t); err != nil {
+		log.Error(err, "invalid configuration for finalizers timeout")
+	} else if exp {
+		log.Info("finalizers timed out for topic: removing finalizer")
+		controllerutil.RemoveFinalizer(t, topic.TopicFinalizer)
+		return r.Update(ctx, t)
+	}
+
+	// Only delete topics we created, and only if asked to
+	if t.GetDeletionPolicy() == vectorizedv1alpha1.TopicDeletionPolicyDelete && t.Status.Created {
+		adm, err := r.adminClient(ctx, cluster)
+		if err != nil {
+			return err
+		}
+		if err := topic.Delete(ctx, adm, t.Status.TopicName); err != nil {
+			return err
+		}
+		log.Info("deleted topic", "topic", t.Status.TopicName)
+	}
+	controllerutil.RemoveFinalizer(t, topic.TopicFinalizer)
+	return r.Update(ctx, t)
+}
+
+func (r *TopicReconciler) adminClient(
+	ctx context.Context, cluster *vectorizedv1alpha1.Cluster,
+) (topic.AdminClient, error) {
+	if err := r.Store.SyncKafka(ctx, cluster); err != nil {
+		return nil, fmt.Errorf("sync kafka certificates: %w", err)
+	}
+	adm, err := r.KafkaAdminClientFactory(ctx, r.Client, cluster, r.Store)
+	if err != nil {
+		return nil, fmt.Errorf("creating kafka admin client: %w", err)
+	}
+	return adm, nil
+}
+
+// refuseExisting reports that the Kafka topic already exists and is not
+// adopted, and checks again later in case it is deleted
+func (r *TopicReconciler) refuseExisting(
+	ctx context.Context, t *vectorizedv1alpha1.Topic, name string,
+) (ctrl.Result, error) {
+	if cond := t.GetCondition(vectorizedv1alpha1.TopicReadyCondition); cond == nil || cond.Reason != TopicExistsEvent {
+		r.EventRecorder.Eventf(t, corev1.EventTypeWarning, TopicExistsEvent,
+			"Kafka topic %s already exists and was not created for this Topic; set adoptExisting to manage it", name)
+	}
+	return ctrl.Result{RequeueAfter: r.getResyncPeriod()},
+		r.setNotReady(ctx, t, TopicExistsEvent, fmt.Sprintf("Kafka topic %s already exists and adoptExisting is not set", name))
+}
+
+// setTopicName records the Kafka topic managed by the Topic, and whether the
+// operator creates it
+func (r *TopicReconciler) setTopicName(
+	ctx context.Context, t *vectorizedv1alpha1.Topic, name string, created bool,
+) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var latest vectorizedv1alpha1.Topic
+		if err := r.Get(ctx, client.ObjectKeyFromObject(t), &latest); err != nil {
+			return err
+		}
+		latest.Status.TopicName = name
+		latest.Status.Created = created
+		err := r.Status().Update(ctx, &latest)
+		if err == nil {
+			// sync the original Topic to avoid conflicts on subsequent updates
+			*t = latest
+		}
+		return err
+	})
+}
+
+func (r *TopicReconciler) setNotReady(
+	ctx context.Context, t *vectorizedv1alpha1.Topic, reason, message string,
+) error {
+	if !t.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+	t.SetCondition(vectorizedv1alpha1.TopicReadyCondition, metav1.ConditionFalse, reason, message)
+	return r.Status().Update(ctx, t)
+}
+
+func (r *TopicReconciler) getResyncPeriod() time.Duration {
+	if r.ResyncPeriod != nil {
+		return *r.ResyncPeriod
+	}
+	return defaultTopicResyncPeriod
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TopicReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&vectorizedv1alpha1.Topic{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&source.Kind{Type: &vectorizedv1alpha1.Cluster{}},
+			handler.EnqueueRequestsFromMapFunc(r.reconcileTopicsForCluster),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Complete(r)
+}
+
+func (r *TopicReconciler) reconcileTopicsForCluster(c client.Object) []reconcile.Request {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var topics vectorizedv1alpha1.TopicList
+	if err := r.Client.List(ctx, &topics); err != nil {
+		r.Log.Error(err, "unexpected: could not list topics for propagating reconcile events")
+		return nil
+	}
+
+	var res []reconcile.Request
+	for i := range topics.Items {
+		t := &topics.Items[i]
+		if t.GetClusterRef() == client.ObjectKeyFromObject(c) {
+			res = append(res, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: t.Namespace, Name: t.Name},
+			})
+		}
+	}
+	return res
+}
@@ -0,0 +1,203 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/topic"
+)
+
+var _ = Describe("Topic controller", func() {
+	const (
+		ClusterName = "topic-cluster"
+
+		timeout  = time.Second * 30
+		interval = time.Millisecond * 100
+	)
+
+	var (
+		key             types.NamespacedName
+		redpandaCluster *vectorizedv1alpha1.Cluster
+		namespace       *corev1.Namespace
+	)
+
+	BeforeEach(func() {
+		ctx := context.Background()
+		if redpandaCluster == nil {
+			key, _, redpandaCluster, namespace = getInitialTestCluster(ClusterName)
+		}
+		if err := k8sClient.Get(ctx, key, &vectorizedv1alpha1.Cluster{}); err != nil {
+			if !apierrors.IsNotFound(err) {
+				Expect(err).To(Equal(nil))
+			}
+			Expect(k8sClient.Create(ctx, namespace)).Should(Succeed())
+			Expect(k8sClient.Create(ctx, redpandaCluster)).Should(Succeed())
+			Eventually(clusterConfiguredConditionStatusGetter(key), timeout, interval).Should(BeTrue())
+		}
+	})
+
+	newTopic := func(name string, policy vectorizedv1alpha1.TopicDeletionPolicy) *vectorizedv1alpha1.Topic {
+		return &vectorizedv1alpha1.Topic{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: key.Namespace,
+			},
+			Spec: vectorizedv1alpha1.TopicSpec{
+				ClusterRef:     vectorizedv1alpha1.NamespaceNameRef{Namespace: key.Namespace, Name: key.Name},
+				Partitions:     pointer.Int32(3),
+				Configs:        map[string]string{"cleanup.policy": "compact"},
+				DeletionPolicy: policy,
+			},
+		}
+	}
+
+	topicReady := func(tk types.NamespacedName) func() bool {
+		return func() bool {
+			var t vectorizedv1alpha1.Topic
+			if err := k8sClient.Get(context.Background(), tk, &t); err != nil {
+				return false
+			}
+			return apimeta.IsStatusConditionTrue(t.Status.Conditions, vectorizedv1alpha1.TopicReadyCondition)
+		}
+	}
+
+	topicReadyCondition := func(tk types.NamespacedName) func() *metav1.Condition {
+		return func() *metav1.Condition {
+			var t vectorizedv1alpha1.Topic
+			if err := k8sClient.Get(context.Background(), tk, &t); err != nil {
+				return nil
+			}
+			return t.GetCondition(vectorizedv1alpha1.TopicReadyCondition)
+		}
+	}
+
+	Context("When creating a Topic", func() {
+		ctx := context.Background()
+		It("Should create the Kafka topic and keep it in sync", func() {
+			t := newTopic("created-topic", vectorizedv1alpha1.TopicDeletionPolicyRetain)
+			tk := client.ObjectKeyFromObject(t)
+			Expect(k8sClient.Create(ctx, t)).Should(Succeed())
+
+			By("Creating the Kafka topic")
+			Eventually(func() topic.MockTopic {
+				got, _ := testTopicAdmin.GetTopic("created-topic")
+				return got
+			}, timeout, interval).Should(And(
+				HaveField("Partitions", 3),
+				HaveField("Configs", map[string]string{"cleanup.policy": "compact"}),
+			))
+			Eventually(topicReady(tk), timeout, interval).Should(BeTrue())
+
+			By("Reverting out-of-band changes")
+			testTopicAdmin.SetTopic("created-topic", topic.MockTopic{Partitions: 3, Replicas: 3, Configs: map[string]string{"cleanup.policy": "delete"}})
+			Eventually(func() map[string]string {
+				got, _ := testTopicAdmin.GetTopic("created-topic")
+				return got.Configs
+			}, timeout, interval).Should(Equal(map[string]string{"cleanup.policy": "compact"}))
+
+			By("Keeping the Kafka topic when the Topic is deleted")
+			Expect(k8sClient.Delete(ctx, t)).Should(Succeed())
+			Eventually(func() bool {
+				return apierrors.IsNotFound(k8sClient.Get(ctx, tk, &vectorizedv1alpha1.Topic{}))
+			}, timeout, interval).Should(BeTrue())
+			_, exists := testTopicAdmin.GetTopic("created-topic")
+			Expect(exists).To(BeTrue())
+		})
+
+		It("Should delete the Kafka topic with the Delete policy", func() {
+			t := newTopic("deleted-topic", vectorizedv1alpha1.TopicDeletionPolicyDelete)
+			tk := client.ObjectKeyFromObject(t)
+			Expect(k8sClient.Create(ctx, t)).Should(Succeed())
+			Eventually(topicReady(tk), timeout, interval).Should(BeTrue())
+
+			Expect(k8sClient.Delete(ctx, t)).Should(Succeed())
+			Eventually(func() bool {
+				return apierrors.IsNotFound(k8sClient.Get(ctx, tk, &vectorizedv1alpha1.Topic{}))
+			}, timeout, interval).Should(BeTrue())
+			_, exists := testTopicAdmin.GetTopic("deleted-topic")
+			Expect(exists).To(BeFalse())
+		})
+
+		It("Should not take over an existing Kafka topic", func() {
+			existing := topic.MockTopic{Partitions: 1, Replicas: 3, Configs: map[string]string{"retention.ms": "1000"}}
+			testTopicAdmin.SetTopic("existing-topic", existing)
+			t := newTopic("existing-topic", vectorizedv1alpha1.TopicDeletionPolicyDelete)
+			tk := client.ObjectKeyFromObject(t)
+			Expect(k8sClient.Create(ctx, t)).Should(Succeed())
+
+			Eventually(topicReadyCondition(tk), timeout, interval).Should(And(
+				HaveField("Status", metav1.ConditionFalse),
+				HaveField("Reason", "TopicExists"),
+			))
+			got, _ := testTopicAdmin.GetTopic("existing-topic")
+			Expect(got).To(Equal(existing))
+
+			By("Keeping the Kafka topic when the Topic is deleted")
+			Expect(k8sClient.Delete(ctx, t)).Should(Succeed())
+			Eventually(func() bool {
+				return apierrors.IsNotFound(k8sClient.Get(ctx, tk, &vectorizedv1alpha1.Topic{}))
+			}, timeout, interval).Should(BeTrue())
+			got, _ = testTopicAdmin.GetTopic("existing-topic")
+			Expect(got).To(Equal(existing))
+		})
+
+		It("Should adopt an existing Kafka topic when asked to, but never delete it", func() {
+			testTopicAdmin.SetTopic("adopted-topic", topic.MockTopic{Partitions: 1, Replicas: 3, Configs: map[string]string{"retention.ms": "1000"}})
+			t := newTopic("adopted-topic", vectorizedv1alpha1.TopicDeletionPolicyDelete)
+			t.Spec.AdoptExisting = true
+			tk := client.ObjectKeyFromObject(t)
+			Expect(k8sClient.Create(ctx, t)).Should(Succeed())
+
+			Eventually(topicReady(tk), timeout, interval).Should(BeTrue())
+			Eventually(func() topic.MockTopic {
+				got, _ := testTopicAdmin.GetTopic("adopted-topic")
+				return got
+			}, timeout, interval).Should(Equal(topic.MockTopic{Partitions: 3, Replicas: 3, Configs: map[string]string{"cleanup.policy": "compact"}}))
+			Expect(k8sClient.Get(ctx, tk, t)).Should(Succeed())
+			Expect(t.Status.Created).To(BeFalse())
+
+			Expect(k8sClient.Delete(ctx, t)).Should(Succeed())
+			Eventually(func() bool {
+				return apierrors.IsNotFound(k8sClient.Get(ctx, tk, &vectorizedv1alpha1.Topic{}))
+			}, timeout, interval).Should(BeTrue())
+			_, exists := testTopicAdmin.GetTopic("adopted-topic")
+			Expect(exists).To(BeTrue())
+		})
+
+		It("Should refuse a Cluster in another namespace", func() {
+			other := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: key.Namespace + "-topics"}}
+			Expect(k8sClient.Create(ctx, other)).Should(Succeed())
+			t := newTopic("foreign-topic", vectorizedv1alpha1.TopicDeletionPolicyDelete)
+			t.Namespace = other.Name
+			tk := client.ObjectKeyFromObject(t)
+			Expect(k8sClient.Create(ctx, t)).Should(Succeed())
+
+			Eventually(topicReadyCondition(tk), timeout, interval).Should(And(
+				HaveField("Status", metav1.ConditionFalse),
+				HaveField("Reason", "ClusterRefNamespace"),
+			))
+			_, exists := testTopicAdmin.GetTopic("foreign-topic")
+			Expect(exists).To(BeFalse())
+		})
+	})
+})
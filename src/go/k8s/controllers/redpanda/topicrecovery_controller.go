@@ -0,0 +1,248 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
+	consolepkg "github.com/redpanda-data/redpanda/src/go/k8s/pkg/console"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/recovery"
+)
+
+const (
+	defaultTopicRecoveryPollPeriod = 30 * time.Second
+
+	// TopicRecoveryStartedEvent is an event for a TopicRecovery whose
+	// recovery was started
+	TopicRecoveryStartedEvent = "RecoveryStarted"
+	// TopicRecoveryCompletedEvent is an event for a TopicRecovery whose
+	// topics were recovered
+	TopicRecoveryCompletedEvent = "RecoveryCompleted"
+	// TopicRecoveryFailedEvent is a warning event for a TopicRecovery that
+	// could not be started or failed to download some partitions
+	TopicRecoveryFailedEvent = "RecoveryFailed"
+)
+
+// TopicRecoveryReconciler reconciles a TopicRecovery object
+type TopicRecoveryReconciler struct {
+	client.Client
+	Scheme                *runtime.Scheme
+	Log                   logr.Logger
+	EventRecorder         record.EventRecorder
+	AdminAPIClientFactory adminutils.AdminAPIClientFactory
+	// PollPeriod is how often a running recovery is polled
+	PollPeriod    *time.Duration
+	clusterDomain string
+}
+
+//+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=topicrecoveries,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=topicrecoveries/status,verbs=get;update;patch
+
+// Reconcile handles TopicRecovery reconcile requests
+//
+// The recovery is started once the referenced cluster is ready and no other
+// recovery of the cluster is running. It is then polled until Redpanda
+// reports it as inactive. A recovery runs once for each generation of the
+// TopicRecovery, so changing its spec recovers the topics again.
+func (r *TopicRecoveryReconciler) Reconcile(
+	ctx context.Context, req ctrl.Request,
+) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx).WithName("TopicRecoveryReconciler.Reconcile")
+
+	tr := &vectorizedv1alpha1.TopicRecovery{}
+	if err := r.Get(ctx, req.NamespacedName, tr); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	// A running recovery cannot be cancelled
+	if !tr.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// A new generation is recovered once the running recovery finished
+	if tr.Status.ObservedGeneration != tr.GetGeneration() && tr.Status.Phase != vectorizedv1alpha1.TopicRecoveryPhaseRunning {
+		tr.Status = vectorizedv1alpha1.TopicRecoveryStatus{
+			ObservedGeneration: tr.GetGeneration(),
+			Phase:              vectorizedv1alpha1.TopicRecoveryPhasePending,
+			Conditions:         tr.Status.Conditions,
+		}
+	}
+	if tr.IsFinished() {
+		return ctrl.Result{}, nil
+	}
+
+	cluster, err := tr.GetCluster(ctx, r.Client)
+	switch {
+	case err == nil:
+	case errors.Is(err, vectorizedv1alpha1.ErrClusterRefNamespace):
+		// Recoveries are never started on a Cluster in another namespace
+		return ctrl.Result{}, r.setNotCompleted(ctx, tr, "ClusterRefNamespace", fmt.Sprintf("Cluster %s is not in namespace %s", tr.GetClusterRef(), tr.Namespace))
+	case apierrors.IsNotFound(err) || (cluster != nil && !cluster.GetDeletionTimestamp().IsZero()):
+		r.EventRecorder.Eventf(
+			tr,
+			corev1.EventTypeWarning, ClusterNotFoundEvent,
+			"Unable to reconcile TopicRecovery as the referenced Cluster %s is not found or is being deleted", tr.GetClusterRef(),
+		)
+		return ctrl.Result{}, r.setNotCompleted(ctx, tr, "ClusterNotFound", fmt.Sprintf("Cluster %s not found", tr.GetClusterRef()))
+	case errors.Is(err, vectorizedv1alpha1.ErrClusterNotConfigured):
+		// When the Cluster is configured, the TopicRecovery will receive a notification trigger
+		return ctrl.Result{}, r.setNotCompleted(ctx, tr, "ClusterNotConfigured", fmt.Sprintf("Cluster %s is not yet configured", tr.GetClusterRef()))
+	default:
+		return ctrl.Result{}, err
+	}
+	if !cluster.Spec.CloudStorage.Enabled {
+		return ctrl.Result{}, r.setNotCompleted(ctx, tr, "CloudStorageNotEnabled", fmt.Sprintf("Cluster %s does not enable cloud storage", tr.GetClusterRef()))
+	}
+	if tr.Status.Phase == vectorizedv1alpha1.TopicRecoveryPhasePending && !isClusterReady(cluster) {
+		return ctrl.Result{}, r.setNotCompleted(ctx, tr, "ClusterNotReady", fmt.Sprintf("Cluster %s is not ready", tr.GetClusterRef()))
+	}
+
+	adminAPI, err := consolepkg.NewAdminAPI(ctx, r.Client, r.Scheme, cluster, r.clusterDomain, r.AdminAPIClientFactory, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	pattern := tr.GetTopicNamesPattern()
+	if tr.Status.Phase == vectorizedv1alpha1.TopicRecoveryPhasePending {
+		started, err := recovery.Start(ctx, adminAPI, pattern)
+		if err != nil {
+			r.EventRecorder.Event(tr, corev1.EventTypeWarning, TopicRecoveryFailedEvent, err.Error())
+			if statusErr := r.setNotCompleted(ctx, tr, "StartFailed", err.Error()); statusErr != nil {
+				log.Error(statusErr, "unable to update TopicRecovery status")
+			}
+			return ctrl.Result{}, err
+		}
+		if !started {
+			return ctrl.Result{RequeueAfter: r.getPollPeriod()}, r.setNotCompleted(ctx, tr, "RecoveryRunning", "Waiting for the running recovery of other topics to finish")
+		}
+		log.Info("started recovery", "pattern", pattern)
+		r.EventRecorder.Eventf(tr, corev1.EventTypeNormal, TopicRecoveryStartedEvent, "Started recovery of topics matching %q", pattern)
+		now := metav1.Now()
+		tr.Status.Phase = vectorizedv1alpha1.TopicRecoveryPhaseRunning
+		tr.Status.StartTime = &now
+	}
+
+	progress, err := recovery.Poll(ctx, adminAPI)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	tr.Status.State = progress.State
+	if len(progress.Topics) > 0 {
+		tr.Status.Topics = progress.Topics
+	}
+	if !progress.Done {
+		tr.SetCondition(vectorizedv1alpha1.TopicRecoveryCompletedCondition, metav1.ConditionFalse, "Recovering",
+			fmt.Sprintf("Recovering %d topics matching %q", len(tr.Status.Topics), pattern))
+		return ctrl.Result{RequeueAfter: r.getPollPeriod()}, r.Status().Update(ctx, tr)
+	}
+
+	now := metav1.Now()
+	tr.Status.CompletionTime = &now
+	if progress.Failed() {
+		msg := fmt.Sprintf("Some partitions of the topics matching %q failed to be recovered", pattern)
+		log.Info("recovery failed", "pattern", pattern)
+		r.EventRecorder.Event(tr, corev1.EventTypeWarning, TopicRecoveryFailedEvent, msg)
+		tr.Status.Phase = vectorizedv1alpha1.TopicRecoveryPhaseFailed
+		tr.SetCondition(vectorizedv1alpha1.TopicRecoveryCompletedCondition, metav1.ConditionFalse, "DownloadsFailed", msg)
+	} else {
+		msg := fmt.Sprintf("Recovered %d topics matching %q", len(tr.Status.Topics), pattern)
+		log.Info("recovery completed", "pattern", pattern, "topics", len(tr.Status.Topics))
+		r.EventRecorder.Event(tr, corev1.EventTypeNormal, TopicRecoveryCompletedEvent, msg)
+		tr.Status.Phase = vectorizedv1alpha1.TopicRecoveryPhaseCompleted
+		tr.SetCondition(vectorizedv1alpha1.TopicRecoveryCompletedCondition, metav1.ConditionTrue, "Recovered", msg)
+	}
+	return ctrl.Result{}, r.Status().Update(ctx, tr)
+}
+
+// isClusterReady tells if all brokers of the cluster are ready
+func isClusterReady(cluster *vectorizedv1alpha1.Cluster) bool {
+	if cluster.Spec.Replicas == nil || cluster.Status.IsRestarting() {
+		return false
+	}
+	return cluster.Status.ReadyReplicas >= cluster.GetDesiredReplicas()
+}
+
+func (r *TopicRecoveryReconciler) setNotCompleted(
+	ctx context.Context, tr *vectorizedv1alpha1.TopicRecovery, reason, message string,
+) error {
+	tr.SetCondition(vectorizedv1alpha1.TopicRecoveryCompletedCondition, metav1.ConditionFalse, reason, message)
+	return r.Status().Update(ctx, tr)
+}
+
+func (r *TopicRecoveryReconciler) getPollPeriod() time.Duration {
+	if r.PollPeriod != nil {
+		return *r.PollPeriod
+	}
+	return defaultTopicRecoveryPollPeriod
+}
+
+// WithClusterDomain sets the clusterDomain
+func (r *TopicRecoveryReconciler) WithClusterDomain(
+	clusterDomain string,
+) *TopicRecoveryReconciler {
+	r.clusterDomain = clusterDomain
+	return r
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TopicRecoveryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&vectorizedv1alpha1.TopicRecovery{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&source.Kind{Type: &vectorizedv1alpha1.Cluster{}},
+			handler.EnqueueRequestsFromMapFunc(r.reconcileTopicRecoveriesForCluster),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Complete(r)
+}
+
+func (r *TopicRecoveryReconciler) reconcileTopicRecoveriesForCluster(c client.Object) []reconcile.Request {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var recoveries vectorizedv1alpha1.TopicRecoveryList
+	if err := r.Client.List(ctx, &recoveries); err != nil {
+		r.Log.Error(err, "unexpected: could not list topic recoveries for propagating reconcile events")
+		return nil
+	}
+
+	var res []reconcile.Request
+	for i := range recoveries.Items {
+		tr := &recoveries.Items[i]
+		if tr.GetClusterRef() == client.ObjectKeyFromObject(c) && !tr.IsFinished() {
+			res = append(res, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: tr.Namespace, Name: tr.Name},
+			})
+		}
+	}
+	return res
+}
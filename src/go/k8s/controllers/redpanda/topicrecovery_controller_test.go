@@ -0,0 +1,156 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+)
+
+var _ = Describe("TopicRecovery controller", func() {
+	const (
+		ClusterName = "recovery-cluster"
+
+		timeout  = time.Second * 30
+		interval = time.Millisecond * 100
+	)
+
+	var (
+		key             types.NamespacedName
+		redpandaCluster *vectorizedv1alpha1.Cluster
+		namespace       *corev1.Namespace
+	)
+
+	BeforeEach(func() {
+		ctx := context.Background()
+		// The cloud storage properties are unknown to the admin API otherwise
+		for _, p := range []string{
+			"cloud_storage_enabled",
+			"cloud_storage_region",
+			"cloud_storage_bucket",
+			"cloud_storage_disable_tls",
+			"cloud_storage_credentials_source",
+			"cloud_storage_reconciliation_interval_ms",
+			"cloud_storage_max_connections",
+			"cloud_storage_api_endpoint",
+			"cloud_storage_api_endpoint_port",
+			"cloud_storage_trust_file",
+			"cloud_storage_cache_size",
+		} {
+			testAdminAPI.RegisterPropertySchema(p, admin.ConfigPropertyMetadata{NeedsRestart: false})
+		}
+		if redpandaCluster == nil {
+			key, _, redpandaCluster, namespace = getInitialTestCluster(ClusterName)
+			redpandaCluster.Spec.CloudStorage = vectorizedv1alpha1.CloudStorageConfig{
+				Enabled: true,
+				Bucket:  "recovery-bucket",
+				Region:  "us-east-1",
+			}
+		}
+		if err := k8sClient.Get(ctx, key, &vectorizedv1alpha1.Cluster{}); err != nil {
+			if !apierrors.IsNotFound(err) {
+				Expect(err).To(Equal(nil))
+			}
+			Expect(k8sClient.Create(ctx, namespace)).Should(Succeed())
+			Expect(k8sClient.Create(ctx, redpandaCluster)).Should(Succeed())
+			Eventually(clusterConfiguredConditionStatusGetter(key), timeout, interval).Should(BeTrue())
+			Eventually(statefulSetReplicasReconciler(ctrl.Log.WithName("statefulSetReplicasReconciler"), key, redpandaCluster), timeout, interval).Should(Succeed())
+			Eventually(resourceDataGetter(key, redpandaCluster, func() interface{} {
+				return redpandaCluster.Status.ReadyReplicas
+			}), timeout, interval).Should(Equal(int32(1)))
+		}
+	})
+
+	Context("When creating a TopicRecovery", func() {
+		ctx := context.Background()
+		It("Should recover the topics and report the progress", func() {
+			tr := &vectorizedv1alpha1.TopicRecovery{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "orders",
+					Namespace: key.Namespace,
+				},
+				Spec: vectorizedv1alpha1.TopicRecoverySpec{
+					ClusterRef:        vectorizedv1alpha1.NamespaceNameRef{Namespace: key.Namespace, Name: key.Name},
+					TopicNamesPattern: "orders-.*",
+				},
+			}
+			trk := client.ObjectKeyFromObject(tr)
+			Expect(k8sClient.Create(ctx, tr)).Should(Succeed())
+
+			By("Starting the recovery of the matching topics")
+			Eventually(resourceDataGetter(trk, tr, func() interface{} {
+				return tr.Status.Phase
+			}), timeout, interval).Should(Equal(vectorizedv1alpha1.TopicRecoveryPhaseRunning))
+			status, err := testAdminAPI.PollAutomatedRecoveryStatus(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status.RecoveryRequest.TopicNamesPattern).To(Equal("orders-.*"))
+
+			By("Completing once Redpanda reports the recovery as inactive")
+			testAdminAPI.SetRecoveryStatus(admin.TopicRecoveryStatus{
+				State:           "inactive",
+				RecoveryRequest: admin.RecoveryRequestParams{TopicNamesPattern: "orders-.*"},
+				TopicDownloads: []admin.TopicDownloadCounts{
+					{TopicNamespace: "kafka/orders-eu", SuccessfulDownloads: 3},
+				},
+			})
+			Eventually(resourceDataGetter(trk, tr, func() interface{} {
+				return tr.Status.Phase
+			}), timeout, interval).Should(Equal(vectorizedv1alpha1.TopicRecoveryPhaseCompleted))
+			Expect(apimeta.IsStatusConditionTrue(tr.Status.Conditions, vectorizedv1alpha1.TopicRecoveryCompletedCondition)).To(BeTrue())
+			Expect(tr.Status.Topics).To(Equal([]vectorizedv1alpha1.TopicRecoveryProgress{
+				{Topic: "kafka/orders-eu", SuccessfulDownloads: 3},
+			}))
+		})
+
+		It("Should refuse a Cluster in another namespace", func() {
+			testAdminAPI.SetRecoveryStatus(admin.TopicRecoveryStatus{State: "inactive"})
+			other := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: key.Namespace + "-recoveries"}}
+			Expect(k8sClient.Create(ctx, other)).Should(Succeed())
+			tr := &vectorizedv1alpha1.TopicRecovery{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foreign",
+					Namespace: other.Name,
+				},
+				Spec: vectorizedv1alpha1.TopicRecoverySpec{
+					ClusterRef:        vectorizedv1alpha1.NamespaceNameRef{Namespace: key.Namespace, Name: key.Name},
+					TopicNamesPattern: "foreign-.*",
+				},
+			}
+			trk := client.ObjectKeyFromObject(tr)
+			Expect(k8sClient.Create(ctx, tr)).Should(Succeed())
+
+			Eventually(func() *metav1.Condition {
+				if err := k8sClient.Get(ctx, trk, tr); err != nil {
+					return nil
+				}
+				return apimeta.FindStatusCondition(tr.Status.Conditions, vectorizedv1alpha1.TopicRecoveryCompletedCondition)
+			}, timeout, interval).Should(And(
+				HaveField("Status", metav1.ConditionFalse),
+				HaveField("Reason", "ClusterRefNamespace"),
+			))
+			status, err := testAdminAPI.PollAutomatedRecoveryStatus(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status.RecoveryRequest.TopicNamesPattern).ToNot(Equal("foreign-.*"))
+		})
+	})
+})
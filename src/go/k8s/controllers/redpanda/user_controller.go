@@ -0,0 +1,375 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
+	consolepkg "github.com/redpanda-data/redpanda/src/go/k8s/pkg/console"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/user"
+)
+
+const (
+	defaultUserResyncPeriod = 1 * time.Minute
+
+	userPasswordLength = 32
+
+	// UserSecretMechanismKey is the key of the SCRAM mechanism in the Secret
+	// with the generated credentials of a User
+	UserSecretMechanismKey = "mechanism"
+
+	// UserExistsEvent is a warning event for a User whose SASL user already
+	// exists and was not created by the operator
+	UserExistsEvent = "UserExists"
+)
+
+// UserReconciler reconciles a User object
+type UserReconciler struct {
+	client.Client
+	Scheme                *runtime.Scheme
+	Log                   logr.Logger
+	EventRecorder         record.EventRecorder
+	AdminAPIClientFactory adminutils.AdminAPIClientFactory
+	// ResyncPeriod is how often users are checked for drift
+	ResyncPeriod  *time.Duration
+	clusterDomain string
+}
+
+//+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=users,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=users/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=redpanda.vectorized.io,resources=users/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch
+
+// Reconcile handles User reconcile requests
+func (r *UserReconciler) Reconcile(
+	ctx context.Context, req ctrl.Request,
+) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx).WithName("UserReconciler.Reconcile")
+
+	u := &vectorizedv1alpha1.User{}
+	if err := r.Get(ctx, req.NamespacedName, u); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	deleting := !u.GetDeletionTimestamp().IsZero()
+
+	cluster, err := u.GetCluster(ctx, r.Client)
+	switch {
+	case err == nil:
+	case errors.Is(err, vectorizedv1alpha1.ErrClusterRefNamespace):
+		// Users are never reconciled against a Cluster in another namespace
+		if deleting {
+			controllerutil.RemoveFinalizer(u, user.UserFinalizer)
+			return ctrl.Result{}, r.Update(ctx, u)
+		}
+		return ctrl.Result{}, r.setNotReady(ctx, u, "ClusterRefNamespace", fmt.Sprintf("Cluster %s is not in namespace %s", u.GetClusterRef(), u.Namespace))
+	case apierrors.IsNotFound(err) || (cluster != nil && !cluster.GetDeletionTimestamp().IsZero()):
+		// Without the cluster there is no user left to delete
+		if deleting {
+			controllerutil.RemoveFinalizer(u, user.UserFinalizer)
+			return ctrl.Result{}, r.Update(ctx, u)
+		}
+		r.EventRecorder.Eventf(
+			u,
+			corev1.EventTypeWarning, ClusterNotFoundEvent,
+			"Unable to reconcile User as the referenced Cluster %s is not found or is being deleted", u.GetClusterRef(),
+		)
+		return ctrl.Result{}, r.setNotReady(ctx, u, "ClusterNotFound", fmt.Sprintf("Cluster %s not found", u.GetClusterRef()))
+	case errors.Is(err, vectorizedv1alpha1.ErrClusterNotConfigured):
+		// When the Cluster is configured, the User will receive a notification trigger
+		return ctrl.Result{}, r.setNotReady(ctx, u, "ClusterNotConfigured", fmt.Sprintf("Cluster %s is not yet configured", u.GetClusterRef()))
+	default:
+		return ctrl.Result{}, err
+	}
+
+	if deleting {
+		return ctrl.Result{}, r.delete(ctx, u, cluster, log)
+	}
+
+	if !controllerutil.ContainsFinalizer(u, user.UserFinalizer) {
+		controllerutil.AddFinalizer(u, user.UserFinalizer)
+		if err := r.Update(ctx, u); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	password, version, err := r.password(ctx, u)
+	var notReady *userNotReadyError
+	if errors.As(err, &notReady) {
+		// The Secret is not watched, so check it again later
+		return ctrl.Result{RequeueAfter: r.getResyncPeriod()}, r.setNotReady(ctx, u, notReady.reason, notReady.Error())
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	adminAPI, err := consolepkg.NewAdminAPI(ctx, r.Client, r.Scheme, cluster, r.clusterDomain, r.AdminAPIClientFactory, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	username, mechanism := u.GetUsername(), u.GetMechanism()
+	if !u.Status.Created {
+		exists, err := user.Exists(ctx, adminAPI, username)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if exists {
+			return r.refuseExisting(ctx, u, username)
+		}
+		// Record the ownership before creating the user, otherwise a failure
+		// to update the status would turn it into an existing user
+		if err := r.setCreated(ctx, u, username, true); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	update := u.Status.SecretResourceVersion != version || u.Status.Mechanism != mechanism
+	created, err := user.Reconcile(ctx, adminAPI, username, password, string(mechanism), true, update)
+	if err != nil {
+		if exists, existsErr := user.Exists(ctx, adminAPI, username); existsErr == nil && !exists {
+			// Do not claim a user that may be created by someone else
+			if statusErr := r.setCreated(ctx, u, username, false); statusErr != nil {
+				log.Error(statusErr, "unable to update User status")
+			}
+		}
+		if statusErr := r.setNotReady(ctx, u, "ReconcileFailed", err.Error()); statusErr != nil {
+			log.Error(statusErr, "unable to update User status")
+		}
+		return ctrl.Result{}, err
+	}
+	switch {
+	case created:
+		log.Info("created user", "username", username)
+	case update:
+		log.Info("updated user credentials", "username", username)
+	}
+
+	u.Status.ObservedGeneration = u.GetGeneration()
+	u.Status.Username = username
+	u.Status.Mechanism = mechanism
+	u.Status.SecretResourceVersion = version
+	u.SetCondition(vectorizedv1alpha1.UserReadyCondition, metav1.ConditionTrue, "Reconciled", "User reconciled")
+	return ctrl.Result{RequeueAfter: r.getResyncPeriod()}, r.Status().Update(ctx, u)
+}
+
+// userNotReadyError is returned when the password of a User cannot be read
+// until the user fixes its Secret
+type userNotReadyError struct {
+	reason  string
+	message string
+}
+
+func (e *userNotReadyError) Error() string {
+	return e.message
+}
+
+// password returns the password of the User and the resource version of the
+// Secret it was read from, generating it if needed
+func (r *UserReconciler) password(
+	ctx context.Context, u *vectorizedv1alpha1.User,
+) (string, string, error) {
+	if ref := u.Spec.PasswordSecretRef; ref != nil {
+		var secret corev1.Secret
+		if err := r.Get(ctx, types.NamespacedName{Namespace: u.Namespace, Name: ref.Name}, &secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				return "", "", &userNotReadyError{"SecretNotFound", fmt.Sprintf("Secret %s not found", ref.Name)}
+			}
+			return "", "", err
+		}
+		password, ok := secret.Data[ref.Key]
+		if !ok || len(password) == 0 {
+			return "", "", &userNotReadyError{"SecretKeyNotFound", fmt.Sprintf("Secret %s has no %s key", ref.Name, ref.Key)}
+		}
+		return string(password), secret.ResourceVersion, nil
+	}
+
+	name := u.GetSecretName()
+	var secret corev1.Secret
+	err := r.Get(ctx, types.NamespacedName{Namespace: u.Namespace, Name: name}, &secret)
+	switch {
+	case err == nil:
+		if !metav1.IsControlledBy(&secret, u) {
+			return "", "", &userNotReadyError{"SecretConflict", fmt.Sprintf("Secret %s already exists and is not owned by the User", name)}
+		}
+		return string(secret.Data[corev1.BasicAuthPasswordKey]), secret.ResourceVersion, nil
+	case !apierrors.IsNotFound(err):
+		return "", "", err
+	}
+
+	password, err := resources.GeneratePassword(userPasswordLength)
+	if err != nil {
+		return "", "", fmt.Errorf("generating password: %w", err)
+	}
+	secret = corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: u.Namespace,
+		},
+		Type: corev1.SecretTypeBasicAuth,
+		StringData: map[string]string{
+			corev1.BasicAuthUsernameKey: u.GetUsername(),
+			corev1.BasicAuthPasswordKey: password,
+			UserSecretMechanismKey:      string(u.GetMechanism()),
+		},
+	}
+	if err := controllerutil.SetControllerReference(u, &secret, r.Scheme); err != nil {
+		return "", "", err
+	}
+	if err := r.Create(ctx, &secret); err != nil {
+		return "", "", fmt.Errorf("creating Secret %s: %w", name, err)
+	}
+	return password, secret.ResourceVersion, nil
+}
+
+func (r *UserReconciler) delete(
+	ctx context.Context, u *vectorizedv1alpha1.User, cluster *vectorizedv1alpha1.Cluster, log logr.Logger,
+) error {
+	if !controllerutil.ContainsFinalizer(u, user.UserFinalizer) {
+		return nil
+	}
+	if exp, err := vectorizedv1alpha1.FinalizersExpired(u); err != nil {
+		log.Error(err, "invalid configuration for finalizers timeout")
+	} else if exp {
+		log.Info("finalizers timed out for user: removing finalizer")
+		controllerutil.RemoveFinalizer(u, user.UserFinalizer)
+		return r.Update(ctx, u)
+	}
+
+	// Only delete users we created
+	if u.Status.Created {
+		adminAPI, err := consolepkg.NewAdminAPI(ctx, r.Client, r.Scheme, cluster, r.clusterDomain, r.AdminAPIClientFactory, log)
+		if err != nil {
+			return err
+		}
+		if err := user.Delete(ctx, adminAPI, u.Status.Username); err != nil {
+			return err
+		}
+		log.Info("deleted user", "username", u.Status.Username)
+	}
+	controllerutil.RemoveFinalizer(u, user.UserFinalizer)
+	return r.Update(ctx, u)
+}
+
+// refuseExisting reports that the SASL user already exists and was not
+// created for the User, and checks again later in case it is deleted
+func (r *UserReconciler) refuseExisting(
+	ctx context.Context, u *vectorizedv1alpha1.User, username string,
+) (ctrl.Result, error) {
+	if cond := u.GetCondition(vectorizedv1alpha1.UserReadyCondition); cond == nil || cond.Reason != UserExistsEvent {
+		r.EventRecorder.Eventf(u, corev1.EventTypeWarning, UserExistsEvent,
+			"SASL user %s already exists and was not created for this User", username)
+	}
+	return ctrl.Result{RequeueAfter: r.getResyncPeriod()},
+		r.setNotReady(ctx, u, UserExistsEvent, fmt.Sprintf("%v: %q", user.ErrUserExists, username))
+}
+
+// setCreated records whether the SASL user is created for the User
+func (r *UserReconciler) setCreated(
+	ctx context.Context, u *vectorizedv1alpha1.User, username string, created bool,
+) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var latest vectorizedv1alpha1.User
+		if err := r.Get(ctx, client.ObjectKeyFromObject(u), &latest); err != nil {
+			return err
+		}
+		latest.Status.Created = created
+		latest.Status.Username = username
+		err := r.Status().Update(ctx, &latest)
+		if err == nil {
+			// sync the original User to avoid conflicts on subsequent updates
+			*u = latest
+		}
+		return err
+	})
+}
+
+func (r *UserReconciler) setNotReady(
+	ctx context.Context, u *vectorizedv1alpha1.User, reason, message string,
+) error {
+	if !u.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+	u.SetCondition(vectorizedv1alpha1.UserReadyCondition, metav1.ConditionFalse, reason, message)
+	return r.Status().Update(ctx, u)
+}
+
+func (r *UserReconciler) getResyncPeriod() time.Duration {
+	if r.ResyncPeriod != nil {
+		return *r.ResyncPeriod
+	}
+	return defaultUserResyncPeriod
+}
+
+// WithClusterDomain sets the clusterDomain
+func (r *UserReconciler) WithClusterDomain(
+	clusterDomain string,
+) *UserReconciler {
+	r.clusterDomain = clusterDomain
+	return r
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *UserReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&vectorizedv1alpha1.User{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&corev1.Secret{}).
+		Watches(
+			&source.Kind{Type: &vectorizedv1alpha1.Cluster{}},
+			handler.EnqueueRequestsFromMapFunc(r.reconcileUsersForCluster),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Complete(r)
+}
+
+func (r *UserReconciler) reconcileUsersForCluster(c client.Object) []reconcile.Request {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var users vectorizedv1alpha1.UserList
+	if err := r.Client.List(ctx, &users); err != nil {
+		r.Log.Error(err, "unexpected: could not list users for propagating reconcile events")
+		return nil
+	}
+
+	var res []reconcile.Request
+	for i := range users.Items {
+		u := &users.Items[i]
+		if u.GetClusterRef() == client.ObjectKeyFromObject(c) {
+			res = append(res, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: u.Namespace, Name: u.Name},
+			})
+		}
+	}
+	return res
+}
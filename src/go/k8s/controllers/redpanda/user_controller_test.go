@@ -0,0 +1,148 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
+)
+
+var _ = Describe("User controller", func() {
+	const (
+		ClusterName = "user-cluster"
+
+		timeout  = time.Second * 30
+		interval = time.Millisecond * 100
+	)
+
+	var (
+		key             types.NamespacedName
+		redpandaCluster *vectorizedv1alpha1.Cluster
+		namespace       *corev1.Namespace
+	)
+
+	BeforeEach(func() {
+		ctx := context.Background()
+		if redpandaCluster == nil {
+			key, _, redpandaCluster, namespace = getInitialTestCluster(ClusterName)
+		}
+		if err := k8sClient.Get(ctx, key, &vectorizedv1alpha1.Cluster{}); err != nil {
+			if !apierrors.IsNotFound(err) {
+				Expect(err).To(Equal(nil))
+			}
+			Expect(k8sClient.Create(ctx, namespace)).Should(Succeed())
+			Expect(k8sClient.Create(ctx, redpandaCluster)).Should(Succeed())
+			Eventually(clusterConfiguredConditionStatusGetter(key), timeout, interval).Should(BeTrue())
+		}
+	})
+
+	newUser := func(name, ns string) *vectorizedv1alpha1.User {
+		return &vectorizedv1alpha1.User{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+			},
+			Spec: vectorizedv1alpha1.UserSpec{
+				ClusterRef: vectorizedv1alpha1.NamespaceNameRef{Namespace: key.Namespace, Name: key.Name},
+			},
+		}
+	}
+
+	userReadyCondition := func(uk types.NamespacedName) func() *metav1.Condition {
+		return func() *metav1.Condition {
+			var u vectorizedv1alpha1.User
+			if err := k8sClient.Get(context.Background(), uk, &u); err != nil {
+				return nil
+			}
+			return u.GetCondition(vectorizedv1alpha1.UserReadyCondition)
+		}
+	}
+
+	userDeleted := func(uk types.NamespacedName) func() bool {
+		return func() bool {
+			return apierrors.IsNotFound(k8sClient.Get(context.Background(), uk, &vectorizedv1alpha1.User{}))
+		}
+	}
+
+	Context("When creating a User", func() {
+		ctx := context.Background()
+		It("Should create the SASL user and delete it with the User", func() {
+			u := newUser("created-user", key.Namespace)
+			uk := client.ObjectKeyFromObject(u)
+			Expect(k8sClient.Create(ctx, u)).Should(Succeed())
+
+			By("Creating the SASL user with the generated password")
+			Eventually(userReadyCondition(uk), timeout, interval).Should(HaveField("Status", metav1.ConditionTrue))
+			var secret corev1.Secret
+			Expect(k8sClient.Get(ctx, uk, &secret)).Should(Succeed())
+			got, exists := testAdminAPI.GetUser("created-user")
+			Expect(exists).To(BeTrue())
+			Expect(got).To(Equal(adminutils.MockUser{
+				Password:  string(secret.Data[corev1.BasicAuthPasswordKey]),
+				Mechanism: string(vectorizedv1alpha1.UserMechanismScramSha256),
+			}))
+			Expect(k8sClient.Get(ctx, uk, u)).Should(Succeed())
+			Expect(u.Status.Created).To(BeTrue())
+
+			By("Deleting the SASL user with the User")
+			Expect(k8sClient.Delete(ctx, u)).Should(Succeed())
+			Eventually(userDeleted(uk), timeout, interval).Should(BeTrue())
+			_, exists = testAdminAPI.GetUser("created-user")
+			Expect(exists).To(BeFalse())
+		})
+
+		It("Should not take over an existing SASL user", func() {
+			existing := adminutils.MockUser{Password: "secret", Mechanism: string(vectorizedv1alpha1.UserMechanismScramSha256)}
+			testAdminAPI.SetUser("existing-user", existing)
+			u := newUser("existing-user", key.Namespace)
+			uk := client.ObjectKeyFromObject(u)
+			Expect(k8sClient.Create(ctx, u)).Should(Succeed())
+
+			Eventually(userReadyCondition(uk), timeout, interval).Should(And(
+				HaveField("Status", metav1.ConditionFalse),
+				HaveField("Reason", "UserExists"),
+			))
+			got, _ := testAdminAPI.GetUser("existing-user")
+			Expect(got).To(Equal(existing))
+
+			By("Keeping the SASL user when the User is deleted")
+			Expect(k8sClient.Delete(ctx, u)).Should(Succeed())
+			Eventually(userDeleted(uk), timeout, interval).Should(BeTrue())
+			got, _ = testAdminAPI.GetUser("existing-user")
+			Expect(got).To(Equal(existing))
+		})
+
+		It("Should refuse a Cluster in another namespace", func() {
+			other := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: key.Namespace + "-users"}}
+			Expect(k8sClient.Create(ctx, other)).Should(Succeed())
+			u := newUser("foreign-user", other.Name)
+			uk := client.ObjectKeyFromObject(u)
+			Expect(k8sClient.Create(ctx, u)).Should(Succeed())
+
+			Eventually(userReadyCondition(uk), timeout, interval).Should(And(
+				HaveField("Status", metav1.ConditionFalse),
+				HaveField("Reason", "ClusterRefNamespace"),
+			))
+			_, exists := testAdminAPI.GetUser("foreign-user")
+			Expect(exists).To(BeFalse())
+		})
+	})
+})
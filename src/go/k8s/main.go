@@ -39,8 +39,11 @@ import (
 	redpandav1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/redpanda/v1alpha1"
 	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
 	redpandacontrollers "github.com/redpanda-data/redpanda/src/go/k8s/controllers/redpanda"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/acl"
 	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
 	consolepkg "github.com/redpanda-data/redpanda/src/go/k8s/pkg/console"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/schema"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/topic"
 	redpandawebhooks "github.com/redpanda-data/redpanda/src/go/k8s/webhooks/redpanda"
 )
 
@@ -271,6 +274,7 @@ func main() {
 			Client:                    mgr.GetClient(),
 			Log:                       ctrl.Log.WithName("controllers").WithName("redpanda").WithName("ClusterConfigurationDrift"),
 			Scheme:                    mgr.GetScheme(),
+			EventRecorder:             mgr.GetEventRecorderFor("ClusterConfigurationDrift"),
 			AdminAPIClientFactory:     adminutils.NewInternalAdminAPI,
 			RestrictToRedpandaVersion: restrictToRedpandaVersion,
 		}).WithClusterDomain(clusterDomain).SetupWithManager(mgr); err != nil {
@@ -297,6 +301,64 @@ func main() {
 			os.Exit(1)
 		}
 
+		if err = (&redpandacontrollers.TopicReconciler{
+			Client:                  mgr.GetClient(),
+			Scheme:                  mgr.GetScheme(),
+			Log:                     ctrl.Log.WithName("controllers").WithName("redpanda").WithName("Topic"),
+			Store:                   consolepkg.NewStore(mgr.GetClient(), mgr.GetScheme()),
+			EventRecorder:           mgr.GetEventRecorderFor("Topic"),
+			KafkaAdminClientFactory: topic.NewAdminClient,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Topic")
+			os.Exit(1)
+		}
+
+		if err = (&redpandacontrollers.UserReconciler{
+			Client:                mgr.GetClient(),
+			Scheme:                mgr.GetScheme(),
+			Log:                   ctrl.Log.WithName("controllers").WithName("redpanda").WithName("User"),
+			EventRecorder:         mgr.GetEventRecorderFor("User"),
+			AdminAPIClientFactory: adminutils.NewInternalAdminAPI,
+		}).WithClusterDomain(clusterDomain).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "User")
+			os.Exit(1)
+		}
+
+		if err = (&redpandacontrollers.ACLReconciler{
+			Client:                  mgr.GetClient(),
+			Scheme:                  mgr.GetScheme(),
+			Log:                     ctrl.Log.WithName("controllers").WithName("redpanda").WithName("ACL"),
+			Store:                   consolepkg.NewStore(mgr.GetClient(), mgr.GetScheme()),
+			EventRecorder:           mgr.GetEventRecorderFor("ACL"),
+			KafkaAdminClientFactory: acl.NewClient,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ACL")
+			os.Exit(1)
+		}
+
+		if err = (&redpandacontrollers.SchemaReconciler{
+			Client:          mgr.GetClient(),
+			Scheme:          mgr.GetScheme(),
+			Log:             ctrl.Log.WithName("controllers").WithName("redpanda").WithName("Schema"),
+			Store:           consolepkg.NewStore(mgr.GetClient(), mgr.GetScheme()),
+			EventRecorder:   mgr.GetEventRecorderFor("Schema"),
+			RegistryFactory: schema.NewRegistry,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Schema")
+			os.Exit(1)
+		}
+
+		if err = (&redpandacontrollers.TopicRecoveryReconciler{
+			Client:                mgr.GetClient(),
+			Scheme:                mgr.GetScheme(),
+			Log:                   ctrl.Log.WithName("controllers").WithName("redpanda").WithName("TopicRecovery"),
+			EventRecorder:         mgr.GetEventRecorderFor("TopicRecovery"),
+			AdminAPIClientFactory: adminutils.NewInternalAdminAPI,
+		}).WithClusterDomain(clusterDomain).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "TopicRecovery")
+			os.Exit(1)
+		}
+
 		// Setup webhooks
 		if webhookEnabled {
 			setupLog.Info("Setup webhook")
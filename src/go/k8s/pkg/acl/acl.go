@@ -0,0 +1,330 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package acl reconciles Kafka ACLs with ACL custom resources
+package acl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	consolepkg "github.com/redpanda-data/redpanda/src/go/k8s/pkg/console"
+)
+
+// ACLFinalizer is the finalizer for deleting the Kafka ACLs
+const ACLFinalizer = "acls.redpanda.vectorized.io/finalizer"
+
+// clusterName is the resource name of the cluster resource type
+const clusterName = "kafka-cluster"
+
+type (
+	// Client manages Kafka ACLs
+	Client interface {
+		// Describe returns the ACLs of the principals
+		Describe(ctx context.Context, principals ...string) ([]Entry, error)
+		Create(ctx context.Context, e Entry) error
+		Delete(ctx context.Context, e Entry) error
+	}
+
+	// ClientFactory returns a Client
+	ClientFactory func(context.Context, client.Client, *vectorizedv1alpha1.Cluster, *consolepkg.Store) (Client, error)
+)
+
+// NewClient returns a Client using a Kafka admin client for the Cluster
+func NewClient(
+	ctx context.Context,
+	cl client.Client,
+	cluster *vectorizedv1alpha1.Cluster,
+	store *consolepkg.Store,
+) (Client, error) {
+	adm, err := consolepkg.NewKadmClient(ctx, cl, cluster, store)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaClient{adm}, nil
+}
+
+// Entry is a single Kafka ACL
+type Entry struct {
+	Principal  string
+	Host       string
+	Type       kmsg.ACLResourceType
+	Name       string
+	Pattern    kadm.ACLPattern
+	Operation  kadm.ACLOperation
+	Permission kmsg.ACLPermissionType
+}
+
+func (e Entry) String() string {
+	return strings.ToLower(fmt.Sprintf("%s %s on %s %s ", e.Permission, e.Operation, e.Pattern, e.Type)) +
+		fmt.Sprintf("%q for %s from %s", e.Name, e.Principal, e.Host)
+}
+
+var (
+	resourceTypes = map[vectorizedv1alpha1.ACLResourceType]kmsg.ACLResourceType{
+		vectorizedv1alpha1.ACLResourceTypeTopic:           kmsg.ACLResourceTypeTopic,
+		vectorizedv1alpha1.ACLResourceTypeGroup:           kmsg.ACLResourceTypeGroup,
+		vectorizedv1alpha1.ACLResourceTypeCluster:         kmsg.ACLResourceTypeCluster,
+		vectorizedv1alpha1.ACLResourceTypeTransactionalID: kmsg.ACLResourceTypeTransactionalId,
+	}
+
+	operations = map[vectorizedv1alpha1.ACLOperation]kadm.ACLOperation{
+		"All":             kadm.OpAll,
+		"Read":            kadm.OpRead,
+		"Write":           kadm.OpWrite,
+		"Create":          kadm.OpCreate,
+		"Delete":          kadm.OpDelete,
+		"Alter":           kadm.OpAlter,
+		"Describe":        kadm.OpDescribe,
+		"ClusterAction":   kadm.OpClusterAction,
+		"DescribeConfigs": kadm.OpDescribeConfigs,
+		"AlterConfigs":    kadm.OpAlterConfigs,
+		"IdempotentWrite": kadm.OpIdempotentWrite,
+	}
+)
+
+// Entries expands the rules of the principal into Kafka ACLs
+func Entries(principal string, rules []vectorizedv1alpha1.ACLRule) ([]Entry, error) {
+	var entries []Entry
+	for i, rule := range rules {
+		typ, ok := resourceTypes[rule.ResourceType]
+		if !ok {
+			return nil, fmt.Errorf("rule %d: unknown resource type %q", i, rule.ResourceType)
+		}
+		name := rule.ResourceName
+		switch {
+		case typ == kmsg.ACLResourceTypeCluster:
+			name = clusterName
+		case name == "":
+			return nil, fmt.Errorf("rule %d: resource name is required for resource type %s", i, rule.ResourceType)
+		}
+		pattern := kadm.ACLPatternLiteral
+		if rule.PatternType == vectorizedv1alpha1.ACLPatternTypePrefixed {
+			pattern = kadm.ACLPatternPrefixed
+		}
+		permission := kmsg.ACLPermissionTypeAllow
+		if rule.Permission == vectorizedv1alpha1.ACLPermissionDeny {
+			permission = kmsg.ACLPermissionTypeDeny
+		}
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+		for _, o := range rule.Operations {
+			op, ok := operations[o]
+			if !ok {
+				return nil, fmt.Errorf("rule %d: unknown operation %q", i, o)
+			}
+			entries = append(entries, Entry{
+				Principal:  principal,
+				Host:       host,
+				Type:       typ,
+				Name:       name,
+				Pattern:    pattern,
+				Operation:  op,
+				Permission: permission,
+			})
+		}
+	}
+	return entries, nil
+}
+
+// Result describes the ACLs changed by Reconcile
+type Result struct {
+	// Created are the ACLs that were created
+	Created []string
+	// Restored are previously applied ACLs that were missing and were created
+	// again
+	Restored []string
+	// Deleted are previously applied ACLs that are no longer wanted
+	Deleted []string
+}
+
+// Reconcile creates the wanted ACLs that are missing and deletes the applied
+// ACLs that are no longer wanted. ACLs of the principals that were not applied
+// by the operator are left untouched.
+func Reconcile(ctx context.Context, cl Client, want, applied []Entry) (Result, error) {
+	var res Result
+
+	existing, err := describe(ctx, cl, append(append([]Entry{}, want...), applied...))
+	if err != nil {
+		return res, err
+	}
+	wanted := make(map[Entry]bool, len(want))
+	for _, e := range want {
+		wanted[e] = true
+	}
+	wasApplied := make(map[Entry]bool, len(applied))
+	for _, e := range applied {
+		wasApplied[e] = true
+	}
+
+	for _, e := range applied {
+		if wanted[e] || !existing[e] {
+			continue
+		}
+		if err := cl.Delete(ctx, e); err != nil {
+			return res, err
+		}
+		res.Deleted = append(res.Deleted, e.String())
+	}
+	for e := range wanted {
+		if existing[e] {
+			continue
+		}
+		if err := cl.Create(ctx, e); err != nil {
+			return res, err
+		}
+		if wasApplied[e] {
+			res.Restored = append(res.Restored, e.String())
+		} else {
+			res.Created = append(res.Created, e.String())
+		}
+	}
+	sort.Strings(res.Created)
+	sort.Strings(res.Restored)
+	sort.Strings(res.Deleted)
+	return res, nil
+}
+
+// Delete deletes the applied ACLs
+func Delete(ctx context.Context, cl Client, applied []Entry) error {
+	for _, e := range applied {
+		if err := cl.Delete(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describe returns the existing ACLs of the principals of the entries
+func describe(ctx context.Context, cl Client, entries []Entry) (map[Entry]bool, error) {
+	existing := make(map[Entry]bool)
+	seen := make(map[string]bool)
+	var principals []string
+	for _, e := range entries {
+		if !seen[e.Principal] {
+			seen[e.Principal] = true
+			principals = append(principals, e.Principal)
+		}
+	}
+	if len(principals) == 0 {
+		return existing, nil
+	}
+	described, err := cl.Describe(ctx, principals...)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range described {
+		existing[e] = true
+	}
+	return existing, nil
+}
+
+// kafkaAdmin contains functions from kadm.Client used to manage ACLs
+type kafkaAdmin interface {
+	CreateACLs(context.Context, *kadm.ACLBuilder) (kadm.CreateACLsResults, error)
+	DeleteACLs(context.Context, *kadm.ACLBuilder) (kadm.DeleteACLsResults, error)
+	DescribeACLs(context.Context, *kadm.ACLBuilder) (kadm.DescribeACLsResults, error)
+}
+
+// kafkaClient is a Client using the Kafka ACL APIs
+type kafkaClient struct {
+	adm kafkaAdmin
+}
+
+func (k *kafkaClient) Describe(ctx context.Context, principals ...string) ([]Entry, error) {
+	b := kadm.NewACLs().
+		Allow(principals...).AllowHosts().
+		Deny(principals...).DenyHosts().
+		AnyResource().Operations(kadm.OpAny).
+		ResourcePatternType(kadm.ACLPatternAny)
+	results, err := k.adm.DescribeACLs(ctx, b)
+	if err != nil {
+		return nil, fmt.Errorf("describing ACLs: %w", err)
+	}
+	var entries []Entry
+	for _, r := range results {
+		if r.Err != nil {
+			return nil, fmt.Errorf("describing ACLs: %w", r.Err)
+		}
+		for _, d := range r.Described {
+			entries = append(entries, Entry{
+				Principal:  d.Principal,
+				Host:       d.Host,
+				Type:       d.Type,
+				Name:       d.Name,
+				Pattern:    d.Pattern,
+				Operation:  d.Operation,
+				Permission: d.Permission,
+			})
+		}
+	}
+	return entries, nil
+}
+
+func (k *kafkaClient) Create(ctx context.Context, e Entry) error {
+	results, err := k.adm.CreateACLs(ctx, builder(e))
+	if err == nil {
+		for _, r := range results {
+			if r.Err != nil {
+				err = r.Err
+				break
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("creating ACL %s: %w", e, err)
+	}
+	return nil
+}
+
+func (k *kafkaClient) Delete(ctx context.Context, e Entry) error {
+	results, err := k.adm.DeleteACLs(ctx, builder(e))
+	if err == nil {
+		for _, r := range results {
+			if r.Err != nil {
+				err = r.Err
+				break
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("deleting ACL %s: %w", e, err)
+	}
+	return nil
+}
+
+// builder returns an ACL builder matching exactly the entry
+func builder(e Entry) *kadm.ACLBuilder {
+	b := kadm.NewACLs().Operations(e.Operation).ResourcePatternType(e.Pattern)
+	if e.Permission == kmsg.ACLPermissionTypeDeny {
+		b.Deny(e.Principal).DenyHosts(e.Host)
+	} else {
+		b.Allow(e.Principal).AllowHosts(e.Host)
+	}
+	switch e.Type { //nolint:exhaustive // only the types of Entries are used
+	case kmsg.ACLResourceTypeTopic:
+		b.Topics(e.Name)
+	case kmsg.ACLResourceTypeGroup:
+		b.Groups(e.Name)
+	case kmsg.ACLResourceTypeCluster:
+		b.Clusters()
+	case kmsg.ACLResourceTypeTransactionalId:
+		b.TransactionalIDs(e.Name)
+	}
+	return b
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package acl_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/acl"
+)
+
+func TestEntries(t *testing.T) {
+	entries, err := acl.Entries("User:alice", []vectorizedv1alpha1.ACLRule{
+		{
+			ResourceType: vectorizedv1alpha1.ACLResourceTypeTopic,
+			ResourceName: "orders-",
+			PatternType:  vectorizedv1alpha1.ACLPatternTypePrefixed,
+			Operations:   []vectorizedv1alpha1.ACLOperation{"Read", "Describe"},
+		},
+		{
+			ResourceType: vectorizedv1alpha1.ACLResourceTypeCluster,
+			Operations:   []vectorizedv1alpha1.ACLOperation{"IdempotentWrite"},
+			Permission:   vectorizedv1alpha1.ACLPermissionDeny,
+			Host:         "10.0.0.1",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []acl.Entry{
+		{Principal: "User:alice", Host: "*", Type: kmsg.ACLResourceTypeTopic, Name: "orders-", Pattern: kadm.ACLPatternPrefixed, Operation: kadm.OpRead, Permission: kmsg.ACLPermissionTypeAllow},
+		{Principal: "User:alice", Host: "*", Type: kmsg.ACLResourceTypeTopic, Name: "orders-", Pattern: kadm.ACLPatternPrefixed, Operation: kadm.OpDescribe, Permission: kmsg.ACLPermissionTypeAllow},
+		{Principal: "User:alice", Host: "10.0.0.1", Type: kmsg.ACLResourceTypeCluster, Name: "kafka-cluster", Pattern: kadm.ACLPatternLiteral, Operation: kadm.OpIdempotentWrite, Permission: kmsg.ACLPermissionTypeDeny},
+	}, entries)
+	assert.Equal(t, `allow read on prefixed topic "orders-" for User:alice from *`, entries[0].String())
+
+	_, err = acl.Entries("User:alice", []vectorizedv1alpha1.ACLRule{{
+		ResourceType: vectorizedv1alpha1.ACLResourceTypeGroup,
+		Operations:   []vectorizedv1alpha1.ACLOperation{"Read"},
+	}})
+	assert.Error(t, err)
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	read := acl.Entry{Principal: "User:alice", Host: "*", Type: kmsg.ACLResourceTypeTopic, Name: "foo", Pattern: kadm.ACLPatternLiteral, Operation: kadm.OpRead, Permission: kmsg.ACLPermissionTypeAllow}
+	write := read
+	write.Operation = kadm.OpWrite
+	other := read
+	other.Name = "bar"
+	cl := &acl.MockClient{}
+	require.NoError(t, cl.Create(ctx, other))
+
+	res, err := acl.Reconcile(ctx, cl, []acl.Entry{read, write}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{read.String(), write.String()}, res.Created)
+	assert.Equal(t, map[acl.Entry]bool{read: true, write: true, other: true}, cl.ACLs())
+
+	// ACLs deleted out of band are restored
+	require.NoError(t, cl.Delete(ctx, write))
+	res, err = acl.Reconcile(ctx, cl, []acl.Entry{read, write}, []acl.Entry{read, write})
+	require.NoError(t, err)
+	assert.Empty(t, res.Created)
+	assert.Equal(t, []string{write.String()}, res.Restored)
+
+	// Only applied ACLs that are no longer wanted are deleted
+	res, err = acl.Reconcile(ctx, cl, []acl.Entry{read}, []acl.Entry{read, write})
+	require.NoError(t, err)
+	assert.Equal(t, []string{write.String()}, res.Deleted)
+	assert.Equal(t, map[acl.Entry]bool{read: true, other: true}, cl.ACLs())
+
+	require.NoError(t, acl.Delete(ctx, cl, []acl.Entry{read}))
+	assert.Equal(t, map[acl.Entry]bool{other: true}, cl.ACLs())
+}
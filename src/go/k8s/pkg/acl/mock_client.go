@@ -0,0 +1,74 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package acl
+
+import (
+	"context"
+	"sync"
+)
+
+// MockClient is an in-memory Client for tests
+type MockClient struct {
+	monitor sync.Mutex
+	acls    map[Entry]bool
+}
+
+var _ Client = &MockClient{}
+
+// ACLs returns a copy of the ACLs
+func (m *MockClient) ACLs() map[Entry]bool {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	res := make(map[Entry]bool, len(m.acls))
+	for e := range m.acls {
+		res[e] = true
+	}
+	return res
+}
+
+// Clear removes all ACLs
+func (m *MockClient) Clear() {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	m.acls = nil
+}
+
+func (m *MockClient) Describe(
+	_ context.Context, principals ...string,
+) ([]Entry, error) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	var entries []Entry
+	for e := range m.acls {
+		for _, p := range principals {
+			if e.Principal == p {
+				entries = append(entries, e)
+			}
+		}
+	}
+	return entries, nil
+}
+
+func (m *MockClient) Create(_ context.Context, e Entry) error {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	if m.acls == nil {
+		m.acls = make(map[Entry]bool)
+	}
+	m.acls[e] = true
+	return nil
+}
+
+func (m *MockClient) Delete(_ context.Context, e Entry) error {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	delete(m.acls, e)
+	return nil
+}
@@ -105,6 +105,9 @@ type AdminAPIClient interface {
 	DisableMaintenanceMode(ctx context.Context, node int, useLeaderNode bool) error
 
 	GetHealthOverview(ctx context.Context) (admin.ClusterHealthOverview, error)
+
+	StartAutomatedRecovery(ctx context.Context, topicNamesPattern string) (admin.RecoveryStartResponse, error)
+	PollAutomatedRecoveryStatus(ctx context.Context) (*admin.TopicRecoveryStatus, error)
 }
 
 var _ AdminAPIClient = &admin.AdminAPI{}
@@ -38,6 +38,14 @@ type MockAdminAPI struct {
 	Log               logr.Logger
 	clusterHealth     bool
 	MaintenanceStatus *admin.MaintenanceStatus
+	recoveryStatus    *admin.TopicRecoveryStatus
+	users             map[string]MockUser
+}
+
+// MockUser is a SASL user held by MockAdminAPI
+type MockUser struct {
+	Password  string
+	Mechanism string
 }
 
 var _ AdminAPIClient = &MockAdminAPI{Log: ctrl.Log.WithName("AdminAPIClient").WithName("mockAdminAPI")}
@@ -162,23 +170,32 @@ func (m *MockAdminAPI) PatchClusterConfig(
 	return admin.ClusterConfigWriteResult{}, nil
 }
 
-func (m *MockAdminAPI) CreateUser(_ context.Context, _, _, _ string) error {
+func (m *MockAdminAPI) CreateUser(
+	_ context.Context, username, password, mechanism string,
+) error {
 	m.Log.WithName("CreateUser").Info("called")
 	m.monitor.Lock()
 	defer m.monitor.Unlock()
 	if m.unavailable {
 		return &unavailableError{}
 	}
+	if _, ok := m.users[username]; ok {
+		return fmt.Errorf("creating user: User already exists")
+	}
+	m.setUser(username, MockUser{Password: password, Mechanism: mechanism})
 	return nil
 }
 
-func (m *MockAdminAPI) UpdateUser(_ context.Context, _, _, _ string) error {
+func (m *MockAdminAPI) UpdateUser(
+	_ context.Context, username, password, mechanism string,
+) error {
 	m.Log.WithName("UpdateUser").Info("called")
 	m.monitor.Lock()
 	defer m.monitor.Unlock()
 	if m.unavailable {
 		return &unavailableError{}
 	}
+	m.setUser(username, MockUser{Password: password, Mechanism: mechanism})
 	return nil
 }
 
@@ -190,19 +207,46 @@ func (m *MockAdminAPI) ListUsers(_ context.Context) ([]string, error) {
 	if m.unavailable {
 		return users, &unavailableError{}
 	}
+	for u := range m.users {
+		users = append(users, u)
+	}
+	sort.Strings(users)
 	return users, nil
 }
 
-func (m *MockAdminAPI) DeleteUser(_ context.Context, _ string) error {
+func (m *MockAdminAPI) DeleteUser(_ context.Context, username string) error {
 	m.Log.WithName("DeleteUser").Info("called")
 	m.monitor.Lock()
 	defer m.monitor.Unlock()
 	if m.unavailable {
 		return &unavailableError{}
 	}
+	delete(m.users, username)
 	return nil
 }
 
+// SetUser creates or replaces a SASL user
+func (m *MockAdminAPI) SetUser(username string, user MockUser) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	m.setUser(username, user)
+}
+
+// GetUser returns a SASL user and whether it exists
+func (m *MockAdminAPI) GetUser(username string) (MockUser, bool) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	user, ok := m.users[username]
+	return user, ok
+}
+
+func (m *MockAdminAPI) setUser(username string, user MockUser) {
+	if m.users == nil {
+		m.users = make(map[string]MockUser)
+	}
+	m.users[username] = user
+}
+
 func (m *MockAdminAPI) Clear() {
 	m.Log.WithName("Clear").Info("called")
 	m.monitor.Lock()
@@ -217,6 +261,8 @@ func (m *MockAdminAPI) Clear() {
 	m.brokers = nil
 	m.clusterHealth = true
 	m.MaintenanceStatus = &admin.MaintenanceStatus{}
+	m.recoveryStatus = nil
+	m.users = nil
 }
 
 func (m *MockAdminAPI) GetFeatures(
@@ -434,6 +480,40 @@ func (m *MockAdminAPI) GetHealthOverview(_ context.Context) (admin.ClusterHealth
 	}, nil
 }
 
+func (m *MockAdminAPI) StartAutomatedRecovery(
+	_ context.Context, topicNamesPattern string,
+) (admin.RecoveryStartResponse, error) {
+	m.Log.WithName("StartAutomatedRecovery").WithValues("pattern", topicNamesPattern).Info("called")
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	m.recoveryStatus = &admin.TopicRecoveryStatus{
+		State:           "recovering_data",
+		RecoveryRequest: admin.RecoveryRequestParams{TopicNamesPattern: topicNamesPattern},
+	}
+	return admin.RecoveryStartResponse{Code: http.StatusOK, Message: "Automated recovery started"}, nil
+}
+
+func (m *MockAdminAPI) PollAutomatedRecoveryStatus(
+	_ context.Context,
+) (*admin.TopicRecoveryStatus, error) {
+	m.Log.WithName("PollAutomatedRecoveryStatus").Info("called")
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	if m.recoveryStatus == nil {
+		return &admin.TopicRecoveryStatus{State: "inactive"}, nil
+	}
+	var res admin.TopicRecoveryStatus
+	makeCopy(m.recoveryStatus, &res)
+	return &res, nil
+}
+
+// SetRecoveryStatus sets the status of the automated recovery
+func (m *MockAdminAPI) SetRecoveryStatus(status admin.TopicRecoveryStatus) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	m.recoveryStatus = &status
+}
+
 //nolint:goerr113 // test code
 func (m *MockAdminAPI) SetBrokerStatus(
 	id int, status admin.MembershipStatus,
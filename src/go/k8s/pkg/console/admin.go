@@ -6,8 +6,11 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/httpapi"
 	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kgo"
 	"github.com/twmb/franz-go/pkg/sasl/scram"
@@ -21,6 +24,8 @@ import (
 	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/certmanager"
 )
 
+const schemaRegistryClientTimeout = 10 * time.Second
+
 // NewAdminAPI create an Admin API client
 func NewAdminAPI(
 	ctx context.Context,
@@ -66,6 +71,19 @@ func NewKafkaAdmin(
 	cluster *vectorizedv1alpha1.Cluster,
 	store *Store,
 ) (KafkaAdminClient, error) {
+	return NewKadmClient(ctx, cl, cluster, store)
+}
+
+// NewKadmClient creates a franz-go admin client for the internal Kafka
+// listener of the Cluster, authenticating as the Cluster superuser. The client
+// is closed when ctx is done. The store must have the Kafka certificates of
+// the Cluster synced, see Store.SyncKafka.
+func NewKadmClient(
+	ctx context.Context,
+	cl client.Client,
+	cluster *vectorizedv1alpha1.Cluster,
+	store *Store,
+) (*kadm.Client, error) {
 	opts := []kgo.Opt{kgo.SeedBrokers(getBrokers(cluster)...)}
 	if cluster.IsSASLOnInternalEnabled() {
 		sasl, err := getSASLOpt(ctx, cl, cluster)
@@ -94,11 +112,65 @@ func NewKafkaAdmin(
 	return admClient, nil
 }
 
+// NewSchemaRegistryClient creates an HTTP client for the Schema Registry of
+// the Cluster, authenticating as the Cluster superuser when HTTP basic
+// authentication is enabled. The store must have the Schema Registry
+// certificates of the Cluster synced, see Store.SyncSchemaRegistry.
+func NewSchemaRegistryClient(
+	ctx context.Context,
+	cl client.Client,
+	cluster *vectorizedv1alpha1.Cluster,
+	store *Store,
+) (*httpapi.Client, error) {
+	opts := []httpapi.Opt{httpapi.Host(cluster.SchemaRegistryAPIURL())}
+	if cluster.IsSchemaRegistryAuthHTTPBasic() {
+		user, pass, err := superuserCredentials(ctx, cl, cluster)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, httpapi.BasicAuth(user, pass))
+	}
+	if cluster.IsSchemaRegistryTLSEnabled() {
+		config := &tls.Config{MinVersion: tls.VersionTLS12}
+		if cluster.IsSchemaRegistryMutualTLSEnabled() {
+			keypair, err := clientCert(store.GetSchemaRegistryClientCert(cluster))
+			if err != nil {
+				return nil, fmt.Errorf("getting client certificate: %w", err)
+			}
+			config.Certificates = keypair
+		}
+		ca := &SecretTLSCa{NodeSecretRef: cluster.SchemaRegistryAPITLS().TLS.NodeSecretRef}
+		if ca.useCaCert() {
+			rootca, err := caCert(store.GetSchemaRegistryNodeCert(cluster))
+			if err != nil {
+				return nil, fmt.Errorf("getting root certificate: %w", err)
+			}
+			config.RootCAs = rootca
+		}
+		opts = append(opts, httpapi.HTTPClient(&http.Client{
+			Timeout:   schemaRegistryClientTimeout,
+			Transport: &http.Transport{TLSClientConfig: config},
+		}))
+	}
+	return httpapi.NewClient(opts...), nil
+}
+
 func getSASLOpt(
 	ctx context.Context, cl client.Client, cluster *vectorizedv1alpha1.Cluster,
 ) (kgo.Opt, error) {
 	// Use Cluster superuser to manage Kafka
 	// Console Kafka Service Account can't add ACLs to itself
+	user, pass, err := superuserCredentials(ctx, cl, cluster)
+	if err != nil {
+		return nil, err
+	}
+	mech := scram.Auth{User: user, Pass: pass}
+	return kgo.SASL(mech.AsSha256Mechanism()), nil
+}
+
+func superuserCredentials(
+	ctx context.Context, cl client.Client, cluster *vectorizedv1alpha1.Cluster,
+) (user, pass string, err error) {
 	su := vectorizedv1alpha1.SecretKeyRef{
 		Namespace: cluster.GetNamespace(),
 		Name:      fmt.Sprintf("%s-superuser", cluster.GetName()),
@@ -106,25 +178,23 @@ func getSASLOpt(
 
 	secret, err := su.GetSecret(ctx, cl)
 	if err != nil {
-		return nil, err
+		return "", "", err
 	}
-	user, err := su.GetValue(secret, corev1.BasicAuthUsernameKey)
+	u, err := su.GetValue(secret, corev1.BasicAuthUsernameKey)
 	if err != nil {
-		return nil, err
+		return "", "", err
 	}
-	pass, err := su.GetValue(secret, corev1.BasicAuthPasswordKey)
+	p, err := su.GetValue(secret, corev1.BasicAuthPasswordKey)
 	if err != nil {
-		return nil, err
+		return "", "", err
 	}
-
-	mech := scram.Auth{User: string(user), Pass: string(pass)}
-	return kgo.SASL(mech.AsSha256Mechanism()), nil
+	return string(u), string(p), nil
 }
 
 func getTLSConfigOpt(
 	cluster *vectorizedv1alpha1.Cluster, store *Store,
 ) (kgo.Opt, error) {
-	keypair, err := clientCert(cluster, store)
+	keypair, err := clientCert(store.GetKafkaClientCert(cluster))
 	if err != nil {
 		return nil, fmt.Errorf("getting client certificate: %w", err)
 	}
@@ -137,7 +207,7 @@ func getTLSConfigOpt(
 
 	ca := &SecretTLSCa{NodeSecretRef: cluster.KafkaListener().TLS.NodeSecretRef}
 	if ca.useCaCert() {
-		rootca, err := caCert(cluster, store)
+		rootca, err := caCert(store.GetKafkaNodeCert(cluster))
 		if err != nil {
 			return nil, fmt.Errorf("getting root certificate: %w", err)
 		}
@@ -148,10 +218,9 @@ func getTLSConfigOpt(
 }
 
 func clientCert(
-	cluster *vectorizedv1alpha1.Cluster, store *Store,
+	secret *corev1.Secret, exists bool,
 ) ([]tls.Certificate, error) {
 	clientcert := vectorizedv1alpha1.SecretKeyRef{}
-	secret, exists := store.GetKafkaClientCert(cluster)
 	if !exists {
 		return nil, fmt.Errorf("not in store") //nolint:goerr113 // no need to declare new error type
 	}
@@ -173,10 +242,9 @@ func clientCert(
 }
 
 func caCert(
-	cluster *vectorizedv1alpha1.Cluster, store *Store,
+	secret *corev1.Secret, exists bool,
 ) (*x509.CertPool, error) {
 	rootca := vectorizedv1alpha1.SecretKeyRef{}
-	secret, exists := store.GetKafkaNodeCert(cluster)
 	if !exists {
 		return nil, fmt.Errorf("not in store") //nolint:goerr113 // no need to declare new error type
 	}
@@ -54,7 +54,7 @@ func (s *Store) Sync(
 	if cluster == nil {
 		return nil
 	}
-	if err := s.syncSchemaRegistry(ctx, cluster, console); err != nil {
+	if err := s.syncSchemaRegistry(ctx, cluster, console.Spec.SchemaRegistry.UseSchemaRegistryCA); err != nil {
 		return err
 	}
 	if err := s.syncKafka(ctx, cluster); err != nil {
@@ -66,8 +66,16 @@ func (s *Store) Sync(
 	return nil
 }
 
+// SyncSchemaRegistry synchronizes the Schema Registry certificates of the
+// Cluster to the store, which is all that NewSchemaRegistryClient needs
+func (s *Store) SyncSchemaRegistry(
+	ctx context.Context, cluster *vectorizedv1alpha1.Cluster,
+) error {
+	return s.syncSchemaRegistry(ctx, cluster, true)
+}
+
 func (s *Store) syncSchemaRegistry(
-	ctx context.Context, cluster *vectorizedv1alpha1.Cluster, console *vectorizedv1alpha1.Console,
+	ctx context.Context, cluster *vectorizedv1alpha1.Cluster, useSchemaRegistryCA bool,
 ) error {
 	if cluster == nil {
 		return nil
@@ -93,7 +101,7 @@ func (s *Store) syncSchemaRegistry(
 	// Only sync CA cert if not using DefaultCaFilePath
 	ca := &SecretTLSCa{
 		NodeSecretRef:  cluster.SchemaRegistryAPITLS().TLS.NodeSecretRef,
-		UsePublicCerts: !console.Spec.SchemaRegistry.UseSchemaRegistryCA,
+		UsePublicCerts: !useSchemaRegistryCA,
 	}
 	if ca.useCaCert() {
 		nodeSecretRef := cluster.SchemaRegistryAPITLS().TLS.NodeSecretRef
@@ -111,6 +119,14 @@ func (s *Store) syncSchemaRegistry(
 	return nil
 }
 
+// SyncKafka synchronizes the Kafka certificates of the Cluster to the store,
+// which is all that NewKadmClient needs
+func (s *Store) SyncKafka(
+	ctx context.Context, cluster *vectorizedv1alpha1.Cluster,
+) error {
+	return s.syncKafka(ctx, cluster)
+}
+
 func (s *Store) syncKafka(
 	ctx context.Context, cluster *vectorizedv1alpha1.Cluster,
 ) error {
@@ -0,0 +1,94 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package recovery recovers topics from cloud storage with TopicRecovery
+// custom resources
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+)
+
+// StateInactive is the state reported by Redpanda when no recovery is
+// running
+const StateInactive = "inactive"
+
+// AdminClient contains functions from the admin API used to recover topics
+type AdminClient interface {
+	StartAutomatedRecovery(ctx context.Context, topicNamesPattern string) (admin.RecoveryStartResponse, error)
+	PollAutomatedRecoveryStatus(ctx context.Context) (*admin.TopicRecoveryStatus, error)
+}
+
+// Start starts the recovery of the topics matching the pattern. It returns
+// false when a recovery of other topics is running, in which case the
+// recovery must be started again later. A running recovery of the same
+// topics is considered as started.
+func Start(ctx context.Context, adm AdminClient, pattern string) (bool, error) {
+	status, err := adm.PollAutomatedRecoveryStatus(ctx)
+	if err != nil {
+		return false, fmt.Errorf("getting recovery status: %w", err)
+	}
+	if status.State != StateInactive {
+		return status.RecoveryRequest.TopicNamesPattern == pattern, nil
+	}
+	if _, err := adm.StartAutomatedRecovery(ctx, pattern); err != nil {
+		return false, fmt.Errorf("starting recovery of topics %q: %w", pattern, err)
+	}
+	return true, nil
+}
+
+// Progress is the progress of a running recovery
+type Progress struct {
+	// State is the state reported by Redpanda
+	State string
+	// Done is true once the recovery is no longer running
+	Done bool
+	// Topics is the progress of the recovery of each topic, sorted by name
+	Topics []vectorizedv1alpha1.TopicRecoveryProgress
+}
+
+// Failed tells if some partitions failed to be downloaded
+func (p *Progress) Failed() bool {
+	for _, t := range p.Topics {
+		if t.FailedDownloads > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Poll returns the progress of the recovery
+func Poll(ctx context.Context, adm AdminClient) (*Progress, error) {
+	status, err := adm.PollAutomatedRecoveryStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting recovery status: %w", err)
+	}
+	p := &Progress{
+		State: status.State,
+		Done:  status.State == StateInactive,
+	}
+	for _, d := range status.TopicDownloads {
+		p.Topics = append(p.Topics, vectorizedv1alpha1.TopicRecoveryProgress{
+			Topic:               d.TopicNamespace,
+			PendingDownloads:    d.PendingDownloads,
+			SuccessfulDownloads: d.SuccessfulDownloads,
+			FailedDownloads:     d.FailedDownloads,
+		})
+	}
+	sort.Slice(p.Topics, func(i, j int) bool {
+		return p.Topics[i].Topic < p.Topics[j].Topic
+	})
+	return p, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package recovery_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/recovery"
+)
+
+func TestStart(t *testing.T) {
+	ctx := context.Background()
+	adm := &adminutils.MockAdminAPI{Log: logr.Discard()}
+
+	started, err := recovery.Start(ctx, adm, "orders-.*")
+	require.NoError(t, err)
+	assert.True(t, started)
+	status, err := adm.PollAutomatedRecoveryStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "orders-.*", status.RecoveryRequest.TopicNamesPattern)
+
+	// the running recovery of the same topics is not started again
+	running := admin.TopicRecoveryStatus{
+		State:           "scanning_bucket",
+		RecoveryRequest: admin.RecoveryRequestParams{TopicNamesPattern: "orders-.*"},
+	}
+	adm.SetRecoveryStatus(running)
+	started, err = recovery.Start(ctx, adm, "orders-.*")
+	require.NoError(t, err)
+	assert.True(t, started)
+	status, err = adm.PollAutomatedRecoveryStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &running, status)
+
+	// the recovery of other topics waits for the running recovery
+	started, err = recovery.Start(ctx, adm, "payments-.*")
+	require.NoError(t, err)
+	assert.False(t, started)
+	status, err = adm.PollAutomatedRecoveryStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &running, status)
+}
+
+func TestPoll(t *testing.T) {
+	ctx := context.Background()
+	adm := &adminutils.MockAdminAPI{Log: logr.Discard()}
+	status := admin.TopicRecoveryStatus{
+		State: "recovering_data",
+		TopicDownloads: []admin.TopicDownloadCounts{
+			{TopicNamespace: "kafka/orders", PendingDownloads: 2, SuccessfulDownloads: 1},
+			{TopicNamespace: "kafka/invoices", SuccessfulDownloads: 3},
+		},
+	}
+	adm.SetRecoveryStatus(status)
+
+	p, err := recovery.Poll(ctx, adm)
+	require.NoError(t, err)
+	assert.False(t, p.Done)
+	assert.False(t, p.Failed())
+	assert.Equal(t, []vectorizedv1alpha1.TopicRecoveryProgress{
+		{Topic: "kafka/invoices", SuccessfulDownloads: 3},
+		{Topic: "kafka/orders", PendingDownloads: 2, SuccessfulDownloads: 1},
+	}, p.Topics)
+
+	status.State = recovery.StateInactive
+	status.TopicDownloads[0] = admin.TopicDownloadCounts{TopicNamespace: "kafka/orders", SuccessfulDownloads: 2, FailedDownloads: 1}
+	adm.SetRecoveryStatus(status)
+	p, err = recovery.Poll(ctx, adm)
+	require.NoError(t, err)
+	assert.True(t, p.Done)
+	assert.True(t, p.Failed())
+}
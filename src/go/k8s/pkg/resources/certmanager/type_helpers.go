@@ -458,7 +458,8 @@ func (ac *apiCertificates) resources(
 	return res, nil
 }
 
-// Creates copy of secret in Redpanda cluster's namespace
+// Creates copy of secret in Redpanda cluster's namespace. The copy is updated
+// when the secret is renewed, so brokers pick up the renewed certificate.
 func copyNodeSecretToLocalNamespace(
 	ctx context.Context,
 	secretRef *corev1.ObjectReference,
@@ -493,7 +494,16 @@ func copyNodeSecretToLocalNamespace(
 			corev1.TLSPrivateKeyKey: tlsKey,
 		},
 	}
-	_, err = resources.CreateIfNotExists(ctx, k8sClient, caSecret, logger)
+	created, err := resources.CreateIfNotExists(ctx, k8sClient, caSecret, logger)
+	if err != nil || created {
+		return err
+	}
+
+	var current corev1.Secret
+	if err = k8sClient.Get(ctx, client.ObjectKeyFromObject(caSecret), &current); err != nil {
+		return err
+	}
+	_, err = resources.Update(ctx, &current, caSecret, k8sClient, logger)
 	return err
 }
 
@@ -602,3 +602,59 @@ func TestClusterCertificates(t *testing.T) {
 		})
 	}
 }
+
+func TestRenewedNodeSecretIsCopied(t *testing.T) {
+	secret := corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "cluster-tls-secret-node-certificate",
+			Namespace: "cert-manager",
+		},
+		Data: map[string][]byte{
+			"tls.crt": []byte("XXX"),
+			"tls.key": []byte("XXX"),
+			"ca.crt":  []byte("XXX"),
+		},
+	}
+	pandaCluster := &v1alpha1.Cluster{
+		ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec: v1alpha1.ClusterSpec{
+			Configuration: v1alpha1.RedpandaConfig{
+				KafkaAPI: []v1alpha1.KafkaAPI{
+					{
+						TLS: v1alpha1.KafkaAPITLS{
+							Enabled: true,
+							NodeSecretRef: &corev1.ObjectReference{
+								Name:      secret.Name,
+								Namespace: secret.Namespace,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithRuntimeObjects(&secret).Build()
+	copyKey := types.NamespacedName{Name: secret.Name, Namespace: "test"}
+	copyNodeSecret := func() corev1.Secret {
+		cc, err := certmanager.NewClusterCertificates(context.TODO(), pandaCluster,
+			types.NamespacedName{Name: "test", Namespace: "test"}, c, "cluster.local", "cluster2.local", scheme.Scheme, logr.Discard())
+		require.NoError(t, err)
+		_, err = cc.Resources(context.TODO())
+		require.NoError(t, err)
+		var copied corev1.Secret
+		require.NoError(t, c.Get(context.TODO(), copyKey, &copied))
+		return copied
+	}
+
+	require.Equal(t, []byte("XXX"), copyNodeSecret().Data["tls.crt"])
+
+	secret.Data = map[string][]byte{
+		"tls.crt": []byte("YYY"),
+		"tls.key": []byte("YYY"),
+		"ca.crt":  []byte("YYY"),
+	}
+	require.NoError(t, c.Update(context.TODO(), &secret))
+	copied := copyNodeSecret()
+	require.Equal(t, []byte("YYY"), copied.Data["tls.crt"])
+	require.Equal(t, []byte("YYY"), copied.Data["ca.crt"])
+}
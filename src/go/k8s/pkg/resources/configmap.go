@@ -675,7 +675,8 @@ func ConfigMapKey(pandaCluster *vectorizedv1alpha1.Cluster) types.NamespacedName
 // TODO move to utilities
 var letters = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
 
-func generatePassword(length int) (string, error) {
+// GeneratePassword returns a random alphanumeric password of the given length
+func GeneratePassword(length int) (string, error) {
 	pwdBytes := make([]byte, length)
 
 	if _, err := rand.Read(pwdBytes); err != nil {
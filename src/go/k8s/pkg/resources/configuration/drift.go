@@ -0,0 +1,95 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package configuration
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+)
+
+const (
+	redactedValue = "[REDACTED]"
+	unsetValue    = "<unset>"
+
+	// maxDriftSummaryProperties limits the properties listed by
+	// DriftSummary, since it is used in status conditions and events
+	maxDriftSummaryProperties = 10
+)
+
+// PropertyDrift describes a centralized configuration property whose value
+// in the cluster diverged from the value last applied by the operator. The
+// admin API does not record who changed the property.
+type PropertyDrift struct {
+	Name    string
+	Desired string
+	Actual  string
+}
+
+// String gives a concise representation of the drift
+func (d PropertyDrift) String() string {
+	return fmt.Sprintf("%s (desired: %s, actual: %s)", d.Name, d.Desired, d.Actual)
+}
+
+// Drift lists the properties of the patch computed from the last applied
+// configuration against the current cluster configuration, i.e. the
+// properties that were changed in the cluster without going through the
+// operator. Values of secret properties are redacted.
+func Drift(
+	patch CentralConfigurationPatch,
+	current map[string]interface{},
+	schema map[string]admin.ConfigPropertyMetadata,
+) []PropertyDrift {
+	drift := make([]PropertyDrift, 0, len(patch.Upsert)+len(patch.Remove))
+	for k, desired := range patch.Upsert {
+		d := PropertyDrift{
+			Name:    k,
+			Desired: fmt.Sprintf("%v", desired),
+			Actual:  unsetValue,
+		}
+		if actual, ok := current[k]; ok {
+			d.Actual = fmt.Sprintf("%v", actual)
+		}
+		if schema[k].IsSecret {
+			d.Desired, d.Actual = redactedValue, redactedValue
+		}
+		drift = append(drift, d)
+	}
+	for _, k := range patch.Remove {
+		d := PropertyDrift{
+			Name:    k,
+			Desired: unsetValue,
+			Actual:  fmt.Sprintf("%v", current[k]),
+		}
+		if schema[k].IsSecret {
+			d.Actual = redactedValue
+		}
+		drift = append(drift, d)
+	}
+	sort.Slice(drift, func(i, j int) bool {
+		return drift[i].Name < drift[j].Name
+	})
+	return drift
+}
+
+// DriftSummary describes the drifted properties in a single message
+func DriftSummary(drift []PropertyDrift) string {
+	props := make([]string, 0, len(drift))
+	for i, d := range drift {
+		if i == maxDriftSummaryProperties {
+			props = append(props, fmt.Sprintf("and %d more", len(drift)-i))
+			break
+		}
+		props = append(props, d.String())
+	}
+	return fmt.Sprintf("%d properties diverged from the desired configuration: %s", len(drift), strings.Join(props, "; "))
+}
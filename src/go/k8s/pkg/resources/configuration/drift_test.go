@@ -0,0 +1,69 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package configuration_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/configuration"
+)
+
+func TestDrift(t *testing.T) {
+	patch := configuration.CentralConfigurationPatch{
+		Upsert: map[string]interface{}{
+			"log_retention_ms":         604800000,
+			"cloud_storage_secret_key": "secret",
+			"auto_create_topics":       true,
+		},
+		Remove: []string{"kafka_qdc_enable"},
+	}
+	current := map[string]interface{}{
+		"log_retention_ms":         86400000,
+		"cloud_storage_secret_key": "other",
+		"kafka_qdc_enable":         true,
+	}
+	schema := map[string]admin.ConfigPropertyMetadata{
+		"cloud_storage_secret_key": {Type: "string", IsSecret: true},
+	}
+
+	drift := configuration.Drift(patch, current, schema)
+	require.Len(t, drift, 4)
+	assert.Equal(t, configuration.PropertyDrift{
+		Name:    "auto_create_topics",
+		Desired: "true",
+		Actual:  "<unset>",
+	}, drift[0])
+	assert.Equal(t, "cloud_storage_secret_key", drift[1].Name)
+	assert.Equal(t, "[REDACTED]", drift[1].Desired)
+	assert.Equal(t, "[REDACTED]", drift[1].Actual)
+	assert.Equal(t, "kafka_qdc_enable", drift[2].Name)
+	assert.Equal(t, "<unset>", drift[2].Desired)
+	assert.Equal(t, "true", drift[2].Actual)
+	assert.Equal(t, "log_retention_ms", drift[3].Name)
+	assert.Equal(t, "604800000", drift[3].Desired)
+	assert.Equal(t, "86400000", drift[3].Actual)
+}
+
+func TestDriftSummary(t *testing.T) {
+	drift := make([]configuration.PropertyDrift, 12)
+	for i := range drift {
+		drift[i] = configuration.PropertyDrift{Name: fmt.Sprintf("p%d", i), Desired: "a", Actual: "b"}
+	}
+	summary := configuration.DriftSummary(drift)
+	assert.True(t, strings.HasPrefix(summary, "12 properties diverged from the desired configuration: p0 (desired: a, actual: b); "))
+	assert.True(t, strings.HasSuffix(summary, "; and 2 more"))
+	assert.NotContains(t, summary, "p10")
+}
@@ -80,6 +80,13 @@ func RackAwareness(version string) bool {
 	return atLeastVersion(V22_1, version)
 }
 
+// CertificateReload feature gate tells if Redpanda reloads renewed listener
+// certificates from disk without a restart. It should be removed when the
+// operator will no longer support 21.x or older versions
+func CertificateReload(version string) bool {
+	return atLeastVersion(V22_1, version)
+}
+
 // atLeastVersion tells if the given version is greater or equal than the
 // minVersion.
 // All semver incompatible versions (such as "dev" or "latest") and non-version
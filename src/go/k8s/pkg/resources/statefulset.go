@@ -72,6 +72,8 @@ var (
 	ConfigMapHashAnnotationKey = vectorizedv1alpha1.GroupVersion.Group + "/configmap-hash"
 	// CentralizedConfigurationHashAnnotationKey contains the hash of the centralized configuration properties that require a restart when changed
 	CentralizedConfigurationHashAnnotationKey = vectorizedv1alpha1.GroupVersion.Group + "/centralized-configuration-hash"
+	// CertificatesHashAnnotationKey contains the hash of the mounted certificates that require a restart when changed
+	CertificatesHashAnnotationKey = vectorizedv1alpha1.GroupVersion.Group + "/certificates-hash"
 
 	// terminationGracePeriodSeconds should account for additional delay introduced by hooks
 	terminationGracePeriodSeconds int64 = 120
@@ -191,18 +193,34 @@ func (r *StatefulSetResource) Ensure(ctx context.Context) error {
 	}
 	r.LastObservedState = &sts
 
+	tlsVolumes, _ := r.volumeProvider.Volumes()
+	err = r.updateCertificatesStatus(ctx, tlsVolumes)
+	if err != nil {
+		return err
+	}
+
 	// Hack for: https://github.com/redpanda-data/redpanda/issues/4999
 	err = r.disableMaintenanceModeOnDecommissionedNodes(ctx)
 	if err != nil {
 		return err
 	}
 
+	err = r.expandVolumes(ctx)
+	if err != nil {
+		return err
+	}
+
 	r.logger.Info("Running update")
 	err = r.runUpdate(ctx, &sts, obj.(*appsv1.StatefulSet))
 	if err != nil {
 		return err
 	}
 
+	err = r.autoscale(ctx)
+	if err != nil {
+		return err
+	}
+
 	r.logger.Info("Running scale handler")
 	return r.handleScaling(ctx)
 }
@@ -316,6 +334,13 @@ func (r *StatefulSetResource) obj(
 	}
 
 	tlsVolumes, tlsVolumeMounts := r.volumeProvider.Volumes()
+	certs, err := r.readCertificates(ctx, tlsVolumes)
+	if err != nil {
+		return nil, err
+	}
+	if certs.hash != "" {
+		annotations[CertificatesHashAnnotationKey] = certs.hash
+	}
 
 	// We set statefulset replicas via status.currentReplicas in order to control it from the handleScaling function
 	replicas := r.pandaCluster.GetCurrentReplicas()
@@ -567,14 +592,6 @@ func (r *StatefulSetResource) obj(
 							},
 						},
 					},
-					TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
-						{
-							MaxSkew:           1,
-							TopologyKey:       corev1.LabelZoneFailureDomainStable,
-							WhenUnsatisfiable: corev1.ScheduleAnyway,
-							LabelSelector:     clusterLabels.AsAPISelector(),
-						},
-					},
 				},
 			},
 		},
@@ -599,6 +616,7 @@ func (r *StatefulSetResource) obj(
 	}
 
 	setVolumes(ss, r.pandaCluster)
+	setTopology(ss, r.pandaCluster)
 
 	rpkStatusContainer := r.rpkStatusContainer(tlsVolumeMounts)
 	if rpkStatusContainer != nil {
@@ -627,7 +645,9 @@ func (r *StatefulSetResource) getHook(script string) *corev1.LifecycleHandler {
 // setVolumes manipulates v1.StatefulSet object in order to add cloud storage and
 // Redpanda data volume
 func setVolumes(ss *appsv1.StatefulSet, cluster *vectorizedv1alpha1.Cluster) {
-	pvcDataDir := preparePVCResource(datadirName, cluster.Namespace, cluster.Spec.Storage, ss.Labels)
+	storage := cluster.Spec.Storage
+	storage.Capacity = cluster.DataVolumeCapacity()
+	pvcDataDir := preparePVCResource(datadirName, cluster.Namespace, storage, ss.Labels)
 	ss.Spec.VolumeClaimTemplates = append(ss.Spec.VolumeClaimTemplates, pvcDataDir)
 	vol := corev1.Volume{
 		Name: datadirName,
@@ -0,0 +1,358 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/utils"
+)
+
+const (
+	// AutoscalingInterval is how often the broker metrics of a cluster with
+	// autoscaling are evaluated
+	AutoscalingInterval = time.Minute
+
+	defaultScaleUpCooldown   = 5 * time.Minute
+	defaultScaleDownCooldown = 30 * time.Minute
+
+	// autoscalingTolerance is the relative difference between a metric and
+	// its target under which the replicas are not changed
+	autoscalingTolerance = 0.1
+	// maxScalingHistory limits the scaling decisions kept in the status
+	maxScalingHistory = 10
+
+	cpuUtilizationTarget = "cpuUtilizationPercentage"
+	diskUsageTarget      = "diskUsagePercentage"
+	partitionsTarget     = "partitionsPerBroker"
+	produceTarget        = "produceBytesPerSecond"
+
+	reactorUtilizationMetric = "vectorized_reactor_utilization"
+	producedBytesMetric      = "vectorized_cluster_partition_bytes_produced_total"
+	// The disk gauges are only reported by the public metrics endpoint
+	diskTotalBytesMetric = "redpanda_storage_disk_total_bytes"
+	diskFreeBytesMetric  = "redpanda_storage_disk_free_bytes"
+	// The partitions of the cluster, replicas not included, are only reported
+	// by the public metrics endpoint of the controller leader
+	clusterPartitionsMetric = "redpanda_cluster_partitions"
+)
+
+// autoscale evaluates the broker metrics against the autoscaling targets and
+// sets the desired number of replicas in the autoscaling status, which is then
+// applied by handleScaling in place of spec.replicas.
+//
+// The metrics are evaluated every AutoscalingInterval, only while the cluster
+// is stable, i.e. all brokers are ready and no scaling, restart or upgrade is
+// in progress. A change of replicas is held back until the cooldown since the
+// last scaling decision has passed.
+func (r *StatefulSetResource) autoscale(ctx context.Context) error {
+	spec := r.pandaCluster.Spec.Autoscaling
+	if spec == nil {
+		if r.pandaCluster.Status.Autoscaling == nil {
+			return nil
+		}
+		r.pandaCluster.Status.Autoscaling = nil
+		return r.Status().Update(ctx, r.pandaCluster)
+	}
+	log := r.logger.WithName("autoscale")
+
+	status := r.pandaCluster.Status.Autoscaling.DeepCopy()
+	if status == nil {
+		status = &vectorizedv1alpha1.AutoscalingStatus{}
+	}
+	now := time.Now()
+	if now.Sub(status.LastEvaluationTime.Time) < AutoscalingInterval {
+		return nil
+	}
+	if reason := r.autoscalingBlockedReason(); reason != "" {
+		log.Info("Skipping autoscaling evaluation", "reason", reason)
+		return nil
+	}
+
+	podList, err := r.getPodList(ctx)
+	if err != nil {
+		return err
+	}
+	current := r.pandaCluster.GetDesiredReplicas()
+	if len(podList.Items) != int(current) {
+		log.Info("Skipping autoscaling evaluation", "reason", "not all brokers are running", "pods", len(podList.Items))
+		return nil
+	}
+	loads := make([]map[string]float64, 0, len(podList.Items))
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !utils.IsPodReady(pod) {
+			log.Info("Skipping autoscaling evaluation", "reason", "broker is not ready", "pod", pod.Name)
+			return nil
+		}
+		brokerLoad, err := r.getBrokerLoad(ctx, pod)
+		if err != nil {
+			log.Info("Skipping autoscaling evaluation", "reason", "unable to get broker metrics", "pod", pod.Name, "error", err.Error())
+			return nil
+		}
+		loads = append(loads, brokerLoad)
+	}
+
+	partitions, partitionsOk := clusterPartitions(loads)
+	load := averageLoad(loads)
+	delete(load, clusterPartitionsMetric)
+	if partitionsOk {
+		load[partitionsTarget] = partitions / float64(current)
+	}
+	producedBytes := int64(load[producedBytesMetric] * float64(len(loads)))
+	delete(load, producedBytesMetric)
+	// The counters are reset when a broker restarts or is removed, in which
+	// case the throughput is evaluated again at the next interval
+	if elapsed := now.Sub(status.LastEvaluationTime.Time); !status.LastEvaluationTime.IsZero() && producedBytes >= status.ProducedBytes {
+		load[produceTarget] = float64(producedBytes-status.ProducedBytes) / elapsed.Seconds() / float64(current)
+	}
+
+	desired, metrics := desiredReplicas(spec, current, load)
+	status.DesiredReplicas = desired
+	status.Replicas = current
+	status.CurrentMetrics = metrics
+	status.LastEvaluationTime = metav1.NewTime(now)
+	status.ProducedBytes = producedBytes
+
+	if desired != current {
+		cooldown := autoscalingCooldown(spec.ScaleUpCooldown, defaultScaleUpCooldown)
+		if desired < current {
+			cooldown = autoscalingCooldown(spec.ScaleDownCooldown, defaultScaleDownCooldown)
+		}
+		if status.LastScaleTime != nil && now.Sub(status.LastScaleTime.Time) < cooldown {
+			log.Info("Scaling held back by cooldown", "replicas", current, "desired", desired, "cooldown", cooldown)
+		} else {
+			reason := scalingReason(metrics)
+			log.Info("Autoscaling cluster", "from", current, "to", desired, "reason", reason)
+			status.Replicas = desired
+			scaleTime := metav1.NewTime(now)
+			status.LastScaleTime = &scaleTime
+			status.History = append(status.History, vectorizedv1alpha1.ScalingDecision{
+				Time:         status.LastEvaluationTime,
+				FromReplicas: current,
+				ToReplicas:   desired,
+				Reason:       reason,
+			})
+			if len(status.History) > maxScalingHistory {
+				status.History = status.History[len(status.History)-maxScalingHistory:]
+			}
+		}
+	}
+
+	r.pandaCluster.Status.Autoscaling = status
+	if err := r.Status().Update(ctx, r.pandaCluster); err != nil {
+		return fmt.Errorf("unable to update autoscaling status: %w", err)
+	}
+	return nil
+}
+
+// autoscalingBlockedReason tells why the cluster cannot be autoscaled, or
+// returns an empty string
+func (r *StatefulSetResource) autoscalingBlockedReason() string {
+	c := r.pandaCluster
+	switch {
+	case c.GetDecommissionBrokerID() != nil:
+		return "a broker is being decommissioned"
+	case c.Status.CurrentReplicas != c.GetDesiredReplicas():
+		return "scaling is in progress"
+	case c.Status.IsRestarting():
+		return "the cluster is restarting"
+	case c.Status.Upgrade != nil:
+		return "a canary upgrade is in progress"
+	}
+	return ""
+}
+
+// desiredReplicas computes the number of replicas for each target with a
+// reported metric. The highest one is returned, within the replica limits.
+func desiredReplicas(
+	spec *vectorizedv1alpha1.AutoscalingSpec,
+	current int32,
+	load map[string]float64,
+) (int32, []vectorizedv1alpha1.AutoscalingMetricStatus) {
+	targets := map[string]float64{}
+	targetValues := map[string]string{}
+	if t := spec.Targets.CPUUtilizationPercentage; t != nil {
+		targets[cpuUtilizationTarget], targetValues[cpuUtilizationTarget] = float64(*t), fmt.Sprint(*t)
+	}
+	if t := spec.Targets.DiskUsagePercentage; t != nil {
+		targets[diskUsageTarget], targetValues[diskUsageTarget] = float64(*t), fmt.Sprint(*t)
+	}
+	if t := spec.Targets.PartitionsPerBroker; t != nil {
+		targets[partitionsTarget], targetValues[partitionsTarget] = float64(*t), fmt.Sprint(*t)
+	}
+	if t := spec.Targets.ProduceBytesPerSecond; t != nil {
+		targets[produceTarget], targetValues[produceTarget] = t.AsApproximateFloat64(), t.String()
+	}
+
+	var desired int32
+	var metrics []vectorizedv1alpha1.AutoscalingMetricStatus
+	for _, name := range []string{cpuUtilizationTarget, diskUsageTarget, partitionsTarget, produceTarget} {
+		target, ok := targets[name]
+		if !ok || target <= 0 {
+			continue
+		}
+		value, ok := load[name]
+		if !ok {
+			continue
+		}
+		d := current
+		if ratio := value / target; math.Abs(ratio-1) > autoscalingTolerance {
+			d = int32(math.Ceil(float64(current) * ratio))
+		}
+		if d < 1 {
+			d = 1
+		}
+		metrics = append(metrics, vectorizedv1alpha1.AutoscalingMetricStatus{
+			Name:            name,
+			Current:         fmt.Sprintf("%.2f", value),
+			Target:          targetValues[name],
+			DesiredReplicas: d,
+		})
+		if d > desired {
+			desired = d
+		}
+	}
+	if len(metrics) == 0 {
+		desired = current
+	}
+
+	if desired < spec.MinReplicas {
+		desired = spec.MinReplicas
+	}
+	if spec.MaxReplicas > 0 && desired > spec.MaxReplicas {
+		desired = spec.MaxReplicas
+	}
+	return desired, metrics
+}
+
+// getBrokerLoad scrapes the internal and public metrics of the broker and
+// returns its load. The disk usage is left out when the public metrics cannot
+// be scraped, for example when they are disabled.
+func (r *StatefulSetResource) getBrokerLoad(
+	ctx context.Context, pod *corev1.Pod,
+) (map[string]float64, error) {
+	adminURL := r.brokerMetricsURL(pod, "")
+	metrics, err := r.getBrokerMetrics(ctx, &adminURL)
+	if err != nil {
+		return nil, err
+	}
+	publicURL := r.brokerPublicMetricsURL(pod)
+	publicMetrics, err := r.getBrokerMetrics(ctx, &publicURL)
+	if err != nil {
+		r.logger.Info("Unable to get broker public metrics, disk usage is not evaluated", "pod", pod.Name, "error", err.Error())
+	}
+	return brokerLoad(metrics, publicMetrics), nil
+}
+
+// brokerLoad extracts the values of the autoscaling targets from the internal
+// and public metrics of a broker. The produced bytes are a counter, from which
+// the throughput is computed between two evaluations, and the partitions of
+// the cluster are divided by the number of brokers.
+func brokerLoad(metrics, publicMetrics map[string]*dto.MetricFamily) map[string]float64 {
+	load := map[string]float64{}
+	if f, ok := metrics[reactorUtilizationMetric]; ok && len(f.Metric) > 0 {
+		var sum float64
+		for _, m := range f.Metric {
+			sum += metricValue(m)
+		}
+		load[cpuUtilizationTarget] = sum / float64(len(f.Metric))
+	}
+	total, totalOk := sumMetric(publicMetrics, diskTotalBytesMetric)
+	free, freeOk := sumMetric(publicMetrics, diskFreeBytesMetric)
+	if totalOk && freeOk && total > 0 {
+		load[diskUsageTarget] = (total - free) / total * 100
+	}
+	if partitions, ok := sumMetric(publicMetrics, clusterPartitionsMetric); ok {
+		load[clusterPartitionsMetric] = partitions
+	}
+	if produced, ok := sumMetric(metrics, producedBytesMetric); ok {
+		load[producedBytesMetric] = produced
+	}
+	return load
+}
+
+// averageLoad averages each value reported by all brokers
+func averageLoad(loads []map[string]float64) map[string]float64 {
+	avg := map[string]float64{}
+	if len(loads) == 0 {
+		return avg
+	}
+	for name := range loads[0] {
+		var sum float64
+		reported := true
+		for _, l := range loads {
+			v, ok := l[name]
+			if !ok {
+				reported = false
+				break
+			}
+			sum += v
+		}
+		if reported {
+			avg[name] = sum / float64(len(loads))
+		}
+	}
+	return avg
+}
+
+// clusterPartitions returns the partitions of the cluster reported by the
+// controller leader
+func clusterPartitions(loads []map[string]float64) (float64, bool) {
+	var partitions float64
+	var reported bool
+	for _, l := range loads {
+		if p, ok := l[clusterPartitionsMetric]; ok && p >= partitions {
+			partitions, reported = p, true
+		}
+	}
+	return partitions, reported
+}
+
+func sumMetric(metrics map[string]*dto.MetricFamily, name string) (float64, bool) {
+	f, ok := metrics[name]
+	if !ok {
+		return 0, false
+	}
+	var sum float64
+	for _, m := range f.Metric {
+		if m != nil {
+			sum += metricValue(m)
+		}
+	}
+	return sum, true
+}
+
+func scalingReason(metrics []vectorizedv1alpha1.AutoscalingMetricStatus) string {
+	if len(metrics) == 0 {
+		return "replicas out of the autoscaling limits"
+	}
+	reasons := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		reasons = append(reasons, fmt.Sprintf("%s %s (target %s, %d replicas)", m.Name, m.Current, m.Target, m.DesiredReplicas))
+	}
+	return strings.Join(reasons, ", ")
+}
+
+func autoscalingCooldown(d *metav1.Duration, def time.Duration) time.Duration {
+	if d != nil {
+		return d.Duration
+	}
+	return def
+}
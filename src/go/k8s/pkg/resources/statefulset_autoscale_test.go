@@ -0,0 +1,214 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//nolint:testpackage // the tests use private methods
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/labels"
+)
+
+const autoscalingTestMetrics = `
+# TYPE vectorized_reactor_utilization gauge
+vectorized_reactor_utilization{shard="0"} 80.000000
+vectorized_reactor_utilization{shard="1"} 100.000000
+# TYPE vectorized_cluster_partition_bytes_produced_total counter
+vectorized_cluster_partition_bytes_produced_total{namespace="kafka",partition="0",shard="0",topic="test"} 1000
+vectorized_cluster_partition_bytes_produced_total{namespace="kafka",partition="1",shard="1",topic="test"} 2000
+`
+
+const autoscalingTestPublicMetrics = `
+# TYPE redpanda_storage_disk_total_bytes gauge
+redpanda_storage_disk_total_bytes 1000.000000
+# TYPE redpanda_storage_disk_free_bytes gauge
+redpanda_storage_disk_free_bytes 750.000000
+# TYPE redpanda_cluster_partitions gauge
+redpanda_cluster_partitions 12.000000
+`
+
+func TestBrokerLoad(t *testing.T) {
+	var parser expfmt.TextParser
+	metrics, err := parser.TextToMetricFamilies(strings.NewReader(autoscalingTestMetrics))
+	require.NoError(t, err)
+	publicMetrics, err := parser.TextToMetricFamilies(strings.NewReader(autoscalingTestPublicMetrics))
+	require.NoError(t, err)
+
+	load := brokerLoad(metrics, publicMetrics)
+	assert.Equal(t, map[string]float64{
+		cpuUtilizationTarget:    90,
+		diskUsageTarget:         25,
+		clusterPartitionsMetric: 12,
+		producedBytesMetric:     3000,
+	}, load)
+
+	withoutPublic := brokerLoad(metrics, nil)
+	assert.NotContains(t, withoutPublic, diskUsageTarget)
+
+	avg := averageLoad([]map[string]float64{load, {cpuUtilizationTarget: 50}})
+	assert.Equal(t, map[string]float64{cpuUtilizationTarget: 70}, avg)
+
+	// Only the controller leader reports the partitions of the cluster
+	partitions, ok := clusterPartitions([]map[string]float64{withoutPublic, load})
+	assert.True(t, ok)
+	assert.Equal(t, float64(12), partitions)
+	_, ok = clusterPartitions([]map[string]float64{withoutPublic})
+	assert.False(t, ok)
+}
+
+func TestDesiredReplicas(t *testing.T) {
+	spec := &vectorizedv1alpha1.AutoscalingSpec{
+		MinReplicas: 3,
+		MaxReplicas: 9,
+		Targets: vectorizedv1alpha1.AutoscalingTargets{
+			CPUUtilizationPercentage: pointer.Int32(60),
+			DiskUsagePercentage:      pointer.Int32(50),
+			ProduceBytesPerSecond:    resource.NewQuantity(10*1024*1024, resource.BinarySI),
+		},
+	}
+	tests := []struct {
+		name     string
+		current  int32
+		load     map[string]float64
+		expected int32
+	}{
+		{"no metrics", 4, map[string]float64{}, 4},
+		{"no metrics out of limits", 12, map[string]float64{}, 9},
+		{"within tolerance", 4, map[string]float64{cpuUtilizationTarget: 64}, 4},
+		{"scale up", 4, map[string]float64{cpuUtilizationTarget: 90}, 6},
+		{"highest target wins", 4, map[string]float64{cpuUtilizationTarget: 30, diskUsageTarget: 75}, 6},
+		{"scale down", 6, map[string]float64{cpuUtilizationTarget: 30, diskUsageTarget: 20}, 3},
+		{"min replicas", 4, map[string]float64{cpuUtilizationTarget: 5}, 3},
+		{"max replicas", 4, map[string]float64{cpuUtilizationTarget: 100, produceTarget: 50 * 1024 * 1024}, 9},
+		{"untargeted metric", 4, map[string]float64{partitionsTarget: 5000}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired, _ := desiredReplicas(spec, tt.current, tt.load)
+			assert.Equal(t, tt.expected, desired)
+		})
+	}
+}
+
+//nolint:funlen // this is ok for a test
+func TestAutoscale(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/public_metrics" {
+			fmt.Fprint(w, autoscalingTestPublicMetrics)
+			return
+		}
+		fmt.Fprint(w, autoscalingTestMetrics)
+	}))
+	defer ts.Close()
+	UnderReplicatedPartitionsHostOverwrite = ts.Listener.Addr().String()
+	defer func() { UnderReplicatedPartitionsHostOverwrite = "" }()
+
+	require.NoError(t, vectorizedv1alpha1.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name             string
+		status           *vectorizedv1alpha1.AutoscalingStatus
+		expectedReplicas int32
+		expectedHistory  int
+	}{
+		{"first evaluation", nil, 5, 1},
+		{"recently evaluated", &vectorizedv1alpha1.AutoscalingStatus{
+			LastEvaluationTime: metav1.NewTime(time.Now().Add(-10 * time.Second)),
+		}, 3, 0},
+		{"in cooldown", &vectorizedv1alpha1.AutoscalingStatus{
+			LastEvaluationTime: metav1.NewTime(time.Now().Add(-2 * time.Minute)),
+			LastScaleTime:      &metav1.Time{Time: time.Now().Add(-2 * time.Minute)},
+		}, 3, 0},
+		{"cooldown passed", &vectorizedv1alpha1.AutoscalingStatus{
+			LastEvaluationTime: metav1.NewTime(time.Now().Add(-2 * time.Minute)),
+			LastScaleTime:      &metav1.Time{Time: time.Now().Add(-time.Hour)},
+			History:            []vectorizedv1alpha1.ScalingDecision{{FromReplicas: 4, ToReplicas: 3}},
+		}, 5, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &vectorizedv1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+				Spec: vectorizedv1alpha1.ClusterSpec{
+					Replicas: pointer.Int32(3),
+					Configuration: vectorizedv1alpha1.RedpandaConfig{
+						AdminAPI: []vectorizedv1alpha1.AdminAPI{{Port: 9644}},
+					},
+					Autoscaling: &vectorizedv1alpha1.AutoscalingSpec{
+						MinReplicas: 3,
+						MaxReplicas: 6,
+						Targets: vectorizedv1alpha1.AutoscalingTargets{
+							CPUUtilizationPercentage: pointer.Int32(60),
+						},
+					},
+				},
+				Status: vectorizedv1alpha1.ClusterStatus{
+					CurrentReplicas: 3,
+					Autoscaling:     tt.status,
+				},
+			}
+			objs := []client.Object{cluster}
+			for i := 0; i < 3; i++ {
+				objs = append(objs, &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      fmt.Sprintf("cluster-%d", i),
+						Namespace: "default",
+						Labels:    labels.ForCluster(cluster),
+					},
+					Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{
+						Type:   corev1.PodReady,
+						Status: corev1.ConditionTrue,
+					}}},
+				})
+			}
+			c := fake.NewClientBuilder().WithObjects(objs...).Build()
+			r := StatefulSetResource{
+				Client:         c,
+				pandaCluster:   cluster,
+				logger:         ctrl.Log.WithName("test"),
+				metricsTimeout: time.Second,
+			}
+
+			require.NoError(t, r.autoscale(context.Background()))
+
+			var updated vectorizedv1alpha1.Cluster
+			require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(cluster), &updated))
+			// The replicas of the spec are left to the user
+			assert.Equal(t, int32(3), *updated.Spec.Replicas)
+			assert.Equal(t, tt.expectedReplicas, updated.GetDesiredReplicas())
+			require.NotNil(t, updated.Status.Autoscaling)
+			assert.Len(t, updated.Status.Autoscaling.History, tt.expectedHistory)
+			if tt.expectedHistory > 0 {
+				last := updated.Status.Autoscaling.History[tt.expectedHistory-1]
+				assert.Equal(t, int32(3), last.FromReplicas)
+				assert.Equal(t, int32(5), last.ToReplicas)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/utils"
+)
+
+const defaultCanarySoakPeriod = 10 * time.Minute
+
+// isCanaryUpgrade tells if the rolling update is a version upgrade that
+// starts with a canary broker
+func (r *StatefulSetResource) isCanaryUpgrade() bool {
+	c := r.pandaCluster
+	if c.CanaryUpgrade() == nil || c.IsUpgradeRolledBack() {
+		return false
+	}
+	if c.Status.Upgrade != nil {
+		return c.Status.Upgrade.ToVersion == c.Spec.Version
+	}
+	return c.Status.Version != "" && c.Status.Version != c.Spec.Version
+}
+
+// resetUpgradeStatus removes the state of a canary upgrade that no longer
+// applies, because the desired version or the upgrade strategy changed
+func (r *StatefulSetResource) resetUpgradeStatus(ctx context.Context) error {
+	u := r.pandaCluster.Status.Upgrade
+	if u == nil || (r.pandaCluster.CanaryUpgrade() != nil && u.ToVersion == r.pandaCluster.Spec.Version) {
+		return nil
+	}
+	r.logger.Info("Resetting canary upgrade status", "phase", u.Phase, "to-version", u.ToVersion)
+	r.pandaCluster.Status.Upgrade = nil
+	return r.Status().Update(ctx, r.pandaCluster)
+}
+
+// completeUpgradeStatus removes the state of a canary upgrade once all
+// brokers are upgraded. The state of a rolled back upgrade is kept, as it
+// holds the brokers on the previous version.
+func (r *StatefulSetResource) completeUpgradeStatus(ctx context.Context) error {
+	u := r.pandaCluster.Status.Upgrade
+	if u == nil || u.Phase != vectorizedv1alpha1.UpgradePhaseRollingOut {
+		return nil
+	}
+	r.logger.Info("Canary upgrade completed", "from-version", u.FromVersion, "to-version", u.ToVersion)
+	r.pandaCluster.Status.Upgrade = nil
+	return r.Status().Update(ctx, r.pandaCluster)
+}
+
+// canaryUpgrade upgrades the first broker of the pod list and holds the
+// rolling update until the canary broker passed all checks for the soak
+// period. When a check fails, the upgrade is paused or rolled back according
+// to the failure policy.
+//
+// The steps are as follows: 1) the canary broker is restarted with the new
+// version and has the soak period to become ready and healthy 2) the canary
+// broker must pass all checks during the soak period 3) the rolling update
+// continues with the other brokers.
+func (r *StatefulSetResource) canaryUpgrade(
+	ctx context.Context,
+	podList *corev1.PodList,
+	artificialPod *corev1.Pod,
+	volumes map[string]interface{},
+) error {
+	if !r.isCanaryUpgrade() || len(podList.Items) == 0 {
+		return nil
+	}
+	canary := &podList.Items[0]
+
+	u := r.pandaCluster.Status.Upgrade
+	if u == nil {
+		u = &vectorizedv1alpha1.UpgradeStatus{
+			Phase:          vectorizedv1alpha1.UpgradePhaseCanary,
+			FromVersion:    r.pandaCluster.Status.Version,
+			ToVersion:      r.pandaCluster.Spec.Version,
+			CanaryPod:      canary.Name,
+			PhaseStartTime: metav1.Now(),
+		}
+		r.logger.Info("Starting canary upgrade", "canary-pod", canary.Name, "from-version", u.FromVersion, "to-version", u.ToVersion)
+		if err := r.setUpgradeStatus(ctx, u); err != nil {
+			return err
+		}
+	}
+
+	soakPeriod := r.canarySoakPeriod()
+	switch u.Phase {
+	case vectorizedv1alpha1.UpgradePhaseRollingOut:
+		return nil
+	case vectorizedv1alpha1.UpgradePhasePaused:
+		return &RequeueAfterError{
+			RequeueAfter: RequeueDuration,
+			Msg:          fmt.Sprintf("canary upgrade to %s is paused: %s", u.ToVersion, u.Message),
+		}
+	case vectorizedv1alpha1.UpgradePhaseCanary:
+		err := r.podEviction(ctx, canary, artificialPod, volumes)
+		if err == nil {
+			err = r.checkCanary(ctx, canary)
+		}
+		if err != nil {
+			if time.Since(u.PhaseStartTime.Time) > soakPeriod {
+				return r.failCanaryUpgrade(ctx, u, fmt.Sprintf("canary broker %s did not become healthy within %s: %v", canary.Name, soakPeriod, err))
+			}
+			return &RequeueAfterError{
+				RequeueAfter: RequeueDuration,
+				Msg:          fmt.Sprintf("wait for canary broker %s: %v", canary.Name, err),
+			}
+		}
+		r.logger.Info("Canary broker upgraded, starting soak period", "canary-pod", canary.Name, "soak-period", soakPeriod)
+		u.Phase = vectorizedv1alpha1.UpgradePhaseSoaking
+		u.PhaseStartTime = metav1.Now()
+		if err := r.setUpgradeStatus(ctx, u); err != nil {
+			return err
+		}
+	}
+
+	if err := r.checkCanary(ctx, canary); err != nil {
+		return r.failCanaryUpgrade(ctx, u, fmt.Sprintf("canary broker %s failed a check during the soak period: %v", canary.Name, err))
+	}
+	if remaining := soakPeriod - time.Since(u.PhaseStartTime.Time); remaining > 0 {
+		requeue := RequeueDuration
+		if remaining < requeue {
+			requeue = remaining
+		}
+		return &RequeueAfterError{
+			RequeueAfter: requeue,
+			Msg:          fmt.Sprintf("soaking canary broker %s for %s", canary.Name, remaining.Round(time.Second)),
+		}
+	}
+
+	r.logger.Info("Canary broker passed the soak period, upgrading the other brokers", "canary-pod", canary.Name)
+	u.Phase = vectorizedv1alpha1.UpgradePhaseRollingOut
+	u.PhaseStartTime = metav1.Now()
+	return r.setUpgradeStatus(ctx, u)
+}
+
+// checkCanary checks the readiness, the cluster health, the under replicated
+// partitions and the metric thresholds of the canary broker
+func (r *StatefulSetResource) checkCanary(
+	ctx context.Context, canary *corev1.Pod,
+) error {
+	if !utils.IsPodReady(canary) {
+		return fmt.Errorf("pod %s is not ready", canary.Name)
+	}
+
+	admin, err := r.getAdminAPIClient(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting admin client: %w", err)
+	}
+	health, err := admin.GetHealthOverview(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting health overview: %w", err)
+	}
+	if !health.IsHealthy {
+		return fmt.Errorf("cluster is not healthy")
+	}
+
+	adminURL := r.brokerMetricsURL(canary, "cluster_partition_under_replicated_replicas*")
+	if err = r.evaluateUnderReplicatedPartitions(ctx, &adminURL); err != nil {
+		return fmt.Errorf("broker reported under replicated partitions: %w", err)
+	}
+
+	thresholds := r.pandaCluster.CanaryUpgrade().MetricThresholds
+	if len(thresholds) == 0 {
+		return nil
+	}
+	adminURL = r.brokerMetricsURL(canary, "")
+	metrics, err := r.getBrokerMetrics(ctx, &adminURL)
+	if err != nil {
+		return err
+	}
+	return evaluateMetricThresholds(metrics, thresholds)
+}
+
+// evaluateMetricThresholds checks that the sum of the samples of each metric
+// does not exceed its threshold. Metrics that are not reported are ignored.
+func evaluateMetricThresholds(
+	metrics map[string]*dto.MetricFamily,
+	thresholds []vectorizedv1alpha1.MetricThreshold,
+) error {
+	var exceeded []string
+	for i := range thresholds {
+		t := &thresholds[i]
+		family, ok := metrics[t.Name]
+		if !ok {
+			continue
+		}
+		var sum float64
+		for _, m := range family.Metric {
+			if m == nil || !metricLabelsMatch(m, t.Labels) {
+				continue
+			}
+			sum += metricValue(m)
+		}
+		if max := t.Max.AsApproximateFloat64(); sum > max {
+			exceeded = append(exceeded, fmt.Sprintf("%s is %g, above %s", t.Name, sum, t.Max.String()))
+		}
+	}
+	if len(exceeded) > 0 {
+		return fmt.Errorf("metric thresholds exceeded: %s", strings.Join(exceeded, "; "))
+	}
+	return nil
+}
+
+func metricLabelsMatch(m *dto.Metric, labels map[string]string) bool {
+	matched := 0
+	for _, l := range m.Label {
+		if v, ok := labels[l.GetName()]; ok {
+			if v != l.GetValue() {
+				return false
+			}
+			matched++
+		}
+	}
+	return matched == len(labels)
+}
+
+func metricValue(m *dto.Metric) float64 {
+	switch {
+	case m.Gauge != nil:
+		return m.Gauge.GetValue()
+	case m.Counter != nil:
+		return m.Counter.GetValue()
+	case m.Untyped != nil:
+		return m.Untyped.GetValue()
+	}
+	return 0
+}
+
+// failCanaryUpgrade pauses or rolls back the canary upgrade
+func (r *StatefulSetResource) failCanaryUpgrade(
+	ctx context.Context, u *vectorizedv1alpha1.UpgradeStatus, reason string,
+) error {
+	u.Phase = vectorizedv1alpha1.UpgradePhasePaused
+	if r.pandaCluster.CanaryUpgrade().FailurePolicy == vectorizedv1alpha1.CanaryFailurePolicyRollback {
+		u.Phase = vectorizedv1alpha1.UpgradePhaseRolledBack
+	}
+	u.Message = reason
+	u.PhaseStartTime = metav1.Now()
+	r.logger.Info("Canary upgrade failed", "phase", u.Phase, "reason", reason)
+	if err := r.setUpgradeStatus(ctx, u); err != nil {
+		return err
+	}
+	return &RequeueAfterError{
+		RequeueAfter: RequeueDuration,
+		Msg:          fmt.Sprintf("canary upgrade to %s %s: %s", u.ToVersion, strings.ToLower(string(u.Phase)), reason),
+	}
+}
+
+func (r *StatefulSetResource) setUpgradeStatus(
+	ctx context.Context, u *vectorizedv1alpha1.UpgradeStatus,
+) error {
+	r.pandaCluster.Status.Upgrade = u
+	if err := r.Status().Update(ctx, r.pandaCluster); err != nil {
+		return fmt.Errorf("unable to update canary upgrade status: %w", err)
+	}
+	return nil
+}
+
+// isRolledBackCanary tells if the pod is the canary broker of a rolled back
+// upgrade, that is restarted without draining as it may be failing
+func (r *StatefulSetResource) isRolledBackCanary(pod *corev1.Pod) bool {
+	return r.pandaCluster.IsUpgradeRolledBack() && r.pandaCluster.Status.Upgrade.CanaryPod == pod.Name
+}
+
+func (r *StatefulSetResource) canarySoakPeriod() time.Duration {
+	if c := r.pandaCluster.CanaryUpgrade(); c != nil && c.SoakPeriod != nil {
+		return c.SoakPeriod.Duration
+	}
+	return defaultCanarySoakPeriod
+}
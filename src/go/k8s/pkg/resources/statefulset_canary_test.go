@@ -0,0 +1,191 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//nolint:testpackage // the tests use private methods
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/types"
+)
+
+const canaryTestMetrics = `
+# TYPE vectorized_cluster_partition_under_replicated_replicas gauge
+vectorized_cluster_partition_under_replicated_replicas{namespace="kafka",partition="0",shard="0",topic="test"} 0.000000
+# TYPE vectorized_kafka_rpc_produce_bad_create_time counter
+vectorized_kafka_rpc_produce_bad_create_time{shard="0"} 2
+vectorized_kafka_rpc_produce_bad_create_time{shard="1"} 3
+`
+
+func TestIsCanaryUpgrade(t *testing.T) {
+	canary := &vectorizedv1alpha1.UpgradeStrategy{Canary: &vectorizedv1alpha1.CanaryUpgrade{}}
+	tests := []struct {
+		name     string
+		strategy *vectorizedv1alpha1.UpgradeStrategy
+		status   vectorizedv1alpha1.ClusterStatus
+		expected bool
+	}{
+		{"no strategy", nil, vectorizedv1alpha1.ClusterStatus{Version: "v22.3.11"}, false},
+		{"same version", canary, vectorizedv1alpha1.ClusterStatus{Version: "v23.1.2"}, false},
+		{"new cluster", canary, vectorizedv1alpha1.ClusterStatus{}, false},
+		{"upgrade", canary, vectorizedv1alpha1.ClusterStatus{Version: "v22.3.11"}, true},
+		{"upgrade in progress", canary, vectorizedv1alpha1.ClusterStatus{
+			Version: "v22.3.11",
+			Upgrade: &vectorizedv1alpha1.UpgradeStatus{Phase: vectorizedv1alpha1.UpgradePhaseSoaking, ToVersion: "v23.1.2"},
+		}, true},
+		{"rolled back", canary, vectorizedv1alpha1.ClusterStatus{
+			Version: "v22.3.11",
+			Upgrade: &vectorizedv1alpha1.UpgradeStatus{Phase: vectorizedv1alpha1.UpgradePhaseRolledBack, ToVersion: "v23.1.2"},
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := StatefulSetResource{pandaCluster: &vectorizedv1alpha1.Cluster{
+				Spec:   vectorizedv1alpha1.ClusterSpec{Version: "v23.1.2", UpgradeStrategy: tt.strategy},
+				Status: tt.status,
+			}}
+			assert.Equal(t, tt.expected, r.isCanaryUpgrade())
+		})
+	}
+}
+
+func TestEvaluateMetricThresholds(t *testing.T) {
+	var parser expfmt.TextParser
+	metrics, err := parser.TextToMetricFamilies(strings.NewReader(canaryTestMetrics))
+	require.NoError(t, err)
+
+	threshold := func(max string, labels map[string]string) []vectorizedv1alpha1.MetricThreshold {
+		return []vectorizedv1alpha1.MetricThreshold{{
+			Name:   "vectorized_kafka_rpc_produce_bad_create_time",
+			Labels: labels,
+			Max:    resource.MustParse(max),
+		}}
+	}
+	assert.NoError(t, evaluateMetricThresholds(metrics, threshold("5", nil)))
+	assert.Error(t, evaluateMetricThresholds(metrics, threshold("4", nil)))
+	assert.NoError(t, evaluateMetricThresholds(metrics, threshold("2", map[string]string{"shard": "0"})))
+	assert.Error(t, evaluateMetricThresholds(metrics, threshold("2", map[string]string{"shard": "1"})))
+	assert.NoError(t, evaluateMetricThresholds(metrics, []vectorizedv1alpha1.MetricThreshold{{
+		Name: "not_reported",
+		Max:  resource.MustParse("0"),
+	}}))
+}
+
+//nolint:funlen // this is ok for a test
+func TestCanaryUpgradeSoakPeriod(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, canaryTestMetrics)
+	}))
+	defer ts.Close()
+	UnderReplicatedPartitionsHostOverwrite = ts.Listener.Addr().String()
+	defer func() { UnderReplicatedPartitionsHostOverwrite = "" }()
+
+	require.NoError(t, vectorizedv1alpha1.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name          string
+		policy        vectorizedv1alpha1.CanaryFailurePolicy
+		max           string
+		soakStart     time.Time
+		expectedPhase vectorizedv1alpha1.UpgradePhase
+		expectRequeue bool
+	}{
+		{"soaking", "", "10", time.Now(), vectorizedv1alpha1.UpgradePhaseSoaking, true},
+		{"soak period passed", "", "10", time.Now().Add(-time.Hour), vectorizedv1alpha1.UpgradePhaseRollingOut, false},
+		{"threshold exceeded", vectorizedv1alpha1.CanaryFailurePolicyPause, "1", time.Now(), vectorizedv1alpha1.UpgradePhasePaused, true},
+		{"threshold exceeded with rollback", vectorizedv1alpha1.CanaryFailurePolicyRollback, "1", time.Now(), vectorizedv1alpha1.UpgradePhaseRolledBack, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &vectorizedv1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+				Spec: vectorizedv1alpha1.ClusterSpec{
+					Version:       "v23.1.2",
+					Replicas:      pointer.Int32(3),
+					RestartConfig: &vectorizedv1alpha1.RestartConfig{},
+					Configuration: vectorizedv1alpha1.RedpandaConfig{
+						AdminAPI: []vectorizedv1alpha1.AdminAPI{{Port: 9644}},
+					},
+					UpgradeStrategy: &vectorizedv1alpha1.UpgradeStrategy{Canary: &vectorizedv1alpha1.CanaryUpgrade{
+						SoakPeriod:    &metav1.Duration{Duration: 10 * time.Minute},
+						FailurePolicy: tt.policy,
+						MetricThresholds: []vectorizedv1alpha1.MetricThreshold{{
+							Name: "vectorized_kafka_rpc_produce_bad_create_time",
+							Max:  resource.MustParse(tt.max),
+						}},
+					}},
+				},
+				Status: vectorizedv1alpha1.ClusterStatus{
+					Version: "v22.3.11",
+					Upgrade: &vectorizedv1alpha1.UpgradeStatus{
+						Phase:          vectorizedv1alpha1.UpgradePhaseSoaking,
+						FromVersion:    "v22.3.11",
+						ToVersion:      "v23.1.2",
+						CanaryPod:      "cluster-0",
+						PhaseStartTime: metav1.NewTime(tt.soakStart),
+					},
+				},
+			}
+			c := fake.NewClientBuilder().WithObjects(cluster).Build()
+			r := StatefulSetResource{
+				Client:       c,
+				pandaCluster: cluster,
+				logger:       ctrl.Log.WithName("test"),
+				adminAPIClientFactory: func(
+					context.Context, client.Reader, *vectorizedv1alpha1.Cluster, string, types.AdminTLSConfigProvider, ...int32,
+				) (adminutils.AdminAPIClient, error) {
+					adminAPI := &adminutils.MockAdminAPI{Log: ctrl.Log.WithName("testAdminAPI").WithName("mockAdminAPI")}
+					adminAPI.SetClusterHealth(true)
+					return adminAPI, nil
+				},
+			}
+			podList := &corev1.PodList{Items: []corev1.Pod{{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-0"},
+				Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{
+					Type:   corev1.PodReady,
+					Status: corev1.ConditionTrue,
+				}}},
+			}}}
+
+			err := r.canaryUpgrade(context.Background(), podList, &corev1.Pod{}, nil)
+			if tt.expectRequeue {
+				var requeue *RequeueAfterError
+				require.ErrorAs(t, err, &requeue)
+			} else {
+				require.NoError(t, err)
+			}
+
+			var updated vectorizedv1alpha1.Cluster
+			require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(cluster), &updated))
+			require.NotNil(t, updated.Status.Upgrade)
+			assert.Equal(t, tt.expectedPhase, updated.Status.Upgrade.Phase)
+		})
+	}
+}
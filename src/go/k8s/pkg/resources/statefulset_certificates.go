@@ -0,0 +1,191 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+
+	cmmetav1 "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/resources/featuregates"
+)
+
+// mountedCertificates are the certificates mounted by the TLS volumes of the
+// brokers
+type mountedCertificates struct {
+	// hash of the certificates that require a restart of the brokers when
+	// changed, empty when a Secret is missing
+	hash     string
+	statuses []vectorizedv1alpha1.CertificateStatus
+}
+
+// readCertificates reads the Secrets mounted by the TLS volumes.
+//
+// Renewed Secrets are updated in the mounted files by the kubelet. Redpanda
+// versions that reload their certificates from disk pick up renewed node
+// certificates without a restart, so with the Auto reload policy only the CA
+// certificates are hashed: a changed hash restarts the brokers with the
+// rolling update.
+func (r *StatefulSetResource) readCertificates(
+	ctx context.Context, volumes []corev1.Volume,
+) (*mountedCertificates, error) {
+	hashAll := r.pandaCluster.GetCertificateReloadPolicy() == vectorizedv1alpha1.CertificateReloadPolicyRollingRestart ||
+		!featuregates.CertificateReload(r.pandaCluster.Spec.Version)
+
+	hash := sha256.New()
+	missing := false
+	statuses := map[string]vectorizedv1alpha1.CertificateStatus{}
+	for i := range volumes {
+		src := volumes[i].Secret
+		if src == nil {
+			continue
+		}
+		var secret corev1.Secret
+		err := r.Get(ctx, types.NamespacedName{Namespace: r.pandaCluster.Namespace, Name: src.SecretName}, &secret)
+		if apierrors.IsNotFound(err) {
+			missing = true
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to get Secret %s: %w", src.SecretName, err)
+		}
+		for _, item := range src.Items {
+			data := secret.Data[item.Key]
+			if hashAll || item.Key == cmmetav1.TLSCAKey {
+				fmt.Fprintf(hash, "%s/%s:", secret.Name, item.Key)
+				hash.Write(data)
+			}
+			if item.Key == corev1.TLSPrivateKeyKey {
+				continue
+			}
+			if status := certificateStatus(secret.Name, item.Key, data); status != nil {
+				statuses[secret.Name+"/"+item.Key] = *status
+			}
+		}
+	}
+
+	certs := &mountedCertificates{
+		statuses: make([]vectorizedv1alpha1.CertificateStatus, 0, len(statuses)),
+	}
+	if !missing {
+		certs.hash = fmt.Sprintf("%x", hash.Sum(nil))
+	}
+	for _, s := range statuses {
+		certs.statuses = append(certs.statuses, s)
+	}
+	sort.Slice(certs.statuses, func(i, j int) bool {
+		if certs.statuses[i].SecretName != certs.statuses[j].SecretName {
+			return certs.statuses[i].SecretName < certs.statuses[j].SecretName
+		}
+		return certs.statuses[i].Key < certs.statuses[j].Key
+	})
+	return certs, nil
+}
+
+// certificateStatus returns the validity of the PEM encoded certificates,
+// reporting the certificate that expires first, or nil when the data holds
+// no certificate
+func certificateStatus(
+	secretName, key string, data []byte,
+) *vectorizedv1alpha1.CertificateStatus {
+	var first *x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		if first == nil || cert.NotAfter.Before(first.NotAfter) {
+			first = cert
+		}
+	}
+	if first == nil {
+		return nil
+	}
+	return &vectorizedv1alpha1.CertificateStatus{
+		SecretName: secretName,
+		Key:        key,
+		Subject:    first.Subject.String(),
+		NotBefore:  metav1.NewTime(first.NotBefore),
+		NotAfter:   metav1.NewTime(first.NotAfter),
+	}
+}
+
+// updateCertificatesStatus reports the certificates mounted by the brokers
+// in the cluster status
+func (r *StatefulSetResource) updateCertificatesStatus(
+	ctx context.Context, volumes []corev1.Volume,
+) error {
+	certs, err := r.readCertificates(ctx, volumes)
+	if err != nil {
+		return err
+	}
+	if len(certs.statuses) == 0 && len(r.pandaCluster.Status.Certificates) == 0 {
+		return nil
+	}
+	if apiequality.Semantic.DeepEqual(certs.statuses, r.pandaCluster.Status.Certificates) {
+		return nil
+	}
+	r.pandaCluster.Status.Certificates = certs.statuses
+	if err = r.Status().Update(ctx, r.pandaCluster); err != nil {
+		return fmt.Errorf("unable to update certificates status: %w", err)
+	}
+	return nil
+}
+
+// reconcileCertificatesHash prepares the certificates hash annotation for the
+// update of the statefulset. A hash that cannot be computed yet keeps the
+// current one, and a statefulset without hash adopts it without restarting
+// the brokers, e.g. for clusters created by previous operator versions. A
+// changed hash makes the rolling update restart the brokers.
+func (r *StatefulSetResource) reconcileCertificatesHash(
+	ctx context.Context, current, modified *appsv1.StatefulSet,
+) error {
+	currentHash, hasCurrent := current.Spec.Template.Annotations[CertificatesHashAnnotationKey]
+	modifiedHash, hasModified := modified.Spec.Template.Annotations[CertificatesHashAnnotationKey]
+	switch {
+	case hasCurrent && !hasModified:
+		if modified.Spec.Template.Annotations == nil {
+			modified.Spec.Template.Annotations = make(map[string]string)
+		}
+		modified.Spec.Template.Annotations[CertificatesHashAnnotationKey] = currentHash
+	case !hasCurrent && hasModified:
+		if current.Spec.Template.Annotations == nil {
+			current.Spec.Template.Annotations = make(map[string]string)
+		}
+		current.Spec.Template.Annotations[CertificatesHashAnnotationKey] = modifiedHash
+		if err := r.Update(ctx, current); err != nil {
+			return fmt.Errorf("unable to store certificates hash in statefulset: %w", err)
+		}
+	case hasCurrent && currentHash != modifiedHash:
+		r.logger.Info("Certificates changed, the brokers will be restarted",
+			"reload policy", r.pandaCluster.GetCertificateReloadPolicy())
+	}
+	return nil
+}
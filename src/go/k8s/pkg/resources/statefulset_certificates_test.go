@@ -0,0 +1,188 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//nolint:testpackage // the tests use private methods
+package resources
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+)
+
+func selfSignedCertificate(t *testing.T, cn string, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestCertificateStatus(t *testing.T) {
+	first := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bundle := append(
+		selfSignedCertificate(t, "second", first.Add(time.Hour)),
+		selfSignedCertificate(t, "first", first)...)
+
+	status := certificateStatus("secret", "ca.crt", bundle)
+	require.NotNil(t, status)
+	assert.Equal(t, "CN=first", status.Subject)
+	assert.True(t, status.NotAfter.Time.Equal(first))
+	assert.True(t, status.NotBefore.Time.Equal(first.Add(-24*time.Hour)))
+
+	assert.Nil(t, certificateStatus("secret", "ca.crt", []byte("not a certificate")))
+}
+
+//nolint:funlen // this is ok for a test
+func TestCertificatesHash(t *testing.T) {
+	require.NoError(t, vectorizedv1alpha1.AddToScheme(scheme.Scheme))
+	ctx := context.Background()
+
+	notAfter := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-redpanda", Namespace: "default"},
+		Data: map[string][]byte{
+			"tls.crt": selfSignedCertificate(t, "node", notAfter),
+			"tls.key": []byte("key"),
+			"ca.crt":  selfSignedCertificate(t, "ca", notAfter.Add(time.Hour)),
+		},
+	}
+	volumes := []corev1.Volume{{
+		Name: "tlscert",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+			SecretName: secret.Name,
+			Items: []corev1.KeyToPath{
+				{Key: "tls.key", Path: "tls.key"},
+				{Key: "tls.crt", Path: "tls.crt"},
+				{Key: "ca.crt", Path: "ca.crt"},
+			},
+		}},
+	}}
+	cluster := &vectorizedv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Spec:       vectorizedv1alpha1.ClusterSpec{Version: "v23.1.1"},
+	}
+	c := fake.NewClientBuilder().WithObjects(cluster, secret).Build()
+	r := StatefulSetResource{
+		Client:       c,
+		pandaCluster: cluster,
+		logger:       ctrl.Log.WithName("test"),
+	}
+
+	certs, err := r.readCertificates(ctx, volumes)
+	require.NoError(t, err)
+	require.NotEmpty(t, certs.hash)
+	require.Len(t, certs.statuses, 2)
+	assert.Equal(t, "ca.crt", certs.statuses[0].Key)
+	assert.Equal(t, "CN=ca", certs.statuses[0].Subject)
+	assert.Equal(t, "tls.crt", certs.statuses[1].Key)
+	assert.True(t, certs.statuses[1].NotAfter.Time.Equal(notAfter))
+
+	// a renewed node certificate is reloaded by Redpanda
+	secret.Data["tls.crt"] = selfSignedCertificate(t, "node", notAfter.Add(90*24*time.Hour))
+	require.NoError(t, c.Update(ctx, secret))
+	renewed, err := r.readCertificates(ctx, volumes)
+	require.NoError(t, err)
+	assert.Equal(t, certs.hash, renewed.hash)
+	assert.NotEqual(t, certs.statuses, renewed.statuses)
+
+	// a changed CA restarts the brokers
+	secret.Data["ca.crt"] = selfSignedCertificate(t, "ca", notAfter.Add(365*24*time.Hour))
+	require.NoError(t, c.Update(ctx, secret))
+	rotated, err := r.readCertificates(ctx, volumes)
+	require.NoError(t, err)
+	assert.NotEqual(t, renewed.hash, rotated.hash)
+
+	// any change restarts the brokers with the rolling restart policy
+	cluster.Spec.CertificateReloadPolicy = vectorizedv1alpha1.CertificateReloadPolicyRollingRestart
+	secret.Data["tls.crt"] = selfSignedCertificate(t, "node", notAfter.Add(180*24*time.Hour))
+	before, err := r.readCertificates(ctx, volumes)
+	require.NoError(t, err)
+	require.NoError(t, c.Update(ctx, secret))
+	after, err := r.readCertificates(ctx, volumes)
+	require.NoError(t, err)
+	assert.NotEqual(t, before.hash, after.hash)
+
+	// no hash until all certificates are issued
+	missing, err := r.readCertificates(ctx, append(volumes, corev1.Volume{
+		Name:         "tlsadmincert",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "missing"}},
+	}))
+	require.NoError(t, err)
+	assert.Empty(t, missing.hash)
+
+	require.NoError(t, r.updateCertificatesStatus(ctx, volumes))
+	var updated vectorizedv1alpha1.Cluster
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(cluster), &updated))
+	assert.Len(t, updated.Status.Certificates, 2)
+}
+
+func TestReconcileCertificatesHash(t *testing.T) {
+	ctx := context.Background()
+	withHash := func(hash string) *appsv1.StatefulSet {
+		sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}}
+		if hash != "" {
+			sts.Spec.Template.Annotations = map[string]string{CertificatesHashAnnotationKey: hash}
+		}
+		return sts
+	}
+
+	current := withHash("")
+	c := fake.NewClientBuilder().WithObjects(current).Build()
+	r := StatefulSetResource{
+		Client:       c,
+		pandaCluster: &vectorizedv1alpha1.Cluster{},
+		logger:       ctrl.Log.WithName("test"),
+	}
+
+	// the hash is adopted without restarting the brokers
+	modified := withHash("a")
+	require.NoError(t, r.reconcileCertificatesHash(ctx, current, modified))
+	var stored appsv1.StatefulSet
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(current), &stored))
+	assert.Equal(t, "a", stored.Spec.Template.Annotations[CertificatesHashAnnotationKey])
+	assert.Equal(t, "a", current.Spec.Template.Annotations[CertificatesHashAnnotationKey])
+
+	// the current hash is kept while a certificate is missing
+	modified = withHash("")
+	require.NoError(t, r.reconcileCertificatesHash(ctx, current, modified))
+	assert.Equal(t, "a", modified.Spec.Template.Annotations[CertificatesHashAnnotationKey])
+
+	// a changed hash is left to the rolling update
+	modified = withHash("b")
+	require.NoError(t, r.reconcileCertificatesHash(ctx, current, modified))
+	assert.Equal(t, "b", modified.Spec.Template.Annotations[CertificatesHashAnnotationKey])
+	assert.Equal(t, "a", current.Spec.Template.Annotations[CertificatesHashAnnotationKey])
+}
@@ -70,7 +70,7 @@ func (r *StatefulSetResource) handleScaling(ctx context.Context) error {
 	log := r.logger.WithName("handleScaling")
 	// decommission already in progress
 	if r.pandaCluster.GetDecommissionBrokerID() != nil {
-		if *r.pandaCluster.Spec.Replicas >= r.pandaCluster.GetCurrentReplicas() {
+		if r.pandaCluster.GetDesiredReplicas() >= r.pandaCluster.GetCurrentReplicas() {
 			// Decommissioning can also be canceled and we need to recommission
 			err := r.handleRecommission(ctx)
 			if !errors.Is(err, &RecommissionFatalError{}) {
@@ -111,13 +111,13 @@ func (r *StatefulSetResource) handleScaling(ctx context.Context) error {
 		return r.Status().Update(ctx, r.pandaCluster)
 	}
 
-	if *r.pandaCluster.Spec.Replicas == r.pandaCluster.Status.CurrentReplicas {
+	if r.pandaCluster.GetDesiredReplicas() == r.pandaCluster.Status.CurrentReplicas {
 		// No changes to replicas, we do nothing here
 		return nil
 	}
 
-	if *r.pandaCluster.Spec.Replicas > r.pandaCluster.Status.CurrentReplicas {
-		r.logger.Info("Upscaling cluster", "replicas", *r.pandaCluster.Spec.Replicas)
+	if r.pandaCluster.GetDesiredReplicas() > r.pandaCluster.Status.CurrentReplicas {
+		r.logger.Info("Upscaling cluster", "replicas", r.pandaCluster.GetDesiredReplicas())
 
 		// We care about upscaling only when the cluster is moving off 1 replica, which happen e.g. at cluster startup
 		if r.pandaCluster.Status.CurrentReplicas == 1 {
@@ -129,14 +129,14 @@ func (r *StatefulSetResource) handleScaling(ctx context.Context) error {
 			if !formed {
 				return &RequeueAfterError{
 					RequeueAfter: wait.Jitter(r.decommissionWaitInterval, decommissionWaitJitterFactor),
-					Msg:          fmt.Sprintf("Waiting for cluster to be formed before upscaling to %d replicas", *r.pandaCluster.Spec.Replicas),
+					Msg:          fmt.Sprintf("Waiting for cluster to be formed before upscaling to %d replicas", r.pandaCluster.GetDesiredReplicas()),
 				}
 			}
 			r.logger.Info("Initial cluster has been formed")
 		}
 
 		// Upscaling request: this is already handled by Redpanda, so we just increase status currentReplicas
-		return setCurrentReplicas(ctx, r, r.pandaCluster, *r.pandaCluster.Spec.Replicas, r.logger)
+		return setCurrentReplicas(ctx, r, r.pandaCluster, r.pandaCluster.GetDesiredReplicas(), r.logger)
 	}
 
 	// User required replicas is lower than current replicas (currentReplicas): start the decommissioning process
@@ -0,0 +1,101 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/labels"
+)
+
+const (
+	// rackNodeLabelEnvVar is the node label read by the configurator to
+	// set the rack of the broker
+	rackNodeLabelEnvVar = "RACK_NODE_LABEL"
+
+	zoneAntiAffinityWeight = 50
+)
+
+// setTopology spreads the brokers across the zones of the cluster topology.
+// Without topology the brokers are spread across the nodes labelled with
+// topology.kubernetes.io/zone with a max skew of 1.
+func setTopology(ss *appsv1.StatefulSet, cluster *vectorizedv1alpha1.Cluster) {
+	podSpec := &ss.Spec.Template.Spec
+	selector := labels.ForCluster(cluster).AsAPISelector()
+
+	constraint := corev1.TopologySpreadConstraint{
+		MaxSkew:           1,
+		TopologyKey:       cluster.ZoneLabel(),
+		WhenUnsatisfiable: corev1.ScheduleAnyway,
+		LabelSelector:     selector,
+	}
+	topology := cluster.Spec.Topology
+	if topology == nil {
+		podSpec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{constraint}
+		return
+	}
+	if topology.MaxSkew != nil {
+		constraint.MaxSkew = *topology.MaxSkew
+	}
+	if topology.WhenUnsatisfiable != "" {
+		constraint.WhenUnsatisfiable = topology.WhenUnsatisfiable
+	}
+	podSpec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{constraint}
+
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	if len(topology.Zones) > 0 {
+		podSpec.Affinity.NodeAffinity = &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{
+						Key:      cluster.ZoneLabel(),
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   topology.Zones,
+					}},
+				}},
+			},
+		}
+	}
+
+	zoneTerm := corev1.PodAffinityTerm{
+		LabelSelector: selector,
+		Namespaces:    []string{cluster.Namespace},
+		TopologyKey:   cluster.ZoneLabel(),
+	}
+	if podSpec.Affinity.PodAntiAffinity == nil {
+		podSpec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	}
+	antiAffinity := podSpec.Affinity.PodAntiAffinity
+	switch topology.ZoneAntiAffinity {
+	case vectorizedv1alpha1.ZoneAntiAffinityPreferred:
+		antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			corev1.WeightedPodAffinityTerm{Weight: zoneAntiAffinityWeight, PodAffinityTerm: zoneTerm})
+	case vectorizedv1alpha1.ZoneAntiAffinityRequired:
+		antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+			antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, zoneTerm)
+	case vectorizedv1alpha1.ZoneAntiAffinityNone:
+	}
+
+	if rackLabel := cluster.RackLabel(); rackLabel != "" {
+		for i := range podSpec.InitContainers {
+			if podSpec.InitContainers[i].Name == configuratorContainerName {
+				podSpec.InitContainers[i].Env = append(podSpec.InitContainers[i].Env, corev1.EnvVar{
+					Name:  rackNodeLabelEnvVar,
+					Value: rackLabel,
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//nolint:testpackage // the tests use private methods
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+)
+
+func TestSetTopology(t *testing.T) {
+	newStatefulSet := func() *appsv1.StatefulSet {
+		ss := &appsv1.StatefulSet{}
+		ss.Spec.Template.Spec.InitContainers = []corev1.Container{{Name: configuratorContainerName}}
+		return ss
+	}
+	cluster := &vectorizedv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+	}
+
+	t.Run("default topology", func(t *testing.T) {
+		ss := newStatefulSet()
+		setTopology(ss, cluster)
+
+		podSpec := ss.Spec.Template.Spec
+		require.Len(t, podSpec.TopologySpreadConstraints, 1)
+		assert.Equal(t, int32(1), podSpec.TopologySpreadConstraints[0].MaxSkew)
+		assert.Equal(t, corev1.LabelTopologyZone, podSpec.TopologySpreadConstraints[0].TopologyKey)
+		assert.Equal(t, corev1.ScheduleAnyway, podSpec.TopologySpreadConstraints[0].WhenUnsatisfiable)
+		assert.Nil(t, podSpec.Affinity)
+		assert.Empty(t, podSpec.InitContainers[0].Env)
+	})
+
+	t.Run("custom topology", func(t *testing.T) {
+		c := cluster.DeepCopy()
+		c.Spec.Topology = &vectorizedv1alpha1.TopologySpec{
+			ZoneLabel:         "example.com/zone",
+			Zones:             []string{"a", "b", "c"},
+			MaxSkew:           pointer.Int32(2),
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			ZoneAntiAffinity:  vectorizedv1alpha1.ZoneAntiAffinityRequired,
+		}
+		ss := newStatefulSet()
+		setTopology(ss, c)
+
+		podSpec := ss.Spec.Template.Spec
+		require.Len(t, podSpec.TopologySpreadConstraints, 1)
+		assert.Equal(t, int32(2), podSpec.TopologySpreadConstraints[0].MaxSkew)
+		assert.Equal(t, "example.com/zone", podSpec.TopologySpreadConstraints[0].TopologyKey)
+		assert.Equal(t, corev1.DoNotSchedule, podSpec.TopologySpreadConstraints[0].WhenUnsatisfiable)
+
+		require.NotNil(t, podSpec.Affinity)
+		terms := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		require.Len(t, terms, 1)
+		assert.Equal(t, []corev1.NodeSelectorRequirement{{
+			Key:      "example.com/zone",
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{"a", "b", "c"},
+		}}, terms[0].MatchExpressions)
+		required := podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+		require.Len(t, required, 1)
+		assert.Equal(t, "example.com/zone", required[0].TopologyKey)
+
+		assert.Equal(t, []corev1.EnvVar{{Name: rackNodeLabelEnvVar, Value: "example.com/zone"}}, podSpec.InitContainers[0].Env)
+	})
+}
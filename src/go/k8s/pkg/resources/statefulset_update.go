@@ -23,6 +23,7 @@ import (
 
 	"github.com/cisco-open/k8s-objectmatcher/patch"
 	"github.com/fluxcd/pkg/runtime/logger"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -63,6 +64,9 @@ var (
 // verify the previously updated pod and requeue as necessary. Currently, the
 // verification checks the pod has started listening in its http Admin API port and may be
 // extended.
+//
+// When a canary upgrade strategy is configured, version upgrades start with a
+// canary broker that must pass all checks for a soak period (see canaryUpgrade).
 func (r *StatefulSetResource) runUpdate(
 	ctx context.Context, current, modified *appsv1.StatefulSet,
 ) error {
@@ -74,6 +78,17 @@ func (r *StatefulSetResource) runUpdate(
 		modified.Spec.Template.Annotations[CentralizedConfigurationHashAnnotationKey] = ann
 	}
 
+	// Data volumes are expanded by patching their claims, see expandVolumes
+	keepDataVolumeClaimCapacity(current, modified)
+
+	if err := r.reconcileCertificatesHash(ctx, current, modified); err != nil {
+		return err
+	}
+
+	if err := r.resetUpgradeStatus(ctx); err != nil {
+		return fmt.Errorf("unable to reset canary upgrade status in cluster custom resource: %w", err)
+	}
+
 	update, err := r.shouldUpdate(r.pandaCluster.Status.IsRestarting(), current, modified)
 	if err != nil {
 		return fmt.Errorf("unable to determine the update procedure: %w", err)
@@ -91,7 +106,8 @@ func (r *StatefulSetResource) runUpdate(
 		return err
 	}
 
-	if err = r.isClusterHealthy(ctx); err != nil {
+	// A rolled back canary broker may be the reason why the cluster is not healthy
+	if err = r.isClusterHealthy(ctx); err != nil && !r.pandaCluster.IsUpgradeRolledBack() {
 		return err
 	}
 
@@ -99,6 +115,10 @@ func (r *StatefulSetResource) runUpdate(
 		return err
 	}
 
+	if err = r.completeUpgradeStatus(ctx); err != nil {
+		return fmt.Errorf("unable to complete canary upgrade status in cluster custom resource: %w", err)
+	}
+
 	// Update is complete for all pods (and all are ready). Set restarting status to false.
 	if err = r.updateRestartingStatus(ctx, false); err != nil {
 		return fmt.Errorf("unable to turn off restarting status in cluster custom resource: %w", err)
@@ -179,6 +199,10 @@ func (r *StatefulSetResource) rollingUpdate(
 		volumes[vol.Name] = new(interface{})
 	}
 
+	if err = r.canaryUpgrade(ctx, podList, &artificialPod, volumes); err != nil {
+		return err
+	}
+
 	for i := range podList.Items {
 		pod := podList.Items[i]
 
@@ -214,23 +238,7 @@ func (r *StatefulSetResource) rollingUpdate(
 			}
 		}
 
-		headlessServiceWithPort := fmt.Sprintf("%s:%d", r.serviceFQDN,
-			r.pandaCluster.AdminAPIInternal().Port)
-
-		adminURL := url.URL{
-			Scheme: "http",
-			Host:   hostOverwrite(&pod, headlessServiceWithPort),
-			Path:   "metrics",
-		}
-
-		params := url.Values{}
-		if featuregates.MetricsQueryParamName(r.pandaCluster.Spec.Version) {
-			params.Add("__name__", "cluster_partition_under_replicated_replicas*")
-		} else {
-			params.Add("name", "cluster_partition_under_replicated_replicas*")
-		}
-		adminURL.RawQuery = params.Encode()
-
+		adminURL := r.brokerMetricsURL(&pod, "cluster_partition_under_replicated_replicas*")
 		if err = r.evaluateUnderReplicatedPartitions(ctx, &adminURL); err != nil {
 			return &RequeueAfterError{
 				RequeueAfter: RequeueDuration,
@@ -242,6 +250,39 @@ func (r *StatefulSetResource) rollingUpdate(
 	return nil
 }
 
+// brokerMetricsURL returns the URL of the metrics endpoint of the broker,
+// restricted to the metrics matching the filter when not empty
+func (r *StatefulSetResource) brokerMetricsURL(pod *corev1.Pod, filter string) url.URL {
+	headlessServiceWithPort := fmt.Sprintf("%s:%d", r.serviceFQDN,
+		r.pandaCluster.AdminAPIInternal().Port)
+
+	adminURL := url.URL{
+		Scheme: "http",
+		Host:   hostOverwrite(pod, headlessServiceWithPort),
+		Path:   "metrics",
+	}
+	if filter == "" {
+		return adminURL
+	}
+
+	params := url.Values{}
+	if featuregates.MetricsQueryParamName(r.pandaCluster.Spec.Version) {
+		params.Add("__name__", filter)
+	} else {
+		params.Add("name", filter)
+	}
+	adminURL.RawQuery = params.Encode()
+	return adminURL
+}
+
+// brokerPublicMetricsURL returns the URL of the public metrics endpoint of
+// the broker
+func (r *StatefulSetResource) brokerPublicMetricsURL(pod *corev1.Pod) url.URL {
+	publicURL := r.brokerMetricsURL(pod, "")
+	publicURL.Path = "public_metrics"
+	return publicURL
+}
+
 var UnderReplicatedPartitionsHostOverwrite string
 
 func hostOverwrite(pod *corev1.Pod, headlessServiceWithPort string) string {
@@ -270,7 +311,8 @@ func (r *StatefulSetResource) podEviction(ctx context.Context, pod, artificialPo
 		return fmt.Errorf("cluster %s: cannot convert pod name (%s) to ordinal: %w", r.pandaCluster.Name, pod.Name, err)
 	}
 
-	if patchResult.IsEmpty() {
+	resizePending := r.isFileSystemResizePending(pod)
+	if patchResult.IsEmpty() && !resizePending {
 		if err = r.checkMaintenanceMode(ctx, int32(ordinal)); err != nil {
 			return &RequeueAfterError{
 				RequeueAfter: RequeueDuration,
@@ -280,7 +322,7 @@ func (r *StatefulSetResource) podEviction(ctx context.Context, pod, artificialPo
 		return nil
 	}
 
-	if *r.pandaCluster.Spec.Replicas > 1 {
+	if r.pandaCluster.GetDesiredReplicas() > 1 && !r.isRolledBackCanary(pod) {
 		r.logger.Info("Put broker into maintenance mode",
 			"pod-name", pod.Name,
 			"patch", patchResult.Patch)
@@ -295,9 +337,14 @@ func (r *StatefulSetResource) podEviction(ctx context.Context, pod, artificialPo
 		}
 	}
 
-	r.logger.Info("Changes in Pod definition other than activeDeadlineSeconds, configurator and Redpanda container name. Deleting pod",
-		"pod-name", pod.Name,
-		"patch", patchResult.Patch)
+	if patchResult.IsEmpty() {
+		r.logger.Info("File system of the data volume is resized on restart. Deleting pod",
+			"pod-name", pod.Name)
+	} else {
+		r.logger.Info("Changes in Pod definition other than activeDeadlineSeconds, configurator and Redpanda container name. Deleting pod",
+			"pod-name", pod.Name,
+			"patch", patchResult.Patch)
+	}
 
 	if err = r.Delete(ctx, pod); err != nil {
 		return fmt.Errorf("unable to remove Redpanda pod: %w", err)
@@ -344,7 +391,7 @@ func (r *StatefulSetResource) putInMaintenanceMode(ctx context.Context, ordinal
 }
 
 func (r *StatefulSetResource) checkMaintenanceMode(ctx context.Context, ordinal int32) error {
-	if *r.pandaCluster.Spec.Replicas <= 1 {
+	if r.pandaCluster.GetDesiredReplicas() <= 1 {
 		return nil
 	}
 
@@ -580,44 +627,7 @@ func deleteKubernetesTokenVolumeMounts(obj []byte) ([]byte, error) {
 func (r *StatefulSetResource) evaluateUnderReplicatedPartitions(
 	ctx context.Context, adminURL *url.URL,
 ) error {
-	client := &http.Client{Timeout: r.metricsTimeout}
-
-	// TODO right now we support TLS only on one listener so if external
-	// connectivity is enabled, TLS is enabled only on external listener. This
-	// will be fixed by https://github.com/redpanda-data/redpanda/issues/1084
-	if r.pandaCluster.AdminAPITLS() != nil &&
-		r.pandaCluster.AdminAPIExternal() == nil {
-		tlsConfig, err := r.adminTLSConfigProvider.GetTLSConfig(ctx, r)
-		if err != nil {
-			return err
-		}
-
-		client.Transport = &http.Transport{
-			TLSClientConfig: tlsConfig,
-		}
-		adminURL.Scheme = "https"
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, adminURL.String(), http.NoBody)
-	if err != nil {
-		return err
-	}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			r.logger.Error(err, "error closing connection to Redpanda admin API")
-		}
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("getting broker metrics (%s): %w", adminURL.String(), errRedpandaNotReady)
-	}
-
-	var parser expfmt.TextParser
-	metrics, err := parser.TextToMetricFamilies(resp.Body)
+	metrics, err := r.getBrokerMetrics(ctx, adminURL)
 	if err != nil {
 		return err
 	}
@@ -659,6 +669,50 @@ func (r *StatefulSetResource) evaluateUnderReplicatedPartitions(
 	return nil
 }
 
+// getBrokerMetrics scrapes the metrics endpoint of a broker
+func (r *StatefulSetResource) getBrokerMetrics(
+	ctx context.Context, adminURL *url.URL,
+) (map[string]*dto.MetricFamily, error) {
+	client := &http.Client{Timeout: r.metricsTimeout}
+
+	// TODO right now we support TLS only on one listener so if external
+	// connectivity is enabled, TLS is enabled only on external listener. This
+	// will be fixed by https://github.com/redpanda-data/redpanda/issues/1084
+	if r.pandaCluster.AdminAPITLS() != nil &&
+		r.pandaCluster.AdminAPIExternal() == nil {
+		tlsConfig, err := r.adminTLSConfigProvider.GetTLSConfig(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+
+		client.Transport = &http.Transport{
+			TLSClientConfig: tlsConfig,
+		}
+		adminURL.Scheme = "https"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, adminURL.String(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			r.logger.Error(err, "error closing connection to Redpanda admin API")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("getting broker metrics (%s): %w", adminURL.String(), errRedpandaNotReady)
+	}
+
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(resp.Body)
+}
+
 // RequeueAfterError error carrying the time after which to requeue.
 type RequeueAfterError struct {
 	RequeueAfter time.Duration
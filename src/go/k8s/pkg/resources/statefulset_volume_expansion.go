@@ -0,0 +1,312 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/utils"
+)
+
+const (
+	// volumeExpansionRoundingBytes rounds up the capacity of automatically
+	// expanded volumes
+	volumeExpansionRoundingBytes = 1 << 30
+)
+
+// expandVolumes expands the data volumes of the brokers to the capacity of
+// the cluster. The volume claim templates of the statefulset cannot be
+// updated, so they keep their capacity (see keepDataVolumeClaimCapacity) and
+// the persistent volume claims are patched instead. The expansion of each
+// volume is reported in the status.
+//
+// The steps are as follows: 1) when automatic expansion is enabled and the
+// usage of a data volume crossed the threshold, the capacity in the status is
+// increased 2) the persistent volume claims with a lower capacity are updated
+// when their storage class allows volume expansion 3) the reconciliation is
+// requeued until the volumes are expanded 4) file systems that are not
+// resized online are reported as FileSystemResizePending, and a rolling update
+// restarts their Pods so that the file systems get resized.
+func (r *StatefulSetResource) expandVolumes(ctx context.Context) error {
+	status := r.pandaCluster.Status.Storage.DeepCopy()
+	if status == nil {
+		status = &vectorizedv1alpha1.StorageStatus{}
+	}
+
+	podList, err := r.getPodList(ctx)
+	if err != nil {
+		return err
+	}
+	if err = r.autoExpandVolumes(ctx, podList, status); err != nil {
+		return err
+	}
+
+	capacity := r.pandaCluster.DataVolumeCapacity()
+	if status.Capacity.Cmp(capacity) > 0 {
+		capacity = status.Capacity
+	}
+	if capacity.IsZero() {
+		capacity = resource.MustParse(defaultDatadirCapacity)
+	}
+
+	volumes := make([]vectorizedv1alpha1.VolumeStatus, 0, len(podList.Items))
+	for i := range podList.Items {
+		claimName := dataVolumeClaimName(&podList.Items[i])
+		if claimName == "" {
+			continue
+		}
+		var pvc corev1.PersistentVolumeClaim
+		err = r.Get(ctx, types.NamespacedName{Namespace: r.pandaCluster.Namespace, Name: claimName}, &pvc)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("unable to get PersistentVolumeClaim %s: %w", claimName, err)
+		}
+		v, err := r.expandVolume(ctx, &pvc, capacity)
+		if err != nil {
+			return err
+		}
+		volumes = append(volumes, v)
+	}
+	if len(volumes) == 0 && r.pandaCluster.Status.Storage == nil && status.LastEvaluationTime == nil {
+		return nil
+	}
+	status.Volumes = volumes
+
+	if !apiequality.Semantic.DeepEqual(status, r.pandaCluster.Status.Storage) {
+		r.pandaCluster.Status.Storage = status
+		if err = r.Status().Update(ctx, r.pandaCluster); err != nil {
+			return fmt.Errorf("unable to update storage status: %w", err)
+		}
+	}
+
+	for _, v := range volumes {
+		if v.Phase == vectorizedv1alpha1.VolumeExpansionPhaseFileSystemResizePending {
+			r.logger.Info("Restarting brokers to resize the file system of their data volume", "name", v.Name)
+			if err = r.updateRestartingStatus(ctx, true); err != nil {
+				return fmt.Errorf("unable to turn on restarting status in cluster custom resource: %w", err)
+			}
+			break
+		}
+	}
+	for _, v := range volumes {
+		if v.Phase == vectorizedv1alpha1.VolumeExpansionPhaseResizing {
+			return &RequeueAfterError{
+				RequeueAfter: RequeueDuration,
+				Msg:          fmt.Sprintf("wait for PersistentVolumeClaim %s to be expanded to %s", v.Name, capacity.String()),
+			}
+		}
+	}
+	return nil
+}
+
+// keepDataVolumeClaimCapacity sets the capacity of the data volume claim
+// template of the current statefulset to the modified one, since volume
+// claim templates cannot be updated. Volumes of new brokers are expanded
+// after their creation by expandVolumes.
+func keepDataVolumeClaimCapacity(current, modified *appsv1.StatefulSet) {
+	var capacity *resource.Quantity
+	for i := range current.Spec.VolumeClaimTemplates {
+		pvc := &current.Spec.VolumeClaimTemplates[i]
+		if q, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok && pvc.Name == datadirName {
+			capacity = &q
+		}
+	}
+	if capacity == nil {
+		return
+	}
+	for i := range modified.Spec.VolumeClaimTemplates {
+		if pvc := &modified.Spec.VolumeClaimTemplates[i]; pvc.Name == datadirName && pvc.Spec.Resources.Requests != nil {
+			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = capacity.DeepCopy()
+		}
+	}
+}
+
+// expandVolume requests the capacity for the persistent volume claim and
+// returns the state of its expansion
+func (r *StatefulSetResource) expandVolume(
+	ctx context.Context, pvc *corev1.PersistentVolumeClaim, capacity resource.Quantity,
+) (vectorizedv1alpha1.VolumeStatus, error) {
+	v := vectorizedv1alpha1.VolumeStatus{
+		Name:     pvc.Name,
+		Phase:    vectorizedv1alpha1.VolumeExpansionPhaseResized,
+		Capacity: pvc.Status.Capacity[corev1.ResourceStorage],
+	}
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if requested.Cmp(capacity) < 0 {
+		expandable, err := r.isVolumeExpandable(ctx, pvc)
+		if err != nil {
+			return v, err
+		}
+		if !expandable {
+			v.Phase = vectorizedv1alpha1.VolumeExpansionPhaseNotExpandable
+			v.Message = fmt.Sprintf("storage class %s does not allow volume expansion", pointer.StringDeref(pvc.Spec.StorageClassName, ""))
+			return v, nil
+		}
+		r.logger.Info("Expanding PersistentVolumeClaim", "name", pvc.Name, "from", requested.String(), "to", capacity.String())
+		if pvc.Spec.Resources.Requests == nil {
+			pvc.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = capacity
+		if err = r.Update(ctx, pvc); err != nil {
+			if apierrors.IsForbidden(err) || apierrors.IsInvalid(err) {
+				v.Phase = vectorizedv1alpha1.VolumeExpansionPhaseNotExpandable
+				v.Message = err.Error()
+				return v, nil
+			}
+			return v, fmt.Errorf("unable to expand PersistentVolumeClaim %s: %w", pvc.Name, err)
+		}
+		requested = capacity
+	}
+
+	if v.Capacity.Cmp(requested) >= 0 {
+		return v, nil
+	}
+	v.Phase = vectorizedv1alpha1.VolumeExpansionPhaseResizing
+	for _, c := range pvc.Status.Conditions {
+		if c.Type == corev1.PersistentVolumeClaimFileSystemResizePending && c.Status == corev1.ConditionTrue {
+			v.Phase = vectorizedv1alpha1.VolumeExpansionPhaseFileSystemResizePending
+		}
+	}
+	return v, nil
+}
+
+// isVolumeExpandable tells if the storage class of the persistent volume
+// claim allows volume expansion. Claims without storage class are assumed to
+// be expandable, and rejected by the API server otherwise.
+func (r *StatefulSetResource) isVolumeExpandable(
+	ctx context.Context, pvc *corev1.PersistentVolumeClaim,
+) (bool, error) {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return true, nil
+	}
+	var sc storagev1.StorageClass
+	if err := r.Get(ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, &sc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("unable to get StorageClass %s: %w", *pvc.Spec.StorageClassName, err)
+	}
+	return sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion, nil
+}
+
+// autoExpandVolumes increases the capacity in the status when the usage of a
+// data volume crossed the threshold. The usage is evaluated every
+// AutoscalingInterval, only when all brokers are ready and all volumes are
+// resized, including the file systems resized by restarting the brokers.
+func (r *StatefulSetResource) autoExpandVolumes(
+	ctx context.Context, podList *corev1.PodList, status *vectorizedv1alpha1.StorageStatus,
+) error {
+	ae := r.pandaCluster.Spec.Storage.AutoExpansion
+	if ae == nil {
+		return nil
+	}
+	now := time.Now()
+	if status.LastEvaluationTime != nil && now.Sub(status.LastEvaluationTime.Time) < AutoscalingInterval {
+		return nil
+	}
+	for _, v := range status.Volumes {
+		if v.Phase != vectorizedv1alpha1.VolumeExpansionPhaseResized {
+			return nil
+		}
+	}
+	log := r.logger.WithName("autoExpandVolumes")
+
+	var usage float64
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !utils.IsPodReady(pod) {
+			return nil
+		}
+		load, err := r.getBrokerLoad(ctx, pod)
+		if err != nil {
+			log.Info("Skipping volume usage evaluation", "reason", "unable to get broker metrics", "pod", pod.Name, "error", err.Error())
+			return nil
+		}
+		if u, ok := load[diskUsageTarget]; ok && u > usage {
+			usage = u
+		}
+	}
+	evaluated := metav1.NewTime(now)
+	status.LastEvaluationTime = &evaluated
+	if usage < float64(ae.UsageThresholdPercentage) {
+		return nil
+	}
+
+	current := r.pandaCluster.DataVolumeCapacity()
+	if current.IsZero() {
+		current = resource.MustParse(defaultDatadirCapacity)
+	}
+	expanded := expandedCapacity(current, ae)
+	if expanded.Cmp(current) <= 0 {
+		log.Info("Data volumes reached the maximum capacity", "usage", usage, "capacity", current.String())
+		return nil
+	}
+	log.Info("Expanding data volumes", "usage", usage, "from", current.String(), "to", expanded.String())
+	status.Capacity = expanded
+	status.LastExpansionTime = &evaluated
+	return nil
+}
+
+// expandedCapacity increases the capacity by the increment percentage,
+// rounded up to a GiB and limited to the maximum capacity
+func expandedCapacity(
+	current resource.Quantity, ae *vectorizedv1alpha1.VolumeAutoExpansion,
+) resource.Quantity {
+	bytes := current.Value() + current.Value()*int64(ae.IncrementPercentage)/100
+	if rem := bytes % volumeExpansionRoundingBytes; rem != 0 {
+		bytes += volumeExpansionRoundingBytes - rem
+	}
+	expanded := *resource.NewQuantity(bytes, resource.BinarySI)
+	if !ae.MaxCapacity.IsZero() && expanded.Cmp(ae.MaxCapacity) > 0 {
+		return ae.MaxCapacity.DeepCopy()
+	}
+	return expanded
+}
+
+// isFileSystemResizePending tells if the file system of the data volume of the
+// Pod is resized once the Pod is restarted
+func (r *StatefulSetResource) isFileSystemResizePending(pod *corev1.Pod) bool {
+	claimName := dataVolumeClaimName(pod)
+	if claimName == "" || r.pandaCluster.Status.Storage == nil {
+		return false
+	}
+	for _, v := range r.pandaCluster.Status.Storage.Volumes {
+		if v.Name == claimName {
+			return v.Phase == vectorizedv1alpha1.VolumeExpansionPhaseFileSystemResizePending
+		}
+	}
+	return false
+}
+
+// dataVolumeClaimName returns the name of the persistent volume claim of the
+// data volume of the Pod
+func dataVolumeClaimName(pod *corev1.Pod) string {
+	for _, v := range pod.Spec.Volumes {
+		if v.Name == datadirName && v.PersistentVolumeClaim != nil {
+			return v.PersistentVolumeClaim.ClaimName
+		}
+	}
+	return ""
+}
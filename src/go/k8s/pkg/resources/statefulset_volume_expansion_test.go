@@ -0,0 +1,266 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//nolint:testpackage // the tests use private methods
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/labels"
+)
+
+func TestExpandedCapacity(t *testing.T) {
+	ae := &vectorizedv1alpha1.VolumeAutoExpansion{
+		IncrementPercentage: 20,
+		MaxCapacity:         resource.MustParse("150Gi"),
+	}
+	tests := []struct {
+		current  string
+		expected string
+	}{
+		{"100Gi", "120Gi"},
+		{"10G", "12Gi"},
+		{"130Gi", "150Gi"},
+		{"150Gi", "150Gi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.current, func(t *testing.T) {
+			expanded := expandedCapacity(resource.MustParse(tt.current), ae)
+			assert.Equal(t, 0, expanded.Cmp(resource.MustParse(tt.expected)), expanded.String())
+		})
+	}
+}
+
+//nolint:funlen // this is ok for a test
+func TestExpandVolumes(t *testing.T) {
+	require.NoError(t, vectorizedv1alpha1.AddToScheme(scheme.Scheme))
+
+	cluster := &vectorizedv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Spec: vectorizedv1alpha1.ClusterSpec{
+			Replicas: pointer.Int32(3),
+			Storage:  vectorizedv1alpha1.StorageSpec{Capacity: resource.MustParse("200Gi")},
+		},
+	}
+	objs := []client.Object{
+		cluster,
+		&storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: "expandable"},
+			AllowVolumeExpansion: pointer.Bool(true),
+		},
+		&storagev1.StorageClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "fixed"},
+		},
+	}
+	pvcs := []struct {
+		storageClass string
+		requested    string
+		capacity     string
+		conditions   []corev1.PersistentVolumeClaimCondition
+	}{
+		{"expandable", "100Gi", "100Gi", nil},
+		{"expandable", "200Gi", "100Gi", []corev1.PersistentVolumeClaimCondition{{
+			Type:   corev1.PersistentVolumeClaimFileSystemResizePending,
+			Status: corev1.ConditionTrue,
+		}}},
+		{"fixed", "100Gi", "100Gi", nil},
+	}
+	for i, p := range pvcs {
+		name := fmt.Sprintf("cluster-%d", i)
+		objs = append(objs,
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels.ForCluster(cluster)},
+				Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+					Name: datadirName,
+					VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: datadirName + "-" + name,
+					}},
+				}}},
+			},
+			&corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: datadirName + "-" + name, Namespace: "default"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					StorageClassName: pointer.String(p.storageClass),
+					Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse(p.requested),
+					}},
+				},
+				Status: corev1.PersistentVolumeClaimStatus{
+					Capacity:   corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(p.capacity)},
+					Conditions: p.conditions,
+				},
+			})
+	}
+	c := fake.NewClientBuilder().WithObjects(objs...).Build()
+	r := StatefulSetResource{
+		Client:       c,
+		pandaCluster: cluster,
+		logger:       ctrl.Log.WithName("test"),
+	}
+
+	err := r.expandVolumes(context.Background())
+	var requeue *RequeueAfterError
+	require.ErrorAs(t, err, &requeue)
+
+	var pvc corev1.PersistentVolumeClaim
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "datadir-cluster-0"}, &pvc))
+	assert.Equal(t, "200Gi", pvc.Spec.Resources.Requests.Storage().String())
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "datadir-cluster-2"}, &pvc))
+	assert.Equal(t, "100Gi", pvc.Spec.Resources.Requests.Storage().String())
+
+	var updated vectorizedv1alpha1.Cluster
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(cluster), &updated))
+	require.NotNil(t, updated.Status.Storage)
+	phases := make([]vectorizedv1alpha1.VolumeExpansionPhase, 0, len(updated.Status.Storage.Volumes))
+	for _, v := range updated.Status.Storage.Volumes {
+		phases = append(phases, v.Phase)
+	}
+	assert.Equal(t, []vectorizedv1alpha1.VolumeExpansionPhase{
+		vectorizedv1alpha1.VolumeExpansionPhaseResizing,
+		vectorizedv1alpha1.VolumeExpansionPhaseFileSystemResizePending,
+		vectorizedv1alpha1.VolumeExpansionPhaseNotExpandable,
+	}, phases)
+	// The broker is restarted to resize its file system
+	assert.True(t, updated.Status.IsRestarting())
+}
+
+func TestPodEvictionFileSystemResizePending(t *testing.T) {
+	require.NoError(t, vectorizedv1alpha1.AddToScheme(scheme.Scheme))
+
+	cluster := &vectorizedv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Spec:       vectorizedv1alpha1.ClusterSpec{Replicas: pointer.Int32(1)},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-0", Namespace: "default", Labels: labels.ForCluster(cluster)},
+		Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+			Name: datadirName,
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: "datadir-cluster-0",
+			}},
+		}}},
+	}
+	c := fake.NewClientBuilder().WithObjects(cluster, pod).Build()
+	r := StatefulSetResource{
+		Client:       c,
+		pandaCluster: cluster,
+		logger:       ctrl.Log.WithName("test"),
+	}
+	volumes := map[string]interface{}{datadirName: new(interface{})}
+
+	// The Pod is kept when it matches the template
+	require.NoError(t, r.podEviction(context.Background(), pod, pod.DeepCopy(), volumes))
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(pod), &corev1.Pod{}))
+
+	cluster.Status.Storage = &vectorizedv1alpha1.StorageStatus{Volumes: []vectorizedv1alpha1.VolumeStatus{{
+		Name:  "datadir-cluster-0",
+		Phase: vectorizedv1alpha1.VolumeExpansionPhaseFileSystemResizePending,
+	}}}
+	err := r.podEviction(context.Background(), pod, pod.DeepCopy(), volumes)
+	var requeue *RequeueAfterError
+	require.ErrorAs(t, err, &requeue)
+	assert.True(t, apierrors.IsNotFound(c.Get(context.Background(), client.ObjectKeyFromObject(pod), &corev1.Pod{})))
+}
+
+func TestKeepDataVolumeClaimCapacity(t *testing.T) {
+	cluster := &vectorizedv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Spec: vectorizedv1alpha1.ClusterSpec{
+			Storage: vectorizedv1alpha1.StorageSpec{Capacity: resource.MustParse("100Gi")},
+		},
+	}
+	current := &appsv1.StatefulSet{}
+	setVolumes(current, cluster)
+
+	cluster.Status.Storage = &vectorizedv1alpha1.StorageStatus{Capacity: resource.MustParse("120Gi")}
+	modified := &appsv1.StatefulSet{}
+	setVolumes(modified, cluster)
+	require.Equal(t, "120Gi", modified.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests.Storage().String())
+
+	keepDataVolumeClaimCapacity(current, modified)
+	assert.Equal(t, current.Spec.VolumeClaimTemplates, modified.Spec.VolumeClaimTemplates)
+}
+
+func TestAutoExpandVolumes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/public_metrics" {
+			fmt.Fprint(w, autoscalingTestPublicMetrics)
+			return
+		}
+		fmt.Fprint(w, autoscalingTestMetrics)
+	}))
+	defer ts.Close()
+	UnderReplicatedPartitionsHostOverwrite = ts.Listener.Addr().String()
+	defer func() { UnderReplicatedPartitionsHostOverwrite = "" }()
+
+	tests := []struct {
+		threshold int32
+		expected  string
+	}{
+		{20, "120Gi"},
+		{30, "0"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("threshold %d", tt.threshold), func(t *testing.T) {
+			cluster := &vectorizedv1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+				Spec: vectorizedv1alpha1.ClusterSpec{
+					Configuration: vectorizedv1alpha1.RedpandaConfig{
+						AdminAPI: []vectorizedv1alpha1.AdminAPI{{Port: 9644}},
+					},
+					Storage: vectorizedv1alpha1.StorageSpec{
+						Capacity: resource.MustParse("100Gi"),
+						AutoExpansion: &vectorizedv1alpha1.VolumeAutoExpansion{
+							UsageThresholdPercentage: tt.threshold,
+							IncrementPercentage:      20,
+						},
+					},
+				},
+			}
+			podList := &corev1.PodList{Items: []corev1.Pod{{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-0", Namespace: "default"},
+				Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{
+					Type:   corev1.PodReady,
+					Status: corev1.ConditionTrue,
+				}}},
+			}}}
+			r := StatefulSetResource{
+				pandaCluster:   cluster,
+				logger:         ctrl.Log.WithName("test"),
+				metricsTimeout: time.Second,
+			}
+
+			var status vectorizedv1alpha1.StorageStatus
+			require.NoError(t, r.autoExpandVolumes(context.Background(), podList, &status))
+			assert.NotNil(t, status.LastEvaluationTime)
+			assert.Equal(t, tt.expected, status.Capacity.String())
+		})
+	}
+}
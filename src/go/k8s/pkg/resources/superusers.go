@@ -102,7 +102,7 @@ func (r *SuperUsersResource) Ensure(ctx context.Context) error {
 }
 
 func (r *SuperUsersResource) obj() (k8sclient.Object, error) {
-	password, err := generatePassword(scramPasswordLength)
+	password, err := GeneratePassword(scramPasswordLength)
 	if err != nil {
 		return nil, fmt.Errorf("could not generate SASL password: %w", err)
 	}
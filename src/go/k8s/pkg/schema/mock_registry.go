@@ -0,0 +1,100 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schema
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// MockRegistry is an in-memory Registry for tests. Schemas containing
+// "incompatible" are not compatible with any previous version.
+type MockRegistry struct {
+	monitor       sync.Mutex
+	subjects      map[string][]int
+	schemas       []string
+	compatibility map[string]string
+}
+
+var _ Registry = &MockRegistry{}
+
+// Versions returns the number of versions of the subject
+func (m *MockRegistry) Versions(subject string) int {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	return len(m.subjects[subject])
+}
+
+// Clear removes all subjects
+func (m *MockRegistry) Clear() {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	m.subjects = nil
+	m.schemas = nil
+	m.compatibility = nil
+}
+
+func (m *MockRegistry) Lookup(
+	_ context.Context, subject string, s *Schema,
+) (*SubjectSchema, error) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	for v, id := range m.subjects[subject] {
+		if m.schemas[id-1] == s.Schema {
+			return &SubjectSchema{Subject: subject, Version: v + 1, ID: id}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockRegistry) CheckCompatibility(
+	_ context.Context, subject string, s *Schema,
+) (bool, error) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	if len(m.subjects[subject]) == 0 {
+		return true, nil
+	}
+	return !strings.Contains(s.Schema, "incompatible"), nil
+}
+
+func (m *MockRegistry) Register(
+	_ context.Context, subject string, s *Schema,
+) error {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	if m.subjects == nil {
+		m.subjects = make(map[string][]int)
+	}
+	m.schemas = append(m.schemas, s.Schema)
+	m.subjects[subject] = append(m.subjects[subject], len(m.schemas))
+	return nil
+}
+
+func (m *MockRegistry) Compatibility(
+	_ context.Context, subject string,
+) (string, error) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	return m.compatibility[subject], nil
+}
+
+func (m *MockRegistry) SetCompatibility(
+	_ context.Context, subject, level string,
+) error {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	if m.compatibility == nil {
+		m.compatibility = make(map[string]string)
+	}
+	m.compatibility[subject] = level
+	return nil
+}
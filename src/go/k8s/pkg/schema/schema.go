@@ -0,0 +1,251 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package schema reconciles Schema Registry subjects with Schema custom
+// resources
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/httpapi"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	consolepkg "github.com/redpanda-data/redpanda/src/go/k8s/pkg/console"
+)
+
+const contentType = "application/vnd.schemaregistry.v1+json"
+
+type (
+	// Registry contains the Schema Registry functions used to manage schemas
+	Registry interface {
+		// Lookup returns the registered version of the schema in the
+		// subject, or nil if it is not registered
+		Lookup(ctx context.Context, subject string, s *Schema) (*SubjectSchema, error)
+		// CheckCompatibility returns whether the schema is compatible with
+		// the latest version of the subject
+		CheckCompatibility(ctx context.Context, subject string, s *Schema) (bool, error)
+		// Register registers the schema as a new version of the subject
+		Register(ctx context.Context, subject string, s *Schema) error
+		// Compatibility returns the compatibility level of the subject, or an
+		// empty string if it is not set for the subject
+		Compatibility(ctx context.Context, subject string) (string, error)
+		SetCompatibility(ctx context.Context, subject, level string) error
+	}
+
+	// RegistryFactory returns a Registry
+	RegistryFactory func(context.Context, client.Client, *vectorizedv1alpha1.Cluster, *consolepkg.Store) (Registry, error)
+)
+
+// Schema is a schema as sent to the Schema Registry
+type Schema struct {
+	Schema     string      `json:"schema"`
+	SchemaType string      `json:"schemaType,omitempty"`
+	References []Reference `json:"references,omitempty"`
+}
+
+// Reference is a reference to a schema of another subject
+type Reference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+var schemaTypes = map[vectorizedv1alpha1.SchemaType]string{
+	vectorizedv1alpha1.SchemaTypeAvro:     "AVRO",
+	vectorizedv1alpha1.SchemaTypeProtobuf: "PROTOBUF",
+	vectorizedv1alpha1.SchemaTypeJSON:     "JSON",
+}
+
+// New returns the Schema of the spec with the given definition
+func New(spec *vectorizedv1alpha1.SchemaSpec, definition string) *Schema {
+	s := &Schema{Schema: definition}
+	// Avro is the default schema type of the Schema Registry
+	if t := schemaTypes[spec.SchemaType]; t != "AVRO" {
+		s.SchemaType = t
+	}
+	for _, ref := range spec.References {
+		s.References = append(s.References, Reference{Name: ref.Name, Subject: ref.Subject, Version: ref.Version})
+	}
+	return s
+}
+
+// SubjectSchema is a registered version of a schema
+type SubjectSchema struct {
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+	ID      int    `json:"id"`
+}
+
+// Error is an error returned by the Schema Registry
+type Error struct {
+	StatusCode int    `json:"-"`
+	ErrorCode  int    `json:"error_code"`
+	Message    string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("schema registry error %d: %s", e.ErrorCode, e.Message)
+}
+
+func isNotFound(err error) bool {
+	var srErr *Error
+	return errors.As(err, &srErr) && srErr.StatusCode == http.StatusNotFound
+}
+
+// NewRegistry returns a Registry for the Schema Registry of the Cluster
+func NewRegistry(
+	ctx context.Context,
+	cl client.Client,
+	cluster *vectorizedv1alpha1.Cluster,
+	store *consolepkg.Store,
+) (Registry, error) {
+	httpCl, err := consolepkg.NewSchemaRegistryClient(ctx, cl, cluster, store)
+	if err != nil {
+		return nil, err
+	}
+	return NewHTTPRegistry(httpCl), nil
+}
+
+// NewHTTPRegistry returns a Registry using the Schema Registry HTTP API
+func NewHTTPRegistry(cl *httpapi.Client) Registry {
+	return &httpRegistry{cl.With(
+		httpapi.Headers("Accept", contentType, "Content-Type", contentType),
+		httpapi.Err4xx(func(code int) error { return &Error{StatusCode: code} }),
+	)}
+}
+
+type httpRegistry struct {
+	cl *httpapi.Client
+}
+
+func (r *httpRegistry) Lookup(
+	ctx context.Context, subject string, s *Schema,
+) (*SubjectSchema, error) {
+	var ss SubjectSchema
+	err := r.cl.Post(ctx, httpapi.Pathfmt("/subjects/%s", subject), nil, contentType, s, &ss)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ss, nil
+}
+
+func (r *httpRegistry) CheckCompatibility(
+	ctx context.Context, subject string, s *Schema,
+) (bool, error) {
+	var res struct {
+		IsCompatible bool `json:"is_compatible"`
+	}
+	err := r.cl.Post(ctx, httpapi.Pathfmt("/compatibility/subjects/%s/versions/latest", subject), nil, contentType, s, &res)
+	if isNotFound(err) {
+		// Any schema is compatible with a new subject
+		return true, nil
+	}
+	return res.IsCompatible, err
+}
+
+func (r *httpRegistry) Register(
+	ctx context.Context, subject string, s *Schema,
+) error {
+	return r.cl.Post(ctx, httpapi.Pathfmt("/subjects/%s/versions", subject), nil, contentType, s, nil)
+}
+
+func (r *httpRegistry) Compatibility(
+	ctx context.Context, subject string,
+) (string, error) {
+	var res struct {
+		CompatibilityLevel string `json:"compatibilityLevel"`
+	}
+	err := r.cl.Get(ctx, httpapi.Pathfmt("/config/%s", subject), nil, &res)
+	if isNotFound(err) {
+		return "", nil
+	}
+	return res.CompatibilityLevel, err
+}
+
+func (r *httpRegistry) SetCompatibility(
+	ctx context.Context, subject, level string,
+) error {
+	body := struct {
+		Compatibility string `json:"compatibility"`
+	}{level}
+	return r.cl.Put(ctx, httpapi.Pathfmt("/config/%s", subject), nil, body, nil)
+}
+
+// Result is the state of a subject after reconciling a schema
+type Result struct {
+	// Registered is the registered version of the schema, nil when the
+	// schema is incompatible
+	Registered *SubjectSchema
+
+	// Incompatible is true when the schema is not compatible with the latest
+	// version of the subject and was not registered
+	Incompatible bool
+
+	// Changes describes what was changed for the subject to match the spec
+	Changes []string
+}
+
+// Reconcile sets the compatibility level of the subject and registers the
+// schema as a new version of the subject, unless it is already registered or
+// is incompatible with the latest version.
+func Reconcile(
+	ctx context.Context, reg Registry, subject string, s *Schema, compatibility string,
+) (Result, error) {
+	var res Result
+
+	if compatibility != "" {
+		current, err := reg.Compatibility(ctx, subject)
+		if err != nil {
+			return res, fmt.Errorf("getting compatibility level of subject %q: %w", subject, err)
+		}
+		if current != compatibility {
+			if err := reg.SetCompatibility(ctx, subject, compatibility); err != nil {
+				return res, fmt.Errorf("setting compatibility level of subject %q: %w", subject, err)
+			}
+			res.Changes = append(res.Changes, fmt.Sprintf("compatibility level set to %s", compatibility))
+		}
+	}
+
+	registered, err := reg.Lookup(ctx, subject, s)
+	if err != nil {
+		return res, fmt.Errorf("looking up schema in subject %q: %w", subject, err)
+	}
+	if registered != nil {
+		res.Registered = registered
+		return res, nil
+	}
+
+	compatible, err := reg.CheckCompatibility(ctx, subject, s)
+	if err != nil {
+		return res, fmt.Errorf("checking compatibility of schema in subject %q: %w", subject, err)
+	}
+	if !compatible {
+		res.Incompatible = true
+		return res, nil
+	}
+
+	if err := reg.Register(ctx, subject, s); err != nil {
+		return res, fmt.Errorf("registering schema in subject %q: %w", subject, err)
+	}
+	registered, err = reg.Lookup(ctx, subject, s)
+	if err != nil {
+		return res, fmt.Errorf("looking up schema in subject %q: %w", subject, err)
+	}
+	res.Registered = registered
+	res.Changes = append(res.Changes, "schema registered")
+	return res, nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schema_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/httpapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/schema"
+)
+
+// registryHandler serves the Schema Registry API from a MockRegistry
+type registryHandler struct {
+	reg *schema.MockRegistry
+}
+
+func (h *registryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		schema.Schema
+		Compatibility string `json:"compatibility"`
+	}
+	if r.Body != nil {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}
+	reply := func(code int, v interface{}) {
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	notFound := func() {
+		reply(http.StatusNotFound, map[string]interface{}{"error_code": 40401, "message": "not found"})
+	}
+
+	ctx := r.Context()
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == http.MethodPost && len(parts) == 2 && parts[0] == "subjects":
+		ss, _ := h.reg.Lookup(ctx, parts[1], &body.Schema)
+		if ss == nil {
+			notFound()
+			return
+		}
+		reply(http.StatusOK, ss)
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[0] == "subjects":
+		_ = h.reg.Register(ctx, parts[1], &body.Schema)
+		ss, _ := h.reg.Lookup(ctx, parts[1], &body.Schema)
+		reply(http.StatusOK, map[string]int{"id": ss.ID})
+	case r.Method == http.MethodPost && parts[0] == "compatibility":
+		if h.reg.Versions(parts[2]) == 0 {
+			notFound()
+			return
+		}
+		compatible, _ := h.reg.CheckCompatibility(ctx, parts[2], &body.Schema)
+		reply(http.StatusOK, map[string]bool{"is_compatible": compatible})
+	case r.Method == http.MethodGet && parts[0] == "config":
+		level, _ := h.reg.Compatibility(ctx, parts[1])
+		if level == "" {
+			notFound()
+			return
+		}
+		reply(http.StatusOK, map[string]string{"compatibilityLevel": level})
+	case r.Method == http.MethodPut && parts[0] == "config":
+		_ = h.reg.SetCompatibility(ctx, parts[1], body.Compatibility)
+		reply(http.StatusOK, map[string]string{"compatibility": body.Compatibility})
+	default:
+		notFound()
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	mock := &schema.MockRegistry{}
+	srv := httptest.NewServer(&registryHandler{mock})
+	defer srv.Close()
+	reg := schema.NewHTTPRegistry(httpapi.NewClient(httpapi.Host(srv.URL), httpapi.Retries(0)))
+
+	v1 := &schema.Schema{Schema: `{"type":"string"}`}
+	res, err := schema.Reconcile(ctx, reg, "orders-value", v1, "BACKWARD")
+	require.NoError(t, err)
+	assert.Equal(t, &schema.SubjectSchema{Subject: "orders-value", Version: 1, ID: 1}, res.Registered)
+	assert.Equal(t, []string{"compatibility level set to BACKWARD", "schema registered"}, res.Changes)
+	level, err := mock.Compatibility(ctx, "orders-value")
+	require.NoError(t, err)
+	assert.Equal(t, "BACKWARD", level)
+
+	// Nothing to do once the schema is registered.
+	res, err = schema.Reconcile(ctx, reg, "orders-value", v1, "BACKWARD")
+	require.NoError(t, err)
+	assert.Empty(t, res.Changes)
+	assert.Equal(t, 1, res.Registered.Version)
+
+	// Incompatible schemas are not registered.
+	res, err = schema.Reconcile(ctx, reg, "orders-value", &schema.Schema{Schema: `"incompatible"`}, "")
+	require.NoError(t, err)
+	assert.True(t, res.Incompatible)
+	assert.Nil(t, res.Registered)
+	assert.Equal(t, 1, mock.Versions("orders-value"))
+
+	res, err = schema.Reconcile(ctx, reg, "orders-value", &schema.Schema{Schema: `{"type":"bytes"}`}, "")
+	require.NoError(t, err)
+	assert.Equal(t, &schema.SubjectSchema{Subject: "orders-value", Version: 2, ID: 2}, res.Registered)
+}
+
+func TestNew(t *testing.T) {
+	s := schema.New(&vectorizedv1alpha1.SchemaSpec{
+		SchemaType: vectorizedv1alpha1.SchemaTypeProtobuf,
+		References: []vectorizedv1alpha1.SchemaReference{{Name: "common.proto", Subject: "common", Version: 2}},
+	}, "syntax = \"proto3\";")
+	assert.Equal(t, &schema.Schema{
+		Schema:     "syntax = \"proto3\";",
+		SchemaType: "PROTOBUF",
+		References: []schema.Reference{{Name: "common.proto", Subject: "common", Version: 2}},
+	}, s)
+	// The schema type is omitted for Avro, the default
+	assert.Empty(t, schema.New(&vectorizedv1alpha1.SchemaSpec{SchemaType: vectorizedv1alpha1.SchemaTypeAvro}, "{}").SchemaType)
+}
@@ -0,0 +1,203 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"context"
+	"sync"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// MockTopic is a topic held by MockAdminClient
+type MockTopic struct {
+	Partitions int
+	Replicas   int
+	Configs    map[string]string
+}
+
+// MockAdminClient is an in-memory AdminClient for tests
+type MockAdminClient struct {
+	monitor sync.Mutex
+	topics  map[string]*MockTopic
+	altered []kadm.AlterConfig
+}
+
+var _ AdminClient = &MockAdminClient{}
+
+// SetTopic creates or replaces a topic
+func (m *MockAdminClient) SetTopic(name string, t MockTopic) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	if m.topics == nil {
+		m.topics = make(map[string]*MockTopic)
+	}
+	if t.Configs == nil {
+		t.Configs = make(map[string]string)
+	}
+	m.topics[name] = &t
+}
+
+// GetTopic returns a copy of a topic and whether it exists
+func (m *MockAdminClient) GetTopic(name string) (MockTopic, bool) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	t, ok := m.topics[name]
+	if !ok {
+		return MockTopic{}, false
+	}
+	res := MockTopic{Partitions: t.Partitions, Replicas: t.Replicas, Configs: make(map[string]string, len(t.Configs))}
+	for k, v := range t.Configs {
+		res.Configs[k] = v
+	}
+	return res, true
+}
+
+// Altered returns the configs sent to AlterTopicConfigs
+func (m *MockAdminClient) Altered() []kadm.AlterConfig {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	return append([]kadm.AlterConfig(nil), m.altered...)
+}
+
+// Clear removes all topics
+func (m *MockAdminClient) Clear() {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	m.topics = nil
+	m.altered = nil
+}
+
+func (m *MockAdminClient) ListTopics(
+	_ context.Context, topics ...string,
+) (kadm.TopicDetails, error) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	details := make(kadm.TopicDetails)
+	for _, name := range topics {
+		t, ok := m.topics[name]
+		if !ok {
+			details[name] = kadm.TopicDetail{Topic: name, Err: kerr.UnknownTopicOrPartition}
+			continue
+		}
+		d := kadm.TopicDetail{Topic: name, Partitions: make(kadm.PartitionDetails)}
+		for p := 0; p < t.Partitions; p++ {
+			d.Partitions[int32(p)] = kadm.PartitionDetail{Topic: name, Partition: int32(p), Replicas: make([]int32, t.Replicas)}
+		}
+		details[name] = d
+	}
+	return details, nil
+}
+
+// CreateTopic creates a topic, defaulting to 1 partition and 3 replicas
+func (m *MockAdminClient) CreateTopic(
+	_ context.Context, partitions int32, replicationFactor int16, configs map[string]*string, name string,
+) (kadm.CreateTopicResponse, error) {
+	if _, exists := m.GetTopic(name); exists {
+		return kadm.CreateTopicResponse{Topic: name, Err: kerr.TopicAlreadyExists}, kerr.TopicAlreadyExists
+	}
+	if partitions == -1 {
+		partitions = 1
+	}
+	if replicationFactor == -1 {
+		replicationFactor = 3
+	}
+	t := MockTopic{Partitions: int(partitions), Replicas: int(replicationFactor), Configs: make(map[string]string)}
+	for k, v := range configs {
+		t.Configs[k] = *v
+	}
+	m.SetTopic(name, t)
+	return kadm.CreateTopicResponse{Topic: name}, nil
+}
+
+func (m *MockAdminClient) UpdatePartitions(
+	_ context.Context, set int, topics ...string,
+) (kadm.CreatePartitionsResponses, error) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	resps := make(kadm.CreatePartitionsResponses)
+	for _, name := range topics {
+		t, ok := m.topics[name]
+		if !ok {
+			resps[name] = kadm.CreatePartitionsResponse{Topic: name, Err: kerr.UnknownTopicOrPartition}
+			continue
+		}
+		t.Partitions = set
+		resps[name] = kadm.CreatePartitionsResponse{Topic: name}
+	}
+	return resps, nil
+}
+
+func (m *MockAdminClient) DescribeTopicConfigs(
+	_ context.Context, topics ...string,
+) (kadm.ResourceConfigs, error) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	var rcs kadm.ResourceConfigs
+	for _, name := range topics {
+		t, ok := m.topics[name]
+		if !ok {
+			rcs = append(rcs, kadm.ResourceConfig{Name: name, Err: kerr.UnknownTopicOrPartition})
+			continue
+		}
+		rc := kadm.ResourceConfig{Name: name, Configs: []kadm.Config{
+			{Key: "retention.bytes", Value: kadm.StringPtr("-1"), Source: kmsg.ConfigSourceDefaultConfig},
+		}}
+		for k, v := range t.Configs {
+			rc.Configs = append(rc.Configs, kadm.Config{Key: k, Value: kadm.StringPtr(v), Source: kmsg.ConfigSourceDynamicTopicConfig})
+		}
+		rcs = append(rcs, rc)
+	}
+	return rcs, nil
+}
+
+func (m *MockAdminClient) AlterTopicConfigs(
+	_ context.Context, configs []kadm.AlterConfig, topics ...string,
+) (kadm.AlterConfigsResponses, error) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	var resps kadm.AlterConfigsResponses
+	for _, name := range topics {
+		t, ok := m.topics[name]
+		if !ok {
+			resps = append(resps, kadm.AlterConfigsResponse{Name: name, Err: kerr.UnknownTopicOrPartition})
+			continue
+		}
+		for _, c := range configs {
+			if c.Op == kadm.DeleteConfig {
+				delete(t.Configs, c.Name)
+			} else {
+				t.Configs[c.Name] = *c.Value
+			}
+		}
+		resps = append(resps, kadm.AlterConfigsResponse{Name: name})
+	}
+	m.altered = append(m.altered, configs...)
+	return resps, nil
+}
+
+func (m *MockAdminClient) DeleteTopics(
+	_ context.Context, topics ...string,
+) (kadm.DeleteTopicResponses, error) {
+	m.monitor.Lock()
+	defer m.monitor.Unlock()
+	resps := make(kadm.DeleteTopicResponses)
+	for _, name := range topics {
+		if _, ok := m.topics[name]; !ok {
+			resps[name] = kadm.DeleteTopicResponse{Topic: name, Err: kerr.UnknownTopicOrPartition}
+			continue
+		}
+		delete(m.topics, name)
+		resps[name] = kadm.DeleteTopicResponse{Topic: name}
+	}
+	return resps, nil
+}
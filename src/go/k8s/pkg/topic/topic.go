@@ -0,0 +1,234 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package topic reconciles Kafka topics with Topic custom resources
+package topic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	consolepkg "github.com/redpanda-data/redpanda/src/go/k8s/pkg/console"
+)
+
+// TopicFinalizer is the finalizer for deleting the Kafka topic
+const TopicFinalizer = "topics.redpanda.vectorized.io/finalizer"
+
+// ErrTopicExists is returned when creating a topic that was created by
+// someone else
+var ErrTopicExists = errors.New("topic already exists")
+
+type (
+	// AdminClient contains functions from kadm.Client used to manage topics
+	AdminClient interface {
+		ListTopics(context.Context, ...string) (kadm.TopicDetails, error)
+		CreateTopic(context.Context, int32, int16, map[string]*string, string) (kadm.CreateTopicResponse, error)
+		UpdatePartitions(context.Context, int, ...string) (kadm.CreatePartitionsResponses, error)
+		DescribeTopicConfigs(context.Context, ...string) (kadm.ResourceConfigs, error)
+		AlterTopicConfigs(context.Context, []kadm.AlterConfig, ...string) (kadm.AlterConfigsResponses, error)
+		DeleteTopics(context.Context, ...string) (kadm.DeleteTopicResponses, error)
+	}
+
+	// AdminClientFactory returns an AdminClient
+	AdminClientFactory func(context.Context, client.Client, *vectorizedv1alpha1.Cluster, *consolepkg.Store) (AdminClient, error)
+)
+
+// NewAdminClient returns a Kafka admin client for the Cluster
+func NewAdminClient(
+	ctx context.Context,
+	cl client.Client,
+	cluster *vectorizedv1alpha1.Cluster,
+	store *consolepkg.Store,
+) (AdminClient, error) {
+	return consolepkg.NewKadmClient(ctx, cl, cluster, store)
+}
+
+// Result is the state of a topic after reconciling it
+type Result struct {
+	// Created is true if the topic did not exist and was created. The
+	// observed partitions and replication factor are only known once the
+	// topic is reconciled again.
+	Created bool
+
+	Partitions        int32
+	ReplicationFactor int32
+
+	// Changes describes what was changed for the topic to match the spec
+	Changes []string
+
+	// Drift describes differences from the spec that cannot be corrected
+	Drift []string
+}
+
+// Exists tells if the topic exists
+func Exists(ctx context.Context, adm AdminClient, name string) (bool, error) {
+	topics, err := adm.ListTopics(ctx, name)
+	if err != nil {
+		return false, fmt.Errorf("listing topic %q: %w", name, err)
+	}
+	detail, exists := topics[name]
+	if !exists || errors.Is(detail.Err, kerr.UnknownTopicOrPartition) {
+		return false, nil
+	}
+	if detail.Err != nil {
+		return false, fmt.Errorf("describing topic %q: %w", name, detail.Err)
+	}
+	return true, nil
+}
+
+// Reconcile creates the topic, or adds partitions and alters configs so that
+// it matches the spec. Callers must make sure that an existing topic is
+// managed by the Topic, since every config that is not in the spec is reset.
+func Reconcile(
+	ctx context.Context, adm AdminClient, name string, spec *vectorizedv1alpha1.TopicSpec,
+) (Result, error) {
+	var res Result
+
+	topics, err := adm.ListTopics(ctx, name)
+	if err != nil {
+		return res, fmt.Errorf("listing topic %q: %w", name, err)
+	}
+	detail, exists := topics[name]
+	if !exists || errors.Is(detail.Err, kerr.UnknownTopicOrPartition) {
+		return create(ctx, adm, name, spec)
+	}
+	if detail.Err != nil {
+		return res, fmt.Errorf("describing topic %q: %w", name, detail.Err)
+	}
+
+	res.Partitions = int32(len(detail.Partitions))
+	if len(detail.Partitions) > 0 {
+		res.ReplicationFactor = int32(len(detail.Partitions[0].Replicas))
+	}
+
+	if want := spec.Partitions; want != nil {
+		switch {
+		case *want > res.Partitions:
+			resps, err := adm.UpdatePartitions(ctx, int(*want), name)
+			if err == nil {
+				err = resps[name].Err
+			}
+			if err != nil {
+				return res, fmt.Errorf("adding partitions to topic %q: %w", name, err)
+			}
+			res.Changes = append(res.Changes, fmt.Sprintf("partitions increased from %d to %d", res.Partitions, *want))
+			res.Partitions = *want
+		case *want < res.Partitions:
+			res.Drift = append(res.Drift, fmt.Sprintf("topic has %d partitions, more than the %d requested; partitions cannot be removed", res.Partitions, *want))
+		}
+	}
+	if want := spec.ReplicationFactor; want != nil && *want != res.ReplicationFactor {
+		res.Drift = append(res.Drift, fmt.Sprintf("topic has replication factor %d rather than %d; the replication factor cannot be changed by the operator", res.ReplicationFactor, *want))
+	}
+
+	changes, err := alterConfigs(ctx, adm, name, spec.Configs)
+	res.Changes = append(res.Changes, changes...)
+	return res, err
+}
+
+func create(
+	ctx context.Context, adm AdminClient, name string, spec *vectorizedv1alpha1.TopicSpec,
+) (Result, error) {
+	partitions, replicationFactor := int32(-1), int16(-1)
+	if spec.Partitions != nil {
+		partitions = *spec.Partitions
+	}
+	if spec.ReplicationFactor != nil {
+		replicationFactor = int16(*spec.ReplicationFactor)
+	}
+	configs := make(map[string]*string, len(spec.Configs))
+	for k, v := range spec.Configs {
+		configs[k] = kadm.StringPtr(v)
+	}
+	if _, err := adm.CreateTopic(ctx, partitions, replicationFactor, configs, name); err != nil {
+		if errors.Is(err, kerr.TopicAlreadyExists) {
+			return Result{}, fmt.Errorf("%w: %q", ErrTopicExists, name)
+		}
+		return Result{}, fmt.Errorf("creating topic %q: %w", name, err)
+	}
+	return Result{Created: true, Changes: []string{"topic created"}}, nil
+}
+
+// alterConfigs sets the configs that differ from the spec and resets the
+// configs that are set on the topic but are not in the spec.
+func alterConfigs(
+	ctx context.Context, adm AdminClient, name string, want map[string]string,
+) ([]string, error) {
+	described, err := adm.DescribeTopicConfigs(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("describing configs of topic %q: %w", name, err)
+	}
+	rc, err := described.On(name, nil)
+	if err == nil {
+		err = rc.Err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("describing configs of topic %q: %w", name, err)
+	}
+
+	current := make(map[string]string)
+	for _, c := range rc.Configs {
+		if c.Source == kmsg.ConfigSourceDynamicTopicConfig && c.Value != nil {
+			current[c.Key] = *c.Value
+		}
+	}
+
+	var (
+		alters  []kadm.AlterConfig
+		changes []string
+	)
+	for k, v := range want {
+		if have, ok := current[k]; ok && have == v {
+			continue
+		}
+		alters = append(alters, kadm.AlterConfig{Op: kadm.SetConfig, Name: k, Value: kadm.StringPtr(v)})
+		changes = append(changes, fmt.Sprintf("config %s set to %q", k, v))
+	}
+	for k := range current {
+		if _, ok := want[k]; !ok {
+			alters = append(alters, kadm.AlterConfig{Op: kadm.DeleteConfig, Name: k})
+			changes = append(changes, fmt.Sprintf("config %s reset to the default", k))
+		}
+	}
+	if len(alters) == 0 {
+		return nil, nil
+	}
+	sort.Strings(changes)
+
+	resps, err := adm.AlterTopicConfigs(ctx, alters, name)
+	if err != nil {
+		return nil, fmt.Errorf("altering configs of topic %q: %w", name, err)
+	}
+	for _, r := range resps {
+		if r.Err != nil {
+			return nil, fmt.Errorf("altering configs of topic %q: %w", name, r.Err)
+		}
+	}
+	return changes, nil
+}
+
+// Delete deletes the topic, if it exists
+func Delete(ctx context.Context, adm AdminClient, name string) error {
+	resps, err := adm.DeleteTopics(ctx, name)
+	if err == nil {
+		err = resps[name].Err
+	}
+	if err != nil && !errors.Is(err, kerr.UnknownTopicOrPartition) {
+		return fmt.Errorf("deleting topic %q: %w", name, err)
+	}
+	return nil
+}
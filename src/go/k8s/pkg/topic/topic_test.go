@@ -0,0 +1,96 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/pointer"
+
+	vectorizedv1alpha1 "github.com/redpanda-data/redpanda/src/go/k8s/apis/vectorized/v1alpha1"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/topic"
+)
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	adm := &topic.MockAdminClient{}
+	spec := &vectorizedv1alpha1.TopicSpec{
+		Partitions: pointer.Int32(3),
+		Configs:    map[string]string{"cleanup.policy": "compact"},
+	}
+
+	res, err := topic.Reconcile(ctx, adm, "foo", spec)
+	require.NoError(t, err)
+	assert.True(t, res.Created)
+	got, _ := adm.GetTopic("foo")
+	assert.Equal(t, topic.MockTopic{Partitions: 3, Replicas: 3, Configs: map[string]string{"cleanup.policy": "compact"}}, got)
+
+	// Nothing to do once the topic matches the spec.
+	res, err = topic.Reconcile(ctx, adm, "foo", spec)
+	require.NoError(t, err)
+	assert.Equal(t, topic.Result{Partitions: 3, ReplicationFactor: 3}, res)
+	assert.Empty(t, adm.Altered())
+
+	// Out-of-band changes are reverted.
+	adm.SetTopic("foo", topic.MockTopic{Partitions: 3, Replicas: 3, Configs: map[string]string{"cleanup.policy": "delete", "retention.ms": "1000"}})
+	res, err = topic.Reconcile(ctx, adm, "foo", spec)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`config cleanup.policy set to "compact"`,
+		"config retention.ms reset to the default",
+	}, res.Changes)
+	got, _ = adm.GetTopic("foo")
+	assert.Equal(t, map[string]string{"cleanup.policy": "compact"}, got.Configs)
+
+	// Partitions can be added, but only reported when there are too many.
+	spec.Partitions = pointer.Int32(6)
+	res, err = topic.Reconcile(ctx, adm, "foo", spec)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"partitions increased from 3 to 6"}, res.Changes)
+	assert.Equal(t, int32(6), res.Partitions)
+	got, _ = adm.GetTopic("foo")
+	assert.Equal(t, 6, got.Partitions)
+
+	spec.Partitions = pointer.Int32(2)
+	spec.ReplicationFactor = pointer.Int32(1)
+	res, err = topic.Reconcile(ctx, adm, "foo", spec)
+	require.NoError(t, err)
+	assert.Empty(t, res.Changes)
+	assert.Len(t, res.Drift, 2)
+	got, _ = adm.GetTopic("foo")
+	assert.Equal(t, 6, got.Partitions)
+}
+
+func TestExists(t *testing.T) {
+	ctx := context.Background()
+	adm := &topic.MockAdminClient{}
+	exists, err := topic.Exists(ctx, adm, "foo")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	adm.SetTopic("foo", topic.MockTopic{Partitions: 1, Replicas: 1})
+	exists, err = topic.Exists(ctx, adm, "foo")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestDelete(t *testing.T) {
+	ctx := context.Background()
+	adm := &topic.MockAdminClient{}
+	adm.SetTopic("foo", topic.MockTopic{Partitions: 1, Replicas: 1})
+	require.NoError(t, topic.Delete(ctx, adm, "foo"))
+	_, exists := adm.GetTopic("foo")
+	assert.False(t, exists)
+	// Deleting a topic that does not exist is not an error.
+	require.NoError(t, topic.Delete(ctx, adm, "foo"))
+}
@@ -0,0 +1,86 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package user reconciles SASL users with User custom resources
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// UserFinalizer is the finalizer for deleting the SASL user
+const UserFinalizer = "users.redpanda.vectorized.io/finalizer"
+
+// ErrUserExists is returned when the user exists but was not created by the
+// caller, so its credentials must not be changed
+var ErrUserExists = errors.New("user already exists")
+
+// AdminClient contains functions from the admin API used to manage users
+type AdminClient interface {
+	CreateUser(ctx context.Context, username, password, mechanism string) error
+	ListUsers(ctx context.Context) ([]string, error)
+	DeleteUser(ctx context.Context, username string) error
+	UpdateUser(ctx context.Context, username, password, mechanism string) error
+}
+
+// Reconcile creates the user, or updates its credentials when update is true.
+// An existing user is only updated when owned is true, meaning that it was
+// created by an earlier call; otherwise ErrUserExists is returned. It returns
+// whether the user was created.
+func Reconcile(
+	ctx context.Context, adm AdminClient, username, password, mechanism string, owned, update bool,
+) (bool, error) {
+	exists, err := Exists(ctx, adm, username)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		if err := adm.CreateUser(ctx, username, password, mechanism); err != nil {
+			return false, fmt.Errorf("creating user %q: %w", username, err)
+		}
+		return true, nil
+	}
+	if !owned {
+		return false, fmt.Errorf("%w: %q", ErrUserExists, username)
+	}
+	if update {
+		if err := adm.UpdateUser(ctx, username, password, mechanism); err != nil {
+			return false, fmt.Errorf("updating user %q: %w", username, err)
+		}
+	}
+	return false, nil
+}
+
+// Delete deletes the user, if it exists
+func Delete(ctx context.Context, adm AdminClient, username string) error {
+	exists, err := Exists(ctx, adm, username)
+	if err != nil || !exists {
+		return err
+	}
+	if err := adm.DeleteUser(ctx, username); err != nil {
+		return fmt.Errorf("deleting user %q: %w", username, err)
+	}
+	return nil
+}
+
+// Exists tells if the user exists
+func Exists(ctx context.Context, adm AdminClient, username string) (bool, error) {
+	users, err := adm.ListUsers(ctx)
+	if err != nil {
+		return false, fmt.Errorf("listing users: %w", err)
+	}
+	for _, u := range users {
+		if u == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
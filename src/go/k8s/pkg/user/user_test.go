@@ -0,0 +1,75 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package user_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	adminutils "github.com/redpanda-data/redpanda/src/go/k8s/pkg/admin"
+	"github.com/redpanda-data/redpanda/src/go/k8s/pkg/user"
+)
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	adm := &adminutils.MockAdminAPI{Log: logr.Discard()}
+
+	created, err := user.Reconcile(ctx, adm, "alice", "secret", "SCRAM-SHA-256", false, false)
+	require.NoError(t, err)
+	assert.True(t, created)
+	got, _ := adm.GetUser("alice")
+	assert.Equal(t, adminutils.MockUser{Password: "secret", Mechanism: "SCRAM-SHA-256"}, got)
+
+	// Existing users are only updated when asked to
+	created, err = user.Reconcile(ctx, adm, "alice", "other", "SCRAM-SHA-512", true, false)
+	require.NoError(t, err)
+	assert.False(t, created)
+	got, _ = adm.GetUser("alice")
+	assert.Equal(t, "secret", got.Password)
+
+	created, err = user.Reconcile(ctx, adm, "alice", "other", "SCRAM-SHA-512", true, true)
+	require.NoError(t, err)
+	assert.False(t, created)
+	got, _ = adm.GetUser("alice")
+	assert.Equal(t, adminutils.MockUser{Password: "other", Mechanism: "SCRAM-SHA-512"}, got)
+
+	// A user deleted out of band is created again
+	require.NoError(t, adm.DeleteUser(ctx, "alice"))
+	created, err = user.Reconcile(ctx, adm, "alice", "other", "SCRAM-SHA-512", true, false)
+	require.NoError(t, err)
+	assert.True(t, created)
+}
+
+func TestReconcileExistingUser(t *testing.T) {
+	ctx := context.Background()
+	adm := &adminutils.MockAdminAPI{Log: logr.Discard()}
+	adm.SetUser("admin", adminutils.MockUser{Password: "secret", Mechanism: "SCRAM-SHA-256"})
+
+	created, err := user.Reconcile(ctx, adm, "admin", "other", "SCRAM-SHA-256", false, true)
+	require.ErrorIs(t, err, user.ErrUserExists)
+	assert.False(t, created)
+	got, _ := adm.GetUser("admin")
+	assert.Equal(t, "secret", got.Password)
+}
+
+func TestDelete(t *testing.T) {
+	ctx := context.Background()
+	adm := &adminutils.MockAdminAPI{Log: logr.Discard()}
+	adm.SetUser("alice", adminutils.MockUser{})
+	require.NoError(t, user.Delete(ctx, adm, "alice"))
+	_, exists := adm.GetUser("alice")
+	assert.False(t, exists)
+	// Deleting a user that does not exist is not an error.
+	require.NoError(t, user.Delete(ctx, adm, "alice"))
+}
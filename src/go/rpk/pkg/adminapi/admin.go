@@ -30,6 +30,7 @@ import (
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/net"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/oauth"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/oauth/providers/auth0"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/oauth/providers/oidc"
 	"github.com/sethgrid/pester"
 	"github.com/spf13/afero"
 	"go.uber.org/zap"
@@ -47,7 +48,7 @@ type HTTPResponseError struct {
 
 type (
 	// Auth affixes auth to an http request.
-	Auth      interface{ apply(req *http.Request) }
+	Auth      interface{ apply(req *http.Request) error }
 	BasicAuth struct {
 		Username string
 		Password string
@@ -55,18 +56,34 @@ type (
 	BearerToken struct {
 		Token string
 	}
+	// TokenSourceAuth authenticates with a bearer token that is requested,
+	// and refreshed when needed, from an OIDC token endpoint.
+	TokenSourceAuth struct {
+		Source *oidc.TokenSource
+	}
 	NopAuth struct{}
 )
 
-func (a *BasicAuth) apply(req *http.Request) {
+func (a *BasicAuth) apply(req *http.Request) error {
 	req.SetBasicAuth(a.Username, a.Password)
+	return nil
 }
 
-func (a *BearerToken) apply(req *http.Request) {
+func (a *BearerToken) apply(req *http.Request) error {
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.Token))
+	return nil
 }
 
-func (*NopAuth) apply(*http.Request) {}
+func (a *TokenSourceAuth) apply(req *http.Request) error {
+	token, err := a.Source.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	return nil
+}
+
+func (*NopAuth) apply(*http.Request) error { return nil }
 
 // GenericErrorBody is the JSON decodable body that is produced by generic error
 // handling in the admin server when a seastar http exception is thrown.
@@ -89,6 +106,12 @@ type AdminAPI struct {
 
 func getAuth(p *config.RpkProfile) (Auth, error) {
 	switch {
+	case p.KafkaAPI.SASL != nil && strings.EqualFold(p.KafkaAPI.SASL.Mechanism, OAuthBearer):
+		source, err := oidc.NewTokenSource(p.KafkaAPI.SASL.OAuth)
+		if err != nil {
+			return nil, err
+		}
+		return &TokenSourceAuth{Source: source}, nil
 	case p.KafkaAPI.SASL != nil && p.KafkaAPI.SASL.Mechanism != CloudOIDC:
 		return &BasicAuth{Username: p.KafkaAPI.SASL.User, Password: p.KafkaAPI.SASL.Password}, nil
 	case p.KafkaAPI.SASL != nil && p.KafkaAPI.SASL.Mechanism == CloudOIDC:
@@ -585,7 +608,9 @@ func (a *AdminAPI) sendAndReceive(
 		return nil, err
 	}
 
-	a.auth.apply(req)
+	if err := a.auth.apply(req); err != nil {
+		return nil, fmt.Errorf("unable to authenticate %s %s: %w", method, url, err)
+	}
 
 	const applicationJSON = "application/json"
 	req.Header.Set("Content-Type", applicationJSON)
@@ -29,6 +29,11 @@ const (
 	ScramSha256 = "SCRAM-SHA-256"
 	ScramSha512 = "SCRAM-SHA-512"
 	CloudOIDC   = "CLOUD-OIDC"
+
+	// Plain and OAuthBearer are SASL mechanisms that rpk can authenticate
+	// with, but that cannot be used to create users.
+	Plain       = "PLAIN"
+	OAuthBearer = "OAUTHBEARER"
 )
 
 // CreateUser creates a user with the given username and password using the
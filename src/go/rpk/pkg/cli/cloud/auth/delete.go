@@ -65,6 +65,9 @@ func deleteAuth(
 	if idx == -1 {
 		return false, fmt.Errorf("cloud auth %q does not exist", name)
 	}
+	if err := y.CloudAuths[idx].EraseCredentials(fs); err != nil {
+		return false, err
+	}
 	y.CloudAuths = append(y.CloudAuths[:idx], y.CloudAuths[idx+1:]...)
 	ca := y.Auth(y.CurrentCloudAuth)
 	if wasUsing = ca != nil && ca.Name == name; wasUsing {
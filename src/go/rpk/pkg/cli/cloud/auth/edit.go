@@ -57,8 +57,20 @@ current cloud auth if no cloud auth is specified.
 					y.CurrentCloudAuth = update.Name
 				}
 			}
+			helper := a.CredentialHelper
 			*a = update
 
+			// Credential helpers key secrets by name, so a rename is
+			// applied in the old helper before the secrets move to
+			// the new one.
+			to := a.CredentialHelper
+			a.CredentialHelper = helper
+			err = a.RenameCredentials(fs, name)
+			a.CredentialHelper = to
+			out.MaybeDie(err, "unable to rename the credentials of cloud auth %q: %v", name, err)
+			err = a.SwitchCredentialHelper(fs, helper)
+			out.MaybeDie(err, "unable to move the credentials of cloud auth %q to the new credential helper: %v", a.Name, err)
+
 			err = y.Write(fs)
 			out.MaybeDie(err, "unable to write rpk.yaml: %v", err)
 
@@ -42,7 +42,10 @@ func newRenameToCommand(fs afero.Fs, p *config.Params) *cobra.Command {
 			if y.Auth(to) != nil {
 				out.Die("destination cloud auth %q already exists", to)
 			}
+			from := p.Name
 			p.Name = to
+			err = p.RenameCredentials(fs, from)
+			out.MaybeDie(err, "unable to rename the credentials of cloud auth %q: %v", from, err)
 			y.CurrentCloudAuth = to
 			y.MoveAuthToFront(p)
 			err = y.Write(fs)
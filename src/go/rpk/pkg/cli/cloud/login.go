@@ -92,7 +92,10 @@ and then re-specify the client credentials next time you log in.`)
 
 			if cc && save {
 				yAct, _ := cfg.ActualRpkYaml() // must exist due to LoadFlow checking
-				yAct.Auth(yAct.CurrentCloudAuth).ClientSecret = auth.ClientSecret
+				authAct := yAct.Auth(yAct.CurrentCloudAuth)
+				authAct.ClientSecret = auth.ClientSecret
+				err = authAct.StoreCredentials(fs)
+				out.MaybeDie(err, "unable to save client secret: %v", err)
 				err = yAct.Write(fs)
 				out.MaybeDie(err, "unable to save client ID and client secret: %v", err)
 			}
@@ -56,6 +56,8 @@ central configuration store (and via 'rpk cluster config edit').
 			out.MaybeDie(err, "unable to load config: %v", err)
 			p := cfg.VirtualProfile()
 			out.CheckExitCloudAdmin(p)
+			err = p.LoadCredentials(fs)
+			out.MaybeDie(err, "unable to load the profile credentials: %v", err)
 
 			client, err := adminapi.NewClient(fs, p)
 			out.MaybeDie(err, "unable to initialize admin client: %v", err)
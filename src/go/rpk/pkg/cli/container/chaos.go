@@ -0,0 +1,326 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/container/common"
+	"github.com/spf13/cobra"
+)
+
+// fault is a failure that can be injected into a node, and recovered from.
+type fault struct {
+	// verb describes the injected fault, e.g. "paused".
+	verb    string
+	inject  func(c common.Client, id uint) error
+	recover func(c common.Client, id uint) error
+}
+
+func newChaosCommand() *cobra.Command {
+	var duration time.Duration
+	command := &cobra.Command{
+		Use:   "chaos",
+		Short: "Inject faults into a local container cluster",
+		Long: `Inject faults into a local container cluster.
+
+These commands inject failures into specific nodes of a cluster started with
+'rpk container start', to test how clients behave when brokers fail: nodes can
+be paused, killed, restarted, partitioned from the network, or slowed down
+with network latency.
+
+With --duration, the command waits for the given time and then recovers the
+nodes; pressing Ctrl-C recovers them early. Without --duration, faults stay
+in place until 'rpk container chaos recover' is run.
+`,
+	}
+	command.PersistentFlags().DurationVar(&duration, "duration", 0, "Recover the nodes after this time (e.g. 30s, 5m); if 0, run 'rpk container chaos recover' to recover")
+
+	command.AddCommand(
+		newChaosFaultCommand(&duration, "pause", "Pause nodes, freezing all of their processes", fault{
+			verb:    "paused",
+			inject:  common.PauseNode,
+			recover: common.UnpauseNode,
+		}),
+		newChaosFaultCommand(&duration, "kill", "Kill nodes with SIGKILL, without a clean shutdown", fault{
+			verb:    "killed",
+			inject:  common.KillNode,
+			recover: common.StartNode,
+		}),
+		newChaosFaultCommand(&duration, "partition", "Isolate nodes by disconnecting them from the redpanda network", fault{
+			verb:    "partitioned",
+			inject:  common.DisconnectNode,
+			recover: common.ConnectNode,
+		}),
+		newChaosRestartCommand(&duration),
+		newChaosLatencyCommand(&duration),
+		newChaosRecoverCommand(),
+	)
+	return command
+}
+
+func newChaosFaultCommand(duration *time.Duration, use, short string, f fault) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " [NODE-IDS...]",
+		Short: short,
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			c, err := common.NewDockerClient()
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ids, err := parseChaosNodeIDs(c, args)
+			if err != nil {
+				return common.WrapIfConnErr(err)
+			}
+			return common.WrapIfConnErr(runFault(c, ids, f, *duration))
+		},
+	}
+}
+
+func newChaosRestartCommand(duration *time.Duration) *cobra.Command {
+	var timeout time.Duration
+	command := &cobra.Command{
+		Use:   "restart [NODE-IDS...]",
+		Short: "Stop nodes with a clean shutdown and start them again",
+		Long: `Stop nodes with a clean shutdown and start them again.
+
+Without --duration, nodes are started again as soon as they stop. With
+--duration, they stay stopped for the given time.
+`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			c, err := common.NewDockerClient()
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ids, err := parseChaosNodeIDs(c, args)
+			if err != nil {
+				return common.WrapIfConnErr(err)
+			}
+			if *duration == 0 {
+				for _, id := range ids {
+					fmt.Printf("Restarting node %d\n", id)
+					if err := common.RestartNode(c, id, timeout); err != nil {
+						return common.WrapIfConnErr(fmt.Errorf("unable to restart node %d: %v", id, err))
+					}
+				}
+				return nil
+			}
+			return common.WrapIfConnErr(runFault(c, ids, fault{
+				verb: "stopped",
+				inject: func(c common.Client, id uint) error {
+					return common.StopNode(c, id, timeout)
+				},
+				recover: common.StartNode,
+			}, *duration))
+		},
+	}
+	command.Flags().DurationVar(&timeout, "timeout", 20*time.Second, "The maximum time to wait for a clean shutdown before killing the nodes")
+	return command
+}
+
+func newChaosLatencyCommand(duration *time.Duration) *cobra.Command {
+	var delay, jitter time.Duration
+	command := &cobra.Command{
+		Use:   "latency [NODE-IDS...]",
+		Short: "Add latency to the network traffic of nodes",
+		Long: `Add latency to the network traffic of nodes.
+
+This command adds --delay (plus or minus --jitter) to all the outgoing traffic
+of the nodes using tc netem. It requires tc to be installed in the Redpanda
+image, and the NET_ADMIN capability, which nodes created by
+'rpk container start' have.
+`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if delay <= 0 {
+				return errors.New("--delay must be greater than 0")
+			}
+			c, err := common.NewDockerClient()
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ids, err := parseChaosNodeIDs(c, args)
+			if err != nil {
+				return common.WrapIfConnErr(err)
+			}
+			return common.WrapIfConnErr(runFault(c, ids, fault{
+				verb: fmt.Sprintf("delayed by %s", delay),
+				inject: func(c common.Client, id uint) error {
+					return common.AddLatency(c, id, delay, jitter)
+				},
+				recover: common.RemoveLatency,
+			}, *duration))
+		},
+	}
+	command.Flags().DurationVar(&delay, "delay", 100*time.Millisecond, "Latency to add to the outgoing traffic")
+	command.Flags().DurationVar(&jitter, "jitter", 0, "Random variation of the latency")
+	return command
+}
+
+func newChaosRecoverCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "recover [NODE-IDS...]",
+		Short: "Recover nodes from any injected fault (default: all nodes)",
+		RunE: func(_ *cobra.Command, args []string) error {
+			c, err := common.NewDockerClient()
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			var ids []uint
+			if len(args) > 0 {
+				ids, err = parseChaosNodeIDs(c, args)
+			} else {
+				ids, err = allNodeIDs(c)
+			}
+			if err != nil {
+				return common.WrapIfConnErr(err)
+			}
+			return common.WrapIfConnErr(recoverNodes(c, ids))
+		},
+	}
+}
+
+// runFault injects the fault into the nodes and, if duration is not 0, waits
+// and recovers them. If the fault cannot be injected into a node, the nodes
+// that were already affected are recovered.
+func runFault(c common.Client, ids []uint, f fault, duration time.Duration) error {
+	var injected []uint
+	for _, id := range ids {
+		if err := f.inject(c, id); err != nil {
+			err = fmt.Errorf("unable to inject the fault into node %d: %v", id, err)
+			if rerr := recoverFault(c, injected, f); rerr != nil {
+				return fmt.Errorf("%v; %v", err, rerr)
+			}
+			return err
+		}
+		injected = append(injected, id)
+		fmt.Printf("Node %d %s\n", id, f.verb)
+	}
+	if duration == 0 {
+		fmt.Println("Run 'rpk container chaos recover' to recover the nodes.")
+		return nil
+	}
+
+	fmt.Printf("Recovering in %s, press Ctrl-C to recover now...\n", duration)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	select {
+	case <-ctx.Done():
+	case <-time.After(duration):
+	}
+	return recoverFault(c, injected, f)
+}
+
+func recoverFault(c common.Client, ids []uint, f fault) error {
+	var errs []error
+	for _, id := range ids {
+		if err := f.recover(c, id); err != nil {
+			errs = append(errs, fmt.Errorf("unable to recover node %d: %v", id, err))
+			continue
+		}
+		fmt.Printf("Node %d recovered\n", id)
+	}
+	return errors.Join(errs...)
+}
+
+// recoverNodes recovers nodes from every kind of fault, based on their
+// current state.
+func recoverNodes(c common.Client, ids []uint) error {
+	var errs []error
+	for _, id := range ids {
+		state, err := common.GetState(c, id)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to get the state of node %d: %v", id, err))
+			continue
+		}
+		var steps []func() error
+		if state.Status == "paused" {
+			steps = append(steps, func() error { return common.UnpauseNode(c, id) })
+		} else if !state.Running {
+			steps = append(steps, func() error { return common.StartNode(c, id) })
+		}
+		if state.ContainerIP == "" {
+			steps = append(steps, func() error { return common.ConnectNode(c, id) })
+		}
+		var failed bool
+		for _, step := range steps {
+			if err := step(); err != nil {
+				errs = append(errs, fmt.Errorf("unable to recover node %d: %v", id, err))
+				failed = true
+				break
+			}
+		}
+		if failed {
+			continue
+		}
+		// tc fails if there is no latency to remove, or if it is not
+		// installed, in which case no latency could have been added.
+		common.RemoveLatency(c, id)
+		fmt.Printf("Node %d recovered\n", id)
+	}
+	return errors.Join(errs...)
+}
+
+func parseChaosNodeIDs(c common.Client, args []string) ([]uint, error) {
+	existing, err := allNodeIDs(c)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		return nil, errors.New("no cluster available; you may start a new cluster with 'rpk container start'")
+	}
+	exists := make(map[uint]bool, len(existing))
+	for _, id := range existing {
+		exists[id] = true
+	}
+	seen := make(map[uint]bool)
+	var ids []uint
+	for _, arg := range args {
+		id, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node ID %q: %v", arg, err)
+		}
+		if !exists[uint(id)] {
+			return nil, fmt.Errorf("node %d does not exist; existing nodes: %v", id, existing)
+		}
+		if !seen[uint(id)] {
+			seen[uint(id)] = true
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids, nil
+}
+
+func allNodeIDs(c common.Client) ([]uint, error) {
+	nodes, err := common.GetExistingNodes(c)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(nodes))
+	for _, n := range nodes {
+		ids = append(ids, n.ID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/container/common"
+	"github.com/stretchr/testify/require"
+)
+
+func mockCluster(n int) *common.MockClient {
+	return &common.MockClient{
+		MockContainerList: func(context.Context, types.ContainerListOptions) ([]types.Container, error) {
+			containers := make([]types.Container, n)
+			for i := range containers {
+				containers[i].Labels = map[string]string{"node-id": fmt.Sprint(n - 1 - i)}
+			}
+			return containers, nil
+		},
+	}
+}
+
+func TestParseChaosNodeIDs(t *testing.T) {
+	c := mockCluster(3)
+	ids, err := parseChaosNodeIDs(c, []string{"2", "0", "2"})
+	require.NoError(t, err)
+	require.Equal(t, []uint{2, 0}, ids)
+
+	_, err = parseChaosNodeIDs(c, []string{"3"})
+	require.Error(t, err)
+	_, err = parseChaosNodeIDs(c, []string{"one"})
+	require.Error(t, err)
+	_, err = parseChaosNodeIDs(mockCluster(0), []string{"0"})
+	require.Error(t, err)
+
+	all, err := allNodeIDs(c)
+	require.NoError(t, err)
+	require.Equal(t, []uint{0, 1, 2}, all)
+}
+
+func TestRunFault(t *testing.T) {
+	var events []string
+	f := fault{
+		verb: "paused",
+		inject: func(_ common.Client, id uint) error {
+			if id == 2 {
+				return errors.New("boom")
+			}
+			events = append(events, fmt.Sprintf("inject %d", id))
+			return nil
+		},
+		recover: func(_ common.Client, id uint) error {
+			events = append(events, fmt.Sprintf("recover %d", id))
+			return nil
+		},
+	}
+	c := mockCluster(3)
+
+	// Without a duration, nodes are left faulty.
+	require.NoError(t, runFault(c, []uint{0, 1}, f, 0))
+	require.Equal(t, []string{"inject 0", "inject 1"}, events)
+
+	// With a duration, nodes are recovered.
+	events = nil
+	require.NoError(t, runFault(c, []uint{1}, f, time.Millisecond))
+	require.Equal(t, []string{"inject 1", "recover 1"}, events)
+
+	// If a node fails, the nodes that were already faulty are recovered.
+	events = nil
+	require.Error(t, runFault(c, []uint{0, 1, 2}, f, time.Hour))
+	require.Equal(t, []string{"inject 0", "inject 1", "recover 0", "recover 1"}, events)
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package common
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+)
+
+// The network interface of the containers in the redpanda network.
+const containerIface = "eth0"
+
+// PauseNode freezes all processes of a node.
+func PauseNode(c Client, nodeID uint) error {
+	ctx, _ := DefaultCtx()
+	return c.ContainerPause(ctx, Name(nodeID))
+}
+
+// UnpauseNode resumes a paused node.
+func UnpauseNode(c Client, nodeID uint) error {
+	ctx, _ := DefaultCtx()
+	return c.ContainerUnpause(ctx, Name(nodeID))
+}
+
+// KillNode sends SIGKILL to a node, which stops without a clean shutdown.
+func KillNode(c Client, nodeID uint) error {
+	ctx, _ := DefaultCtx()
+	return c.ContainerKill(ctx, Name(nodeID), "SIGKILL")
+}
+
+// StartNode starts a stopped node.
+func StartNode(c Client, nodeID uint) error {
+	ctx, _ := DefaultCtx()
+	return c.ContainerStart(ctx, Name(nodeID), types.ContainerStartOptions{})
+}
+
+// StopNode stops a node, waiting up to timeout for a clean shutdown.
+func StopNode(c Client, nodeID uint, timeout time.Duration) error {
+	ctx, _ := DefaultCtx()
+	secs := int(timeout.Seconds())
+	return c.ContainerStop(ctx, Name(nodeID), container.StopOptions{Timeout: &secs})
+}
+
+// RestartNode stops a node, waiting up to timeout for a clean shutdown, and
+// starts it again.
+func RestartNode(c Client, nodeID uint, timeout time.Duration) error {
+	ctx, _ := DefaultCtx()
+	secs := int(timeout.Seconds())
+	return c.ContainerRestart(ctx, Name(nodeID), container.StopOptions{Timeout: &secs})
+}
+
+// DisconnectNode disconnects a node from the redpanda network, isolating it
+// from every other node and from the host.
+func DisconnectNode(c Client, nodeID uint) error {
+	ctx, _ := DefaultCtx()
+	return c.NetworkDisconnect(ctx, redpandaNetwork, Name(nodeID), true)
+}
+
+// ConnectNode connects a node back to the redpanda network, with the same IP
+// and alias it was created with.
+func ConnectNode(c Client, nodeID uint) error {
+	ip, err := nodeIP(c, redpandaNetwork, nodeID)
+	if err != nil {
+		return err
+	}
+	ctx, _ := DefaultCtx()
+	return c.NetworkConnect(ctx, redpandaNetwork, Name(nodeID), &network.EndpointSettings{
+		IPAMConfig: &network.EndpointIPAMConfig{
+			IPv4Address: ip,
+		},
+		Aliases: []string{Name(nodeID)},
+	})
+}
+
+// AddLatency delays all the outgoing traffic of a node using tc netem. The
+// container needs the NET_ADMIN capability, which nodes created by
+// 'rpk container start' have, and tc must be installed in the image.
+func AddLatency(c Client, nodeID uint, delay, jitter time.Duration) error {
+	cmd := []string{"tc", "qdisc", "replace", "dev", containerIface, "root", "netem", "delay", tcDuration(delay)}
+	if jitter > 0 {
+		cmd = append(cmd, tcDuration(jitter))
+	}
+	return execInNode(c, nodeID, cmd)
+}
+
+// RemoveLatency removes any latency added with AddLatency.
+func RemoveLatency(c Client, nodeID uint) error {
+	return execInNode(c, nodeID, []string{"tc", "qdisc", "del", "dev", containerIface, "root"})
+}
+
+func tcDuration(d time.Duration) string {
+	return fmt.Sprintf("%dus", d.Microseconds())
+}
+
+// execInNode runs a command as root in a node and waits for it to finish.
+func execInNode(c Client, nodeID uint, cmd []string) error {
+	ctx, cancel := DefaultCtx()
+	defer cancel()
+	exec, err := c.ContainerExecCreate(ctx, Name(nodeID), types.ExecConfig{
+		User: "root",
+		Cmd:  cmd,
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.ContainerExecStart(ctx, exec.ID, types.ExecStartCheck{}); err != nil {
+		return err
+	}
+	for {
+		inspect, err := c.ContainerExecInspect(ctx, exec.ID)
+		if err != nil {
+			return err
+		}
+		if !inspect.Running {
+			if inspect.ExitCode != 0 {
+				return fmt.Errorf("%q exited with code %d", strings.Join(cmd, " "), inspect.ExitCode)
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
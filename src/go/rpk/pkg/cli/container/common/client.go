@@ -56,6 +56,35 @@ type Client interface {
 		options container.StopOptions,
 	) error
 
+	ContainerPause(ctx context.Context, containerID string) error
+
+	ContainerUnpause(ctx context.Context, containerID string) error
+
+	ContainerKill(ctx context.Context, containerID, signal string) error
+
+	ContainerRestart(
+		ctx context.Context,
+		containerID string,
+		options container.StopOptions,
+	) error
+
+	ContainerExecCreate(
+		ctx context.Context,
+		containerID string,
+		config types.ExecConfig,
+	) (types.IDResponse, error)
+
+	ContainerExecStart(
+		ctx context.Context,
+		execID string,
+		config types.ExecStartCheck,
+	) error
+
+	ContainerExecInspect(
+		ctx context.Context,
+		execID string,
+	) (types.ContainerExecInspect, error)
+
 	ContainerList(
 		ctx context.Context,
 		options types.ContainerListOptions,
@@ -91,6 +120,20 @@ type Client interface {
 		options types.NetworkInspectOptions,
 	) (types.NetworkResource, error)
 
+	NetworkConnect(
+		ctx context.Context,
+		networkID, containerID string,
+		config *network.EndpointSettings,
+	) error
+
+	NetworkDisconnect(
+		ctx context.Context,
+		networkID, containerID string,
+		force bool,
+	) error
+
+	VolumeRemove(ctx context.Context, volumeID string, force bool) error
+
 	IsErrNotFound(err error) bool
 
 	IsErrConnectionFailed(err error) bool
@@ -22,6 +22,7 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 	"github.com/docker/go-connections/nat"
@@ -38,20 +39,48 @@ const (
 	redpandaNetwork   = "redpanda"
 	externalKafkaPort = 9093
 
+	// The data directory of Redpanda in the container image, where
+	// persistent volumes are mounted.
+	containerDataDir = "/var/lib/redpanda/data"
+
+	// Container labels used to record the options of each node, so that
+	// they can be displayed by 'rpk container status'.
+	clusterIDLabel = "cluster-id"
+	nodeIDLabel    = "node-id"
+	rackLabel      = "rack"
+	volumeLabel    = "volume"
+
 	defaultDockerClientTimeout = 60 * time.Second
 )
 
 type NodeState struct {
-	Status        string
-	Running       bool
-	ConfigFile    string
-	HostRPCPort   uint
-	HostKafkaPort uint
-	HostAdminPort uint
-	HostProxyPort uint
-	ID            uint
-	ContainerIP   string
-	ContainerID   string
+	Status            string
+	Running           bool
+	ConfigFile        string
+	HostRPCPort       uint
+	HostKafkaPort     uint
+	HostAdminPort     uint
+	HostProxyPort     uint
+	HostSchemaRegPort uint
+	ID                uint
+	ContainerIP       string
+	ContainerID       string
+	Image             string
+	Rack              string
+	Volume            string
+}
+
+// NodeOptions are the options that can differ between the nodes of a
+// container cluster.
+type NodeOptions struct {
+	// Image is the container image of the node, which allows running
+	// different Redpanda versions in the same cluster.
+	Image string
+	// Rack is the rack of the node, empty for no rack.
+	Rack string
+	// Volume is whether the node's data directory is stored in a named
+	// volume, which persists when the container is removed.
+	Volume bool
 }
 
 func ListenAddresses(ip string, internalPort, externalPort uint) string {
@@ -75,6 +104,11 @@ func Name(nodeID uint) string {
 	return fmt.Sprintf("rp-node-%d", nodeID)
 }
 
+// Returns the name of the data volume for the given node ID.
+func VolumeName(nodeID uint) string {
+	return fmt.Sprintf("rp-node-%d-data", nodeID)
+}
+
 func DefaultImage() string {
 	return redpandaImageBase
 }
@@ -104,7 +138,7 @@ func GetExistingNodes(c Client) ([]*NodeState, error) {
 
 	nodes := make([]*NodeState, len(containers))
 	for i, cont := range containers {
-		nodeIDStr := cont.Labels["node-id"]
+		nodeIDStr := cont.Labels[nodeIDLabel]
 		nodeID, err := strconv.ParseUint(nodeIDStr, 10, 64)
 		if err != nil {
 			return nil, fmt.Errorf(
@@ -167,17 +201,31 @@ func GetState(c Client, nodeID uint) (*NodeState, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &NodeState{
-		Running:       containerJSON.State.Running,
-		Status:        containerJSON.State.Status,
-		ContainerID:   containerJSON.ID,
-		ContainerIP:   ipAddress,
-		HostKafkaPort: hostKafkaPort,
-		HostRPCPort:   hostRPCPort,
-		HostAdminPort: hostAdminPort,
-		HostProxyPort: hostProxyPort,
-		ID:            nodeID,
-	}, nil
+	hostSchemaRegPort, err := getHostPort(
+		config.DefaultSchemaRegPort,
+		containerJSON,
+	)
+	if err != nil {
+		return nil, err
+	}
+	state := &NodeState{
+		Running:           containerJSON.State.Running,
+		Status:            containerJSON.State.Status,
+		ContainerID:       containerJSON.ID,
+		ContainerIP:       ipAddress,
+		HostKafkaPort:     hostKafkaPort,
+		HostRPCPort:       hostRPCPort,
+		HostAdminPort:     hostAdminPort,
+		HostProxyPort:     hostProxyPort,
+		HostSchemaRegPort: hostSchemaRegPort,
+		ID:                nodeID,
+	}
+	if containerJSON.Config != nil {
+		state.Image = containerJSON.Config.Image
+		state.Rack = containerJSON.Config.Labels[rackLabel]
+		state.Volume = containerJSON.Config.Labels[volumeLabel]
+	}
+	return state, nil
 }
 
 // Creates a network for the cluster's containers and returns its ID. If it
@@ -238,7 +286,8 @@ func RemoveNetwork(c Client) error {
 func CreateNode(
 	c Client,
 	nodeID, kafkaPort, proxyPort, schemaRegPort, rpcPort, metricsPort uint,
-	netID, image string,
+	netID string,
+	opts NodeOptions,
 	args ...string,
 ) (*NodeState, error) {
 	rPort, err := nat.NewPort(
@@ -302,8 +351,25 @@ func CreateNode(
 		net.JoinHostPort(ip, strconv.Itoa(config.DevDefault().Redpanda.RPCServer.Port)),
 		"--mode dev-container",
 	}
+	labels := map[string]string{
+		clusterIDLabel: "redpanda",
+		nodeIDLabel:    fmt.Sprint(nodeID),
+	}
+	if opts.Rack != "" {
+		cmd = append(cmd, "--set", "redpanda.rack="+opts.Rack)
+		labels[rackLabel] = opts.Rack
+	}
+	var mounts []mount.Mount
+	if opts.Volume {
+		mounts = append(mounts, mount.Mount{
+			Type:   mount.TypeVolume,
+			Source: VolumeName(nodeID),
+			Target: containerDataDir,
+		})
+		labels[volumeLabel] = VolumeName(nodeID)
+	}
 	containerConfig := container.Config{
-		Image:    image,
+		Image:    opts.Image,
 		Hostname: hostname,
 		Cmd:      append(cmd, args...),
 		ExposedPorts: nat.PortSet{
@@ -312,10 +378,7 @@ func CreateNode(
 			sPort: {},
 			kPort: {},
 		},
-		Labels: map[string]string{
-			"cluster-id": "redpanda",
-			"node-id":    fmt.Sprint(nodeID),
-		},
+		Labels: labels,
 	}
 	hostConfig := container.HostConfig{
 		PortBindings: nat.PortMap{
@@ -335,6 +398,10 @@ func CreateNode(
 				HostPort: fmt.Sprint(metricsPort),
 			}},
 		},
+		Mounts: mounts,
+		// NET_ADMIN allows 'rpk container chaos latency' to add network
+		// latency with tc.
+		CapAdd: []string{"NET_ADMIN"},
 	}
 	networkConfig := network.NetworkingConfig{
 		EndpointsConfig: map[string]*network.EndpointSettings{
@@ -364,9 +431,22 @@ func CreateNode(
 		ID:            nodeID,
 		ContainerID:   container.ID,
 		ContainerIP:   ip,
+		Image:         opts.Image,
+		Rack:          opts.Rack,
+		Volume:        labels[volumeLabel],
 	}, nil
 }
 
+// Removes the named volume, if it exists.
+func RemoveVolume(c Client, name string) error {
+	ctx, _ := DefaultCtx()
+	err := c.VolumeRemove(ctx, name, true)
+	if c.IsErrNotFound(err) {
+		return nil
+	}
+	return err
+}
+
 func PullImage(c Client, image string) error {
 	fmt.Printf("Pulling image: %s\n", image)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
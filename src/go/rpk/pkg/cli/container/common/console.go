@@ -0,0 +1,145 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package common
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+)
+
+const (
+	// ConsoleName is the container name of Redpanda Console.
+	ConsoleName = "rp-console"
+
+	consoleImage = "docker.redpanda.com/redpandadata/console:latest"
+	consolePort  = 8080
+
+	// The console uses the last address of the network, far from the
+	// addresses of the nodes.
+	consoleIPOffset = 252
+)
+
+type ConsoleState struct {
+	Status      string
+	Running     bool
+	HostPort    uint
+	Image       string
+	ContainerID string
+}
+
+func DefaultConsoleImage() string {
+	return consoleImage
+}
+
+// GetConsole returns the state of the Console container, or nil if it does
+// not exist.
+func GetConsole(c Client) (*ConsoleState, error) {
+	ctx, _ := DefaultCtx()
+	containerJSON, err := c.ContainerInspect(ctx, ConsoleName)
+	if err != nil {
+		if c.IsErrNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if containerJSON.NetworkSettings == nil || containerJSON.ContainerJSONBase == nil {
+		return nil, fmt.Errorf("unable to inspect the container %v, please make sure you have Docker installed and running", ConsoleName)
+	}
+	hostPort, err := getHostPort(consolePort, containerJSON)
+	if err != nil {
+		return nil, err
+	}
+	state := &ConsoleState{
+		Running:     containerJSON.State.Running,
+		Status:      containerJSON.State.Status,
+		HostPort:    hostPort,
+		ContainerID: containerJSON.ID,
+	}
+	if containerJSON.Config != nil {
+		state.Image = containerJSON.Config.Image
+	}
+	return state, nil
+}
+
+// CreateConsole creates a Redpanda Console container connected to the given
+// nodes through their internal listeners, listening on hostPort.
+func CreateConsole(
+	c Client, netID, image string, hostPort uint, nodeIDs []uint,
+) (*ConsoleState, error) {
+	port, err := nat.NewPort("tcp", strconv.Itoa(consolePort))
+	if err != nil {
+		return nil, err
+	}
+	ip, err := nodeIP(c, netID, consoleIPOffset)
+	if err != nil {
+		return nil, err
+	}
+	var brokers, schemaRegs, admins []string
+	for _, id := range nodeIDs {
+		host := Name(id)
+		brokers = append(brokers, net.JoinHostPort(host, strconv.Itoa(config.DefaultKafkaPort)))
+		schemaRegs = append(schemaRegs, "http://"+net.JoinHostPort(host, strconv.Itoa(config.DefaultSchemaRegPort)))
+		admins = append(admins, "http://"+net.JoinHostPort(host, strconv.Itoa(config.DefaultAdminPort)))
+	}
+	containerConfig := container.Config{
+		Image:    image,
+		Hostname: ConsoleName,
+		Env: []string{
+			"KAFKA_BROKERS=" + strings.Join(brokers, ","),
+			"KAFKA_SCHEMAREGISTRY_ENABLED=true",
+			"KAFKA_SCHEMAREGISTRY_URLS=" + strings.Join(schemaRegs, ","),
+			"REDPANDA_ADMINAPI_ENABLED=true",
+			"REDPANDA_ADMINAPI_URLS=" + strings.Join(admins, ","),
+		},
+		ExposedPorts: nat.PortSet{port: {}},
+		Labels: map[string]string{
+			clusterIDLabel: "redpanda",
+		},
+	}
+	hostConfig := container.HostConfig{
+		PortBindings: nat.PortMap{
+			port: []nat.PortBinding{{HostPort: fmt.Sprint(hostPort)}},
+		},
+	}
+	networkConfig := network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{
+			redpandaNetwork: {
+				IPAMConfig: &network.EndpointIPAMConfig{
+					IPv4Address: ip,
+				},
+				Aliases: []string{ConsoleName},
+			},
+		},
+	}
+	ctx, _ := DefaultCtx()
+	resp, err := c.ContainerCreate(
+		ctx,
+		&containerConfig,
+		&hostConfig,
+		&networkConfig,
+		nil,
+		ConsoleName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &ConsoleState{
+		HostPort:    hostPort,
+		Image:       image,
+		ContainerID: resp.ID,
+	}, nil
+}
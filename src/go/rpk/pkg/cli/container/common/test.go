@@ -55,6 +55,35 @@ type MockClient struct {
 		options container.StopOptions,
 	) error
 
+	MockContainerPause func(ctx context.Context, containerID string) error
+
+	MockContainerUnpause func(ctx context.Context, containerID string) error
+
+	MockContainerKill func(ctx context.Context, containerID, signal string) error
+
+	MockContainerRestart func(
+		ctx context.Context,
+		containerID string,
+		options container.StopOptions,
+	) error
+
+	MockContainerExecCreate func(
+		ctx context.Context,
+		containerID string,
+		config types.ExecConfig,
+	) (types.IDResponse, error)
+
+	MockContainerExecStart func(
+		ctx context.Context,
+		execID string,
+		config types.ExecStartCheck,
+	) error
+
+	MockContainerExecInspect func(
+		ctx context.Context,
+		execID string,
+	) (types.ContainerExecInspect, error)
+
 	MockContainerList func(
 		ctx context.Context,
 		options types.ContainerListOptions,
@@ -93,6 +122,24 @@ type MockClient struct {
 		options types.NetworkInspectOptions,
 	) (types.NetworkResource, error)
 
+	MockNetworkConnect func(
+		ctx context.Context,
+		networkID, containerID string,
+		config *network.EndpointSettings,
+	) error
+
+	MockNetworkDisconnect func(
+		ctx context.Context,
+		networkID, containerID string,
+		force bool,
+	) error
+
+	MockVolumeRemove func(
+		ctx context.Context,
+		volumeID string,
+		force bool,
+	) error
+
 	MockIsErrNotFound func(err error) bool
 
 	MockIsErrConnectionFailed func(err error) bool
@@ -164,6 +211,63 @@ func (c *MockClient) ContainerStop(
 	return nil
 }
 
+func (c *MockClient) ContainerPause(ctx context.Context, containerID string) error {
+	if c.MockContainerPause != nil {
+		return c.MockContainerPause(ctx, containerID)
+	}
+	return nil
+}
+
+func (c *MockClient) ContainerUnpause(ctx context.Context, containerID string) error {
+	if c.MockContainerUnpause != nil {
+		return c.MockContainerUnpause(ctx, containerID)
+	}
+	return nil
+}
+
+func (c *MockClient) ContainerKill(ctx context.Context, containerID, signal string) error {
+	if c.MockContainerKill != nil {
+		return c.MockContainerKill(ctx, containerID, signal)
+	}
+	return nil
+}
+
+func (c *MockClient) ContainerRestart(
+	ctx context.Context, containerID string, options container.StopOptions,
+) error {
+	if c.MockContainerRestart != nil {
+		return c.MockContainerRestart(ctx, containerID, options)
+	}
+	return nil
+}
+
+func (c *MockClient) ContainerExecCreate(
+	ctx context.Context, containerID string, config types.ExecConfig,
+) (types.IDResponse, error) {
+	if c.MockContainerExecCreate != nil {
+		return c.MockContainerExecCreate(ctx, containerID, config)
+	}
+	return types.IDResponse{}, nil
+}
+
+func (c *MockClient) ContainerExecStart(
+	ctx context.Context, execID string, config types.ExecStartCheck,
+) error {
+	if c.MockContainerExecStart != nil {
+		return c.MockContainerExecStart(ctx, execID, config)
+	}
+	return nil
+}
+
+func (c *MockClient) ContainerExecInspect(
+	ctx context.Context, execID string,
+) (types.ContainerExecInspect, error) {
+	if c.MockContainerExecInspect != nil {
+		return c.MockContainerExecInspect(ctx, execID)
+	}
+	return types.ContainerExecInspect{}, nil
+}
+
 func (c *MockClient) ContainerList(
 	ctx context.Context, options types.ContainerListOptions,
 ) ([]types.Container, error) {
@@ -225,6 +329,33 @@ func (c *MockClient) NetworkInspect(
 	return types.NetworkResource{}, nil
 }
 
+func (c *MockClient) NetworkConnect(
+	ctx context.Context, networkID, containerID string, config *network.EndpointSettings,
+) error {
+	if c.MockNetworkConnect != nil {
+		return c.MockNetworkConnect(ctx, networkID, containerID, config)
+	}
+	return nil
+}
+
+func (c *MockClient) NetworkDisconnect(
+	ctx context.Context, networkID, containerID string, force bool,
+) error {
+	if c.MockNetworkDisconnect != nil {
+		return c.MockNetworkDisconnect(ctx, networkID, containerID, force)
+	}
+	return nil
+}
+
+func (c *MockClient) VolumeRemove(
+	ctx context.Context, volumeID string, force bool,
+) error {
+	if c.MockVolumeRemove != nil {
+		return c.MockVolumeRemove(ctx, volumeID, force)
+	}
+	return nil
+}
+
 func (c *MockClient) IsErrNotFound(err error) bool {
 	if c.MockIsErrNotFound != nil {
 		return c.MockIsErrNotFound(err)
@@ -85,6 +85,30 @@ func purgeCluster(c common.Client) error {
 	if err != nil {
 		return err
 	}
+	ctx, _ := common.DefaultCtx()
+	err = c.ContainerRemove(
+		ctx,
+		common.ConsoleName,
+		types.ContainerRemoveOptions{
+			RemoveVolumes: true,
+			Force:         true,
+		},
+	)
+	if err == nil {
+		fmt.Printf("Removed container %s\n", common.ConsoleName)
+	} else if !c.IsErrNotFound(err) {
+		return err
+	}
+	// Named volumes are not removed with their container.
+	for _, node := range nodes {
+		if node.Volume == "" {
+			continue
+		}
+		if err := common.RemoveVolume(c, node.Volume); err != nil {
+			return fmt.Errorf("unable to remove volume %s: %v", node.Volume, err)
+		}
+		fmt.Printf("Removed volume %s (node %d)\n", node.Volume, node.ID)
+	}
 	err = common.RemoveNetwork(c)
 	if err != nil {
 		return err
@@ -13,6 +13,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"net"
 	"os"
 	"sort"
@@ -50,15 +51,60 @@ func collectFlags(args []string, flag string) []string {
 	return flags
 }
 
+// clusterOptions are the options of a new container cluster.
+type clusterOptions struct {
+	// nodes holds the options of each node; node i has ID i.
+	nodes []common.NodeOptions
+	// proxyPort and schemaRegPort are the host ports of the first node's
+	// Pandaproxy and Schema Registry, incremented by one for every other
+	// node. If 0, random free ports are used.
+	proxyPort     uint
+	schemaRegPort uint
+	// console is whether to start a Redpanda Console container.
+	console      bool
+	consoleImage string
+}
+
 func newStartCommand() *cobra.Command {
 	var (
-		nodes   uint
-		retries uint
-		image   string
+		nodes         uint
+		retries       uint
+		image         string
+		nodeImages    []string
+		racks         []string
+		proxyPort     uint
+		schemaRegPort uint
+		volumes       bool
+		console       bool
+		consoleImage  string
 	)
 	command := &cobra.Command{
 		Use:   "start",
 		Short: "Start a local container cluster",
+		Long: `Start a local container cluster.
+
+This command starts a cluster of --nodes Redpanda containers. If a cluster was
+started previously and is stopped, it is started again; to change the number
+of nodes or any of their options, purge the cluster first with
+'rpk container purge'.
+
+Nodes can be customized individually to reproduce realistic topologies:
+
+  * --racks assigns racks to the nodes round-robin and enables rack awareness
+    in the cluster. For example, --nodes 6 --racks a,b,c places two nodes in
+    each of the three racks.
+  * --node-image runs a specific node with a different image, e.g. to test a
+    rolling upgrade: --node-image 2=vectorized/redpanda:v23.1.13.
+  * --proxy-port and --schema-registry-port bind the Pandaproxy and Schema
+    Registry of the first node to fixed host ports, and of every other node to
+    the following ports. The command fails if any of these ports is in use.
+  * --volumes stores the data of each node in a named volume
+    (rp-node-<ID>-data), which is only deleted by 'rpk container purge'.
+  * --console also starts Redpanda Console, connected to every node.
+
+The options of each node are recorded in its container labels and are shown
+by 'rpk container status'.
+`,
 		FParseErrWhitelist: cobra.FParseErrWhitelist{
 			// Allow unknown flags so that arbitrary flags can be passed
 			// through to the containers without the need to pass '--'
@@ -71,6 +117,10 @@ func newStartCommand() *cobra.Command {
 					"--nodes should be 1 or greater",
 				)
 			}
+			nodeOpts, err := nodeOptions(nodes, image, nodeImages, racks, volumes)
+			if err != nil {
+				return err
+			}
 			c, err := common.NewDockerClient()
 			if err != nil {
 				return err
@@ -81,10 +131,15 @@ func newStartCommand() *cobra.Command {
 
 			return common.WrapIfConnErr(startCluster(
 				c,
-				nodes,
+				clusterOptions{
+					nodes:         nodeOpts,
+					proxyPort:     proxyPort,
+					schemaRegPort: schemaRegPort,
+					console:       console,
+					consoleImage:  consoleImage,
+				},
 				checkBrokers,
 				retries,
-				image,
 				configKvs,
 			))
 		},
@@ -114,17 +169,98 @@ func newStartCommand() *cobra.Command {
 	)
 	command.Flags().MarkHidden(imageFlag)
 
+	command.Flags().StringSliceVar(
+		&nodeImages,
+		"node-image",
+		nil,
+		"Container image of a specific node, in the format <node ID>=<image>; repeatable",
+	)
+	command.Flags().StringSliceVar(
+		&racks,
+		"racks",
+		nil,
+		"Comma-separated list of racks to assign to the nodes round-robin; enables rack awareness",
+	)
+	command.Flags().UintVar(
+		&proxyPort,
+		"proxy-port",
+		0,
+		"Host port of the first node's Pandaproxy; other nodes use the following ports (default: random ports)",
+	)
+	command.Flags().UintVar(
+		&schemaRegPort,
+		"schema-registry-port",
+		0,
+		"Host port of the first node's Schema Registry; other nodes use the following ports (default: random ports)",
+	)
+	command.Flags().BoolVar(
+		&volumes,
+		"volumes",
+		false,
+		"Store the data of each node in a named volume that persists until 'rpk container purge'",
+	)
+	command.Flags().BoolVar(
+		&console,
+		"console",
+		false,
+		"Also start a Redpanda Console container",
+	)
+	consoleImageFlag := "console-image"
+	command.Flags().StringVar(
+		&consoleImage,
+		consoleImageFlag,
+		common.DefaultConsoleImage(),
+		"An arbitrary Redpanda Console container image to use.",
+	)
+	command.Flags().MarkHidden(consoleImageFlag)
+
 	return command
 }
 
+// nodeOptions returns the options of each of the n nodes. Every node uses
+// image unless it is overridden with nodeImages (<node ID>=<image>), and
+// racks are assigned round-robin.
+func nodeOptions(
+	n uint, image string, nodeImages, racks []string, volumes bool,
+) ([]common.NodeOptions, error) {
+	opts := make([]common.NodeOptions, n)
+	for i := range opts {
+		opts[i].Image = image
+		opts[i].Volume = volumes
+		if len(racks) > 0 {
+			opts[i].Rack = racks[i%len(racks)]
+		}
+	}
+	for _, r := range racks {
+		if strings.TrimSpace(r) == "" {
+			return nil, errors.New("--racks cannot contain empty rack names")
+		}
+	}
+	for _, ni := range nodeImages {
+		idStr, img, ok := strings.Cut(ni, "=")
+		if !ok || img == "" {
+			return nil, fmt.Errorf("invalid --node-image %q, expected <node ID>=<image>", ni)
+		}
+		id, err := strconv.ParseUint(idStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node ID in --node-image %q: %v", ni, err)
+		}
+		if id >= uint64(n) {
+			return nil, fmt.Errorf("invalid --node-image %q: node IDs go from 0 to %d", ni, n-1)
+		}
+		opts[id].Image = img
+	}
+	return opts, nil
+}
+
 func startCluster(
 	c common.Client,
-	n uint,
+	opts clusterOptions,
 	check func([]node) func() error,
 	retries uint,
-	image string,
 	extraArgs []string,
 ) error {
+	n := uint(len(opts.nodes))
 	// Check if cluster exists and start it again.
 	restarted, err := restartCluster(c, check, retries)
 	if err != nil {
@@ -135,21 +271,25 @@ func startCluster(
 		fmt.Print("Found an existing cluster:\n\n")
 		renderClusterInfo(c)
 		if len(restarted) != int(n) {
-			fmt.Print("\nTo change the number of nodes, first purge the existing cluster with\n'rpk container purge'.\n\n")
+			fmt.Print("\nTo change the number of nodes or their options, first purge the existing\ncluster with 'rpk container purge'.\n\n")
 		}
 		return nil
 	}
 
-	fmt.Println("Checking for a local image...")
-	present, checkErr := common.CheckIfImgPresent(c, image)
-	if checkErr != nil {
-		fmt.Printf("Error trying to list local images: %v\n", err)
+	fixed, err := fixedPorts(n, opts)
+	if err != nil {
+		return err
 	}
-	if !present {
-		// If the image isn't present locally, try to pull it.
-		fmt.Println("Downloading latest version of redpanda")
-		err = common.PullImage(c, image)
-		if err != nil {
+
+	images := make(map[string]bool)
+	for _, o := range opts.nodes {
+		images[o.Image] = true
+	}
+	if opts.console {
+		images[opts.consoleImage] = true
+	}
+	for image := range images {
+		if err := ensureImage(c, image); err != nil {
 			return err
 		}
 	}
@@ -161,17 +301,46 @@ func startCluster(
 	}
 
 	reqPorts := n * 5 // we need 5 ports per node
-	ports, err := vnet.GetFreePortPool(int(reqPorts))
+	if opts.console {
+		reqPorts++
+	}
+	// The pool may include free ports that we bind to the fixed ports.
+	pool, err := vnet.GetFreePortPool(int(reqPorts) + len(fixed))
 	if err != nil {
 		return err
 	}
+	ports := make([]uint, 0, reqPorts)
+	for _, p := range pool {
+		if !fixed[p] && len(ports) < int(reqPorts) {
+			ports = append(ports, p)
+		}
+	}
+	proxyPort := func(id uint) uint {
+		if opts.proxyPort > 0 {
+			return opts.proxyPort + id
+		}
+		return ports[1+5*id]
+	}
+	schemaRegPort := func(id uint) uint {
+		if opts.schemaRegPort > 0 {
+			return opts.schemaRegPort + id
+		}
+		return ports[2+5*id]
+	}
+
+	for _, o := range opts.nodes {
+		if o.Rack != "" {
+			extraArgs = append(extraArgs, "--set", "redpanda.enable_rack_awareness=true")
+			break
+		}
+	}
 
 	// Start a seed node.
 	var (
 		seedID            uint
 		seedKafkaPort     = ports[0]
-		seedProxyPort     = ports[1]
-		seedSchemaRegPort = ports[2]
+		seedProxyPort     = proxyPort(seedID)
+		seedSchemaRegPort = schemaRegPort(seedID)
 		seedRPCPort       = ports[3]
 		seedMetricsPort   = ports[4]
 	)
@@ -185,7 +354,7 @@ func startCluster(
 		seedRPCPort,
 		seedMetricsPort,
 		netID,
-		image,
+		opts.nodes[seedID],
 		extraArgs...,
 	)
 	if err != nil {
@@ -217,8 +386,8 @@ func startCluster(
 		grp.Go(func() error {
 			var (
 				kafkaPort     = ports[0+5*id]
-				proxyPort     = ports[1+5*id]
-				schemaRegPort = ports[2+5*id]
+				proxyPort     = proxyPort(id)
+				schemaRegPort = schemaRegPort(id)
 				rpcPort       = ports[3+5*id]
 				metricsPort   = ports[4+5*id]
 			)
@@ -239,7 +408,7 @@ func startCluster(
 				rpcPort,
 				metricsPort,
 				netID,
-				image,
+				opts.nodes[id],
 				append(args, extraArgs...)...,
 			)
 			if err != nil {
@@ -271,13 +440,43 @@ func startCluster(
 		return err
 	}
 
+	if opts.console {
+		ids := make([]uint, 0, n)
+		for id := uint(0); id < n; id++ {
+			ids = append(ids, id)
+		}
+		fmt.Println("Starting Redpanda Console...")
+		console, err := common.CreateConsole(c, netID, opts.consoleImage, ports[reqPorts-1], ids)
+		if err != nil {
+			return err
+		}
+		if err := startNode(c, console.ContainerID); err != nil {
+			return err
+		}
+	}
+
 	fmt.Println("Cluster started!")
 	dockerNodes, err := renderClusterInfo(c)
 	if err != nil {
 		return err
 	}
-	renderClusterInteract(dockerNodes)
+	renderClusterInteract(c, dockerNodes)
+
+	return nil
+}
 
+// ensureImage pulls the image if it is not present locally.
+func ensureImage(c common.Client, image string) error {
+	fmt.Printf("Checking for a local image %s...\n", image)
+	present, checkErr := common.CheckIfImgPresent(c, image)
+	if checkErr != nil {
+		fmt.Printf("Error trying to list local images: %v\n", checkErr)
+	}
+	if !present {
+		// If the image isn't present locally, try to pull it.
+		fmt.Printf("Downloading %s\n", image)
+		return common.PullImage(c, image)
+	}
 	return nil
 }
 
@@ -331,6 +530,15 @@ func restartCluster(
 	if err != nil {
 		return nil, err
 	}
+	console, err := common.GetConsole(c)
+	if err != nil {
+		return nil, err
+	}
+	if console != nil && !console.Running {
+		if err := startNode(c, console.ContainerID); err != nil {
+			return nil, err
+		}
+	}
 	return nodes, nil
 }
 
@@ -386,36 +594,54 @@ func renderClusterInfo(c common.Client) ([]*common.NodeState, error) {
 		return nil, nil
 	}
 
-	tw := out.NewTable("Node-ID", "Status", "Kafka-Address", "Admin-Address", "Proxy-Address")
+	// Racks, images and volumes are only shown if they are used, to keep
+	// the default single-image cluster output short.
+	var withRack, withImage, withVolume bool
+	for _, node := range nodes {
+		withRack = withRack || node.Rack != ""
+		withImage = withImage || node.Image != nodes[0].Image
+		withVolume = withVolume || node.Volume != ""
+	}
+	headers := []string{"Node-ID", "Status"}
+	if withRack {
+		headers = append(headers, "Rack")
+	}
+	if withImage {
+		headers = append(headers, "Image")
+	}
+	if withVolume {
+		headers = append(headers, "Volume")
+	}
+	headers = append(headers, "Kafka-Address", "Admin-Address", "Proxy-Address", "Schema-Registry-Address")
+
+	tw := out.NewTable(headers...)
 	defer tw.Flush()
 	sort.Slice(nodes, func(i, j int) bool {
 		return nodes[i].ID < nodes[j].ID
 	})
 	for _, node := range nodes {
-		kafka := nodeAddr(node.HostKafkaPort)
-		admin := nodeAddr(node.HostAdminPort)
-		proxy := nodeAddr(node.HostProxyPort)
-		if node.HostKafkaPort == 0 {
-			kafka = "-"
+		row := []string{fmt.Sprint(node.ID), node.Status}
+		if withRack {
+			row = append(row, orDash(node.Rack))
 		}
-		if node.HostAdminPort == 0 {
-			admin = "-"
+		if withImage {
+			row = append(row, orDash(node.Image))
 		}
-		if node.HostProxyPort == 0 {
-			proxy = "-"
+		if withVolume {
+			row = append(row, orDash(node.Volume))
 		}
-		tw.PrintStrings(
-			fmt.Sprint(node.ID),
-			node.Status,
-			kafka,
-			admin,
-			proxy,
+		row = append(row,
+			portAddr(node.HostKafkaPort),
+			portAddr(node.HostAdminPort),
+			portAddr(node.HostProxyPort),
+			portAddr(node.HostSchemaRegPort),
 		)
+		tw.PrintStrings(row...)
 	}
 	return nodes, nil
 }
 
-func renderClusterInteract(nodes []*common.NodeState) {
+func renderClusterInteract(c common.Client, nodes []*common.NodeState) {
 	var (
 		brokers    []string
 		adminAddrs []string
@@ -448,6 +674,64 @@ broker and admin API addresses:
 	b := strings.Join(brokers, ",")
 	a := strings.Join(adminAddrs, ",")
 	fmt.Printf(m, b, a, b, a)
+
+	console, err := common.GetConsole(c)
+	if err != nil || console == nil || !console.Running {
+		return
+	}
+	fmt.Printf("Redpanda Console is available at http://%s\n\n", nodeAddr(console.HostPort))
+}
+
+// fixedPorts returns the host ports set with --proxy-port and
+// --schema-registry-port for every node, failing if they are out of range,
+// overlap, or are not available.
+func fixedPorts(n uint, opts clusterOptions) (map[uint]bool, error) {
+	fixed := make(map[uint]bool)
+	for _, f := range []struct {
+		flag string
+		port uint
+	}{
+		{"--proxy-port", opts.proxyPort},
+		{"--schema-registry-port", opts.schemaRegPort},
+	} {
+		if f.port == 0 {
+			continue
+		}
+		for id := uint(0); id < n; id++ {
+			p := f.port + id
+			if p > math.MaxUint16 {
+				return nil, fmt.Errorf("invalid %s %d: port %d of node %d is out of range", f.flag, f.port, p, id)
+			}
+			if fixed[p] {
+				return nil, fmt.Errorf("invalid %s %d: port %d of node %d is already used by another fixed port", f.flag, f.port, p, id)
+			}
+			fixed[p] = true
+		}
+	}
+	for p := range fixed {
+		l, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(int(p))))
+		if err != nil {
+			return nil, fmt.Errorf("fixed host port %d is not available: %v", p, err)
+		}
+		l.Close()
+	}
+	return fixed, nil
+}
+
+// portAddr returns the host address of a port, or "-" if the port is not
+// bound.
+func portAddr(port uint) string {
+	if port == 0 {
+		return "-"
+	}
+	return nodeAddr(port)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
 }
 
 func nodeAddr(port uint) string {
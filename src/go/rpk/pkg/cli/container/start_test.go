@@ -0,0 +1,89 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package container
+
+import (
+	"net"
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/container/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeOptions(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		n          uint
+		nodeImages []string
+		racks      []string
+		volumes    bool
+		exp        []common.NodeOptions
+		expErr     bool
+	}{
+		{
+			name: "defaults",
+			n:    2,
+			exp:  []common.NodeOptions{{Image: "rp:latest"}, {Image: "rp:latest"}},
+		},
+		{
+			name:    "racks round-robin and volumes",
+			n:       4,
+			racks:   []string{"a", "b", "c"},
+			volumes: true,
+			exp: []common.NodeOptions{
+				{Image: "rp:latest", Rack: "a", Volume: true},
+				{Image: "rp:latest", Rack: "b", Volume: true},
+				{Image: "rp:latest", Rack: "c", Volume: true},
+				{Image: "rp:latest", Rack: "a", Volume: true},
+			},
+		},
+		{
+			name:       "per-node image",
+			n:          3,
+			nodeImages: []string{"2=rp:v23.1.13"},
+			exp:        []common.NodeOptions{{Image: "rp:latest"}, {Image: "rp:latest"}, {Image: "rp:v23.1.13"}},
+		},
+		{name: "node ID out of range", n: 3, nodeImages: []string{"3=rp:v23.1.13"}, expErr: true},
+		{name: "missing image", n: 3, nodeImages: []string{"1="}, expErr: true},
+		{name: "invalid node ID", n: 3, nodeImages: []string{"one=rp:v23.1.13"}, expErr: true},
+		{name: "empty rack", n: 3, racks: []string{"a", ""}, expErr: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			opts, err := nodeOptions(test.n, "rp:latest", test.nodeImages, test.racks, test.volumes)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.exp, opts)
+		})
+	}
+}
+
+func TestFixedPorts(t *testing.T) {
+	l, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer l.Close()
+	used := uint(l.Addr().(*net.TCPAddr).Port)
+
+	fixed, err := fixedPorts(3, clusterOptions{})
+	require.NoError(t, err)
+	require.Empty(t, fixed)
+
+	// The port of the third node is in use.
+	_, err = fixedPorts(3, clusterOptions{proxyPort: used - 2})
+	require.ErrorContains(t, err, "is not available")
+
+	_, err = fixedPorts(3, clusterOptions{proxyPort: used + 1, schemaRegPort: used + 3})
+	require.ErrorContains(t, err, "already used by another fixed port")
+
+	_, err = fixedPorts(3, clusterOptions{schemaRegPort: 65534})
+	require.ErrorContains(t, err, "out of range")
+}
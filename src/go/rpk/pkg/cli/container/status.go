@@ -29,7 +29,7 @@ func newStatusCommand() *cobra.Command {
 			if err != nil {
 				return common.WrapIfConnErr(err)
 			}
-			renderClusterInteract(nodes)
+			renderClusterInteract(c, nodes)
 			return nil
 		},
 	}
@@ -79,5 +79,24 @@ func stopCluster(c common.Client) error {
 		}(node)
 	}
 	wg.Wait()
+
+	console, err := common.GetConsole(c)
+	if err != nil {
+		return err
+	}
+	if console != nil && console.Running {
+		fmt.Println("Stopping Redpanda Console")
+		timeout := 10 // seconds
+		err := c.ContainerStop(
+			context.Background(),
+			common.ConsoleName,
+			container.StopOptions{
+				Timeout: &timeout,
+			},
+		)
+		if err != nil {
+			fmt.Printf("Unable to stop Redpanda Console: %v\n", err)
+		}
+	}
 	return nil
 }
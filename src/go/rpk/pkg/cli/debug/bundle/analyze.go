@@ -0,0 +1,446 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package bundle
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+type severity string
+
+const (
+	sevCrit severity = "CRITICAL"
+	sevWarn severity = "WARNING"
+	sevInfo severity = "INFO"
+)
+
+// severityOrder sorts the most severe findings first.
+var severityOrder = map[severity]int{sevCrit: 0, sevWarn: 1, sevInfo: 2}
+
+type finding struct {
+	Severity severity
+	Check    string
+	Message  string
+}
+
+type analyzeOpts struct {
+	diskThreshold int
+	maxClockSkew  time.Duration
+	maxListed     int
+}
+
+func newAnalyzeCommand(fs afero.Fs) *cobra.Command {
+	opts := analyzeOpts{maxListed: 10}
+	cmd := &cobra.Command{
+		Use:   "analyze [BUNDLE]",
+		Short: "Analyze a debug bundle and report common problems",
+		Long: `Analyze a debug bundle and report common problems.
+
+This command opens a ZIP file created with 'rpk debug bundle' and reports
+findings from the data it contains, without connecting to the cluster:
+
+ - Partitions: leaderless and under-replicated partitions in the Kafka
+   metadata.
+
+ - Clock skew: the NTP clock offset is larger than --max-clock-skew.
+
+ - Disk usage: the data directory's filesystem is fuller than
+   --disk-threshold percent.
+
+ - Logs: out of memory kills, reactor stalls, and hung tasks in the kernel
+   and redpanda logs.
+
+ - Kernel parameters: parameters that differ from what 'rpk redpanda tune'
+   sets.
+
+ - Cluster configuration: properties that are set to a non-default value.
+
+Checks whose data is missing from the bundle (for example, if the bundle was
+created by an older rpk, or on Kubernetes) are skipped and reported as such.
+This command exits 1 if there are any critical findings.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			f, err := fs.Open(args[0])
+			out.MaybeDie(err, "unable to open bundle: %v", err)
+			defer f.Close()
+			stat, err := f.Stat()
+			out.MaybeDie(err, "unable to stat bundle: %v", err)
+			zr, err := zip.NewReader(f, stat.Size())
+			out.MaybeDie(err, "unable to read bundle %q as a zip file: %v", args[0], err)
+
+			findings := analyzeBundle(zr, opts)
+
+			tw := out.NewTable("SEVERITY", "CHECK", "FINDING")
+			var crit bool
+			for _, f := range findings {
+				tw.Print(f.Severity, f.Check, f.Message)
+				crit = crit || f.Severity == sevCrit
+			}
+			tw.Flush()
+			if crit {
+				out.Die("the bundle has critical findings")
+			}
+		},
+	}
+	cmd.Flags().IntVar(&opts.diskThreshold, "disk-threshold", 80, "Disk usage percentage over which to report the data directory's filesystem")
+	cmd.Flags().DurationVar(&opts.maxClockSkew, "max-clock-skew", 500*time.Millisecond, "Clock offset to NTP over which to report clock skew")
+	return cmd
+}
+
+// bundleFiles indexes the files in a bundle by name.
+type bundleFiles map[string]*zip.File
+
+func (b bundleFiles) read(name string) ([]byte, bool) {
+	f, ok := b[name]
+	if !ok {
+		return nil, false
+	}
+	r, err := f.Open()
+	if err != nil {
+		return nil, false
+	}
+	defer r.Close()
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		return nil, false
+	}
+	return bs, true
+}
+
+// match returns the sorted names of all files matching the glob pattern.
+func (b bundleFiles) match(pattern string) []string {
+	var names []string
+	for name := range b {
+		if ok, _ := path.Match(pattern, name); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// analyzeBundle runs every check against the bundle and returns the findings
+// sorted by severity.
+func analyzeBundle(zr *zip.Reader, opts analyzeOpts) []finding {
+	files := make(bundleFiles)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var findings []finding
+	for _, check := range []func(bundleFiles, analyzeOpts) []finding{
+		checkPartitions,
+		checkClockSkew,
+		checkDiskUsage,
+		checkLogs,
+		checkKernelParams,
+		checkClusterConfig,
+	} {
+		findings = append(findings, check(files, opts)...)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return severityOrder[findings[i].Severity] < severityOrder[findings[j].Severity]
+	})
+	return findings
+}
+
+func skipped(check, missing string) []finding {
+	return []finding{{sevInfo, check, fmt.Sprintf("skipped, the bundle has no %s", missing)}}
+}
+
+// listed joins at most max items, noting how many were left out.
+func listed(items []string, max int) string {
+	if len(items) <= max {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s, and %d more", strings.Join(items[:max], ", "), len(items)-max)
+}
+
+// bundleMetadata mirrors the fields we need of the kadm.Metadata that is
+// saved in kafka.json. kadm errors are not decodable, so we cannot decode
+// into kadm types directly.
+type bundleMetadata struct {
+	Brokers []struct {
+		NodeID int32
+	}
+	Topics map[string]struct {
+		Partitions map[string]struct {
+			Partition int32
+			Leader    int32
+			Replicas  []int32
+			ISR       []int32
+		}
+	}
+}
+
+func checkPartitions(files bundleFiles, opts analyzeOpts) []finding {
+	const check = "partitions"
+	raw, ok := files.read("kafka.json")
+	if !ok {
+		return skipped(check, "kafka.json")
+	}
+	var resps []struct {
+		Name     string
+		Response json.RawMessage
+		Error    []string
+	}
+	if err := json.Unmarshal(raw, &resps); err != nil {
+		return []finding{{sevWarn, check, fmt.Sprintf("unable to decode kafka.json: %v", err)}}
+	}
+	var meta *bundleMetadata
+	for _, r := range resps {
+		if r.Name != "metadata" {
+			continue
+		}
+		if len(r.Error) > 0 {
+			return []finding{{sevWarn, check, fmt.Sprintf("metadata was not collected: %s", strings.Join(r.Error, "; "))}}
+		}
+		meta = new(bundleMetadata)
+		if err := json.Unmarshal(r.Response, meta); err != nil {
+			return []finding{{sevWarn, check, fmt.Sprintf("unable to decode metadata: %v", err)}}
+		}
+	}
+	if meta == nil {
+		return skipped(check, "metadata response in kafka.json")
+	}
+
+	var leaderless, underReplicated []string
+	var total int
+	for topic, t := range meta.Topics {
+		for _, p := range t.Partitions {
+			total++
+			tp := fmt.Sprintf("%s/%d", topic, p.Partition)
+			switch {
+			case p.Leader < 0:
+				leaderless = append(leaderless, tp)
+			case len(p.ISR) < len(p.Replicas):
+				underReplicated = append(underReplicated, fmt.Sprintf("%s (isr %v of replicas %v)", tp, p.ISR, p.Replicas))
+			}
+		}
+	}
+	sort.Strings(leaderless)
+	sort.Strings(underReplicated)
+
+	var findings []finding
+	if len(leaderless) > 0 {
+		findings = append(findings, finding{sevCrit, check, fmt.Sprintf("%d leaderless partitions: %s", len(leaderless), listed(leaderless, opts.maxListed))})
+	}
+	if len(underReplicated) > 0 {
+		findings = append(findings, finding{sevWarn, check, fmt.Sprintf("%d under-replicated partitions: %s", len(underReplicated), listed(underReplicated, opts.maxListed))})
+	}
+	if len(findings) == 0 {
+		findings = append(findings, finding{sevInfo, check, fmt.Sprintf("all %d partitions across %d brokers have a leader and a full ISR", total, len(meta.Brokers))})
+	}
+	return findings
+}
+
+func checkClockSkew(files bundleFiles, opts analyzeOpts) []finding {
+	const check = "clock skew"
+	raw, ok := files.read("ntp.txt")
+	if !ok {
+		return skipped(check, "ntp.txt")
+	}
+	var ntp struct {
+		Host   string        `json:"host"`
+		Offset time.Duration `json:"offset"`
+	}
+	if err := json.Unmarshal(raw, &ntp); err != nil {
+		return []finding{{sevWarn, check, fmt.Sprintf("unable to decode ntp.txt: %v", err)}}
+	}
+	offset := ntp.Offset
+	if offset < 0 {
+		offset = -offset
+	}
+	if offset > opts.maxClockSkew {
+		return []finding{{sevWarn, check, fmt.Sprintf("clock is off by %v from %s", ntp.Offset, ntp.Host)}}
+	}
+	return []finding{{sevInfo, check, fmt.Sprintf("clock offset from %s is %v", ntp.Host, ntp.Offset)}}
+}
+
+func checkDiskUsage(files bundleFiles, opts analyzeOpts) []finding {
+	const check = "disk usage"
+	raw, ok := files.read("df.txt")
+	if !ok {
+		return skipped(check, "df.txt")
+	}
+	var findings []finding
+	s := bufio.NewScanner(bytes.NewReader(raw))
+	for s.Scan() {
+		// POSIX df output: Filesystem 1024-blocks Used Available Capacity Mounted-on
+		fields := strings.Fields(s.Text())
+		if len(fields) < 6 || !strings.HasSuffix(fields[4], "%") {
+			continue
+		}
+		pct, err := strconv.Atoi(strings.TrimSuffix(fields[4], "%"))
+		if err != nil {
+			continue
+		}
+		mount := strings.Join(fields[5:], " ")
+		sev := sevInfo
+		if pct >= opts.diskThreshold {
+			sev = sevWarn
+			if pct >= 95 {
+				sev = sevCrit
+			}
+		}
+		findings = append(findings, finding{sev, check, fmt.Sprintf("%s (%s) is %d%% full", mount, fields[0], pct)})
+	}
+	if len(findings) == 0 {
+		return []finding{{sevWarn, check, "unable to parse df.txt"}}
+	}
+	return findings
+}
+
+// logPatterns are the problems we look for in the kernel and redpanda logs.
+var logPatterns = []struct {
+	sev     severity
+	problem string
+	re      *regexp.Regexp
+}{
+	{sevCrit, "out of memory kills", regexp.MustCompile(`(?i)out of memory: kill|oom-kill|invoked oom-killer|OOMKilled`)},
+	{sevWarn, "reactor stalls", regexp.MustCompile(`Reactor stalled for`)},
+	{sevWarn, "hung tasks", regexp.MustCompile(`blocked for more than \d+ seconds`)},
+}
+
+func checkLogs(files bundleFiles, _ analyzeOpts) []finding {
+	const check = "logs"
+	names := append([]string{"syslog.txt", "redpanda.log"}, files.match("logs/*")...)
+
+	var findings []finding
+	var scanned int
+	for _, name := range names {
+		raw, ok := files.read(name)
+		if !ok {
+			continue
+		}
+		scanned++
+		counts := make([]int, len(logPatterns))
+		firsts := make([]string, len(logPatterns))
+		s := bufio.NewScanner(bytes.NewReader(raw))
+		s.Buffer(nil, 1<<20)
+		for s.Scan() {
+			line := s.Text()
+			for i, p := range logPatterns {
+				if p.re.MatchString(line) {
+					if counts[i] == 0 {
+						firsts[i] = strings.TrimSpace(line)
+					}
+					counts[i]++
+				}
+			}
+		}
+		for i, p := range logPatterns {
+			if counts[i] > 0 {
+				findings = append(findings, finding{p.sev, check, fmt.Sprintf("%d %s in %s, first: %s", counts[i], p.problem, name, firsts[i])})
+			}
+		}
+	}
+	if scanned == 0 {
+		return skipped(check, "log files")
+	}
+	if len(findings) == 0 {
+		findings = append(findings, finding{sevInfo, check, fmt.Sprintf("no out of memory kills, reactor stalls, or hung tasks in %d log files", scanned)})
+	}
+	return findings
+}
+
+// expectedKernelParams are the values that 'rpk redpanda tune' sets. Each
+// check returns whether the value is fine, and the value it expects.
+var expectedKernelParams = []struct {
+	file   string
+	name   string
+	expect string
+	ok     func(string) bool
+}{
+	{"proc/sys/vm/swappiness", "vm.swappiness", "1", intAtMost(1)},
+	{"proc/sys/fs/aio-max-nr", "fs.aio-max-nr", ">= 1048576", intAtLeast(1048576)},
+	{"proc/sys/net/core/somaxconn", "net.core.somaxconn", ">= 4096", intAtLeast(4096)},
+	{"proc/sys/net/ipv4/tcp_max_syn_backlog", "net.ipv4.tcp_max_syn_backlog", ">= 4096", intAtLeast(4096)},
+	{"sys/devices/system/clocksource/clocksource0/current_clocksource", "clocksource", "tsc", func(v string) bool { return v == "tsc" }},
+	{"sys/kernel/mm/transparent_hugepage/enabled", "transparent hugepages", "[always]", func(v string) bool { return strings.Contains(v, "[always]") }},
+}
+
+func intAtMost(max int) func(string) bool {
+	return func(v string) bool { n, err := strconv.Atoi(v); return err == nil && n <= max }
+}
+
+func intAtLeast(min int) func(string) bool {
+	return func(v string) bool { n, err := strconv.Atoi(v); return err == nil && n >= min }
+}
+
+func checkKernelParams(files bundleFiles, _ analyzeOpts) []finding {
+	const check = "kernel parameters"
+	var findings []finding
+	var seen int
+	for _, p := range expectedKernelParams {
+		raw, ok := files.read(p.file)
+		if !ok {
+			continue
+		}
+		seen++
+		v := strings.TrimSpace(string(raw))
+		if !p.ok(v) {
+			findings = append(findings, finding{sevWarn, check, fmt.Sprintf("%s is %q, expected %s; run 'rpk redpanda tune all'", p.name, v, p.expect)})
+		}
+	}
+	if seen == 0 {
+		return skipped(check, "kernel parameters")
+	}
+	if len(findings) == 0 {
+		findings = append(findings, finding{sevInfo, check, fmt.Sprintf("all %d collected kernel parameters are tuned", seen)})
+	}
+	return findings
+}
+
+func checkClusterConfig(files bundleFiles, opts analyzeOpts) []finding {
+	const check = "cluster config"
+	raw, ok := files.read("admin/cluster_config_overrides.json")
+	if !ok {
+		// A bundle merged from --hosts has a copy of the cluster-wide
+		// config in each host directory; any of them will do.
+		for _, name := range files.match("*/admin/cluster_config_overrides.json") {
+			if raw, ok = files.read(name); ok {
+				break
+			}
+		}
+	}
+	if !ok {
+		return skipped(check, "admin/cluster_config_overrides.json")
+	}
+	var overrides map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &overrides); err != nil {
+		return []finding{{sevWarn, check, fmt.Sprintf("unable to decode cluster config overrides: %v", err)}}
+	}
+	if len(overrides) == 0 {
+		return []finding{{sevInfo, check, "all cluster properties have their default values"}}
+	}
+	var kvs []string
+	for k, v := range overrides {
+		kvs = append(kvs, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(kvs)
+	return []finding{{sevInfo, check, fmt.Sprintf("%d properties differ from defaults: %s", len(kvs), listed(kvs, 3*opts.maxListed))}}
+}
@@ -0,0 +1,113 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mkBundle(t *testing.T, files map[string]string) *zip.Reader {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, contents := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	return zr
+}
+
+func TestAnalyzeBundle(t *testing.T) {
+	opts := analyzeOpts{diskThreshold: 80, maxClockSkew: 500 * time.Millisecond, maxListed: 10}
+
+	for _, test := range []struct {
+		name  string
+		files map[string]string
+		exp   []finding
+	}{
+		{
+			name:  "empty bundle skips everything",
+			files: map[string]string{},
+			exp: []finding{
+				{sevInfo, "partitions", "skipped, the bundle has no kafka.json"},
+				{sevInfo, "clock skew", "skipped, the bundle has no ntp.txt"},
+				{sevInfo, "disk usage", "skipped, the bundle has no df.txt"},
+				{sevInfo, "logs", "skipped, the bundle has no log files"},
+				{sevInfo, "kernel parameters", "skipped, the bundle has no kernel parameters"},
+				{sevInfo, "cluster config", "skipped, the bundle has no admin/cluster_config_overrides.json"},
+			},
+		},
+		{
+			name: "problems everywhere",
+			files: map[string]string{
+				"kafka.json": `[{"Name":"metadata","Response":{"Brokers":[{"NodeID":0},{"NodeID":1},{"NodeID":2}],"Topics":{"foo":{"Partitions":{
+					"0":{"Partition":0,"Leader":0,"Replicas":[0,1,2],"ISR":[0,1,2]},
+					"1":{"Partition":1,"Leader":-1,"Replicas":[0,1,2],"ISR":[]},
+					"2":{"Partition":2,"Leader":1,"Replicas":[0,1,2],"ISR":[1]}}}}},"Error":null}]`,
+				"ntp.txt": `{"host":"pool.ntp.org","offset":2000000000}`,
+				"df.txt": "Filesystem 1024-blocks Used Available Capacity Mounted on\n" +
+					"/dev/nvme0n1 1000 970 30 97% /var/lib/redpanda\n",
+				"syslog.txt":                          "Jun 1 kernel: Out of memory: Killed process 1234 (redpanda)\nJun 1 ok\n",
+				"redpanda.log":                        "WARN Reactor stalled for 30 ms on shard 1\nWARN Reactor stalled for 40 ms on shard 2\n",
+				"proc/sys/vm/swappiness":              "60\n",
+				"proc/sys/fs/aio-max-nr":              "1048576\n",
+				"admin/cluster_config_overrides.json": `{"log_segment_size":1048576,"auto_create_topics_enabled":true}`,
+			},
+			exp: []finding{
+				{sevCrit, "partitions", "1 leaderless partitions: foo/1"},
+				{sevCrit, "disk usage", "/var/lib/redpanda (/dev/nvme0n1) is 97% full"},
+				{sevCrit, "logs", "1 out of memory kills in syslog.txt, first: Jun 1 kernel: Out of memory: Killed process 1234 (redpanda)"},
+				{sevWarn, "partitions", "1 under-replicated partitions: foo/2 (isr [1] of replicas [0 1 2])"},
+				{sevWarn, "clock skew", "clock is off by 2s from pool.ntp.org"},
+				{sevWarn, "logs", "2 reactor stalls in redpanda.log, first: WARN Reactor stalled for 30 ms on shard 1"},
+				{sevWarn, "kernel parameters", `vm.swappiness is "60", expected 1; run 'rpk redpanda tune all'`},
+				{sevInfo, "cluster config", "2 properties differ from defaults: auto_create_topics_enabled=true, log_segment_size=1048576"},
+			},
+		},
+		{
+			name: "healthy",
+			files: map[string]string{
+				"kafka.json":                          `[{"Name":"metadata","Response":{"Brokers":[{"NodeID":0}],"Topics":{"foo":{"Partitions":{"0":{"Partition":0,"Leader":0,"Replicas":[0],"ISR":[0]}}}}}}]`,
+				"ntp.txt":                             `{"host":"pool.ntp.org","offset":-1000000}`,
+				"df.txt":                              "Filesystem 1024-blocks Used Available Capacity Mounted on\n/dev/sda 1000 100 900 10% /\n",
+				"logs/redpanda-0.txt":                 "INFO all good\n",
+				"proc/sys/vm/swappiness":              "1",
+				"admin/cluster_config_overrides.json": `{}`,
+			},
+			exp: []finding{
+				{sevInfo, "partitions", "all 1 partitions across 1 brokers have a leader and a full ISR"},
+				{sevInfo, "clock skew", "clock offset from pool.ntp.org is -1ms"},
+				{sevInfo, "disk usage", "/ (/dev/sda) is 10% full"},
+				{sevInfo, "logs", "no out of memory kills, reactor stalls, or hung tasks in 1 log files"},
+				{sevInfo, "kernel parameters", "all 1 collected kernel parameters are tuned"},
+				{sevInfo, "cluster config", "all cluster properties have their default values"},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := analyzeBundle(mkBundle(t, test.files), opts)
+			require.Equal(t, test.exp, got)
+		})
+	}
+}
+
+func TestListed(t *testing.T) {
+	require.Equal(t, "a, b", listed([]string{"a", "b"}, 2))
+	require.Equal(t, "a, b, and 2 more", listed([]string{"a", "b", "c", "d"}, 2))
+}
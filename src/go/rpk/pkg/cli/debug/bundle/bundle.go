@@ -14,6 +14,8 @@ import (
 	"encoding/xml"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/docker/go-units"
@@ -40,6 +42,16 @@ type bundleParams struct {
 	controllerLogLimitBytes int
 	timeout                 time.Duration
 	metricsInterval         time.Duration
+	metricsDuration         time.Duration
+
+	sshHosts            []string
+	sshUser             string
+	sshKey              string
+	sshKnownHosts       string
+	sshSkipHostKeyCheck bool
+	sshRemoteRpk        string
+
+	redactor *redactor
 }
 
 func NewCommand(fs afero.Fs, p *config.Params) *cobra.Command {
@@ -57,6 +69,17 @@ func NewCommand(fs afero.Fs, p *config.Params) *cobra.Command {
 
 		timeout         time.Duration
 		metricsInterval time.Duration
+		metricsDuration time.Duration
+
+		sshHosts            []string
+		sshUser             string
+		sshKey              string
+		sshKnownHosts       string
+		sshSkipHostKeyCheck bool
+		sshRemoteRpk        string
+
+		redactPolicy  string
+		redactPresets []string
 	)
 	cmd := &cobra.Command{
 		Use:   "bundle",
@@ -79,6 +102,8 @@ func NewCommand(fs afero.Fs, p *config.Params) *cobra.Command {
 			if !ok {
 				yActual = y
 			}
+			err = p.LoadCredentials(fs)
+			out.MaybeDie(err, "unable to load the profile credentials: %v", err)
 
 			cl, err := kafka.NewFranzClient(fs, p)
 			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
@@ -89,6 +114,9 @@ func NewCommand(fs afero.Fs, p *config.Params) *cobra.Command {
 
 			controllerLogsLimit, err := units.FromHumanSize(controllerLogsSizeLimit)
 			out.MaybeDie(err, "unable to parse --controller-logs-size-limit: %v", err)
+
+			redactor, err := newRedactor(fs, redactPolicy, redactPresets)
+			out.MaybeDieErr(err)
 			bp := bundleParams{
 				fs:                      fs,
 				p:                       p,
@@ -103,15 +131,28 @@ func NewCommand(fs afero.Fs, p *config.Params) *cobra.Command {
 				controllerLogLimitBytes: int(controllerLogsLimit),
 				timeout:                 timeout,
 				metricsInterval:         metricsInterval,
+				metricsDuration:         metricsDuration,
+
+				sshHosts:            sshHosts,
+				sshUser:             sshUser,
+				sshKey:              sshKey,
+				sshKnownHosts:       sshKnownHosts,
+				sshSkipHostKeyCheck: sshSkipHostKeyCheck,
+				sshRemoteRpk:        sshRemoteRpk,
+
+				redactor: redactor,
 			}
 
 			// to execute the appropriate bundle we look for
 			// kubernetes_service_* env variables as an indicator that we are
 			// in a k8s environment
 			host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
-			if len(host) == 0 || len(port) == 0 {
+			switch {
+			case len(sshHosts) > 0:
+				err = executeSSHBundle(cmd.Context(), bp)
+			case len(host) == 0 || len(port) == 0:
 				err = executeBundle(cmd.Context(), bp)
-			} else {
+			default:
 				err = executeK8SBundle(cmd.Context(), bp)
 			}
 			out.MaybeDie(err, "unable to create bundle: %v", err)
@@ -130,12 +171,23 @@ func NewCommand(fs afero.Fs, p *config.Params) *cobra.Command {
 	f.StringVarP(&outFile, outputFlag, "o", "", "The file path where the debug file will be written (default ./<timestamp>-bundle.zip)")
 	f.DurationVar(&timeout, "timeout", 12*time.Second, "How long to wait for child commands to execute (e.g. 30s, 1.5m)")
 	f.DurationVar(&metricsInterval, "metrics-interval", 10*time.Second, "Interval between metrics snapshots (e.g. 30s, 1.5m)")
+	f.DurationVar(&metricsDuration, "metrics-duration", 0, "If non-zero, capture a metrics time series of every broker at --metrics-interval for this long (e.g. 5m)")
 	f.StringVar(&logsSince, "logs-since", "", "Include log entries on or newer than the specified date (journalctl date format, e.g. YYYY-MM-DD")
 	f.StringVar(&logsUntil, "logs-until", "", "Include log entries on or older than the specified date (journalctl date format, e.g. YYYY-MM-DD")
 	f.StringVar(&logsSizeLimit, "logs-size-limit", "100MiB", "Read the logs until the given size is reached (e.g. 3MB, 1GiB)")
 	f.StringVar(&controllerLogsSizeLimit, "controller-logs-size-limit", "20MB", "The size limit of the controller logs that can be stored in the bundle (e.g. 3MB, 1GiB)")
 	f.StringVar(&uploadURL, "upload-url", "", "If provided, where to upload the bundle in addition to creating a copy on disk")
 	f.StringVarP(&namespace, "namespace", "n", "redpanda", "The namespace to use to collect the resources from (k8s only)")
+	f.StringVar(&redactPolicy, "redact-policy", "", "Redaction policy file applied to every file in the bundle (see REDACTION)")
+	f.StringSliceVar(&redactPresets, "redact", nil, "Comma separated list of built-in redaction presets to apply: ips, hostnames, sasl_users, topics")
+	f.StringSliceVar(&sshHosts, "hosts", nil, "Comma separated list of brokers ([user@]host[:port]) to collect bundles from over SSH and merge into one bundle")
+	f.StringVar(&sshUser, "ssh-user", "", "Default SSH user for --hosts (defaults to the current user)")
+	f.StringVar(&sshKey, "ssh-key", "", "Private key to authenticate with over SSH, rather than the SSH agent and default keys")
+	f.StringVar(&sshKnownHosts, "ssh-known-hosts", "", "Known hosts file to verify broker host keys with (default ~/.ssh/known_hosts)")
+	f.BoolVar(&sshSkipHostKeyCheck, "ssh-skip-host-key-check", false, "Do not verify broker SSH host keys (insecure)")
+	f.StringVar(&sshRemoteRpk, "ssh-remote-rpk", "rpk", "Command used to run rpk on the brokers (e.g. 'sudo rpk')")
+
+	cmd.AddCommand(newAnalyzeCommand(fs))
 
 	return cmd
 }
@@ -195,6 +247,11 @@ COMMON FILES
 
  - /proc/interrupts: IRQ distribution across CPU cores.
 
+ - Kernel parameters: The kernel parameters that 'rpk redpanda tune' sets,
+   such as vm.swappiness, fs.aio-max-nr, and the clocksource.
+
+ - Disk space: The usage of the data directory's filesystem, as output by 'df'.
+
  - Resource usage data: CPU usage percentage, free memory available for the
    redpanda process.
 
@@ -207,6 +264,11 @@ COMMON FILES
  - Broker metrics: The broker's Prometheus metrics, fetched through its
    admin API (/metrics and /public_metrics).
 
+ - Metrics time series: With --metrics-duration, the metrics of every broker
+   are scraped every --metrics-interval for the given duration and saved to
+   metrics/timeseries.gz. Use 'rpk debug metrics-replay' to serve the time
+   series to a local Prometheus and Grafana.
+
 BARE-METAL
 
  - Kernel logs: The kernel logs ring buffer (syslog).
@@ -233,6 +295,54 @@ BARE-METAL
  - dmidecode: The DMI table contents. Only included if this command is run
    as root.
 
+REDACTION
+
+SASL credentials and TLS keys are always redacted from the configuration. To
+redact more, use --redact to enable built-in presets, or --redact-policy with a
+policy file. Redaction applies to the contents of every file in the bundle:
+
+ - ips: IPv4 and IPv6 addresses.
+ - hostnames: fully qualified host names.
+ - sasl_users: SASL and SCRAM user names from the configuration and logs.
+ - topics: the names of every non-internal topic in the cluster.
+
+A policy file enables presets, excludes files from the bundle, and adds rules
+that either match a regex anywhere (if the regex has a capture group, only the
+group is redacted), or match a dot delimited key path in JSON and YAML files
+("*" matches any key or array index):
+
+    presets: [ips, topics]
+    exclude: ["controller/*"]
+    rules:
+      - name: customer_ids
+        regex: 'customer-id=(\w+)'
+      - name: racks
+        key_path: redpanda.rack
+        replacement: "(REDACTED)"
+
+Unless a rule has a replacement, every distinct redacted value is replaced with
+a stable token such as REDACTED-topics-3, so that events can still be
+correlated across files. The bundle contains redactions.json, which lists how
+many values each rule redacted per file, without the original values, as well
+as excluded files and binary files that could not be redacted. The compressed
+metrics time series is decoded and every scrape in it is redacted.
+
+CLUSTER-WIDE OVER SSH
+
+With --hosts, this command connects to each broker over SSH, runs 'rpk debug
+bundle' there, and merges the per-broker bundles into a single ZIP file with a
+directory per broker and an index.json that lists each broker's collection
+status. SSH authentication uses --ssh-key, or the local SSH agent and the
+default keys in ~/.ssh, and host keys are verified against ~/.ssh/known_hosts.
+rpk must be installed on every broker; use --ssh-remote-rpk to run it with
+sudo, for example to include dmidecode output:
+
+    rpk debug bundle --hosts rp-0,rp-1,admin@rp-2:2222 --ssh-remote-rpk 'sudo rpk'
+
+The --logs-*, --controller-logs-size-limit, --timeout, and --metrics-* flags
+are forwarded to every broker. Redaction is applied locally while merging
+the per-broker bundles.
+
 KUBERNETES
 
  - Kubernetes Resources: Kubernetes manifests for all resources in the given 
@@ -243,6 +353,43 @@ KUBERNETES
    included.
 
 
+To inspect a bundle for common problems, use 'rpk debug bundle analyze'.
+
 If you have an upload URL from the Redpanda support team, provide it in the 
 --upload-url flag to upload your diagnostics bundle to Redpanda.
 `
+
+// bundleFilepath will process the given path and sets:
+//   - File Name: If the path is empty, the filename will be <timestamp>-bundle.zip
+//   - File Extension: if no extension is provided we default to .zip
+func bundleFilepath(fs afero.Fs, path string) (string, error) {
+	// if it's empty, use ./<timestamp>-bundle.zip
+	if path == "" {
+		timestamp := time.Now().Unix()
+		path = fmt.Sprintf("%d-bundle.zip", timestamp)
+	} else if isDir, _ := afero.IsDir(fs, path); isDir {
+		return "", fmt.Errorf("output file path is a directory, please specify the name of the file")
+	}
+
+	// Check for file extension, if extension is empty, defaults to .zip
+	switch ext := filepath.Ext(path); ext {
+	case ".zip":
+		return path, nil
+	case "":
+		return path + ".zip", nil
+	default:
+		return "", fmt.Errorf("extension %q not supported", ext)
+	}
+}
+
+// sanitizeName replace any of the following characters with "-": "<", ">", ":",
+// `"`, "/", "|", "?", "*". This is to avoid having forbidden names in Windows
+// environments.
+func sanitizeName(name string) string {
+	forbidden := []string{"<", ">", ":", `"`, "/", `\`, "|", "?", "*"}
+	r := name
+	for _, s := range forbidden {
+		r = strings.Replace(r, s, "-", -1)
+	}
+	return r
+}
@@ -26,6 +26,8 @@ func executeK8SBundle(context.Context, bundleParams) error {
 	return errors.New("rpk debug bundle is unsupported on your operating system")
 }
 
-func determineFilepath(afero.Fs, string, bool) (string, error) {
-	return "", errors.New("rpk debug bundle is not supported on your operating system")
+// determineFilepath does not check for write permissions, as only remote
+// bundles can be collected on this operating system (see executeSSHBundle).
+func determineFilepath(fs afero.Fs, path string, _ bool) (string, error) {
+	return bundleFilepath(fs, path)
 }
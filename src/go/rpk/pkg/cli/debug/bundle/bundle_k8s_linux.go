@@ -52,10 +52,12 @@ func executeK8SBundle(ctx context.Context, bp bundleParams) error {
 	defer w.Close()
 
 	ps := &stepParams{
-		fs:      bp.fs,
-		w:       w,
-		timeout: bp.timeout,
+		fs:       bp.fs,
+		w:        w,
+		timeout:  bp.timeout,
+		redactor: bp.redactor,
 	}
+	seedRedactor(ctx, bp)
 	var errs *multierror.Error
 
 	steps := []step{
@@ -64,9 +66,11 @@ func executeK8SBundle(ctx context.Context, bp bundleParams) error {
 		saveConfig(ps, bp.yActual),
 		saveCPUInfo(ps),
 		saveInterrupts(ps),
+		saveKernelParams(ps),
 		saveResourceUsageData(ps, bp.y),
 		saveNTPDrift(ps),
 		saveDiskUsage(ctx, ps, bp.y),
+		saveDiskFree(ctx, ps, bp.y),
 		saveControllerLogDir(ps, bp.y, bp.controllerLogLimitBytes),
 		saveK8SResources(ctx, ps, bp.namespace),
 		saveK8SLogs(ctx, ps, bp.namespace, bp.logsSince, bp.logsLimitBytes),
@@ -80,6 +84,9 @@ func executeK8SBundle(ctx context.Context, bp bundleParams) error {
 			saveClusterAdminAPICalls(ctx, ps, bp.fs, bp.p, adminAddresses),
 			saveSingleAdminAPICalls(ctx, ps, bp.fs, bp.p, adminAddresses, bp.metricsInterval),
 		}...)
+		if bp.metricsDuration > 0 {
+			steps = append(steps, saveMetricsTimeSeries(ctx, ps, bp.fs, bp.p, adminAddresses, bp.metricsInterval, bp.metricsDuration))
+		}
 	}
 	for _, s := range steps {
 		grp.Go(s)
@@ -94,6 +101,9 @@ func executeK8SBundle(ctx context.Context, bp bundleParams) error {
 		}
 		fmt.Println(errs.Error())
 	}
+	if err := writeRedactManifest(ps); err != nil {
+		fmt.Println(err)
+	}
 
 	fmt.Printf("Debug bundle saved to %q\n", f.Name())
 	return nil
@@ -194,6 +204,7 @@ func getClusterDomain() string {
 //   - Brokers: /v1/brokers
 //   - License Info: /v1/features/license
 //   - Cluster Config: /v1/cluster_config
+//   - Cluster Config overrides: /v1/cluster_config?include_defaults=false
 //   - Reconfigurations: /v1/partitions/reconfigurations
 func saveClusterAdminAPICalls(ctx context.Context, ps *stepParams, fs afero.Fs, p *config.RpkProfile, adminAddresses []string) step {
 	return func() error {
@@ -224,6 +235,12 @@ func saveClusterAdminAPICalls(ctx context.Context, ps *stepParams, fs afero.Fs,
 				}
 				return requestAndSave(ctx, ps, "admin/cluster_config.json", f)
 			},
+			func() error {
+				f := func(ctx context.Context) (adminapi.Config, error) {
+					return cl.Config(ctx, false)
+				}
+				return requestAndSave(ctx, ps, "admin/cluster_config_overrides.json", f)
+			},
 		} {
 			grp.Go(f)
 		}
@@ -476,15 +493,3 @@ func parseJournalTime(str string, now time.Time) (time.Time, error) {
 		return adjustedTime, nil
 	}
 }
-
-// sanitizeName replace any of the following characters with "-": "<", ">", ":",
-// `"`, "/", "|", "?", "*". This is to avoid having forbidden names in Windows
-// environments.
-func sanitizeName(name string) string {
-	forbidden := []string{"<", ">", ":", `"`, "/", `\`, "|", "?", "*"}
-	r := name
-	for _, s := range forbidden {
-		r = strings.Replace(r, s, "-", -1)
-	}
-	return r
-}
@@ -13,6 +13,7 @@ package bundle
 
 import (
 	"archive/zip"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -36,7 +37,9 @@ import (
 	"github.com/beevik/ntp"
 	"github.com/docker/go-units"
 	"github.com/hashicorp/go-multierror"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/adminapi"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/metricsts"
 	osutil "github.com/redpanda-data/redpanda/src/go/rpk/pkg/os"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/system"
@@ -50,27 +53,13 @@ import (
 )
 
 // determineFilepath will process the given path and sets:
-//   - File Name: If the path is empty, the filename will be <timestamp>-bundle.zip
-//   - File Extension: if no extension is provided we default to .zip
+//   - File Name and Extension: see bundleFilepath
 //   - File Location: we check for write permissions in the pwd (for backcompat);
 //     if permission is denied we default to $HOME unless isFlag is true.
 func determineFilepath(fs afero.Fs, path string, isFlag bool) (finalPath string, err error) {
-	// if it's empty, use ./<timestamp>-bundle.zip
-	if path == "" {
-		timestamp := time.Now().Unix()
-		path = fmt.Sprintf("%d-bundle.zip", timestamp)
-	} else if isDir, _ := afero.IsDir(fs, path); isDir {
-		return "", fmt.Errorf("output file path is a directory, please specify the name of the file")
-	}
-
-	// Check for file extension, if extension is empty, defaults to .zip
-	switch ext := filepath.Ext(path); ext {
-	case ".zip":
-		finalPath = path
-	case "":
-		finalPath = path + ".zip"
-	default:
-		return "", fmt.Errorf("extension %q not supported", ext)
+	finalPath, err = bundleFilepath(fs, path)
+	if err != nil {
+		return "", err
 	}
 
 	// Now we check for write permissions:
@@ -114,10 +103,12 @@ func executeBundle(ctx context.Context, bp bundleParams) error {
 	defer w.Close()
 
 	ps := &stepParams{
-		fs:      bp.fs,
-		w:       w,
-		timeout: bp.timeout,
+		fs:       bp.fs,
+		w:        w,
+		timeout:  bp.timeout,
+		redactor: bp.redactor,
 	}
+	seedRedactor(ctx, bp)
 
 	addrs := bp.y.Rpk.AdminAPI.Addresses
 
@@ -127,6 +118,7 @@ func executeBundle(ctx context.Context, bp bundleParams) error {
 		saveConfig(ps, bp.y),
 		saveCPUInfo(ps),
 		saveInterrupts(ps),
+		saveKernelParams(ps),
 		saveResourceUsageData(ps, bp.y),
 		saveNTPDrift(ps),
 		saveSyslog(ps),
@@ -134,6 +126,7 @@ func executeBundle(ctx context.Context, bp bundleParams) error {
 		saveClusterAdminAPICalls(ctx, ps, bp.fs, bp.p, addrs),
 		saveDNSData(ctx, ps),
 		saveDiskUsage(ctx, ps, bp.y),
+		saveDiskFree(ctx, ps, bp.y),
 		saveLogs(ctx, ps, bp.logsSince, bp.logsUntil, bp.logsLimitBytes),
 		saveSocketData(ctx, ps),
 		saveTopOutput(ctx, ps),
@@ -143,6 +136,9 @@ func executeBundle(ctx context.Context, bp bundleParams) error {
 		saveDmidecode(ctx, ps),
 		saveControllerLogDir(ps, bp.y, bp.controllerLogLimitBytes),
 	}
+	if bp.metricsDuration > 0 {
+		steps = append(steps, saveMetricsTimeSeries(ctx, ps, bp.fs, bp.p, addrs, bp.metricsInterval, bp.metricsDuration))
+	}
 
 	for _, s := range steps {
 		grp.Go(s)
@@ -156,6 +152,9 @@ func executeBundle(ctx context.Context, bp bundleParams) error {
 		}
 		fmt.Println(errs.Error())
 	}
+	if err := writeRedactManifest(ps); err != nil {
+		fmt.Println(err)
+	}
 
 	fmt.Printf("Debug bundle saved to '%s'\n", bp.path)
 	return nil
@@ -164,10 +163,11 @@ func executeBundle(ctx context.Context, bp bundleParams) error {
 type step func() error
 
 type stepParams struct {
-	fs      afero.Fs
-	m       sync.Mutex
-	w       *zip.Writer
-	timeout time.Duration
+	fs       afero.Fs
+	m        sync.Mutex
+	w        *zip.Writer
+	timeout  time.Duration
+	redactor *redactor
 }
 
 type fileInfo struct {
@@ -206,8 +206,22 @@ func (l *limitedWriter) Write(p []byte) (int, error) {
 	return n, nil
 }
 
-// Creates a file in the zip writer with name 'filename' and writes 'contents' to it.
+// Creates a file in the zip writer with name 'filename' and writes 'contents'
+// to it, after applying the redaction policy if there is one.
 func writeFileToZip(ps *stepParams, filename string, contents []byte) error {
+	if ps.redactor != nil {
+		var keep bool
+		contents, keep = ps.redactor.redact(filename, contents)
+		if !keep {
+			return nil
+		}
+	}
+	return writeRawFileToZip(ps, filename, contents)
+}
+
+// Creates a file in the zip writer with name 'filename' and writes 'contents'
+// to it as is.
+func writeRawFileToZip(ps *stepParams, filename string, contents []byte) error {
 	ps.m.Lock()
 	defer ps.m.Unlock()
 
@@ -261,9 +275,6 @@ func writeCommandOutputToZipLimit(
 	command string,
 	args ...string,
 ) error {
-	ps.m.Lock()
-	defer ps.m.Unlock()
-
 	ctx, cancel := context.WithTimeout(rootCtx, ps.timeout)
 	defer cancel()
 	cmd := exec.CommandContext(ctx, command, args...)
@@ -271,13 +282,28 @@ func writeCommandOutputToZipLimit(
 	// Strip any non-default library path
 	cmd.Env = osutil.SystemLdPathEnv()
 
-	wr, err := ps.w.CreateHeader(&zip.FileHeader{
-		Name:     filename,
-		Method:   zip.Deflate,
-		Modified: time.Now(),
-	})
-	if err != nil {
-		return err
+	// With a redaction policy, we need the entire output before we can
+	// write it; otherwise we stream the output directly into the zip.
+	var (
+		wr       io.Writer
+		redacted *bytes.Buffer
+	)
+	if ps.redactor != nil {
+		redacted = new(bytes.Buffer)
+		wr = redacted
+	} else {
+		ps.m.Lock()
+		defer ps.m.Unlock()
+
+		var err error
+		wr, err = ps.w.CreateHeader(&zip.FileHeader{
+			Name:     filename,
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	if outputLimitBytes > 0 {
@@ -290,12 +316,17 @@ func writeCommandOutputToZipLimit(
 	cmd.Stdout = wr
 	cmd.Stderr = wr
 
-	err = cmd.Start()
+	err := cmd.Start()
 	if err != nil {
 		return err
 	}
 
 	err = cmd.Wait()
+	if redacted != nil {
+		if werr := writeFileToZip(ps, filename, redacted.Bytes()); werr != nil {
+			return werr
+		}
+	}
 	if err != nil {
 		if !strings.Contains(err.Error(), "broken pipe") {
 			return fmt.Errorf("couldn't save '%s': %w", filename, err)
@@ -317,6 +348,106 @@ func writeCommandOutputToZip(
 	return writeCommandOutputToZipLimit(ctx, ps, filename, -1, command, args...)
 }
 
+// saveMetricsTimeSeries scrapes /metrics and /public_metrics of every broker
+// every interval for the given duration, and saves the scrapes to a time series
+// file that 'rpk debug metrics-replay' can serve.
+func saveMetricsTimeSeries(ctx context.Context, ps *stepParams, fs afero.Fs, p *config.RpkProfile, adminAddresses []string, interval, duration time.Duration) step {
+	return func() error {
+		if len(adminAddresses) == 0 {
+			return errors.New("unable to capture a metrics time series: no admin API addresses")
+		}
+		type scraper struct {
+			addr     string
+			endpoint string
+			fn       func(context.Context) ([]byte, error)
+		}
+		var scrapers []scraper
+		for _, a := range adminAddresses {
+			cl, err := adminapi.NewClient(fs, &config.RpkProfile{
+				KafkaAPI: config.RpkKafkaAPI{
+					SASL: p.KafkaAPI.SASL,
+				},
+				AdminAPI: config.RpkAdminAPI{
+					Addresses: []string{a},
+					TLS:       p.AdminAPI.TLS,
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("unable to initialize admin client for %q: %v", a, err)
+			}
+			scrapers = append(scrapers,
+				scraper{a, metricsts.EndpointMetrics, cl.PrometheusMetrics},
+				scraper{a, metricsts.EndpointPublicMetrics, cl.PublicMetrics},
+			)
+		}
+
+		var (
+			buf  bytes.Buffer
+			mu   sync.Mutex
+			errs *multierror.Error
+		)
+		w, err := metricsts.NewWriter(&buf)
+		if err != nil {
+			return err
+		}
+		deadline := time.Now().Add(duration)
+		for {
+			var wg sync.WaitGroup
+			for _, s := range scrapers {
+				s := s
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					now := time.Now()
+					body, err := s.fn(ctx)
+					mu.Lock()
+					defer mu.Unlock()
+					if err != nil {
+						errs = multierror.Append(errs, fmt.Errorf("unable to scrape %s of %s: %v", s.endpoint, s.addr, err))
+						return
+					}
+					if err := w.Write(metricsts.Scrape{Time: now, Instance: s.addr, Endpoint: s.endpoint, Body: body}); err != nil {
+						errs = multierror.Append(errs, err)
+					}
+				}()
+			}
+			wg.Wait()
+			if time.Now().Add(interval).After(deadline) {
+				break
+			}
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		// The redaction policy applies to the decoded scrapes, see
+		// redactor.redactTimeSeries.
+		if err := writeFileToZip(ps, metricsTimeSeriesFile, buf.Bytes()); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+		return errs.ErrorOrNil()
+	}
+}
+
+// writeRedactManifest writes the redaction manifest, if there is a redaction
+// policy. The manifest itself is not redacted.
+func writeRedactManifest(ps *stepParams) error {
+	if ps.redactor == nil {
+		return nil
+	}
+	m, err := ps.redactor.manifest()
+	if err != nil {
+		return fmt.Errorf("unable to encode the redaction manifest: %v", err)
+	}
+	return writeRawFileToZip(ps, redactManifestFile, m)
+}
+
 // Parses an error return from kadm, and if the return is a shard errors,
 // returns a list of each individual error.
 func stringifyKadmErr(err error) []string {
@@ -461,6 +592,9 @@ func saveConfig(ps *stepParams, y *config.RedpandaYaml) step {
 		if y.Rpk.KafkaAPI.SASL != nil {
 			y.Rpk.KafkaAPI.SASL.User = redacted
 			y.Rpk.KafkaAPI.SASL.Password = redacted
+			if y.Rpk.KafkaAPI.SASL.OAuth != nil {
+				y.Rpk.KafkaAPI.SASL.OAuth.ClientSecret = redacted
+			}
 		}
 		// We want to redact any blindly decoded parameters.
 		redactOtherMap(y.Other)
@@ -529,6 +663,35 @@ func saveInterrupts(ps *stepParams) step {
 	}
 }
 
+// kernelParamFiles are the kernel parameters that Redpanda's tuners set, which
+// are saved to the bundle at the same path without the leading slash.
+var kernelParamFiles = []string{
+	"/proc/sys/vm/swappiness",
+	"/proc/sys/fs/aio-max-nr",
+	"/proc/sys/net/core/somaxconn",
+	"/proc/sys/net/ipv4/tcp_max_syn_backlog",
+	"/sys/devices/system/clocksource/clocksource0/current_clocksource",
+	"/sys/kernel/mm/transparent_hugepage/enabled",
+}
+
+// Saves the kernel parameters that Redpanda's tuners set.
+func saveKernelParams(ps *stepParams) step {
+	return func() error {
+		var errs *multierror.Error
+		for _, file := range kernelParamFiles {
+			bs, err := afero.ReadFile(ps.fs, file)
+			if err != nil {
+				errs = multierror.Append(errs, err)
+				continue
+			}
+			if err := writeFileToZip(ps, strings.TrimPrefix(file, "/"), bs); err != nil {
+				errs = multierror.Append(errs, err)
+			}
+		}
+		return errs.ErrorOrNil()
+	}
+}
+
 // Writes a file containing memory, disk & CPU usage metrics for a local
 // redpanda process.
 func saveResourceUsageData(ps *stepParams, y *config.RedpandaYaml) step {
@@ -642,6 +805,18 @@ func saveDiskUsage(ctx context.Context, ps *stepParams, y *config.RedpandaYaml)
 	}
 }
 
+// Saves the filesystem usage of redpanda's data directory, as output by 'df'.
+func saveDiskFree(ctx context.Context, ps *stepParams, y *config.RedpandaYaml) step {
+	return func() error {
+		return writeCommandOutputToZip(
+			ctx,
+			ps,
+			"df.txt",
+			"df", "-P", "-k", y.Redpanda.Directory,
+		)
+	}
+}
+
 // Writes the journald redpanda logs, if available, to the bundle.
 func saveLogs(ctx context.Context, ps *stepParams, since, until string, logsLimitBytes int) step {
 	return func() error {
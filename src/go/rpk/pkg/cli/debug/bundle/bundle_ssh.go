@@ -0,0 +1,464 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// sshTarget is a broker host that we collect a bundle from over SSH.
+type sshTarget struct {
+	user string
+	addr string // host:port
+	dir  string // directory of this host's files in the merged bundle
+}
+
+// parseSSHHost parses a [user@]host[:port] host spec.
+func parseSSHHost(spec, defaultUser string) (sshTarget, error) {
+	t := sshTarget{user: defaultUser}
+	host := spec
+	if at := strings.LastIndexByte(host, '@'); at >= 0 {
+		t.user, host = host[:at], host[at+1:]
+	}
+	if host == "" || t.user == "" {
+		return t, fmt.Errorf("invalid host %q, expected [user@]host[:port]", spec)
+	}
+	h, port, err := net.SplitHostPort(host)
+	if err != nil {
+		// No port, or an unbracketed IPv6 address.
+		h, port = strings.Trim(host, "[]"), "22"
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return t, fmt.Errorf("invalid port in host %q", spec)
+	}
+	t.addr = net.JoinHostPort(h, port)
+	t.dir = sanitizeName(h)
+	return t, nil
+}
+
+// sshClientConfig returns the SSH configuration shared by every host,
+// authenticating with --ssh-key if specified, otherwise with the local SSH
+// agent and the default keys in ~/.ssh.
+func sshClientConfig(fs afero.Fs, bp bundleParams) (*ssh.ClientConfig, error) {
+	home, _ := os.UserHomeDir()
+
+	var auths []ssh.AuthMethod
+	if bp.sshKey != "" {
+		signer, err := readSSHKey(fs, bp.sshKey)
+		if err != nil {
+			return nil, err
+		}
+		auths = append(auths, ssh.PublicKeys(signer))
+	} else {
+		if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+			conn, err := net.Dial("unix", sock)
+			if err != nil {
+				zap.L().Sugar().Debugf("unable to connect to the SSH agent at %q: %v", sock, err)
+			} else {
+				auths = append(auths, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+			}
+		}
+		var signers []ssh.Signer
+		for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+			signer, err := readSSHKey(fs, filepath.Join(home, ".ssh", name))
+			if err != nil {
+				zap.L().Sugar().Debugf("skipping default SSH key: %v", err)
+				continue
+			}
+			signers = append(signers, signer)
+		}
+		if len(signers) > 0 {
+			auths = append(auths, ssh.PublicKeys(signers...))
+		}
+	}
+	if len(auths) == 0 {
+		return nil, errors.New("no SSH credentials found: start an SSH agent or specify --ssh-key")
+	}
+
+	hostKeyCallback := ssh.InsecureIgnoreHostKey() //nolint:gosec // opted into with --ssh-skip-host-key-check
+	if !bp.sshSkipHostKeyCheck {
+		knownHosts := bp.sshKnownHosts
+		if knownHosts == "" {
+			knownHosts = filepath.Join(home, ".ssh", "known_hosts")
+		}
+		cb, err := knownhosts.New(knownHosts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load known hosts file %q: %v", knownHosts, err)
+		}
+		hostKeyCallback = cb
+	}
+
+	return &ssh.ClientConfig{
+		Auth:            auths,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         bp.timeout,
+	}, nil
+}
+
+func readSSHKey(fs afero.Fs, path string) (ssh.Signer, error) {
+	raw, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read SSH key %q: %v", path, err)
+	}
+	signer, err := ssh.ParsePrivateKey(raw)
+	if err != nil {
+		var missing *ssh.PassphraseMissingError
+		if errors.As(err, &missing) {
+			return nil, fmt.Errorf("SSH key %q is encrypted; add it to your SSH agent instead", path)
+		}
+		return nil, fmt.Errorf("unable to parse SSH key %q: %v", path, err)
+	}
+	return signer, nil
+}
+
+// remoteBundleCommand returns the shell command that creates a bundle on a
+// broker and writes it to stdout. rpk's own output is sent to stderr.
+func remoteBundleCommand(bp bundleParams) string {
+	args := []string{bp.sshRemoteRpk, "debug", "bundle", `-o "$d/bundle.zip"`}
+	for _, f := range []struct {
+		name, value string
+	}{
+		{"--logs-since", bp.logsSince},
+		{"--logs-until", bp.logsUntil},
+		{"--logs-size-limit", strconv.Itoa(bp.logsLimitBytes) + "B"},
+		{"--controller-logs-size-limit", strconv.Itoa(bp.controllerLogLimitBytes) + "B"},
+		{"--timeout", bp.timeout.String()},
+		{"--metrics-interval", bp.metricsInterval.String()},
+		{"--metrics-duration", durationOrEmpty(bp.metricsDuration)},
+	} {
+		if f.value != "" {
+			args = append(args, f.name, shellQuote(f.value))
+		}
+	}
+	return `set -e; d=$(mktemp -d); trap 'rm -rf "$d"' EXIT; ` + strings.Join(args, " ") + ` >&2; cat "$d/bundle.zip"`
+}
+
+func durationOrEmpty(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return d.String()
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// sshNodeResult is a host's entry in the merged bundle's index.json.
+type sshNodeResult struct {
+	Host      string `json:"host"`
+	Directory string `json:"directory,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Files     int    `json:"files"`
+	Duration  string `json:"duration"`
+
+	zipPath string
+}
+
+// collectSSHBundle runs 'rpk debug bundle' on the host and downloads the
+// resulting zip to a local temporary file.
+func collectSSHBundle(ctx context.Context, base *ssh.ClientConfig, bp bundleParams, t sshTarget) (string, error) {
+	cfg := *base
+	cfg.User = t.user
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", t.addr)
+	if err != nil {
+		return "", fmt.Errorf("unable to dial: %v", err)
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, t.addr, &cfg)
+	if err != nil {
+		conn.Close()
+		return "", fmt.Errorf("unable to establish SSH connection: %v", err)
+	}
+	cl := ssh.NewClient(c, chans, reqs)
+	defer cl.Close()
+
+	sess, err := cl.NewSession()
+	if err != nil {
+		return "", fmt.Errorf("unable to open SSH session: %v", err)
+	}
+	defer sess.Close()
+
+	f, err := os.CreateTemp("", "rpk-bundle-*.zip")
+	if err != nil {
+		return "", fmt.Errorf("unable to create temporary file: %v", err)
+	}
+	defer f.Close()
+
+	var stderr bytes.Buffer
+	sess.Stdout = f
+	sess.Stderr = &stderr
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			cl.Close()
+		case <-done:
+		}
+	}()
+
+	if err := sess.Run(remoteBundleCommand(bp)); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("remote bundle failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	zap.L().Sugar().Debugf("bundle output from %s:\n%s", t.addr, stderr.String())
+	return f.Name(), nil
+}
+
+// executeSSHBundle collects a bundle from every host over SSH and merges them
+// into a single zip with a directory per host and a top level index.json.
+func executeSSHBundle(ctx context.Context, bp bundleParams) error {
+	defaultUser := bp.sshUser
+	if defaultUser == "" {
+		if u, err := user.Current(); err == nil {
+			defaultUser = u.Username
+		}
+	}
+	var targets []sshTarget
+	dirs := make(map[string]bool)
+	for _, h := range bp.sshHosts {
+		t, err := parseSSHHost(h, defaultUser)
+		if err != nil {
+			return err
+		}
+		// Two hosts may sanitize to the same directory, e.g. the same
+		// host on two ports.
+		for base, i := t.dir, 2; dirs[t.dir]; i++ {
+			t.dir = fmt.Sprintf("%s-%d", base, i)
+		}
+		dirs[t.dir] = true
+		targets = append(targets, t)
+	}
+
+	cfg, err := sshClientConfig(bp.fs, bp)
+	if err != nil {
+		return err
+	}
+
+	results := make([]sshNodeResult, len(targets))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		i, t := i, t
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fmt.Printf("Collecting bundle from %s...\n", t.addr)
+			start := time.Now()
+			path, err := collectSSHBundle(ctx, cfg, bp, t)
+			r := sshNodeResult{Host: t.addr, Directory: t.dir, zipPath: path}
+			if err != nil {
+				r.Error = err.Error()
+				r.Directory = ""
+				fmt.Printf("Unable to collect bundle from %s: %v\n", t.addr, err)
+			}
+			r.Duration = time.Since(start).Round(time.Millisecond).String()
+			results[i] = r
+		}()
+	}
+	wg.Wait()
+	defer func() {
+		for _, r := range results {
+			if r.zipPath != "" {
+				os.Remove(r.zipPath)
+			}
+		}
+	}()
+
+	f, err := bp.fs.OpenFile(bp.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to create bundle file: %v", err)
+	}
+	defer f.Close()
+	seedRedactor(ctx, bp)
+	if err := mergeBundles(f, results, bp.redactor); err != nil {
+		return err
+	}
+
+	var failed int
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if failed == len(results) {
+		return errors.New("unable to collect a bundle from any host")
+	}
+	fmt.Printf("Debug bundle from %d of %d hosts saved to '%s'\n", len(results)-failed, len(results), bp.path)
+	return nil
+}
+
+// mergeBundles writes every collected per-host bundle into w under the host's
+// directory, followed by index.json describing each host's collection and, if
+// there is a redaction policy, the redaction manifest.
+func mergeBundles(w io.Writer, results []sshNodeResult, red *redactor) error {
+	if red != nil {
+		redactSSHHosts(results, red)
+	}
+	zw := zip.NewWriter(w)
+	for i := range results {
+		r := &results[i]
+		if r.zipPath == "" {
+			continue
+		}
+		if err := copyBundleInto(zw, r, red); err != nil {
+			r.Error = err.Error()
+		}
+	}
+	if red != nil {
+		for i := range results {
+			results[i].Error = red.redactString(sshIndexFile, results[i].Error)
+		}
+	}
+
+	index, err := json.MarshalIndent(struct {
+		Created time.Time       `json:"created"`
+		Hosts   []sshNodeResult `json:"hosts"`
+	}{time.Now().UTC(), results}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode %s: %v", sshIndexFile, err)
+	}
+	if red != nil {
+		m, err := red.manifest()
+		if err != nil {
+			return fmt.Errorf("unable to encode the redaction manifest: %v", err)
+		}
+		if err := writeZipEntry(zw, redactManifestFile, m); err != nil {
+			return err
+		}
+	}
+	if err := writeZipEntry(zw, sshIndexFile, index); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// sshIndexFile describes the collection of each host of a merged bundle.
+const sshIndexFile = "index.json"
+
+// redactSSHHosts redacts the host addresses of the results, and names their
+// directories after the redacted address, since the directory names are
+// derived from the address.
+func redactSSHHosts(results []sshNodeResult, red *redactor) {
+	dirs := make(map[string]bool)
+	for i := range results {
+		r := &results[i]
+		redacted := red.redactString(sshIndexFile, r.Host)
+		if redacted == r.Host || r.Directory == "" {
+			r.Host = redacted
+			dirs[r.Directory] = true
+			continue
+		}
+		r.Host = redacted
+		h, _, err := net.SplitHostPort(redacted)
+		if err != nil {
+			h = redacted
+		}
+		dir := sanitizeName(h)
+		for base, n := dir, 2; dirs[dir]; n++ {
+			dir = fmt.Sprintf("%s-%d", base, n)
+		}
+		dirs[dir] = true
+		r.Directory = dir
+	}
+}
+
+func writeZipEntry(zw *zip.Writer, name string, contents []byte) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to write %s: %v", name, err)
+	}
+	if _, err := w.Write(contents); err != nil {
+		return fmt.Errorf("unable to write %s: %v", name, err)
+	}
+	return nil
+}
+
+// copyBundleInto copies the files of a host's bundle into zw. Without a
+// redaction policy, files are copied without recompressing them.
+func copyBundleInto(zw *zip.Writer, r *sshNodeResult, red *redactor) error {
+	zr, err := zip.OpenReader(r.zipPath)
+	if err != nil {
+		return fmt.Errorf("unable to open bundle from %s: %v", r.Host, err)
+	}
+	defer zr.Close()
+	for _, f := range zr.File {
+		name := r.Directory + "/" + f.Name
+		if red != nil {
+			copied, err := copyRedacted(zw, f, r.Directory, red)
+			if err != nil {
+				return fmt.Errorf("unable to copy %q from %s: %v", f.Name, r.Host, err)
+			}
+			if copied {
+				r.Files++
+			}
+			continue
+		}
+		fh := f.FileHeader
+		fh.Name = name
+		dst, err := zw.CreateRaw(&fh)
+		if err != nil {
+			return fmt.Errorf("unable to copy %q from %s: %v", f.Name, r.Host, err)
+		}
+		src, err := f.OpenRaw()
+		if err != nil {
+			return fmt.Errorf("unable to copy %q from %s: %v", f.Name, r.Host, err)
+		}
+		if _, err := io.Copy(dst, src); err != nil {
+			return fmt.Errorf("unable to copy %q from %s: %v", f.Name, r.Host, err)
+		}
+		r.Files++
+	}
+	return nil
+}
+
+// copyRedacted copies a redacted file into dir in zw, returning false if the
+// redaction policy excludes the file.
+func copyRedacted(zw *zip.Writer, f *zip.File, dir string, red *redactor) (bool, error) {
+	src, err := f.Open()
+	if err != nil {
+		return false, err
+	}
+	defer src.Close()
+	contents, err := io.ReadAll(src)
+	if err != nil {
+		return false, err
+	}
+	contents, keep := red.redactIn(dir, f.Name, contents)
+	if !keep {
+		return false, nil
+	}
+	return true, writeZipEntry(zw, dir+"/"+f.Name, contents)
+}
@@ -0,0 +1,163 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSSHHost(t *testing.T) {
+	for _, test := range []struct {
+		spec   string
+		exp    sshTarget
+		expErr bool
+	}{
+		{spec: "rp-0", exp: sshTarget{user: "me", addr: "rp-0:22", dir: "rp-0"}},
+		{spec: "admin@rp-1:2222", exp: sshTarget{user: "admin", addr: "rp-1:2222", dir: "rp-1"}},
+		{spec: "10.0.0.1", exp: sshTarget{user: "me", addr: "10.0.0.1:22", dir: "10.0.0.1"}},
+		{spec: "[::1]:23", exp: sshTarget{user: "me", addr: "[::1]:23", dir: "--1"}},
+		{spec: "::1", exp: sshTarget{user: "me", addr: "[::1]:22", dir: "--1"}},
+		{spec: "admin@", expErr: true},
+		{spec: "rp-0:notaport", expErr: true},
+	} {
+		t.Run(test.spec, func(t *testing.T) {
+			got, err := parseSSHHost(test.spec, "me")
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.exp, got)
+		})
+	}
+}
+
+func TestRemoteBundleCommand(t *testing.T) {
+	cmd := remoteBundleCommand(bundleParams{
+		sshRemoteRpk:            "sudo rpk",
+		logsSince:               "yesterday's",
+		logsLimitBytes:          100 << 20,
+		controllerLogLimitBytes: 20 << 20,
+		timeout:                 12 * time.Second,
+		metricsInterval:         10 * time.Second,
+	})
+	require.Equal(t,
+		`set -e; d=$(mktemp -d); trap 'rm -rf "$d"' EXIT; sudo rpk debug bundle -o "$d/bundle.zip" --logs-since 'yesterday'\''s' --logs-size-limit '104857600B' --controller-logs-size-limit '20971520B' --timeout '12s' --metrics-interval '10s' >&2; cat "$d/bundle.zip"`,
+		cmd,
+	)
+
+	// The forwarded size limits must parse back to the same size.
+	n, err := units.FromHumanSize("104857600B")
+	require.NoError(t, err)
+	require.Equal(t, int64(100<<20), n)
+}
+
+func TestMergeBundles(t *testing.T) {
+	dir := t.TempDir()
+	writeZip := func(name string, files map[string]string) string {
+		var buf bytes.Buffer
+		w := zip.NewWriter(&buf)
+		for name, contents := range files {
+			f, err := w.Create(name)
+			require.NoError(t, err)
+			_, err = f.Write([]byte(contents))
+			require.NoError(t, err)
+		}
+		require.NoError(t, w.Close())
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+		return path
+	}
+
+	results := []sshNodeResult{
+		{Host: "rp-0:22", Directory: "rp-0", zipPath: writeZip("0.zip", map[string]string{"kafka.json": "[]", "proc/cpuinfo": "cpu"})},
+		{Host: "rp-1:22", Error: "unable to dial"},
+		{Host: "rp-2:22", Directory: "rp-2", zipPath: writeZip("2.zip", map[string]string{"kafka.json": "{}"})},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, mergeBundles(&buf, results, nil))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	got := make(map[string]string)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		got[f.Name] = string(b)
+	}
+
+	var index struct {
+		Hosts []sshNodeResult `json:"hosts"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(got["index.json"]), &index))
+	delete(got, "index.json")
+
+	require.Equal(t, map[string]string{
+		"rp-0/kafka.json":   "[]",
+		"rp-0/proc/cpuinfo": "cpu",
+		"rp-2/kafka.json":   "{}",
+	}, got)
+	require.Len(t, index.Hosts, 3)
+	require.Equal(t, 2, index.Hosts[0].Files)
+	require.Equal(t, "unable to dial", index.Hosts[1].Error)
+	require.Equal(t, 1, index.Hosts[2].Files)
+}
+
+func TestMergeBundlesRedactsHosts(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "0.zip")
+	var zbuf bytes.Buffer
+	zw := zip.NewWriter(&zbuf)
+	f, err := zw.Create("kafka.json")
+	require.NoError(t, err)
+	_, err = f.Write([]byte("[]"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	require.NoError(t, os.WriteFile(path, zbuf.Bytes(), 0o644))
+
+	red, err := newRedactor(afero.NewMemMapFs(), "", []string{presetIPs})
+	require.NoError(t, err)
+	results := []sshNodeResult{
+		{Host: "10.0.0.1:22", Directory: "10.0.0.1", zipPath: path},
+		{Host: "10.0.0.2:22", Error: "unable to dial 10.0.0.2:22: connection refused"},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, mergeBundles(&buf, results, red))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	var names []string
+	var index []byte
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+		if f.Name == "index.json" {
+			r, err := f.Open()
+			require.NoError(t, err)
+			index, err = io.ReadAll(r)
+			require.NoError(t, err)
+		}
+	}
+	require.ElementsMatch(t, []string{"REDACTED-ips-1/kafka.json", redactManifestFile, "index.json"}, names)
+	require.NotContains(t, string(index), "10.0.0.")
+	require.Contains(t, string(index), "unable to dial REDACTED-ips-2:22")
+}
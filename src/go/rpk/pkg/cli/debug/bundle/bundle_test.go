@@ -13,10 +13,16 @@
 package bundle
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
 )
@@ -329,3 +335,40 @@ func TestSliceControllerDir(t *testing.T) {
 		})
 	}
 }
+
+// The bare-metal bundle saves the cluster config overrides that the
+// analyzer's cluster config check reads.
+func TestSaveClusterAdminAPICallsConfigOverrides(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/cluster_config" && r.URL.Query().Get("include_defaults") == "false":
+			io.WriteString(w, `{"log_segment_size":1048576}`)
+		case r.URL.Path == "/v1/cluster_config":
+			io.WriteString(w, `{"log_segment_size":1048576,"retention_bytes":null}`)
+		case r.URL.Path == "/v1/brokers", r.URL.Path == "/v1/partitions/reconfigurations":
+			io.WriteString(w, `[]`)
+		default:
+			io.WriteString(w, `{}`)
+		}
+	}))
+	defer svr.Close()
+
+	var buf bytes.Buffer
+	ps := &stepParams{fs: afero.NewMemMapFs(), w: zip.NewWriter(&buf), timeout: time.Second}
+	err := saveClusterAdminAPICalls(context.Background(), ps, ps.fs, &config.RpkProfile{}, []string{svr.URL})()
+	require.NoError(t, err)
+	require.NoError(t, ps.w.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	files, merged := make(bundleFiles), make(bundleFiles)
+	for _, f := range zr.File {
+		files[f.Name] = f
+		merged["10.0.0.1/"+f.Name] = f // as merged from --hosts
+	}
+	exp := []finding{
+		{sevInfo, "cluster config", "1 properties differ from defaults: log_segment_size=1048576"},
+	}
+	require.Equal(t, exp, checkClusterConfig(files, analyzeOpts{maxListed: 5}))
+	require.Equal(t, exp, checkClusterConfig(merged, analyzeOpts{maxListed: 5}))
+}
@@ -0,0 +1,611 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/metricsts"
+	"github.com/spf13/afero"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"gopkg.in/yaml.v3"
+)
+
+// Redaction presets that can be enabled with --redact or in a policy file.
+const (
+	presetIPs       = "ips"
+	presetHostnames = "hostnames"
+	presetSASLUsers = "sasl_users"
+	presetTopics    = "topics"
+)
+
+var redactPresets = []string{presetIPs, presetHostnames, presetSASLUsers, presetTopics}
+
+// redactManifestFile is the file in the bundle that lists what was redacted.
+const redactManifestFile = "redactions.json"
+
+// metricsTimeSeriesFile is where saveMetricsTimeSeries saves scrapes. It is
+// compressed, so the redactor decodes it to redact every scrape.
+const metricsTimeSeriesFile = "metrics/timeseries.gz"
+
+// redactPolicy is the format of the file passed with --redact-policy.
+type redactPolicy struct {
+	// Presets are built-in rules to enable, see redactPresets.
+	Presets []string `yaml:"presets,omitempty"`
+	// Exclude contains glob patterns of bundle files to leave out of the
+	// bundle entirely, e.g. "controller/*".
+	Exclude []string `yaml:"exclude,omitempty"`
+	// Rules are custom redaction rules.
+	Rules []redactPolicyRule `yaml:"rules,omitempty"`
+}
+
+// redactPolicyRule is a custom rule: either a regex applied to the contents
+// of every file, or a dot delimited key path applied to the values of JSON
+// and YAML files. In key paths, "*" matches any key or array index.
+type redactPolicyRule struct {
+	Name        string `yaml:"name"`
+	Regex       string `yaml:"regex,omitempty"`
+	KeyPath     string `yaml:"key_path,omitempty"`
+	Replacement string `yaml:"replacement,omitempty"`
+}
+
+// textRule replaces regex matches in file contents. If the regex has a
+// capture group, only the first group is replaced.
+type textRule struct {
+	name        string
+	re          *regexp.Regexp
+	replacement string
+	keep        func(string) bool // optional, returns true for matches to leave as is
+	standalone  bool              // only replace matches that are not part of a larger name
+}
+
+// keyRule replaces values at a key path in JSON and YAML files.
+type keyRule struct {
+	name        string
+	path        []string
+	replacement string
+}
+
+// redactor applies a redaction policy to every file written to a bundle, and
+// tracks what it redacted for the redaction manifest.
+type redactor struct {
+	mu sync.Mutex
+
+	presets []string
+	exclude []string
+	text    []*textRule
+	keys    []*keyRule
+
+	// tokens maps rule => original value => the stable token replacing it,
+	// so the same value is replaced with the same token across the bundle.
+	tokens map[string]map[string]string
+
+	counts   map[string]map[string]int // file => rule => redactions
+	excluded []string
+	binary   []string
+}
+
+// newRedactor returns a redactor for the policy file, if any, plus the given
+// presets. This returns nil if there is nothing to redact.
+func newRedactor(fs afero.Fs, policyFile string, presets []string) (*redactor, error) {
+	var policy redactPolicy
+	if policyFile != "" {
+		raw, err := afero.ReadFile(fs, policyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read redaction policy: %v", err)
+		}
+		dec := yaml.NewDecoder(bytes.NewReader(raw))
+		dec.KnownFields(true)
+		if err := dec.Decode(&policy); err != nil {
+			return nil, fmt.Errorf("unable to decode redaction policy %q: %v", policyFile, err)
+		}
+	}
+	policy.Presets = append(policy.Presets, presets...)
+	if len(policy.Presets) == 0 && len(policy.Exclude) == 0 && len(policy.Rules) == 0 {
+		return nil, nil
+	}
+
+	r := &redactor{
+		exclude: policy.Exclude,
+		tokens:  make(map[string]map[string]string),
+		counts:  make(map[string]map[string]int),
+	}
+	for _, pattern := range r.exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %v", pattern, err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, p := range policy.Presets {
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		switch p {
+		case presetIPs:
+			r.text = append(r.text, &textRule{name: presetIPs, re: ipRe})
+		case presetHostnames:
+			r.text = append(r.text, &textRule{name: presetHostnames, re: hostnameRe, keep: isFilename})
+		case presetSASLUsers:
+			r.text = append(r.text, &textRule{name: presetSASLUsers, re: userRe})
+			for _, kp := range []string{
+				"rpk.kafka_api.sasl.user",
+				"pandaproxy_client.scram_username",
+				"schema_registry_client.scram_username",
+			} {
+				r.keys = append(r.keys, &keyRule{name: presetSASLUsers, path: strings.Split(kp, ".")})
+			}
+		case presetTopics:
+			// Topic names are only known once we talk to the cluster;
+			// see addLiterals.
+		default:
+			return nil, fmt.Errorf("unknown redaction preset %q, available presets: %s", p, strings.Join(redactPresets, ", "))
+		}
+		r.presets = append(r.presets, p)
+	}
+
+	for i, rule := range policy.Rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		switch {
+		case rule.Regex != "" && rule.KeyPath != "":
+			return nil, fmt.Errorf("redaction rule %q: only one of regex and key_path can be set", rule.Name)
+		case rule.Regex != "":
+			re, err := regexp.Compile(rule.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("redaction rule %q: invalid regex: %v", rule.Name, err)
+			}
+			r.text = append(r.text, &textRule{name: rule.Name, re: re, replacement: rule.Replacement})
+		case rule.KeyPath != "":
+			r.keys = append(r.keys, &keyRule{name: rule.Name, path: strings.Split(rule.KeyPath, "."), replacement: rule.Replacement})
+		default:
+			return nil, fmt.Errorf("redaction rule %q: one of regex or key_path is required", rule.Name)
+		}
+	}
+	return r, nil
+}
+
+func (r *redactor) hasPreset(preset string) bool {
+	if r == nil {
+		return false
+	}
+	for _, p := range r.presets {
+		if p == preset {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	ipRe = regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b` +
+		`|(?i)\b(?:[0-9a-f]{1,4}:){7}[0-9a-f]{1,4}\b|\b(?:[0-9a-f]{1,4}:)+:(?:[0-9a-f]{1,4}:)*[0-9a-f]{1,4}\b`)
+
+	hostnameRe = regexp.MustCompile(`(?i)\b(?:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z][a-z0-9-]{0,61}[a-z0-9]\b`)
+
+	userRe = regexp.MustCompile(`(?i)\b(?:user(?:name)?|principal)["']?\s*[=:]\s*["']?(?:User:)?([\w.@+-]+)`)
+
+	// fileExts are extensions that make a "hostname" more likely a file
+	// name, which we do not redact.
+	fileExts = map[string]bool{
+		"yaml": true, "yml": true, "json": true, "txt": true, "log": true, "zip": true,
+		"pem": true, "crt": true, "key": true, "conf": true, "cc": true, "h": true,
+		"go": true, "py": true, "sh": true, "so": true, "service": true, "socket": true,
+	}
+)
+
+func isFilename(s string) bool {
+	return fileExts[strings.ToLower(s[strings.LastIndexByte(s, '.')+1:])]
+}
+
+// addLiterals adds a rule that replaces every occurrence of the given values
+// that is not part of a larger name, e.g. topic names discovered from the
+// cluster.
+func (r *redactor) addLiterals(name string, values []string) {
+	var quoted []string
+	for _, v := range values {
+		if v != "" {
+			quoted = append(quoted, regexp.QuoteMeta(v))
+		}
+	}
+	if len(quoted) == 0 {
+		return
+	}
+	// Longest first, so that "foo-bar" is preferred over "foo".
+	sort.Slice(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+	// RE2 has no lookaround: matching the delimiters would consume them,
+	// and a name right after another one ("orders orders") would not be
+	// matched. We match the names alone and check the neighbors in replace.
+	re := regexp.MustCompile(strings.Join(quoted, "|"))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.text = append(r.text, &textRule{name: name, re: re, standalone: true})
+}
+
+// isNameByte returns whether c can be part of a topic or user name, as
+// opposed to delimiting one.
+func isNameByte(c byte) bool {
+	return c == '_' || c == '.' || c == '-' ||
+		'0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// isStandalone returns whether s[start:end] is not adjacent to other name
+// bytes.
+func isStandalone(s string, start, end int) bool {
+	return (start == 0 || !isNameByte(s[start-1])) && (end == len(s) || !isNameByte(s[end]))
+}
+
+// token returns the replacement for a value matched by a rule. Unless the
+// rule has a fixed replacement, the same value is always replaced with the
+// same token, which keeps the bundle useful for correlating events.
+func (r *redactor) token(rule, replacement, orig string) string {
+	if replacement != "" {
+		return replacement
+	}
+	m := r.tokens[rule]
+	if m == nil {
+		m = make(map[string]string)
+		r.tokens[rule] = m
+	}
+	t, ok := m[orig]
+	if !ok {
+		t = fmt.Sprintf("REDACTED-%s-%d", strings.ReplaceAll(rule, " ", "_"), len(m)+1)
+		m[orig] = t
+	}
+	return t
+}
+
+func (r *redactor) count(file, rule string) {
+	m := r.counts[file]
+	if m == nil {
+		m = make(map[string]int)
+		r.counts[file] = m
+	}
+	m[rule]++
+}
+
+// redact returns the redacted contents of a bundle file, or false if the file
+// is excluded from the bundle.
+func (r *redactor) redact(filename string, contents []byte) ([]byte, bool) {
+	return r.redactIn("", filename, contents)
+}
+
+// redactIn is redact for a file within a directory of a merged bundle. Exclude
+// patterns match the file name within the directory.
+func (r *redactor) redactIn(dir, name string, contents []byte) ([]byte, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	filename := path.Join(dir, name)
+	for _, pattern := range r.exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			r.excluded = append(r.excluded, filename)
+			return nil, false
+		}
+	}
+	if name == metricsTimeSeriesFile {
+		return r.redactTimeSeries(filename, contents)
+	}
+	if !utf8.Valid(contents) {
+		// Rewriting binary files would corrupt them; we record them
+		// so that they can be reviewed or excluded.
+		r.binary = append(r.binary, filename)
+		return contents, true
+	}
+
+	if len(r.keys) > 0 {
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".json":
+			contents = r.redactJSON(filename, contents)
+		case ".yaml", ".yml":
+			contents = r.redactYAML(filename, contents)
+		}
+	}
+
+	s := string(contents)
+	for _, rule := range r.text {
+		s = r.replace(filename, rule, s)
+	}
+	return []byte(s), true
+}
+
+// redactString applies the text rules to a string that is written to the
+// bundle outside of any collected file, e.g. a host name in index.json.
+func (r *redactor) redactString(filename, s string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rule := range r.text {
+		s = r.replace(filename, rule, s)
+	}
+	return s
+}
+
+// redactTimeSeries applies the text rules to the instance and body of every
+// scrape of a metrics time series file. A file that cannot be decoded is
+// excluded, since we cannot tell what it contains.
+func (r *redactor) redactTimeSeries(filename string, contents []byte) ([]byte, bool) {
+	scrapes, err := metricsts.Read(bytes.NewReader(contents))
+	if err != nil {
+		r.excluded = append(r.excluded, filename)
+		return nil, false
+	}
+	var buf bytes.Buffer
+	w, err := metricsts.NewWriter(&buf)
+	if err != nil {
+		r.excluded = append(r.excluded, filename)
+		return nil, false
+	}
+	for _, s := range scrapes {
+		body := string(s.Body)
+		for _, rule := range r.text {
+			s.Instance = r.replace(filename, rule, s.Instance)
+			body = r.replace(filename, rule, body)
+		}
+		s.Body = []byte(body)
+		if err := w.Write(s); err != nil {
+			r.excluded = append(r.excluded, filename)
+			return nil, false
+		}
+	}
+	if err := w.Close(); err != nil {
+		r.excluded = append(r.excluded, filename)
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
+
+func (r *redactor) replace(filename string, rule *textRule, s string) string {
+	var b strings.Builder
+	var last int
+	for _, m := range rule.re.FindAllStringSubmatchIndex(s, -1) {
+		start, end := m[0], m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		orig := s[start:end]
+		if strings.HasPrefix(orig, "REDACTED-") || rule.keep != nil && rule.keep(orig) {
+			continue
+		}
+		if rule.standalone && !isStandalone(s, start, end) {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(r.token(rule.name, rule.replacement, orig))
+		last = end
+		r.count(filename, rule.name)
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+func keyMatches(pattern []string, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i := range pattern {
+		if pattern[i] != "*" && pattern[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// redactJSON applies key rules to a JSON file. If the file cannot be decoded,
+// it is returned unchanged and only text rules apply.
+func (r *redactor) redactJSON(filename string, contents []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(contents))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return contents
+	}
+	var changed bool
+	var walk func(v interface{}, path []string) interface{}
+	walk = func(v interface{}, path []string) interface{} {
+		for _, rule := range r.keys {
+			if keyMatches(rule.path, path) {
+				changed = true
+				return r.redactLeaves(filename, rule, v)
+			}
+		}
+		switch t := v.(type) {
+		case map[string]interface{}:
+			for k, e := range t {
+				t[k] = walk(e, append(path, k))
+			}
+		case []interface{}:
+			for i, e := range t {
+				t[i] = walk(e, append(path, fmt.Sprint(i)))
+			}
+		}
+		return v
+	}
+	v = walk(v, nil)
+	if !changed {
+		return contents
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return contents
+	}
+	return out
+}
+
+// redactLeaves replaces every scalar in v.
+func (r *redactor) redactLeaves(filename string, rule *keyRule, v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = r.redactLeaves(filename, rule, e)
+		}
+		return t
+	case []interface{}:
+		for i, e := range t {
+			t[i] = r.redactLeaves(filename, rule, e)
+		}
+		return t
+	case nil:
+		return nil
+	default:
+		r.count(filename, rule.name)
+		return r.token(rule.name, rule.replacement, fmt.Sprint(t))
+	}
+}
+
+// redactYAML applies key rules to a YAML file, preserving its order and
+// comments. If the file cannot be decoded, it is returned unchanged.
+func (r *redactor) redactYAML(filename string, contents []byte) []byte {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(contents, &doc); err != nil || len(doc.Content) == 0 {
+		return contents
+	}
+	var changed bool
+	var walk func(n *yaml.Node, path []string)
+	walk = func(n *yaml.Node, path []string) {
+		for _, rule := range r.keys {
+			if keyMatches(rule.path, path) {
+				changed = true
+				r.redactYAMLLeaves(filename, rule, n)
+				return
+			}
+		}
+		switch n.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				walk(n.Content[i+1], append(path, n.Content[i].Value))
+			}
+		case yaml.SequenceNode:
+			for i, e := range n.Content {
+				walk(e, append(path, fmt.Sprint(i)))
+			}
+		}
+	}
+	walk(doc.Content[0], nil)
+	if !changed {
+		return contents
+	}
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return contents
+	}
+	return out
+}
+
+func (r *redactor) redactYAMLLeaves(filename string, rule *keyRule, n *yaml.Node) {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if n.Tag == "!!null" {
+			return
+		}
+		r.count(filename, rule.name)
+		n.Value = r.token(rule.name, rule.replacement, n.Value)
+		n.Tag = "!!str"
+		n.Style = 0
+	case yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			r.redactYAMLLeaves(filename, rule, n.Content[i])
+		}
+	case yaml.SequenceNode:
+		for _, e := range n.Content {
+			r.redactYAMLLeaves(filename, rule, e)
+		}
+	}
+}
+
+// manifest returns the contents of the redaction manifest. The manifest
+// describes what was redacted, never the original values.
+func (r *redactor) manifest() ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var rules []string
+	seen := make(map[string]bool)
+	for _, t := range r.text {
+		if !seen[t.name] {
+			seen[t.name] = true
+			rules = append(rules, t.name)
+		}
+	}
+	for _, k := range r.keys {
+		if !seen[k.name] {
+			seen[k.name] = true
+			rules = append(rules, k.name)
+		}
+	}
+	distinct := make(map[string]int)
+	for rule, m := range r.tokens {
+		distinct[rule] = len(m)
+	}
+	sort.Strings(r.excluded)
+	sort.Strings(r.binary)
+
+	return json.MarshalIndent(struct {
+		Presets        []string                  `json:"presets,omitempty"`
+		Rules          []string                  `json:"rules"`
+		Files          map[string]map[string]int `json:"redactions_per_file"`
+		DistinctValues map[string]int            `json:"distinct_values_per_rule"`
+		Excluded       []string                  `json:"excluded_files,omitempty"`
+		Binary         []string                  `json:"unredacted_binary_files,omitempty"`
+	}{r.presets, rules, r.counts, distinct, r.excluded, r.binary}, "", "  ")
+}
+
+// seedRedactor adds the values that only the cluster and config know about to
+// the redaction policy: topic names for the topics preset, and the configured
+// SASL users for the sasl_users preset.
+func seedRedactor(ctx context.Context, bp bundleParams) {
+	r := bp.redactor
+	if r.hasPreset(presetTopics) {
+		ctx, cancel := context.WithTimeout(ctx, bp.timeout)
+		defer cancel()
+		topics, err := kadm.NewClient(bp.cl).ListTopics(ctx)
+		if err != nil {
+			fmt.Printf("Unable to list topics to redact, topic names will not be redacted: %v\n", err)
+		} else {
+			var names []string
+			for _, t := range topics {
+				// Internal topics such as __consumer_offsets and
+				// _schemas are not customer data.
+				if !t.IsInternal && !strings.HasPrefix(t.Topic, "_") {
+					names = append(names, t.Topic)
+				}
+			}
+			r.addLiterals(presetTopics, names)
+		}
+	}
+	if r.hasPreset(presetSASLUsers) {
+		var users []string
+		if sasl := bp.p.KafkaAPI.SASL; sasl != nil {
+			users = append(users, sasl.User)
+		}
+		if c := bp.y.PandaproxyClient; c != nil && c.SCRAMUsername != nil {
+			users = append(users, *c.SCRAMUsername)
+		}
+		if c := bp.y.SchemaRegistryClient; c != nil && c.SCRAMUsername != nil {
+			users = append(users, *c.SCRAMUsername)
+		}
+		r.addLiterals(presetSASLUsers, users)
+	}
+}
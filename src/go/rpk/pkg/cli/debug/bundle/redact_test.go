@@ -0,0 +1,175 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/metricsts"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRedactor(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		policy  string
+		presets []string
+		expNil  bool
+		expErr  bool
+	}{
+		{name: "nothing to redact", expNil: true},
+		{name: "presets only", presets: []string{"ips", "topics"}},
+		{name: "unknown preset", presets: []string{"emails"}, expErr: true},
+		{name: "policy", policy: "presets: [hostnames]\nexclude: [\"controller/*\"]\nrules:\n  - regex: 'id=(\\d+)'\n  - key_path: a.*.b\n"},
+		{name: "unknown field", policy: "rulez: []\n", expErr: true},
+		{name: "invalid regex", policy: "rules:\n  - regex: '('\n", expErr: true},
+		{name: "regex and key path", policy: "rules:\n  - regex: a\n    key_path: b\n", expErr: true},
+		{name: "empty rule", policy: "rules:\n  - name: foo\n", expErr: true},
+		{name: "invalid exclude", policy: "exclude: ['[']\n", expErr: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			var file string
+			if test.policy != "" {
+				file = "/policy.yaml"
+				require.NoError(t, afero.WriteFile(fs, file, []byte(test.policy), 0o644))
+			}
+			r, err := newRedactor(fs, file, test.presets)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expNil, r == nil)
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	policy := `
+presets: [ips, hostnames, sasl_users]
+exclude: ["controller/*"]
+rules:
+  - name: customer
+    regex: 'customer-id=(\w+)'
+  - name: rack
+    key_path: redpanda.rack
+    replacement: (REDACTED)
+  - name: hosts
+    key_path: Brokers.*.Host
+`
+	require.NoError(t, afero.WriteFile(fs, "/policy.yaml", []byte(policy), 0o644))
+	r, err := newRedactor(fs, "/policy.yaml", []string{"topics"})
+	require.NoError(t, err)
+	r.addLiterals(presetTopics, []string{"orders", "orders-eu"})
+
+	redact := func(name, contents string) string {
+		got, keep := r.redact(name, []byte(contents))
+		require.True(t, keep)
+		return string(got)
+	}
+
+	// Text rules, with stable tokens across files.
+	require.Equal(t,
+		"connect from REDACTED-ips-1 to REDACTED-hostnames-1 for REDACTED-topics-1 and REDACTED-topics-2/0, read redpanda.yaml",
+		redact("redpanda.log", "connect from 10.0.0.1 to rp-0.example.com for orders and orders-eu/0, read redpanda.yaml"),
+	)
+	require.Equal(t,
+		"REDACTED-ips-1 REDACTED-ips-2 REDACTED-ips-3 user=REDACTED-sasl_users-1 customer-id=REDACTED-customer-1 ordersx",
+		redact("syslog.txt", "10.0.0.1 fe80::1 2001:db8:0:0:0:0:0:1 user=alice customer-id=acme ordersx"),
+	)
+	// Names separated by a single delimiter are all redacted.
+	require.Equal(t,
+		"REDACTED-topics-1 REDACTED-topics-1,REDACTED-topics-2 xorders",
+		redact("syslog.txt", "orders orders,orders-eu xorders"),
+	)
+	// Timestamps are not IPv6 addresses.
+	require.Equal(t, "12:30:45 ok", redact("syslog.txt", "12:30:45 ok"))
+
+	// Key paths in YAML keep the document and only replace values.
+	require.Equal(t,
+		"redpanda:\n    rack: (REDACTED)\n    node_id: 1\nrpk:\n    kafka_api:\n        sasl:\n            user: REDACTED-sasl_users-1\n",
+		redact("redpanda.yaml", "redpanda:\n  rack: us-east-1a\n  node_id: 1\nrpk:\n  kafka_api:\n    sasl:\n      user: alice\n"),
+	)
+
+	// Key paths in JSON, with wildcards.
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(redact("kafka.json", `{"Brokers":[{"NodeID":0,"Host":"broker-a"},{"NodeID":1,"Host":"broker-b"}]}`)), &got))
+	require.Equal(t, map[string]interface{}{"Brokers": []interface{}{
+		map[string]interface{}{"NodeID": float64(0), "Host": "REDACTED-hosts-1"},
+		map[string]interface{}{"NodeID": float64(1), "Host": "REDACTED-hosts-2"},
+	}}, got)
+
+	// Excluded and binary files.
+	_, keep := r.redact("controller/0-1-v1.log", []byte("x"))
+	require.False(t, keep)
+	bin := []byte{0xff, 0xfe, '1', '0', '.', '0', '.', '0', '.', '1'}
+	gotBin, keep := r.redact("data.bin", bin)
+	require.True(t, keep)
+	require.Equal(t, bin, gotBin)
+
+	// Within a merged bundle, exclude patterns match within the directory.
+	_, keep = r.redactIn("rp-1", "controller/0-1-v1.log", []byte("x"))
+	require.False(t, keep)
+
+	raw, err := r.manifest()
+	require.NoError(t, err)
+	var m struct {
+		Presets  []string                  `json:"presets"`
+		Files    map[string]map[string]int `json:"redactions_per_file"`
+		Distinct map[string]int            `json:"distinct_values_per_rule"`
+		Excluded []string                  `json:"excluded_files"`
+		Binary   []string                  `json:"unredacted_binary_files"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &m))
+	require.Equal(t, []string{"ips", "hostnames", "sasl_users", "topics"}, m.Presets)
+	require.Equal(t, map[string]int{"ips": 1, "hostnames": 1, "topics": 2}, m.Files["redpanda.log"])
+	require.Equal(t, 3, m.Files["syslog.txt"]["topics"])
+	require.Equal(t, 3, m.Distinct["ips"])
+	require.Equal(t, []string{"controller/0-1-v1.log", "rp-1/controller/0-1-v1.log"}, m.Excluded)
+	require.Equal(t, []string{"data.bin"}, m.Binary)
+	require.NotContains(t, string(raw), "alice", "the manifest never contains original values")
+}
+
+func TestRedactTimeSeries(t *testing.T) {
+	r, err := newRedactor(afero.NewMemMapFs(), "", []string{presetIPs, presetTopics})
+	require.NoError(t, err)
+	r.addLiterals(presetTopics, []string{"orders"})
+
+	var buf bytes.Buffer
+	w, err := metricsts.NewWriter(&buf)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(metricsts.Scrape{
+		Time:     time.UnixMilli(1000),
+		Instance: "10.0.0.1:9644",
+		Endpoint: metricsts.EndpointPublicMetrics,
+		Body:     []byte(`redpanda_kafka_request_bytes_total{redpanda_topic="orders"} 10` + "\n"),
+	}))
+	require.NoError(t, w.Close())
+
+	got, keep := r.redactIn("rp-0", metricsTimeSeriesFile, buf.Bytes())
+	require.True(t, keep)
+	scrapes, err := metricsts.Read(bytes.NewReader(got))
+	require.NoError(t, err)
+	require.Len(t, scrapes, 1)
+	require.Equal(t, "REDACTED-ips-1:9644", scrapes[0].Instance)
+	require.Equal(t, `redpanda_kafka_request_bytes_total{redpanda_topic="REDACTED-topics-1"} 10`+"\n", string(scrapes[0].Body))
+
+	// A time series that cannot be decoded is left out rather than shipped
+	// unredacted.
+	_, keep = r.redact(metricsTimeSeriesFile, []byte{0x1f, 0x8b, 0xff})
+	require.False(t, keep)
+	require.Empty(t, r.binary)
+}
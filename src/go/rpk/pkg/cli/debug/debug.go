@@ -25,6 +25,8 @@ func NewCommand(fs afero.Fs, p *config.Params) *cobra.Command {
 	cmd.AddCommand(
 		bundle.NewCommand(fs, p),
 		NewInfoCommand(),
+		NewMetricsReplayCommand(fs),
+		NewTopCommand(fs, p),
 	)
 
 	return cmd
@@ -0,0 +1,291 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package debug
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/metricsts"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func NewMetricsReplayCommand(fs afero.Fs) *cobra.Command {
+	var (
+		listen      string
+		speed       float64
+		loop        bool
+		openMetrics bool
+	)
+	cmd := &cobra.Command{
+		Use:   "metrics-replay [FILE]",
+		Short: "Serve a metrics time series captured in a debug bundle as a Prometheus endpoint",
+		Long: `Serve a metrics time series captured in a debug bundle as a Prometheus endpoint.
+
+'rpk debug bundle --metrics-duration' captures the metrics of every broker into
+metrics/timeseries.gz in the bundle. This command reads that time series, from
+either the bundle ZIP file itself or the extracted file, and replays it on
+/metrics and /public_metrics, so that you can point a local Prometheus and
+Grafana (for example, with the dashboards from 'rpk generate grafana-dashboard')
+at it.
+
+The replay starts at the first scrape and advances in real time, or --speed
+times faster. Each broker's samples are labeled with its admin API address in
+the "instance" label, so configure the Prometheus scrape job with
+"honor_labels: true":
+
+    scrape_configs:
+      - job_name: redpanda-replay
+        honor_labels: true
+        scrape_interval: 5s
+        static_configs:
+          - targets: ["127.0.0.1:19644"]
+
+With --openmetrics, this command instead prints every sample with its original
+timestamp in the OpenMetrics format and exits. This output can be backfilled
+into Prometheus, which keeps the original time axis:
+
+    rpk debug metrics-replay bundle.zip --openmetrics > replay.om
+    promtool tsdb create-blocks-from openmetrics replay.om ./data
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			scrapes, err := loadTimeSeries(fs, args[0])
+			out.MaybeDieErr(err)
+
+			if openMetrics {
+				err = writeOpenMetrics(os.Stdout, scrapes)
+				out.MaybeDie(err, "unable to write OpenMetrics: %v", err)
+				return
+			}
+			if speed <= 0 {
+				out.Die("--speed must be positive")
+			}
+
+			r := newReplay(scrapes, speed, loop)
+			first, last := scrapes[0].Time, scrapes[len(scrapes)-1].Time
+			fmt.Printf("Replaying %d scrapes from %s to %s (%s) at %gx speed.\n", len(scrapes), first.Format(time.RFC3339), last.Format(time.RFC3339), last.Sub(first), speed)
+			fmt.Printf("Serving http://%s/metrics and http://%s/public_metrics, press Ctrl+C to stop.\n", listen, listen)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/metrics", r.handler(metricsts.EndpointMetrics))
+			mux.HandleFunc("/public_metrics", r.handler(metricsts.EndpointPublicMetrics))
+			srv := &http.Server{Addr: listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+			go func() {
+				<-cmd.Context().Done()
+				srv.Close()
+			}()
+			err = srv.ListenAndServe()
+			if !errors.Is(err, http.ErrServerClosed) {
+				out.MaybeDie(err, "unable to serve: %v", err)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&listen, "listen", "127.0.0.1:19644", "Address to serve the metrics on")
+	cmd.Flags().Float64Var(&speed, "speed", 1, "Replay speed multiplier")
+	cmd.Flags().BoolVar(&loop, "loop", false, "Restart the replay from the beginning once it ends, rather than serving the last scrape")
+	cmd.Flags().BoolVar(&openMetrics, "openmetrics", false, "Print every sample in the OpenMetrics format with its original timestamp and exit")
+	return cmd
+}
+
+// loadTimeSeries loads the scrapes from a time series file, or from every
+// time series file in a debug bundle (a cluster-wide bundle has one per
+// broker).
+func loadTimeSeries(fs afero.Fs, path string) ([]metricsts.Scrape, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open %q: %v", path, err)
+	}
+	defer f.Close()
+
+	var scrapes []metricsts.Scrape
+	if !strings.EqualFold(filepath.Ext(path), ".zip") {
+		scrapes, err = metricsts.Read(f)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		stat, err := f.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("unable to stat %q: %v", path, err)
+		}
+		zr, err := zip.NewReader(f, stat.Size())
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %q as a zip file: %v", path, err)
+		}
+		for _, zf := range zr.File {
+			if zf.Name != "metrics/timeseries.gz" && !strings.HasSuffix(zf.Name, "/metrics/timeseries.gz") {
+				continue
+			}
+			r, err := zf.Open()
+			if err != nil {
+				return nil, fmt.Errorf("unable to open %q in the bundle: %v", zf.Name, err)
+			}
+			s, err := metricsts.Read(r)
+			r.Close()
+			if err != nil {
+				return nil, fmt.Errorf("unable to read %q in the bundle: %v", zf.Name, err)
+			}
+			scrapes = append(scrapes, s...)
+		}
+		sort.SliceStable(scrapes, func(i, j int) bool { return scrapes[i].Time.Before(scrapes[j].Time) })
+	}
+	if len(scrapes) == 0 {
+		return nil, fmt.Errorf("%q contains no metrics time series; was the bundle created with --metrics-duration?", path)
+	}
+	return scrapes, nil
+}
+
+// parseScrape parses a scrape and labels every sample with the scrape's
+// instance, and if withTimestamp, with the scrape's time.
+func parseScrape(s metricsts.Scrape, withTimestamp bool) (map[string]*dto.MetricFamily, error) {
+	var p expfmt.TextParser
+	families, err := p.TextToMetricFamilies(bytes.NewReader(s.Body))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s scrape of %s at %s: %v", s.Endpoint, s.Instance, s.Time, err)
+	}
+	instanceName, instance := "instance", s.Instance
+	ts := s.Time.UnixMilli()
+	for _, mf := range families {
+		for _, m := range mf.Metric {
+			m.Label = append(m.Label, &dto.LabelPair{Name: &instanceName, Value: &instance})
+			if withTimestamp {
+				m.TimestampMs = &ts
+			}
+		}
+	}
+	return families, nil
+}
+
+// mergeFamilies appends the metrics of src to dst.
+func mergeFamilies(dst, src map[string]*dto.MetricFamily) {
+	for name, mf := range src {
+		if have, ok := dst[name]; ok {
+			have.Metric = append(have.Metric, mf.Metric...)
+		} else {
+			dst[name] = mf
+		}
+	}
+}
+
+func sortedFamilies(families map[string]*dto.MetricFamily) []*dto.MetricFamily {
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sorted := make([]*dto.MetricFamily, 0, len(names))
+	for _, name := range names {
+		sorted = append(sorted, families[name])
+	}
+	return sorted
+}
+
+// writeOpenMetrics writes every sample of every scrape with its timestamp.
+// OpenMetrics requires each family to be written once, so we merge every
+// scrape first.
+func writeOpenMetrics(w io.Writer, scrapes []metricsts.Scrape) error {
+	all := make(map[string]*dto.MetricFamily)
+	for _, s := range scrapes {
+		families, err := parseScrape(s, true)
+		if err != nil {
+			return err
+		}
+		mergeFamilies(all, families)
+	}
+	for _, mf := range sortedFamilies(all) {
+		if _, err := expfmt.MetricFamilyToOpenMetrics(w, mf); err != nil {
+			return err
+		}
+	}
+	_, err := expfmt.FinalizeOpenMetrics(w)
+	return err
+}
+
+// replay serves scrapes as if they were happening now.
+type replay struct {
+	scrapes []metricsts.Scrape
+	speed   float64
+	loop    bool
+	start   time.Time
+	now     func() time.Time
+}
+
+func newReplay(scrapes []metricsts.Scrape, speed float64, loop bool) *replay {
+	return &replay{scrapes: scrapes, speed: speed, loop: loop, start: time.Now(), now: time.Now}
+}
+
+// at returns the point in the captured time series that is being replayed.
+func (r *replay) at() time.Time {
+	first, last := r.scrapes[0].Time, r.scrapes[len(r.scrapes)-1].Time
+	elapsed := time.Duration(float64(r.now().Sub(r.start)) * r.speed)
+	span := last.Sub(first)
+	if elapsed > span {
+		if !r.loop || span == 0 {
+			return last
+		}
+		elapsed %= span + 1
+	}
+	return first.Add(elapsed)
+}
+
+// current returns the latest scrape of the endpoint of each instance at the
+// replay's current time.
+func (r *replay) current(endpoint string) []metricsts.Scrape {
+	at := r.at()
+	latest := make(map[string]metricsts.Scrape)
+	for _, s := range r.scrapes {
+		if s.Time.After(at) {
+			break
+		}
+		if s.Endpoint == endpoint {
+			latest[s.Instance] = s
+		}
+	}
+	current := make([]metricsts.Scrape, 0, len(latest))
+	for _, s := range latest {
+		current = append(current, s)
+	}
+	sort.Slice(current, func(i, j int) bool { return current[i].Instance < current[j].Instance })
+	return current
+}
+
+func (r *replay) handler(endpoint string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		all := make(map[string]*dto.MetricFamily)
+		for _, s := range r.current(endpoint) {
+			families, err := parseScrape(s, false)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			mergeFamilies(all, families)
+		}
+		w.Header().Set("Content-Type", string(expfmt.FmtText))
+		for _, mf := range sortedFamilies(all) {
+			if _, err := expfmt.MetricFamilyToText(w, mf); err != nil {
+				return
+			}
+		}
+	}
+}
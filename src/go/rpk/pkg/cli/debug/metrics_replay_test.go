@@ -0,0 +1,120 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package debug
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/metricsts"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func testScrapes() []metricsts.Scrape {
+	t0 := time.UnixMilli(1686000000000)
+	body := func(v string) []byte {
+		return []byte("# HELP redpanda_up Up.\n# TYPE redpanda_up gauge\nredpanda_up{shard=\"0\"} " + v + "\n")
+	}
+	return []metricsts.Scrape{
+		{Time: t0, Instance: "rp-0:9644", Endpoint: metricsts.EndpointPublicMetrics, Body: body("1")},
+		{Time: t0, Instance: "rp-1:9644", Endpoint: metricsts.EndpointPublicMetrics, Body: body("1")},
+		{Time: t0.Add(10 * time.Second), Instance: "rp-0:9644", Endpoint: metricsts.EndpointPublicMetrics, Body: body("0")},
+	}
+}
+
+func TestLoadTimeSeries(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	var ts bytes.Buffer
+	w, err := metricsts.NewWriter(&ts)
+	require.NoError(t, err)
+	for _, s := range testScrapes() {
+		require.NoError(t, w.Write(s))
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, afero.WriteFile(fs, "/timeseries.gz", ts.Bytes(), 0o644))
+
+	// A cluster-wide bundle with the same series for two brokers.
+	var zbuf bytes.Buffer
+	zw := zip.NewWriter(&zbuf)
+	for _, name := range []string{"rp-0/metrics/timeseries.gz", "rp-1/metrics/timeseries.gz", "rp-0/kafka.json"} {
+		f, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write(ts.Bytes())
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, afero.WriteFile(fs, "/bundle.zip", zbuf.Bytes(), 0o644))
+
+	scrapes, err := loadTimeSeries(fs, "/timeseries.gz")
+	require.NoError(t, err)
+	require.Len(t, scrapes, 3)
+
+	scrapes, err = loadTimeSeries(fs, "/bundle.zip")
+	require.NoError(t, err)
+	require.Len(t, scrapes, 6)
+
+	require.NoError(t, afero.WriteFile(fs, "/empty.zip", emptyZip(t), 0o644))
+	_, err = loadTimeSeries(fs, "/empty.zip")
+	require.Error(t, err)
+}
+
+func emptyZip(t *testing.T) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, zip.NewWriter(&buf).Close())
+	return buf.Bytes()
+}
+
+func TestReplay(t *testing.T) {
+	r := newReplay(testScrapes(), 2, false)
+	now := r.start
+	r.now = func() time.Time { return now }
+
+	get := func() string {
+		rec := httptest.NewRecorder()
+		r.handler(metricsts.EndpointPublicMetrics)(rec, httptest.NewRequest("GET", "/public_metrics", nil))
+		b, err := io.ReadAll(rec.Body)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	require.Equal(t, `# HELP redpanda_up Up.
+# TYPE redpanda_up gauge
+redpanda_up{shard="0",instance="rp-0:9644"} 1
+redpanda_up{shard="0",instance="rp-1:9644"} 1
+`, get())
+
+	// At 2x speed, 5s in is the second rp-0 scrape; rp-1 keeps its last.
+	now = now.Add(5 * time.Second)
+	require.Contains(t, get(), `redpanda_up{shard="0",instance="rp-0:9644"} 0`)
+	require.Contains(t, get(), `redpanda_up{shard="0",instance="rp-1:9644"} 1`)
+
+	// Without looping, the replay stays at the end.
+	now = now.Add(time.Hour)
+	require.True(t, r.at().Equal(testScrapes()[2].Time))
+
+	require.Empty(t, r.current(metricsts.EndpointMetrics))
+}
+
+func TestWriteOpenMetrics(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeOpenMetrics(&buf, testScrapes()))
+	require.Equal(t, `# HELP redpanda_up Up.
+# TYPE redpanda_up gauge
+redpanda_up{shard="0",instance="rp-0:9644"} 1.0 1.686e+09
+redpanda_up{shard="0",instance="rp-1:9644"} 1.0 1.686e+09
+redpanda_up{shard="0",instance="rp-0:9644"} 0.0 1.68600001e+09
+# EOF
+`, buf.String())
+}
@@ -0,0 +1,402 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package debug
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/docker/go-units"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/adminapi"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func NewTopCommand(fs afero.Fs, p *config.Params) *cobra.Command {
+	var (
+		interval   time.Duration
+		topics     int
+		iterations int
+	)
+	cmd := &cobra.Command{
+		Use:   "top",
+		Short: "Show live per-broker and per-topic activity",
+		Long: `Show live per-broker and per-topic activity.
+
+This command polls the public metrics endpoint (/public_metrics) of every broker
+in the admin API addresses and shows, for each refresh interval:
+
+* produce and fetch throughput, per broker and for the cluster
+* p99 produce and fetch request latency
+* the number of under-replicated partition replicas
+* disk usage
+* the topics with the most bytes produced and fetched
+
+Rates and latencies are computed from the difference between two consecutive
+polls, so the first view appears after one interval. A broker that cannot be
+polled is shown with its error rather than stopping the view.
+
+When the output is a terminal, the screen is redrawn on every refresh;
+otherwise each refresh is printed after the previous one. Use --iterations to
+stop after a fixed number of refreshes.
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			p, err := p.LoadVirtualProfile(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+			out.CheckExitCloudAdmin(p)
+
+			if interval <= 0 {
+				out.Die("--interval must be positive")
+			}
+			addrs := p.AdminAPI.Addresses
+			if len(addrs) == 0 {
+				out.Die("no admin API addresses configured")
+			}
+			var brokers []topBroker
+			for _, a := range addrs {
+				cl, err := adminapi.NewHostClient(fs, p, a)
+				out.MaybeDie(err, "unable to initialize admin client for %q: %v", a, err)
+				brokers = append(brokers, topBroker{a, cl.PublicMetrics})
+			}
+
+			redraw := term.IsTerminal(int(os.Stdout.Fd()))
+			ctx := cmd.Context()
+			prev := pollBrokers(ctx, brokers)
+			for i := 0; iterations == 0 || i < iterations; i++ {
+				select {
+				case <-time.After(interval):
+				case <-ctx.Done():
+					return
+				}
+				cur := pollBrokers(ctx, brokers)
+				var buf bytes.Buffer
+				if redraw {
+					buf.WriteString("\x1b[H\x1b[2J")
+				}
+				renderTop(&buf, prev, cur, topics)
+				os.Stdout.Write(buf.Bytes())
+				prev = cur
+			}
+		},
+	}
+	p.InstallAdminFlags(cmd)
+	p.InstallSASLFlags(cmd)
+
+	cmd.Flags().DurationVar(&interval, "interval", 3*time.Second, "How often to poll the brokers")
+	cmd.Flags().IntVar(&topics, "topics", 10, "Number of topics to show, sorted by bytes in and out")
+	cmd.Flags().IntVarP(&iterations, "iterations", "n", 0, "Number of refreshes before exiting (0 refreshes until interrupted)")
+	return cmd
+}
+
+type topBroker struct {
+	addr    string
+	metrics func(context.Context) ([]byte, error)
+}
+
+// brokerSample is the part of a broker's public metrics that top shows.
+type brokerSample struct {
+	addr string
+	time time.Time
+	err  error
+
+	produceBytes    float64
+	fetchBytes      float64
+	produceLatency  histogram
+	fetchLatency    histogram
+	underReplicated float64
+	diskFree        float64
+	diskTotal       float64
+	topicIn         map[string]float64
+	topicOut        map[string]float64
+}
+
+// histogram maps a bucket's upper bound to its cumulative count.
+type histogram map[float64]float64
+
+func pollBrokers(ctx context.Context, brokers []topBroker) []brokerSample {
+	samples := make([]brokerSample, len(brokers))
+	var wg sync.WaitGroup
+	for i, b := range brokers {
+		i, b := i, b
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			now := time.Now()
+			body, err := b.metrics(ctx)
+			if err != nil {
+				samples[i] = brokerSample{addr: b.addr, time: now, err: err}
+				return
+			}
+			samples[i], err = parseBrokerSample(b.addr, now, body)
+			if err != nil {
+				samples[i] = brokerSample{addr: b.addr, time: now, err: err}
+			}
+		}()
+	}
+	wg.Wait()
+	return samples
+}
+
+func labelValue(m *dto.Metric, name string) string {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
+
+func sumMetric(mf *dto.MetricFamily) float64 {
+	var sum float64
+	for _, m := range mf.GetMetric() {
+		switch {
+		case m.Gauge != nil:
+			sum += m.GetGauge().GetValue()
+		case m.Counter != nil:
+			sum += m.GetCounter().GetValue()
+		case m.Untyped != nil:
+			sum += m.GetUntyped().GetValue()
+		}
+	}
+	return sum
+}
+
+func parseBrokerSample(addr string, t time.Time, body []byte) (brokerSample, error) {
+	var p expfmt.TextParser
+	families, err := p.TextToMetricFamilies(bytes.NewReader(body))
+	if err != nil {
+		return brokerSample{}, fmt.Errorf("unable to parse metrics: %v", err)
+	}
+	s := brokerSample{
+		addr:           addr,
+		time:           t,
+		produceLatency: make(histogram),
+		fetchLatency:   make(histogram),
+		topicIn:        make(map[string]float64),
+		topicOut:       make(map[string]float64),
+	}
+	if mf, ok := families["redpanda_kafka_request_bytes_total"]; ok {
+		for _, m := range mf.GetMetric() {
+			v := m.GetCounter().GetValue()
+			topic := labelValue(m, "redpanda_topic")
+			if ns := labelValue(m, "redpanda_namespace"); ns != "" && ns != "kafka" {
+				topic = ns + "/" + topic
+			}
+			switch labelValue(m, "redpanda_request") {
+			case "produce":
+				s.produceBytes += v
+				s.topicIn[topic] += v
+			case "consume":
+				s.fetchBytes += v
+				s.topicOut[topic] += v
+			}
+		}
+	}
+	if mf, ok := families["redpanda_kafka_request_latency_seconds"]; ok {
+		for _, m := range mf.GetMetric() {
+			h := s.produceLatency
+			switch labelValue(m, "redpanda_request") {
+			case "produce":
+			case "consume":
+				h = s.fetchLatency
+			default:
+				continue
+			}
+			for _, b := range m.GetHistogram().GetBucket() {
+				h[b.GetUpperBound()] += float64(b.GetCumulativeCount())
+			}
+		}
+	}
+	if mf, ok := families["redpanda_kafka_under_replicated_replicas"]; ok {
+		s.underReplicated = sumMetric(mf)
+	}
+	if mf, ok := families["redpanda_storage_disk_free_bytes"]; ok {
+		s.diskFree = sumMetric(mf)
+	}
+	if mf, ok := families["redpanda_storage_disk_total_bytes"]; ok {
+		s.diskTotal = sumMetric(mf)
+	}
+	return s, nil
+}
+
+// counterDelta returns how much a counter increased, treating a decrease as a
+// counter reset (the broker restarted).
+func counterDelta(prev, cur float64) float64 {
+	if cur < prev {
+		return cur
+	}
+	return cur - prev
+}
+
+// quantile returns the q quantile of the observations made between prev and
+// cur, interpolating linearly within the bucket like Prometheus'
+// histogram_quantile. It returns NaN if there were no observations.
+func quantile(q float64, prev, cur histogram) float64 {
+	bounds := make([]float64, 0, len(cur))
+	for b := range cur {
+		bounds = append(bounds, b)
+	}
+	sort.Float64s(bounds)
+	if len(bounds) == 0 {
+		return math.NaN()
+	}
+	counts := make([]float64, len(bounds))
+	for i, b := range bounds {
+		counts[i] = counterDelta(prev[b], cur[b])
+	}
+	total := counts[len(counts)-1]
+	if total == 0 {
+		return math.NaN()
+	}
+	rank := q * total
+	for i, c := range counts {
+		if c < rank {
+			continue
+		}
+		if math.IsInf(bounds[i], 1) {
+			if i == 0 {
+				return math.NaN()
+			}
+			return bounds[i-1]
+		}
+		var lower, below float64
+		if i > 0 {
+			lower, below = bounds[i-1], counts[i-1]
+		}
+		if c == below {
+			return bounds[i]
+		}
+		return lower + (bounds[i]-lower)*(rank-below)/(c-below)
+	}
+	return bounds[len(bounds)-1]
+}
+
+func formatRate(bytesPerSec float64) string {
+	return units.BytesSize(bytesPerSec) + "/s"
+}
+
+func formatLatency(seconds float64) string {
+	if math.IsNaN(seconds) {
+		return "-"
+	}
+	return time.Duration(seconds * float64(time.Second)).Round(10 * time.Microsecond).String()
+}
+
+type topicRate struct {
+	name    string
+	in, out float64
+}
+
+// renderTop writes the activity between two polls of the same brokers.
+func renderTop(w io.Writer, prev, cur []brokerSample, maxTopics int) {
+	var (
+		totalIn, totalOut, totalURP float64
+		topics                      = make(map[string]*topicRate)
+		errs                        []string
+	)
+	fmt.Fprintf(w, "rpk debug top - %s\n\n", cur[0].time.Format("15:04:05"))
+
+	tw := out.NewTableTo(w, "BROKER", "PRODUCE", "FETCH", "PRODUCE-P99", "FETCH-P99", "UNDER-REPLICATED", "DISK-USED")
+	for i, c := range cur {
+		p := prev[i]
+		if c.err != nil {
+			tw.Print(c.addr, "-", "-", "-", "-", "-", "-")
+			errs = append(errs, fmt.Sprintf("%s: %v", c.addr, c.err))
+			continue
+		}
+		if p.err != nil {
+			// Rates need two good polls; show the gauges until then.
+			p = c
+		}
+		secs := c.time.Sub(p.time).Seconds()
+		rate := func(prev, cur float64) float64 {
+			if secs <= 0 {
+				return 0
+			}
+			return counterDelta(prev, cur) / secs
+		}
+		in, outRate := rate(p.produceBytes, c.produceBytes), rate(p.fetchBytes, c.fetchBytes)
+		totalIn += in
+		totalOut += outRate
+		totalURP += c.underReplicated
+
+		disk := "-"
+		if c.diskTotal > 0 {
+			used := c.diskTotal - c.diskFree
+			disk = fmt.Sprintf("%s/%s (%.0f%%)", units.BytesSize(used), units.BytesSize(c.diskTotal), 100*used/c.diskTotal)
+		}
+		tw.Print(
+			c.addr,
+			formatRate(in),
+			formatRate(outRate),
+			formatLatency(quantile(0.99, p.produceLatency, c.produceLatency)),
+			formatLatency(quantile(0.99, p.fetchLatency, c.fetchLatency)),
+			strconv.FormatFloat(c.underReplicated, 'f', -1, 64),
+			disk,
+		)
+
+		for name, v := range c.topicIn {
+			t := topics[name]
+			if t == nil {
+				t = &topicRate{name: name}
+				topics[name] = t
+			}
+			t.in += rate(p.topicIn[name], v)
+		}
+		for name, v := range c.topicOut {
+			t := topics[name]
+			if t == nil {
+				t = &topicRate{name: name}
+				topics[name] = t
+			}
+			t.out += rate(p.topicOut[name], v)
+		}
+	}
+	tw.Print("TOTAL", formatRate(totalIn), formatRate(totalOut), "-", "-", strconv.FormatFloat(totalURP, 'f', -1, 64), "-")
+	tw.Flush()
+	for _, err := range errs {
+		fmt.Fprintf(w, "unable to poll %s\n", err)
+	}
+
+	sorted := make([]*topicRate, 0, len(topics))
+	for _, t := range topics {
+		sorted = append(sorted, t)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		l, r := sorted[i], sorted[j]
+		if l.in+l.out != r.in+r.out {
+			return l.in+l.out > r.in+r.out
+		}
+		return l.name < r.name
+	})
+	if maxTopics >= 0 && len(sorted) > maxTopics {
+		sorted = sorted[:maxTopics]
+	}
+	fmt.Fprintln(w)
+	tw = out.NewTableTo(w, "TOPIC", "BYTES-IN", "BYTES-OUT")
+	for _, t := range sorted {
+		tw.Print(t.name, formatRate(t.in), formatRate(t.out))
+	}
+	tw.Flush()
+}
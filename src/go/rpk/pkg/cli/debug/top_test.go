@@ -0,0 +1,114 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package debug
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func topMetrics(produced, fetched, fastLatencies, slowLatencies int) []byte {
+	return []byte(fmt.Sprintf(`# HELP redpanda_kafka_request_bytes_total Bytes.
+# TYPE redpanda_kafka_request_bytes_total counter
+redpanda_kafka_request_bytes_total{redpanda_namespace="kafka",redpanda_request="produce",redpanda_topic="foo"} %[1]d
+redpanda_kafka_request_bytes_total{redpanda_namespace="kafka",redpanda_request="consume",redpanda_topic="foo"} %[2]d
+redpanda_kafka_request_bytes_total{redpanda_namespace="kafka",redpanda_request="produce",redpanda_topic="bar"} 100
+redpanda_kafka_request_bytes_total{redpanda_namespace="kafka",redpanda_request="follower_consume",redpanda_topic="bar"} 100
+# HELP redpanda_kafka_request_latency_seconds Latency.
+# TYPE redpanda_kafka_request_latency_seconds histogram
+redpanda_kafka_request_latency_seconds_bucket{redpanda_request="produce",le="0.001"} %[3]d
+redpanda_kafka_request_latency_seconds_bucket{redpanda_request="produce",le="0.01"} %[4]d
+redpanda_kafka_request_latency_seconds_bucket{redpanda_request="produce",le="+Inf"} %[4]d
+redpanda_kafka_request_latency_seconds_sum{redpanda_request="produce"} 1
+redpanda_kafka_request_latency_seconds_count{redpanda_request="produce"} %[4]d
+# HELP redpanda_kafka_under_replicated_replicas URP.
+# TYPE redpanda_kafka_under_replicated_replicas gauge
+redpanda_kafka_under_replicated_replicas{redpanda_namespace="kafka",redpanda_topic="foo"} 2
+# HELP redpanda_storage_disk_free_bytes Free.
+# TYPE redpanda_storage_disk_free_bytes gauge
+redpanda_storage_disk_free_bytes 250
+# HELP redpanda_storage_disk_total_bytes Total.
+# TYPE redpanda_storage_disk_total_bytes gauge
+redpanda_storage_disk_total_bytes 1000
+`, produced, fetched, fastLatencies, fastLatencies+slowLatencies))
+}
+
+func TestParseBrokerSample(t *testing.T) {
+	s, err := parseBrokerSample("rp-0:9644", time.Now(), topMetrics(1000, 500, 90, 10))
+	require.NoError(t, err)
+	require.Equal(t, 1100., s.produceBytes)
+	require.Equal(t, 500., s.fetchBytes)
+	require.Equal(t, map[string]float64{"foo": 1000, "bar": 100}, s.topicIn)
+	require.Equal(t, map[string]float64{"foo": 500}, s.topicOut)
+	require.Equal(t, histogram{0.001: 90, 0.01: 100, math.Inf(1): 100}, s.produceLatency)
+	require.Empty(t, s.fetchLatency)
+	require.Equal(t, 2., s.underReplicated)
+	require.Equal(t, 250., s.diskFree)
+	require.Equal(t, 1000., s.diskTotal)
+
+	_, err = parseBrokerSample("rp-0:9644", time.Now(), []byte("not metrics{"))
+	require.Error(t, err)
+}
+
+func TestQuantile(t *testing.T) {
+	inf := math.Inf(1)
+	for _, test := range []struct {
+		name      string
+		q         float64
+		prev, cur histogram
+		exp       float64
+	}{
+		{"empty", 0.99, nil, histogram{}, math.NaN()},
+		{"no observations", 0.99, histogram{1: 5, inf: 5}, histogram{1: 5, inf: 5}, math.NaN()},
+		{"first bucket", 0.5, nil, histogram{1: 10, 2: 10, inf: 10}, 0.5},
+		{"interpolated", 0.99, histogram{0.001: 10, 0.01: 10, inf: 10}, histogram{0.001: 100, 0.01: 110, inf: 110}, 0.001 + 0.009*(99-90)/10},
+		{"inf bucket", 0.99, nil, histogram{1: 50, inf: 100}, 1},
+		{"counter reset", 0.5, histogram{1: 1000, inf: 1000}, histogram{1: 4, inf: 4}, 0.5},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := quantile(test.q, test.prev, test.cur)
+			if math.IsNaN(test.exp) {
+				require.True(t, math.IsNaN(got), "got %v", got)
+				return
+			}
+			require.InDelta(t, test.exp, got, 1e-9)
+		})
+	}
+}
+
+func TestRenderTop(t *testing.T) {
+	t0 := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	t1 := t0.Add(2 * time.Second)
+	prev0, err := parseBrokerSample("rp-0:9644", t0, topMetrics(1000, 0, 0, 0))
+	require.NoError(t, err)
+	cur0, err := parseBrokerSample("rp-0:9644", t1, topMetrics(5096, 2048, 90, 10))
+	require.NoError(t, err)
+	prev := []brokerSample{prev0, {addr: "rp-1:9644", time: t0, err: errors.New("connection refused")}}
+	cur := []brokerSample{cur0, {addr: "rp-1:9644", time: t1, err: errors.New("connection refused")}}
+
+	var buf bytes.Buffer
+	renderTop(&buf, prev, cur, 1)
+	require.Equal(t, `rpk debug top - 12:00:02
+
+BROKER     PRODUCE  FETCH   PRODUCE-P99  FETCH-P99  UNDER-REPLICATED  DISK-USED
+rp-0:9644  2KiB/s   1KiB/s  9.1ms        -          2                 750B/1000B (75%)
+rp-1:9644  -        -       -            -          -                 -
+TOTAL      2KiB/s   1KiB/s  -            -          2                 -
+unable to poll rp-1:9644: connection refused
+`+"\n"+`TOPIC  BYTES-IN  BYTES-OUT
+foo    2KiB/s    1KiB/s
+`, buf.String())
+}
@@ -23,7 +23,7 @@ import (
 	"go.uber.org/zap"
 )
 
-func newInstallCommand(fs afero.Fs) *cobra.Command {
+func newInstallCommand(fs afero.Fs, src *manifestSource) *cobra.Command {
 	var (
 		dir     string
 		update  bool
@@ -70,7 +70,7 @@ If --dir is not present, rpk will create $HOME/.local/bin if it does not exist.
 			}
 			if body == nil {
 				fmt.Printf("Searching plugin manifest for %q...\n", name)
-				m, err := getManifest()
+				m, base, err := src.getManifest(fs)
 				out.MaybeDieErr(err)
 
 				p, err := m.FindEntry(name)
@@ -106,26 +106,15 @@ Remote sha256: %s
 					fmt.Println("Found! Downloading and validating plugin...")
 				}
 
-				body, err = p.DownloadForUser(urlBase)
+				body, err = p.DownloadForUser(base)
 				out.MaybeDieErr(err)
 				autoComplete = p.HelpAutoComplete
 			}
 
 			fmt.Println("Downloaded! Writing plugin to disk...")
-			dst, err := plugin.WriteBinary(fs, name, dir, body, autoComplete, false)
+			dst, err := writePlugin(fs, installed, name, dir, body, autoComplete)
 			out.MaybeDieErr(err)
 
-			// If we add shas to filenames, then writing our binary
-			// likely will not replace the old plugin. So, if the
-			// old plugin exists, the path is *not* equal to the
-			// new path, but this is technically the same "plugin
-			// path", we remove the old plugin manually.
-			if old, exists := installed.Find(name); exists && old.Path != dst && plugin.IsSamePluginPath(old.Path, dst) {
-				if err := os.Remove(old.Path); err != nil {
-					fmt.Printf("Unable to remove old plugin at %q: %v\n", old.Path, err)
-				}
-			}
-
 			fmt.Printf("Success! Plugin %q has been saved to %q and is now ready to use!\n", name, dst)
 
 			if autoComplete {
@@ -155,6 +144,26 @@ command %q!
 	return cmd
 }
 
+// writePlugin writes a downloaded plugin to dir and returns the path it was
+// written to.
+func writePlugin(fs afero.Fs, installed plugin.Plugins, name, dir string, body []byte, autoComplete bool) (string, error) {
+	dst, err := plugin.WriteBinary(fs, name, dir, body, autoComplete, false)
+	if err != nil {
+		return "", err
+	}
+
+	// If we add shas to filenames, then writing our binary likely will not
+	// replace the old plugin. So, if the old plugin exists, the path is
+	// *not* equal to the new path, but this is technically the same
+	// "plugin path", we remove the old plugin manually.
+	if old, exists := installed.Find(name); exists && old.Path != dst && plugin.IsSamePluginPath(old.Path, dst) {
+		if err := os.Remove(old.Path); err != nil {
+			fmt.Printf("Unable to remove old plugin at %q: %v\n", old.Path, err)
+		}
+	}
+	return dst, nil
+}
+
 // checkAndCreateDefaultPath will verify if the plugin.DefaultBinPath exists, if
 // not, it will create the directory.
 func checkAndCreateDefaultPath(fs afero.Fs) error {
@@ -18,7 +18,7 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func newListCommand(fs afero.Fs) *cobra.Command {
+func newListCommand(fs afero.Fs, src *manifestSource) *cobra.Command {
 	var local bool
 
 	cmd := &cobra.Command{
@@ -64,10 +64,10 @@ whether you have "shadowed" plugins (the same plugin specified multiple times).
 				return
 			}
 
-			m, err := getManifest()
+			m, _, err := src.getManifest(fs)
 			out.MaybeDieErr(err)
 
-			tw := out.NewTable("NAME", "DESCRIPTION", "MESSAGE")
+			tw := out.NewTable("NAME", "VERSION", "DESCRIPTION", "MESSAGE")
 			defer tw.Flush()
 			for _, entry := range m.Plugins {
 				name := entry.Name
@@ -87,7 +87,7 @@ whether you have "shadowed" plugins (the same plugin specified multiple times).
 					}
 				}
 
-				tw.Print(name, entry.Description, message)
+				tw.Print(name, entry.Version, entry.Description, message)
 			}
 		},
 	}
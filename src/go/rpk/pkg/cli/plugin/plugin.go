@@ -11,14 +11,42 @@
 package plugin
 
 import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/plugin"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
 
-const urlBase = "https://vectorized-public.s3.us-west-2.amazonaws.com/rpk-plugins"
+const (
+	urlBase     = "https://vectorized-public.s3.us-west-2.amazonaws.com/rpk-plugins"
+	manifestURL = urlBase + "/manifest.yaml"
+
+	envManifestURL = "RPK_PLUGIN_MANIFEST_URL"
+	envManifestKey = "RPK_PLUGIN_MANIFEST_KEY"
+)
+
+// officialManifestKeys are the public keys that the official plugin manifest
+// is signed with. While the official manifest is not signed, this is empty and
+// the official manifest is trusted through HTTPS, as custom manifests are
+// only used if they are signed.
+var officialManifestKeys []string
+
+// manifestSource is the manifest that plugins are listed and downloaded from,
+// and the keys its signature is verified against.
+type manifestSource struct {
+	url  string
+	keys []string
+
+	officialURL string
+}
 
 func NewCommand(fs afero.Fs) *cobra.Command {
+	src := &manifestSource{officialURL: manifestURL}
 	cmd := &cobra.Command{
 		Use:   "plugin",
 		Short: "List, download, update, and remove rpk plugins",
@@ -64,17 +92,97 @@ following:
 
 where "path" is an underscore delimited argument path to a command. For
 example, "foo_bar_baz" corresponds to the command "rpk foo bar baz".
+
+PRIVATE MANIFESTS
+
+By default, plugins are listed and downloaded from the official Redpanda plugin
+manifest. Teams can host their own plugins by serving a manifest of the same
+format and pointing rpk at it with --manifest-url (or $RPK_PLUGIN_MANIFEST_URL).
+Plugin paths in the manifest are relative to the directory of the manifest URL.
+
+Custom manifests must be signed: rpk downloads the detached signature at the
+manifest URL plus ".sig" and verifies it against the public keys passed with
+--manifest-key (or $RPK_PLUGIN_MANIFEST_KEY, comma separated). The official
+manifest is downloaded over HTTPS, and its signature is verified as well if
+keys are passed. The signature
+is the base64 encoded ed25519 signature of the manifest file, and keys are
+either files containing a PEM encoded public key, or base64 encoded raw ed25519
+public keys. For example, with OpenSSL:
+
+  openssl genpkey -algorithm ed25519 -out key.pem
+  openssl pkey -in key.pem -pubout -out pub.pem
+  openssl pkeyutl -sign -rawin -inkey key.pem -in manifest.yaml | base64 -w0 > manifest.yaml.sig
+
+Because the manifest contains the sha256 sum of every plugin binary, a verified
+manifest also verifies every plugin downloaded through it.
 `,
 		Args: cobra.ExactArgs(0),
 	}
+	cmd.PersistentFlags().StringVar(&src.url, "manifest-url", os.Getenv(envManifestURL), "URL of a custom plugin manifest to use rather than the official manifest")
+	cmd.PersistentFlags().StringSliceVar(&src.keys, "manifest-key", splitKeys(os.Getenv(envManifestKey)), "Public key file or base64 ed25519 key to verify the manifest signature with (repeatable)")
 	cmd.AddCommand(
-		newListCommand(fs),
-		newInstallCommand(fs),
+		newListCommand(fs, src),
+		newInstallCommand(fs, src),
 		newUninstallCommand(fs),
+		newUpgradeCommand(fs, src),
 	)
 	return cmd
 }
 
-func getManifest() (*plugin.Manifest, error) {
-	return plugin.DownloadManifest(urlBase + "/manifest.yaml")
+func splitKeys(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// getManifest downloads and verifies the manifest, returning it and the base
+// URL that the plugins in it are downloaded from.
+func (src *manifestSource) getManifest(fs afero.Fs) (*plugin.Manifest, string, error) {
+	url, keys := src.officialURL, officialManifestKeys
+	if src.url != "" && src.url != src.officialURL {
+		if len(src.keys) == 0 {
+			return nil, "", errors.New("custom plugin manifests must be signed, but no public key was specified with --manifest-key or $" + envManifestKey)
+		}
+		url, keys = src.url, nil
+	}
+	keys = append(keys[:len(keys):len(keys)], src.keys...)
+
+	pubs, err := parseManifestKeys(fs, keys)
+	if err != nil {
+		return nil, "", err
+	}
+	// Without keys, this is the official manifest: the plugins in it are
+	// still verified against their sha256 sums.
+	m, err := plugin.DownloadSignedManifest(url, pubs)
+	if err != nil {
+		return nil, "", err
+	}
+	return m, url[:strings.LastIndexByte(url, '/')], nil
+}
+
+// parseManifestKeys parses each key as a path to a PEM file if such a file
+// exists, and otherwise as a literal key.
+func parseManifestKeys(fs afero.Fs, keys []string) ([]ed25519.PublicKey, error) {
+	var pubs []ed25519.PublicKey
+	for _, k := range keys {
+		k = strings.TrimSpace(k)
+		if k == "" {
+			continue
+		}
+		raw := k
+		if exists, _ := afero.Exists(fs, k); exists {
+			b, err := afero.ReadFile(fs, k)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read manifest key %q: %v", k, err)
+			}
+			raw = string(b)
+		}
+		pub, err := plugin.ParseManifestKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid manifest key %q: %v", k, err)
+		}
+		pubs = append(pubs, pub)
+	}
+	return pubs, nil
 }
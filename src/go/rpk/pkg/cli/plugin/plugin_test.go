@@ -0,0 +1,80 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package plugin
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetManifest(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	const manifest = `
+api_version: 2021-07-27
+plugins:
+  - name: internal
+    version: v1.2.3
+`
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(manifest)))
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/official/manifest.yaml", "/custom/manifest.yaml":
+			io.WriteString(w, manifest)
+		case "/custom/manifest.yaml.sig":
+			io.WriteString(w, sig)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer svr.Close()
+
+	official := svr.URL + "/official/manifest.yaml"
+	custom := svr.URL + "/custom/manifest.yaml"
+	for _, test := range []struct {
+		name    string
+		url     string
+		keys    []string
+		expErr  bool
+		expBase string
+	}{
+		{name: "default official manifest without keys", expBase: svr.URL + "/official"},
+		{name: "official manifest passed explicitly", url: official, expBase: svr.URL + "/official"},
+		{name: "official manifest with a key it is not signed with", keys: []string{base64.StdEncoding.EncodeToString(otherPub)}, expErr: true},
+		{name: "custom manifest without key", url: custom, expErr: true},
+		{name: "custom manifest with key", url: custom, keys: []string{base64.StdEncoding.EncodeToString(pub)}, expBase: svr.URL + "/custom"},
+		{name: "custom manifest with untrusted key", url: custom, keys: []string{base64.StdEncoding.EncodeToString(otherPub)}, expErr: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			src := &manifestSource{url: test.url, keys: test.keys, officialURL: official}
+			m, base, err := src.getManifest(afero.NewMemMapFs())
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expBase, base)
+			require.Len(t, m.Plugins, 1)
+			require.Equal(t, "v1.2.3", m.Plugins[0].Version)
+		})
+	}
+}
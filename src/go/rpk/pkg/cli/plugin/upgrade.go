@@ -0,0 +1,120 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package plugin
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/plugin"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newUpgradeCommand(fs afero.Fs, src *manifestSource) *cobra.Command {
+	var all bool
+	cmd := &cobra.Command{
+		Use:   "upgrade [NAME]",
+		Short: "Upgrade installed rpk plugins to the versions in the manifest",
+		Long: `Upgrade installed rpk plugins to the versions in the manifest.
+
+This command compares the sha256sum of locally installed plugins to the
+sha256sum in the plugin manifest, and downloads and replaces any plugin that
+differs. Upgraded plugins are written to the same directory they are installed
+in. Either specify the plugin to upgrade, or use --all to upgrade every
+installed plugin that is in the manifest.
+
+Plugins that rpk manages itself (e.g., the cloud plugin) are upgraded by the
+commands that manage them and are skipped here.
+`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			if all == (len(args) == 1) {
+				out.Die("specify either one plugin to upgrade or --all")
+			}
+
+			installed := plugin.ListPlugins(fs, plugin.UserPaths())
+			var upgrade plugin.Plugins
+			if all {
+				for _, p := range installed {
+					if !p.Managed {
+						upgrade = append(upgrade, p)
+					}
+				}
+			} else {
+				p, ok := installed.Find(args[0])
+				if !ok {
+					out.Die("plugin %q is not installed; use 'rpk plugin install' to install it", args[0])
+				}
+				if p.Managed {
+					out.Die("plugin %q is managed by rpk and cannot be upgraded with this command", args[0])
+				}
+				upgrade = append(upgrade, *p)
+			}
+			if len(upgrade) == 0 {
+				out.Exit("No plugins are installed.")
+			}
+
+			m, base, err := src.getManifest(fs)
+			out.MaybeDieErr(err)
+
+			tw := out.NewTable("NAME", "VERSION", "STATUS")
+			defer tw.Flush()
+			var failed bool
+			for _, p := range upgrade {
+				name := p.FullName()
+				version, status, err := upgradePlugin(fs, installed, m, base, &p)
+				if err != nil {
+					failed = true
+					status = err.Error()
+				}
+				tw.Print(name, version, status)
+			}
+			if failed {
+				tw.Flush()
+				out.Die("unable to upgrade all plugins")
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", false, "Upgrade all installed plugins")
+	return cmd
+}
+
+// upgradePlugin replaces an installed plugin with the version in the manifest
+// if the binaries differ, returning the manifest version and a status.
+func upgradePlugin(fs afero.Fs, installed plugin.Plugins, m *plugin.Manifest, base string, p *plugin.Plugin) (version, status string, err error) {
+	name := p.FullName()
+	entry, err := m.FindEntry(name)
+	if err != nil {
+		return "", "not in manifest, skipped", nil
+	}
+	_, remoteSha, err := entry.PathShaForUser()
+	if err != nil {
+		return entry.Version, "", err
+	}
+	localSha, err := plugin.Sha256Path(fs, p.Path)
+	if err != nil {
+		return entry.Version, "", fmt.Errorf("unable to calculate local sha256: %v", err)
+	}
+	if localSha == remoteSha {
+		return entry.Version, "up to date", nil
+	}
+
+	body, err := entry.DownloadForUser(base)
+	if err != nil {
+		return entry.Version, "", err
+	}
+	dst, err := writePlugin(fs, installed, name, filepath.Dir(p.Path), body, entry.HelpAutoComplete)
+	if err != nil {
+		return entry.Version, "", err
+	}
+	return entry.Version, fmt.Sprintf("upgraded, saved to %q", dst), nil
+}
@@ -76,6 +76,31 @@ It is recommended to always use the --description flag; the description is
 printed in the output of 'rpk profile list'.
 
 rpk always switches to the newly created profile.
+
+CREDENTIAL HELPERS
+
+By default, secrets (kafka_api.sasl.password and
+kafka_api.sasl.oauth.client_secret) are saved in plaintext in rpk.yaml. If you
+set credential_helper, secrets are saved with the credential helper instead,
+and rpk fetches them whenever it connects to the cluster:
+
+    rpk profile create prod --set credential_helper=encrypted --set pass=...
+
+The special helper "encrypted" saves secrets in a file next to rpk.yaml that
+is encrypted with a passphrase. rpk prompts for the passphrase, or reads it from
+the RPK_CREDENTIALS_PASSPHRASE environment variable.
+
+Any other value is a command to run, with "get", "store", or "erase" appended
+to its arguments, in the style of git credential helpers. rpk writes the
+request to the command's stdin as key=value lines, ending with an empty line:
+
+    profile=prod
+    key=kafka_api.sasl.password
+    secret=...                    (store only)
+
+For get, the command prints "secret=..." to stdout, or nothing if it does not
+have the secret. Cloud auths support credential_helper for their client_secret
+as well.
 `,
 		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -215,6 +240,9 @@ func createProfile(
 	if description != "" {
 		p.Description = description // p.Description could be set by cloud cluster loading; only override if the user specified
 	}
+	if err := p.StoreCredentials(fs); err != nil {
+		return "", false, false, err
+	}
 	y.CurrentProfile = y.PushProfile(p)
 	if err := y.Write(fs); err != nil {
 		return "", false, false, fmt.Errorf("unable to write rpk file: %v", err)
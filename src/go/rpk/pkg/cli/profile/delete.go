@@ -65,6 +65,9 @@ func deleteProfile(
 	if idx == -1 {
 		return false, fmt.Errorf("profile %q does not exist", name)
 	}
+	if err := y.Profiles[idx].EraseCredentials(fs); err != nil {
+		return false, err
+	}
 	y.Profiles = append(y.Profiles[:idx], y.Profiles[idx+1:]...)
 	if y.CurrentProfile == name {
 		y.CurrentProfile = ""
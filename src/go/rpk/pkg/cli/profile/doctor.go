@@ -0,0 +1,367 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package profile
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/adminapi"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	rpknet "github.com/redpanda-data/redpanda/src/go/rpk/pkg/net"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Certificates that expire within this window are reported as a warning.
+const certExpiryWarning = 30 * 24 * time.Hour
+
+type checkStatus string
+
+const (
+	checkOK   checkStatus = "OK"
+	checkWarn checkStatus = "WARN"
+	checkFail checkStatus = "FAIL"
+	checkSkip checkStatus = "SKIP"
+)
+
+// checkResult is the result of a single diagnostic step against a target.
+type checkResult struct {
+	Check   string
+	Target  string
+	Status  checkStatus
+	Message string
+}
+
+type doctor struct {
+	fs      afero.Fs
+	p       *config.RpkProfile
+	timeout time.Duration
+	results []checkResult
+}
+
+func (d *doctor) add(check, target string, status checkStatus, format string, args ...interface{}) {
+	d.results = append(d.results, checkResult{check, target, status, fmt.Sprintf(format, args...)})
+}
+
+func newDoctorCommand(fs afero.Fs, p *config.Params) *cobra.Command {
+	var timeout time.Duration
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose connectivity and authentication problems of the current profile",
+		Long: `Diagnose connectivity and authentication problems of the current profile.
+
+This command runs a series of checks against every Kafka API seed broker and
+Admin API address of the current profile (including any -X or flag overrides),
+and reports exactly which step fails:
+
+  * DNS resolution of each address.
+  * TCP reachability of each address.
+  * If TLS is enabled, the TLS handshake, and the certificate chain: whether it
+    is signed by a trusted CA, whether its SANs match the address, and whether
+    it is expired or about to expire.
+  * SASL authentication and a metadata request against the Kafka API.
+  * Whether the advertised Kafka listeners returned in the metadata are
+    reachable from this machine. Clients use the advertised addresses for all
+    requests after the first, so a seed that works with an advertised listener
+    that does not is a common misconfiguration.
+  * An authenticated request against the Admin API.
+
+This command exits with status 1 if any check fails.
+`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			prof, err := p.LoadVirtualProfile(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			d := &doctor{fs: fs, p: prof, timeout: timeout}
+			d.run(cmd.Context())
+
+			tw := out.NewTable("check", "target", "status", "message")
+			var failed bool
+			for _, r := range d.results {
+				tw.Print(r.Check, r.Target, r.Status, r.Message)
+				failed = failed || r.Status == checkFail
+			}
+			tw.Flush()
+			if failed {
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Second, "Timeout of each individual check")
+	return cmd
+}
+
+func (d *doctor) run(ctx context.Context) {
+	k, a := &d.p.KafkaAPI, &d.p.AdminAPI
+	kafkaOK := d.checkAddrs(ctx, "kafka", k.Brokers, config.DefaultKafkaPort, k.TLS)
+	adminOK := d.checkAddrs(ctx, "admin", a.Addresses, config.DefaultAdminPort, a.TLS)
+
+	if kafkaOK {
+		d.checkKafka(ctx)
+	} else {
+		d.add("kafka metadata", strings.Join(k.Brokers, ","), checkSkip, "no seed broker is reachable")
+	}
+	if adminOK {
+		d.checkAdmin(ctx)
+	} else {
+		d.add("admin request", strings.Join(a.Addresses, ","), checkSkip, "no admin address is reachable")
+	}
+}
+
+// checkAddrs checks DNS, TCP, and TLS for each address, returning whether any
+// address passed every check. Addresses without a port use the default port of
+// the API.
+func (d *doctor) checkAddrs(ctx context.Context, api string, addrs []string, defaultPort int, t *config.TLS) bool {
+	if len(addrs) == 0 {
+		d.add(api+" addresses", "", checkFail, "the profile has no %s addresses", api)
+		return false
+	}
+	tc, err := t.Config(d.fs)
+	if err != nil {
+		d.add(api+" tls config", "", checkFail, "unable to load TLS configuration: %v", err)
+		return false
+	}
+	var anyOK bool
+	for _, addr := range addrs {
+		if d.checkAddr(ctx, api, addr, defaultPort, tc) {
+			anyOK = true
+		}
+	}
+	return anyOK
+}
+
+func (d *doctor) checkAddr(ctx context.Context, api, addr string, defaultPort int, tc *tls.Config) bool {
+	host, hostPort, err := dialAddr(addr, defaultPort)
+	if err != nil {
+		d.add(api+" address", addr, checkFail, "invalid address: %v", err)
+		return false
+	}
+
+	if net.ParseIP(host) == nil {
+		rctx, cancel := context.WithTimeout(ctx, d.timeout)
+		ips, err := net.DefaultResolver.LookupHost(rctx, host)
+		cancel()
+		if err != nil {
+			d.add(api+" dns", addr, checkFail, "unable to resolve %s: %v", host, err)
+			return false
+		}
+		d.add(api+" dns", addr, checkOK, "resolved to %s", strings.Join(ips, ", "))
+	}
+
+	dialer := &net.Dialer{Timeout: d.timeout}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", hostPort)
+	if err != nil {
+		d.add(api+" tcp", addr, checkFail, "unable to connect: %v", err)
+		return false
+	}
+	defer conn.Close()
+	d.add(api+" tcp", addr, checkOK, "connected in %s", time.Since(start).Round(time.Millisecond))
+
+	if tc == nil {
+		return true
+	}
+
+	// We handshake without verification so that we can inspect the
+	// certificates and explain exactly what is wrong with them.
+	insecure := tc.Clone()
+	insecure.InsecureSkipVerify = true //nolint:gosec // we verify the chain ourselves below
+	if insecure.ServerName == "" {
+		insecure.ServerName = host
+	}
+	tlsConn := tls.Client(conn, insecure)
+	tlsConn.SetDeadline(time.Now().Add(d.timeout))
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		d.add(api+" tls", addr, checkFail, "TLS handshake failed (is TLS enabled on this listener?): %v", err)
+		return false
+	}
+	problems, warnings := checkCerts(tlsConn.ConnectionState().PeerCertificates, host, tc.RootCAs, time.Now())
+	switch {
+	case len(problems) > 0:
+		d.add(api+" tls", addr, checkFail, "%s", strings.Join(problems, "; "))
+		return false
+	case len(warnings) > 0:
+		d.add(api+" tls", addr, checkWarn, "%s", strings.Join(warnings, "; "))
+	default:
+		d.add(api+" tls", addr, checkOK, "certificate valid until %s", tlsConn.ConnectionState().PeerCertificates[0].NotAfter.Format(time.RFC3339))
+	}
+	return true
+}
+
+// dialAddr returns the host of the address, and the host and port to dial. An
+// address without a port uses the default port, or the port of its http or
+// https scheme.
+func dialAddr(addr string, defaultPort int) (host, hostPort string, err error) {
+	scheme, host, port, err := rpknet.SplitSchemeHostPort(addr)
+	if err != nil {
+		return "", "", err
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if port == "" {
+		switch scheme {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		default:
+			port = strconv.Itoa(defaultPort)
+		}
+	}
+	return host, net.JoinHostPort(host, port), nil
+}
+
+// checkCerts verifies a server certificate chain for host, returning problems
+// that fail verification and warnings about certificates close to expiry. If
+// roots is nil, the system roots are used.
+func checkCerts(certs []*x509.Certificate, host string, roots *x509.CertPool, now time.Time) (problems, warnings []string) {
+	if len(certs) == 0 {
+		return []string{"the server sent no certificate"}, nil
+	}
+	leaf := certs[0]
+	if err := leaf.VerifyHostname(host); err != nil {
+		sans := append([]string(nil), leaf.DNSNames...)
+		for _, ip := range leaf.IPAddresses {
+			sans = append(sans, ip.String())
+		}
+		problems = append(problems, fmt.Sprintf("the certificate is not valid for %q, its SANs are [%s]", host, strings.Join(sans, ", ")))
+	}
+	for _, c := range certs {
+		switch {
+		case now.After(c.NotAfter):
+			problems = append(problems, fmt.Sprintf("certificate %q expired on %s", c.Subject.CommonName, c.NotAfter.Format(time.RFC3339)))
+		case now.Before(c.NotBefore):
+			problems = append(problems, fmt.Sprintf("certificate %q is not valid until %s", c.Subject.CommonName, c.NotBefore.Format(time.RFC3339)))
+		case c.NotAfter.Sub(now) < certExpiryWarning:
+			warnings = append(warnings, fmt.Sprintf("certificate %q expires on %s", c.Subject.CommonName, c.NotAfter.Format(time.RFC3339)))
+		}
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	})
+	var unknown x509.UnknownAuthorityError
+	if errors.As(err, &unknown) {
+		problems = append(problems, fmt.Sprintf("the certificate is signed by an unknown authority %q, you may need to set the CA with -X tls.ca or -X admin.tls.ca", leaf.Issuer.CommonName))
+	} else if err != nil && len(problems) == 0 {
+		problems = append(problems, fmt.Sprintf("unable to verify the certificate chain: %v", err))
+	}
+	return problems, warnings
+}
+
+func (d *doctor) checkKafka(ctx context.Context) {
+	seeds := strings.Join(d.p.KafkaAPI.Brokers, ",")
+	adm, err := kafka.NewAdmin(d.fs, d.p, kgo.RetryTimeout(d.timeout), kgo.DialTimeout(d.timeout))
+	if err != nil {
+		d.add("kafka metadata", seeds, checkFail, "unable to initialize kafka client: %v", err)
+		return
+	}
+	defer adm.Close()
+
+	mctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+	m, err := adm.BrokerMetadata(mctx)
+	if err != nil {
+		msg := "unable to request metadata: %v"
+		if d.p.KafkaAPI.SASL != nil {
+			msg = "unable to authenticate or request metadata: %v"
+		}
+		d.add("kafka metadata", seeds, checkFail, msg, err)
+		return
+	}
+	var how string
+	if s := d.p.KafkaAPI.SASL; s != nil {
+		how = fmt.Sprintf(" (authenticated as %q)", s.User)
+		if strings.EqualFold(s.Mechanism, adminapi.OAuthBearer) {
+			how = " (authenticated with OAUTHBEARER)"
+		}
+	}
+	d.add("kafka metadata", seeds, checkOK, "%d brokers%s", len(m.Brokers), how)
+
+	for _, f := range compareAdvertised(d.p.KafkaAPI.Brokers, m.Brokers) {
+		d.add("kafka advertised", f.Target, f.Status, "%s", f.Message)
+	}
+	for _, b := range m.Brokers {
+		addr := net.JoinHostPort(b.Host, fmt.Sprint(b.Port))
+		conn, err := (&net.Dialer{Timeout: d.timeout}).DialContext(ctx, "tcp", addr)
+		if err != nil {
+			d.add("kafka advertised", addr, checkFail, "broker %d advertises %s, which is not reachable from here: %v; check the broker's advertised_kafka_api", b.NodeID, addr, err)
+			continue
+		}
+		conn.Close()
+		d.add("kafka advertised", addr, checkOK, "broker %d is reachable at its advertised address", b.NodeID)
+	}
+}
+
+// compareAdvertised reports seeds that are not advertised by any broker. This
+// is fine for load balancers and DNS aliases, but commonly indicates that
+// brokers advertise internal addresses.
+func compareAdvertised(seeds []string, brokers kadm.BrokerDetails) []checkResult {
+	advertised := make(map[string]bool, len(brokers))
+	var list []string
+	for _, b := range brokers {
+		addr := net.JoinHostPort(b.Host, fmt.Sprint(b.Port))
+		advertised[addr] = true
+		list = append(list, addr)
+	}
+	var results []checkResult
+	for _, seed := range seeds {
+		if _, hostPort, err := dialAddr(seed, config.DefaultKafkaPort); err == nil && advertised[hostPort] {
+			continue
+		}
+		results = append(results, checkResult{
+			Target:  seed,
+			Status:  checkWarn,
+			Message: fmt.Sprintf("seed is not an advertised address (brokers advertise %s); this is expected behind a load balancer, otherwise brokers may be advertising internal addresses", strings.Join(list, ", ")),
+		})
+	}
+	return results
+}
+
+func (d *doctor) checkAdmin(ctx context.Context) {
+	addrs := strings.Join(d.p.AdminAPI.Addresses, ",")
+	cl, err := adminapi.NewClient(d.fs, d.p)
+	if err != nil {
+		d.add("admin request", addrs, checkFail, "unable to initialize admin client: %v", err)
+		return
+	}
+	actx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+	brokers, err := cl.Brokers(actx)
+	if err != nil {
+		var he *adminapi.HTTPResponseError
+		if errors.As(err, &he) && (he.Response.StatusCode == http.StatusUnauthorized || he.Response.StatusCode == http.StatusForbidden) {
+			d.add("admin request", addrs, checkFail, "authentication failed; the Admin API uses the Kafka API SASL credentials: %v", err)
+			return
+		}
+		d.add("admin request", addrs, checkFail, "unable to list brokers: %v", err)
+		return
+	}
+	d.add("admin request", addrs, checkOK, "%d brokers", len(brokers))
+}
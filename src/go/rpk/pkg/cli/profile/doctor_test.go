@@ -0,0 +1,132 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package profile
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+func mkCert(t *testing.T, cn string, sans []string, notAfter time.Time, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		DNSNames:              sans,
+		NotBefore:             time.Unix(0, 0),
+		NotAfter:              notAfter,
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func TestCheckCerts(t *testing.T) {
+	now := time.Now()
+	year := now.Add(365 * 24 * time.Hour)
+	ca, caKey := mkCert(t, "ca", nil, year, nil, nil)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	otherCA, otherKey := mkCert(t, "other-ca", nil, year, nil, nil)
+
+	valid, _ := mkCert(t, "broker", []string{"rp.example.com"}, year, ca, caKey)
+	soon, _ := mkCert(t, "broker", []string{"rp.example.com"}, now.Add(7*24*time.Hour), ca, caKey)
+	expired, _ := mkCert(t, "broker", []string{"rp.example.com"}, now.Add(-time.Hour), ca, caKey)
+	untrusted, _ := mkCert(t, "broker", []string{"rp.example.com"}, year, otherCA, otherKey)
+
+	for _, test := range []struct {
+		name        string
+		cert        *x509.Certificate
+		host        string
+		expProblem  string
+		expWarning  string
+		expNoIssues bool
+	}{
+		{name: "valid", cert: valid, host: "rp.example.com", expNoIssues: true},
+		{name: "san mismatch", cert: valid, host: "10.0.0.1", expProblem: "its SANs are [rp.example.com]"},
+		{name: "expiring soon", cert: soon, host: "rp.example.com", expWarning: "expires on"},
+		{name: "expired", cert: expired, host: "rp.example.com", expProblem: "expired on"},
+		{name: "unknown authority", cert: untrusted, host: "rp.example.com", expProblem: "unknown authority \"other-ca\""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			problems, warnings := checkCerts([]*x509.Certificate{test.cert}, test.host, roots, now)
+			if test.expNoIssues {
+				require.Empty(t, problems)
+				require.Empty(t, warnings)
+			}
+			if test.expProblem != "" {
+				require.Contains(t, strings.Join(problems, "; "), test.expProblem)
+			}
+			if test.expWarning != "" {
+				require.Empty(t, problems)
+				require.Contains(t, strings.Join(warnings, "; "), test.expWarning)
+			}
+		})
+	}
+}
+
+func TestCompareAdvertised(t *testing.T) {
+	brokers := kadm.BrokerDetails{
+		{NodeID: 0, Host: "rp-0.internal", Port: 9092},
+		{NodeID: 1, Host: "rp-1.internal", Port: 9092},
+	}
+	require.Empty(t, compareAdvertised([]string{"rp-0.internal:9092"}, brokers))
+	require.Empty(t, compareAdvertised([]string{"rp-1.internal"}, brokers))
+
+	results := compareAdvertised([]string{"rp.example.com:9092", "rp-1.internal:9092"}, brokers)
+	require.Len(t, results, 1)
+	require.Equal(t, "rp.example.com:9092", results[0].Target)
+	require.Equal(t, checkWarn, results[0].Status)
+	require.Contains(t, results[0].Message, "rp-0.internal:9092, rp-1.internal:9092")
+}
+
+func TestDialAddr(t *testing.T) {
+	for _, test := range []struct {
+		addr     string
+		host     string
+		hostPort string
+	}{
+		{"127.0.0.1:19092", "127.0.0.1", "127.0.0.1:19092"},
+		{"rp.example.com", "rp.example.com", "rp.example.com:9092"},
+		{"[::1]", "::1", "[::1]:9092"},
+		{"http://rp.example.com", "rp.example.com", "rp.example.com:80"},
+		{"https://rp.example.com", "rp.example.com", "rp.example.com:443"},
+		{"https://rp.example.com:9644", "rp.example.com", "rp.example.com:9644"},
+	} {
+		t.Run(test.addr, func(t *testing.T) {
+			host, hostPort, err := dialAddr(test.addr, 9092)
+			require.NoError(t, err)
+			require.Equal(t, test.host, host)
+			require.Equal(t, test.hostPort, hostPort)
+		})
+	}
+	_, _, err := dialAddr("not a host", 9092)
+	require.Error(t, err)
+}
@@ -63,8 +63,20 @@ exist, this command creates it and switches to it.
 					y.CurrentProfile = update.Name
 				}
 			}
+			helper := p.CredentialHelper
 			*p = update
 
+			// Credential helpers key secrets by name, so a rename is
+			// applied in the old helper before the secrets move to
+			// the new one.
+			to := p.CredentialHelper
+			p.CredentialHelper = helper
+			err = p.RenameCredentials(fs, name)
+			p.CredentialHelper = to
+			out.MaybeDie(err, "unable to rename the credentials of profile %q: %v", name, err)
+			err = p.SwitchCredentialHelper(fs, helper)
+			out.MaybeDie(err, "unable to move the credentials of profile %q to the new credential helper: %v", p.Name, err)
+
 			err = y.Write(fs)
 			out.MaybeDie(err, "unable to write rpk.yaml: %v", err)
 
@@ -0,0 +1,164 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package profile
+
+import (
+	"fmt"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	rpkos "github.com/redpanda-data/redpanda/src/go/rpk/pkg/os"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// sharedProfile is the self-contained format of 'rpk profile export' and
+// 'rpk profile import'.
+type sharedProfile struct {
+	Profile config.RpkProfile `yaml:"profile"`
+	CACerts sharedCACerts     `yaml:"ca_certs,omitempty"`
+}
+
+// sharedCACerts contains PEM encoded CA certificates that are written to the
+// config directory on import.
+type sharedCACerts struct {
+	KafkaAPI string `yaml:"kafka_api,omitempty"`
+	AdminAPI string `yaml:"admin_api,omitempty"`
+}
+
+func newExportCommand(fs afero.Fs, p *config.Params) *cobra.Command {
+	var (
+		output         string
+		embedCerts     bool
+		includeSecrets bool
+	)
+	cmd := &cobra.Command{
+		Use:   "export [NAME]",
+		Short: "Export an rpk profile to share it",
+		Long: `Export an rpk profile to share it.
+
+This command prints a profile in a self-contained YAML format that can be
+imported on another machine with 'rpk profile import'. If no name is
+specified, this command exports the current profile.
+
+Secrets (kafka_api.sasl.password and kafka_api.sasl.oauth.client_secret) are
+omitted unless you use --include-secrets, so that the exported profile can be
+shared safely; the importer sets their own credentials with 'rpk profile set'.
+With --include-secrets, secrets kept by the profile's credential helper are
+fetched from the helper and exported in plain text.
+
+The credential helper itself is never exported: it is specific to your machine,
+and the importer can set their own.
+
+With --embed-certs, the CA certificates of the Kafka API and the Admin API are
+embedded as PEM into the export, and are written to the rpk config directory on
+import. Client certificates and keys are never embedded: they identify you, and
+their paths are exported as is.
+`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: ValidProfiles(fs, p),
+		Run: func(_ *cobra.Command, args []string) {
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			y, ok := cfg.ActualRpkYaml()
+			if !ok {
+				out.Die("rpk.yaml file does not exist")
+			}
+			if len(args) == 0 {
+				args = append(args, y.CurrentProfile)
+			}
+			p := y.Profile(args[0])
+			if p == nil {
+				out.Die("profile %s does not exist", args[0])
+			}
+
+			s, err := exportProfile(fs, p, embedCerts, includeSecrets)
+			out.MaybeDieErr(err)
+			m, err := yaml.Marshal(s)
+			out.MaybeDie(err, "unable to encode profile: %v", err)
+
+			if output == "" {
+				fmt.Print(string(m))
+				return
+			}
+			err = rpkos.ReplaceFile(fs, output, m, 0o644)
+			out.MaybeDie(err, "unable to write %q: %v", output, err)
+			fmt.Printf("Exported profile %q to %q.\n", p.Name, output)
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write the profile to, rather than stdout")
+	cmd.Flags().BoolVar(&embedCerts, "embed-certs", false, "Embed the Kafka API and Admin API CA certificates as PEM")
+	cmd.Flags().BoolVar(&includeSecrets, "include-secrets", false, "Include secrets in the export")
+	return cmd
+}
+
+// exportProfile returns the shareable form of a profile, embedding CA
+// certificates and stripping secrets as requested. Secrets are loaded from the
+// credential helper if they are included, and the helper is never exported.
+func exportProfile(fs afero.Fs, p *config.RpkProfile, embedCerts, includeSecrets bool) (*sharedProfile, error) {
+	s := &sharedProfile{Profile: *p}
+	exp := &s.Profile
+
+	// Deep copy the pointers that we may modify.
+	if k := exp.KafkaAPI.TLS; k != nil {
+		dup := *k
+		exp.KafkaAPI.TLS = &dup
+	}
+	if a := exp.AdminAPI.TLS; a != nil {
+		dup := *a
+		exp.AdminAPI.TLS = &dup
+	}
+	if sasl := exp.KafkaAPI.SASL; sasl != nil {
+		dup := *sasl
+		if dup.OAuth != nil {
+			oauth := *dup.OAuth
+			dup.OAuth = &oauth
+		}
+		exp.KafkaAPI.SASL = &dup
+	}
+
+	if includeSecrets {
+		if err := exp.LoadCredentials(fs); err != nil {
+			return nil, err
+		}
+	}
+	exp.CredentialHelper = ""
+
+	if !includeSecrets && exp.KafkaAPI.SASL != nil {
+		exp.KafkaAPI.SASL.Password = ""
+		if exp.KafkaAPI.SASL.OAuth != nil {
+			exp.KafkaAPI.SASL.OAuth.ClientSecret = ""
+		}
+	}
+
+	if embedCerts {
+		for _, c := range []struct {
+			tls  *config.TLS
+			dst  *string
+			name string
+		}{
+			{exp.KafkaAPI.TLS, &s.CACerts.KafkaAPI, "kafka_api"},
+			{exp.AdminAPI.TLS, &s.CACerts.AdminAPI, "admin_api"},
+		} {
+			if c.tls == nil || c.tls.TruststoreFile == "" {
+				continue
+			}
+			pem, err := afero.ReadFile(fs, c.tls.TruststoreFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read the %s CA certificate: %v", c.name, err)
+			}
+			*c.dst = string(pem)
+			c.tls.TruststoreFile = ""
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package profile
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExportImportProfile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	const caPEM = "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+	require.NoError(t, afero.WriteFile(fs, "/etc/ca.pem", []byte(caPEM), 0o644))
+
+	p := &config.RpkProfile{
+		Name: "prod",
+		KafkaAPI: config.RpkKafkaAPI{
+			Brokers: []string{"rp.example.com:9092"},
+			TLS:     &config.TLS{TruststoreFile: "/etc/ca.pem", CertFile: "/home/me/cert.pem", KeyFile: "/home/me/key.pem"},
+			SASL:    &config.SASL{User: "me", Password: "secret", Mechanism: "SCRAM-SHA-256"},
+		},
+		AdminAPI: config.RpkAdminAPI{
+			Addresses: []string{"rp.example.com:9644"},
+			TLS:       &config.TLS{TruststoreFile: "/etc/ca.pem"},
+		},
+	}
+
+	s, err := exportProfile(fs, p, true, false)
+	require.NoError(t, err)
+	require.Empty(t, s.Profile.KafkaAPI.SASL.Password, "secrets are omitted")
+	require.Equal(t, "me", s.Profile.KafkaAPI.SASL.User)
+	require.Equal(t, caPEM, s.CACerts.KafkaAPI)
+	require.Equal(t, caPEM, s.CACerts.AdminAPI)
+	require.Empty(t, s.Profile.KafkaAPI.TLS.TruststoreFile)
+	require.Equal(t, "/home/me/cert.pem", s.Profile.KafkaAPI.TLS.CertFile)
+
+	// The original profile is unchanged.
+	require.Equal(t, "secret", p.KafkaAPI.SASL.Password)
+	require.Equal(t, "/etc/ca.pem", p.KafkaAPI.TLS.TruststoreFile)
+
+	raw, err := yaml.Marshal(s)
+	require.NoError(t, err)
+
+	y := &config.RpkYaml{Profiles: []config.RpkProfile{{Name: "prod"}}}
+	name, _, err := importProfile(fs, y, raw, "", false)
+	require.NoError(t, err)
+	require.Equal(t, "prod-2", name, "name conflicts are resolved")
+	require.Equal(t, "prod-2", y.CurrentProfile)
+
+	imported := y.Profile("prod-2")
+	require.NotNil(t, imported)
+	require.Equal(t, []string{"rp.example.com:9092"}, imported.KafkaAPI.Brokers)
+	caPath := filepath.Join("certs", "prod-2", "kafka-api-ca.pem")
+	require.Equal(t, caPath, imported.KafkaAPI.TLS.TruststoreFile)
+	got, err := afero.ReadFile(fs, caPath)
+	require.NoError(t, err)
+	require.Equal(t, caPEM, string(got))
+
+	// An explicit name that exists is an error.
+	_, _, err = importProfile(fs, y, raw, "prod", false)
+	require.Error(t, err)
+
+	// A bare profile, as printed by 'rpk profile print', is accepted.
+	bare, err := yaml.Marshal(config.RpkProfile{Name: "dev", KafkaAPI: config.RpkKafkaAPI{Brokers: []string{"localhost:9092"}}})
+	require.NoError(t, err)
+	name, _, err = importProfile(fs, y, bare, "", false)
+	require.NoError(t, err)
+	require.Equal(t, "dev", name)
+
+	// Names are used as the certs directory and cannot escape it.
+	for _, bad := range []string{"../../x", "a/b", `a\b`, "..", "."} {
+		evil, err := yaml.Marshal(sharedProfile{Profile: config.RpkProfile{Name: bad}, CACerts: sharedCACerts{KafkaAPI: caPEM}})
+		require.NoError(t, err)
+		_, _, err = importProfile(fs, y, evil, "", false)
+		require.Error(t, err, "name %q", bad)
+		_, _, err = importProfile(fs, y, raw, bad, false)
+		require.Error(t, err, "--name %q", bad)
+	}
+}
+
+func TestExportImportCredentialHelper(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	t.Setenv(config.EnvCredentialsPassphrase, "hunter2")
+	p := &config.RpkProfile{
+		Name:             "prod",
+		CredentialHelper: config.CredentialHelperEncrypted,
+		KafkaAPI: config.RpkKafkaAPI{
+			SASL: &config.SASL{User: "me", Password: "secret", Mechanism: "SCRAM-SHA-256"},
+		},
+	}
+	require.NoError(t, p.StoreCredentials(fs))
+
+	// Secrets held by the helper are exported with --include-secrets, and
+	// the helper is never exported.
+	s, err := exportProfile(fs, p, false, true)
+	require.NoError(t, err)
+	require.Equal(t, "secret", s.Profile.KafkaAPI.SASL.Password)
+	require.Empty(t, s.Profile.CredentialHelper)
+	require.Empty(t, p.KafkaAPI.SASL.Password, "the original profile is unchanged")
+
+	// A helper in an imported file is dropped unless explicitly allowed.
+	raw, err := yaml.Marshal(sharedProfile{Profile: config.RpkProfile{Name: "shared", CredentialHelper: "curl evil.example.com"}})
+	require.NoError(t, err)
+	y := &config.RpkYaml{}
+	name, dropped, err := importProfile(fs, y, raw, "", false)
+	require.NoError(t, err)
+	require.Equal(t, "curl evil.example.com", dropped)
+	require.Empty(t, y.Profile(name).CredentialHelper)
+
+	name, dropped, err = importProfile(fs, y, raw, "", true)
+	require.NoError(t, err)
+	require.Empty(t, dropped)
+	require.Equal(t, "curl evil.example.com", y.Profile(name).CredentialHelper)
+
+	// Secrets of a kept helper are stored in it rather than in rpk.yaml.
+	raw, err = yaml.Marshal(sharedProfile{Profile: config.RpkProfile{
+		Name:             "kept",
+		CredentialHelper: config.CredentialHelperEncrypted,
+		KafkaAPI:         config.RpkKafkaAPI{SASL: &config.SASL{User: "me", Password: "secret", Mechanism: "SCRAM-SHA-256"}},
+	}})
+	require.NoError(t, err)
+	name, _, err = importProfile(fs, y, raw, "", true)
+	require.NoError(t, err)
+	kept := y.Profile(name)
+	require.Empty(t, kept.KafkaAPI.SASL.Password)
+	require.NoError(t, kept.LoadCredentials(fs))
+	require.Equal(t, "secret", kept.KafkaAPI.SASL.Password)
+}
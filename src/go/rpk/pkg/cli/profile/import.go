@@ -0,0 +1,173 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	rpkos "github.com/redpanda-data/redpanda/src/go/rpk/pkg/os"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func newImportCommand(fs afero.Fs, p *config.Params) *cobra.Command {
+	var (
+		name                  string
+		allowCredentialHelper bool
+	)
+	cmd := &cobra.Command{
+		Use:   "import [FILE]",
+		Short: "Import an rpk profile exported with 'rpk profile export'",
+		Long: `Import an rpk profile exported with 'rpk profile export'.
+
+This command adds the profile in FILE to rpk.yaml and switches to it. If FILE
+is "-", the profile is read from stdin. The file can either be the output of
+'rpk profile export', or a profile as printed by 'rpk profile print'.
+
+The profile keeps its name, unless you use --name. If a profile with the same
+name already exists, the imported profile is renamed with a numeric suffix
+(e.g., "prod-2"); with --name, an existing name is an error.
+
+CA certificates embedded in the export are written to the certs directory next
+to rpk.yaml, and the profile is updated to use them.
+
+Exports do not contain secrets by default; set them after importing, e.g.:
+
+    rpk profile set pass=...
+
+A credential_helper in the file is a command that rpk runs whenever the
+profile is used. Since the file may come from someone else, the helper is
+dropped on import unless you use --allow-credential-helper; you can set your
+own with 'rpk profile set credential_helper=...'. If the helper is kept, the
+secrets in the file are stored in the helper rather than in rpk.yaml.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			y, err := cfg.ActualRpkYamlOrEmpty()
+			out.MaybeDie(err, "unable to load rpk.yaml: %v", err)
+
+			var raw []byte
+			if args[0] == "-" {
+				raw, err = io.ReadAll(os.Stdin)
+			} else {
+				raw, err = afero.ReadFile(fs, args[0])
+			}
+			out.MaybeDie(err, "unable to read %q: %v", args[0], err)
+
+			imported, dropped, err := importProfile(fs, y, raw, name, allowCredentialHelper)
+			out.MaybeDieErr(err)
+			err = y.Write(fs)
+			out.MaybeDie(err, "unable to write rpk.yaml: %v", err)
+			fmt.Printf("Imported and switched to profile %q.\n", imported)
+			if dropped != "" {
+				fmt.Printf("Dropped the credential helper %q of the imported profile; use --allow-credential-helper to keep it.\n", dropped)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "Name to import the profile as, rather than the name in the file")
+	cmd.Flags().BoolVar(&allowCredentialHelper, "allow-credential-helper", false, "Keep the credential helper command of the imported profile")
+	return cmd
+}
+
+// importProfile decodes a shared profile, writes its embedded certificates to
+// the config directory, and adds it to the front of the rpk.yaml as the
+// current profile. Unless allowHelper is true, the credential helper of the
+// profile is dropped, since it is a command that would run on the next use of
+// the profile. This returns the name the profile was imported as and the
+// dropped credential helper, if any.
+func importProfile(fs afero.Fs, y *config.RpkYaml, raw []byte, name string, allowHelper bool) (imported, dropped string, err error) {
+	var s sharedProfile
+	if err := yaml.Unmarshal(raw, &s); err != nil {
+		return "", "", fmt.Errorf("unable to decode profile: %v", err)
+	}
+	if s.Profile.Name == "" {
+		// Not an export; try a bare profile from 'rpk profile print'.
+		s = sharedProfile{}
+		if err := yaml.Unmarshal(raw, &s.Profile); err != nil {
+			return "", "", fmt.Errorf("unable to decode profile: %v", err)
+		}
+	}
+	p := s.Profile
+	if !allowHelper {
+		dropped, p.CredentialHelper = p.CredentialHelper, ""
+	}
+
+	switch {
+	case name != "":
+		if y.Profile(name) != nil {
+			return "", "", fmt.Errorf("profile %q already exists", name)
+		}
+		p.Name = name
+	case p.Name == "":
+		return "", "", errors.New("the profile has no name, please specify one with --name")
+	default:
+		p.Name = uniqueProfileName(y, p.Name)
+	}
+	// The name is used as the directory of the certificates.
+	if p.Name == "." || strings.Contains(p.Name, "..") || strings.ContainsAny(p.Name, `/\`) {
+		return "", "", fmt.Errorf("invalid profile name %q: profile names cannot contain path separators or \"..\"", p.Name)
+	}
+
+	dir := filepath.Join(filepath.Dir(y.FileLocation()), "certs", p.Name)
+	for _, c := range []struct {
+		pem  string
+		tls  **config.TLS
+		file string
+	}{
+		{s.CACerts.KafkaAPI, &p.KafkaAPI.TLS, "kafka-api-ca.pem"},
+		{s.CACerts.AdminAPI, &p.AdminAPI.TLS, "admin-api-ca.pem"},
+	} {
+		if c.pem == "" {
+			continue
+		}
+		path := filepath.Join(dir, c.file)
+		if err := rpkos.ReplaceFile(fs, path, []byte(c.pem), 0o644); err != nil {
+			return "", "", fmt.Errorf("unable to write CA certificate %q: %v", path, err)
+		}
+		if *c.tls == nil {
+			*c.tls = new(config.TLS)
+		}
+		(*c.tls).TruststoreFile = path
+	}
+
+	// Secrets in the file are moved into a kept helper, like with 'rpk
+	// profile set', rather than written to rpk.yaml.
+	if err := p.StoreCredentials(fs); err != nil {
+		return "", "", err
+	}
+
+	y.CurrentProfile = y.PushProfile(p)
+	return p.Name, dropped, nil
+}
+
+// uniqueProfileName returns name if no profile has it, otherwise the first of
+// name-2, name-3, ... that is free.
+func uniqueProfileName(y *config.RpkYaml, name string) string {
+	if y.Profile(name) == nil {
+		return name
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", name, i)
+		if y.Profile(candidate) == nil {
+			return candidate
+		}
+	}
+}
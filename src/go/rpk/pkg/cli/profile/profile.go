@@ -36,8 +36,11 @@ your configuration in one place.
 		newClearCommand(fs, p),
 		newCurrentCommand(fs, p),
 		newDeleteCommand(fs, p),
+		newDoctorCommand(fs, p),
 		newEditCommand(fs, p),
 		newEditDefaultsCommand(fs, p),
+		newExportCommand(fs, p),
+		newImportCommand(fs, p),
 		newListCommand(fs, p),
 		newPrintCommand(fs, p),
 		newPrintDefaultsCommand(fs, p),
@@ -42,7 +42,10 @@ func newRenameToCommand(fs afero.Fs, p *config.Params) *cobra.Command {
 			if y.Profile(to) != nil {
 				out.Die("destination profile %q already exists", to)
 			}
+			from := p.Name
 			p.Name = to
+			err = p.RenameCredentials(fs, from)
+			out.MaybeDie(err, "unable to rename the credentials of profile %q: %v", from, err)
 			y.CurrentProfile = to
 			y.MoveProfileToFront(p)
 			err = y.Write(fs)
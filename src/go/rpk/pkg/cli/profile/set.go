@@ -39,6 +39,12 @@ format. If you begin writing a YAML path, this command will suggest the rest of
 the path.
 
 You can also use the format 'set key value' if you intend to only set one key.
+
+If the profile has a credential_helper, secrets (kafka_api.sasl.password and
+kafka_api.sasl.oauth.client_secret) are stored in the credential helper rather
+than in rpk.yaml. If you change or remove the credential_helper, the secrets
+are moved out of the old helper into the new one, or back into rpk.yaml. See
+'rpk profile create --help' for more information.
 `,
 
 		Args:              cobra.MinimumNArgs(1),
@@ -61,8 +67,13 @@ You can also use the format 'set key value' if you intend to only set one key.
 			if p == nil {
 				out.Die("current profile %q does not exist", y.CurrentProfile)
 			}
+			helper := p.CredentialHelper
 			err = doSet(p, args)
 			out.MaybeDieErr(err)
+			err = p.SwitchCredentialHelper(fs, helper)
+			out.MaybeDie(err, "unable to move the credentials of profile %q to the new credential helper: %v", p.Name, err)
+			err = p.StoreCredentials(fs)
+			out.MaybeDieErr(err)
 			err = y.Write(fs)
 			out.MaybeDieErr(err)
 			fmt.Printf("Profile %q updated successfully.\n", y.CurrentProfile)
@@ -98,7 +109,7 @@ func validSetArgs(_ *cobra.Command, _ []string, toComplete string) (ps []string,
 	}()
 
 	xf, ypaths := config.XProfileFlags()
-	ypaths = append(ypaths, "description") // we have no xflag for the description field since this is purely informational
+	ypaths = append(ypaths, "description", "credential_helper") // we have no xflag for these fields since they are not used for connecting
 	if len(toComplete) == 0 {
 		return ypaths, cobra.ShellCompDirectiveNoSpace
 	}
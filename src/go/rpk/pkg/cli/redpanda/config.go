@@ -33,6 +33,7 @@ func NewConfigCommand(fs afero.Fs, p *config.Params) *cobra.Command {
 	cmd.AddCommand(
 		set(fs, p),
 		bootstrap(fs, p),
+		validate(fs, p),
 		cobraext.DeprecatedCmd("init", 0),
 	)
 	return cmd
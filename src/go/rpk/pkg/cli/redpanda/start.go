@@ -18,14 +18,17 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/docker/go-units"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cloud"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/net"
 	vos "github.com/redpanda-data/redpanda/src/go/rpk/pkg/os"
 	rp "github.com/redpanda-data/redpanda/src/go/rpk/pkg/redpanda"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/system"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/factory"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/hwloc"
@@ -80,6 +83,12 @@ const (
 	checkFlag             = "check"
 )
 
+// When sizing --memory from a cgroup memory limit and no --reserve-memory is
+// given, we leave this percentage of the limit for the rest of the cgroup.
+// Seastar's own default reservation (at least 1.5GiB) assumes a whole host,
+// which is too much for most containers.
+const cgroupReserveMemoryPercent = 7
+
 func updateConfigWithFlags(y *config.RedpandaYaml, flags *pflag.FlagSet) {
 	if flags.Changed(lockMemoryFlag) {
 		y.Rpk.EnableMemoryLocking, _ = flags.GetBool(lockMemoryFlag)
@@ -127,6 +136,9 @@ func NewStartCommand(fs afero.Fs, p *config.Params, launcher rp.Launcher) *cobra
 		timeout         time.Duration
 		wellKnownIo     string
 		mode            string
+		cgroupResources bool
+		validateConfig  bool
+		strictConfig    bool
 	)
 	sFlags := seastarFlags{}
 
@@ -346,10 +358,31 @@ func NewStartCommand(fs afero.Fs, p *config.Params, launcher rp.Launcher) *cobra
 				return err
 			}
 
+			if cgroupResources {
+				res, err := system.ReadResources(fs)
+				if err != nil {
+					zap.L().Sugar().Warnf("Unable to detect the effective resources, --%s and --%s will not be sized from cgroup limits: %v", smpFlag, memoryFlag, err)
+				} else {
+					fmt.Printf("Effective resources: %s\n", res)
+					if err := applyCgroupResources(rpArgs, res); err != nil {
+						return err
+					}
+				}
+			}
+
 			if y.Redpanda.Directory == "" {
 				y.Redpanda.Directory = config.DevDefault().Redpanda.Directory
 			}
 
+			if validateConfig {
+				if err := validateStartConfig(fs, y); err != nil {
+					if strictConfig {
+						return err
+					}
+					zap.L().Sugar().Warnf("%v", err)
+				}
+			}
+
 			err = prestart(fs, rpArgs, y, prestartCfg, timeout)
 			if err != nil {
 				return err
@@ -387,7 +420,7 @@ https://redpanda.com/feedback
 	f.BoolVar(&prestartCfg.tuneEnabled, "tune", false, "When present will enable tuning before starting redpanda")
 	f.BoolVar(&prestartCfg.checkEnabled, checkFlag, true, "When set to false will disable system checking before starting redpanda")
 	f.IntVar(&sFlags.smp, smpFlag, 0, "Restrict redpanda to the given number of CPUs. This option does not mandate a specific placement of CPUs. See --cpuset if you need to do so.")
-	f.StringVar(&sFlags.reserveMemory, reserveMemoryFlag, "", "Memory reserved for the OS (if --memory isn't specified)")
+	f.StringVar(&sFlags.reserveMemory, reserveMemoryFlag, "", "Memory reserved for the OS (if --memory isn't specified); under a cgroup memory limit, it is subtracted from the limit")
 	f.StringVar(&sFlags.hugepages, hugepagesFlag, "", "Path to accessible hugetlbfs mount (typically /dev/hugepages/something)")
 	f.BoolVar(&sFlags.threadAffinity, threadAffinityFlag, true, "Pin threads to their cpus (disable for overprovisioning)")
 	f.IntVar(&sFlags.numIoQueues, numIoQueuesFlag, 0, "Number of IO queues. Each IO unit will be responsible for a fraction of the IO requests. Defaults to the number of threads")
@@ -399,6 +432,9 @@ https://redpanda.com/feedback
 	f.BoolVar(&sFlags.overprovisioned, overprovisionedFlag, false, "Enable overprovisioning")
 	f.BoolVar(&sFlags.unsafeBypassFsync, unsafeBypassFsyncFlag, false, "Enable unsafe-bypass-fsync")
 	f.StringVar(&mode, modeFlag, "", "Mode sets well-known configuration properties for development or test environments; use --mode help for more info")
+	f.BoolVar(&cgroupResources, "cgroup-resources", true, "Size --smp and --memory from the cgroup CPU quota and memory limit if they are not set")
+	f.BoolVar(&validateConfig, "validate-config", true, "Validate redpanda.yaml and the bootstrap file against the configuration schema before starting, warning about any error found")
+	f.BoolVar(&strictConfig, "strict-config", false, "Fail to start if the configuration validation finds any error, rather than warning")
 
 	f.DurationVar(&timeout, "timeout", 10000*time.Millisecond, "The maximum time to wait for the checks and tune processes to complete (e.g. 300ms, 1.5s, 2h45m)")
 	for flag := range flagsMap(sFlags) {
@@ -534,6 +570,45 @@ func buildRedpandaFlags(
 	}, nil
 }
 
+// applyCgroupResources sets --smp and --memory from the cgroup CPU quota and
+// memory limit if they are lower than what Redpanda would otherwise use, and
+// if neither flag is already set (via flags, rpk.smp, or
+// rpk.additional_start_flags). The cpuset does not need to be handled here
+// since Redpanda already honors the CPU affinity it is started with.
+//
+// A --reserve-memory is subtracted from the cgroup limit instead of the host
+// memory, and then dropped since it is ignored by Redpanda once --memory is
+// set.
+func applyCgroupResources(args *rp.RedpandaArgs, res *system.Resources) error {
+	flags := args.SeastarFlags
+	if _, ok := flags[smpFlag]; !ok && res.CPUQuotaLimited() {
+		flags[smpFlag] = strconv.FormatUint(res.CPUs(), 10)
+		fmt.Printf("Setting --%s=%s from the cgroup CPU quota\n", smpFlag, flags[smpFlag])
+	}
+	if _, ok := flags[memoryFlag]; ok || !res.MemoryLimited() {
+		return nil
+	}
+	limit := res.CgroupMemLimit
+	reserve := limit * cgroupReserveMemoryPercent / 100
+	if r, ok := flags[reserveMemoryFlag]; ok {
+		parsed, err := units.RAMInBytes(r)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("unable to parse --%s %q", reserveMemoryFlag, r)
+		}
+		reserve = uint64(parsed)
+	}
+	if reserve >= limit {
+		return fmt.Errorf(
+			"--%s %s leaves no memory for Redpanda under the cgroup memory limit of %s",
+			reserveMemoryFlag, units.BytesSize(float64(reserve)), units.BytesSize(float64(limit)),
+		)
+	}
+	flags[memoryFlag] = fmt.Sprintf("%dM", (limit-reserve)/units.MiB)
+	delete(flags, reserveMemoryFlag)
+	fmt.Printf("Setting --%s=%s from the cgroup memory limit\n", memoryFlag, flags[memoryFlag])
+	return nil
+}
+
 func flagsFromConf(
 	y *config.RedpandaYaml, flagsMap map[string]interface{}, flags *pflag.FlagSet,
 ) map[string]interface{} {
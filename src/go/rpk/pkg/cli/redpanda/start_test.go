@@ -18,6 +18,7 @@ import (
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/redpanda"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/system"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/iotune"
 	"github.com/spf13/afero"
 	"github.com/spf13/pflag"
@@ -96,6 +97,66 @@ func TestMergeFlags(t *testing.T) {
 	}
 }
 
+func TestApplyCgroupResources(t *testing.T) {
+	limited := &system.Resources{
+		HostCPUs:       16,
+		CgroupCPUs:     8,
+		CPUQuota:       2,
+		HostMemory:     64 << 30,
+		CgroupMemLimit: 10 << 30,
+	}
+	tests := []struct {
+		name      string
+		flags     map[string]string
+		resources *system.Resources
+		expected  map[string]string
+		expErr    bool
+	}{
+		{
+			name:      "it should size smp and memory from the cgroup",
+			flags:     map[string]string{},
+			resources: limited,
+			expected:  map[string]string{"smp": "2", "memory": "9523M"},
+		},
+		{
+			name:      "it should subtract --reserve-memory from the cgroup limit",
+			flags:     map[string]string{"reserve-memory": "2G"},
+			resources: limited,
+			expected:  map[string]string{"smp": "2", "memory": "8192M"},
+		},
+		{
+			name:      "it should not override explicit values",
+			flags:     map[string]string{"smp": "4", "memory": "1G", "reserve-memory": "0M"},
+			resources: limited,
+			expected:  map[string]string{"smp": "4", "memory": "1G", "reserve-memory": "0M"},
+		},
+		{
+			name:      "it should not change anything without cgroup limits",
+			flags:     map[string]string{"reserve-memory": "1G"},
+			resources: &system.Resources{HostCPUs: 16, CgroupCPUs: 16, HostMemory: 64 << 30},
+			expected:  map[string]string{"reserve-memory": "1G"},
+		},
+		{
+			name:      "it should fail if the reservation exceeds the cgroup limit",
+			flags:     map[string]string{"reserve-memory": "10G"},
+			resources: limited,
+			expErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := &redpanda.RedpandaArgs{SeastarFlags: tt.flags}
+			err := applyCgroupResources(args, tt.resources)
+			if tt.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, args.SeastarFlags)
+		})
+	}
+}
+
 func TestParseNamedAuthNAddress(t *testing.T) {
 	authNSasl := "sasl"
 	tests := []struct {
@@ -1638,6 +1699,41 @@ func TestStartCommand(t *testing.T) {
 		name:           "Fails if unknown mode is passed",
 		args:           []string{"--install-dir", "/var/lib/redpanda", "--mode", "foo"},
 		expectedErrMsg: `unrecognized mode "foo"`,
+	}, {
+		name: "it should start if redpanda.yaml does not match the configuration schema",
+		args: []string{"--install-dir", "/var/lib/redpanda"},
+		before: func(fs afero.Fs) error {
+			y := config.DevDefault()
+			y.Redpanda.Other = map[string]interface{}{"developer_mod": true}
+			return y.Write(fs)
+		},
+	}, {
+		name: "Fails if redpanda.yaml does not match the configuration schema with --strict-config",
+		args: []string{"--install-dir", "/var/lib/redpanda", "--strict-config"},
+		before: func(fs afero.Fs) error {
+			y := config.DevDefault()
+			y.Redpanda.Other = map[string]interface{}{"developer_mod": true}
+			return y.Write(fs)
+		},
+		expectedErrMsg: `redpanda.developer_mod: unknown property "developer_mod", did you mean "developer_mode"?`,
+	}, {
+		name: "Fails if the bootstrap file does not match the configuration schema with --strict-config",
+		args: []string{"--install-dir", "/var/lib/redpanda", "--strict-config"},
+		before: func(fs afero.Fs) error {
+			if err := config.DevDefault().Write(fs); err != nil {
+				return err
+			}
+			return afero.WriteFile(fs, "/etc/redpanda/.bootstrap.yaml", []byte("log_segment_size: big\n"), 0o644)
+		},
+		expectedErrMsg: `.bootstrap.yaml: log_segment_size: expected an integer, got "big"`,
+	}, {
+		name: "it should skip the configuration validation with --validate-config=false",
+		args: []string{"--install-dir", "/var/lib/redpanda", "--validate-config=false", "--strict-config"},
+		before: func(fs afero.Fs) error {
+			y := config.DevDefault()
+			y.Redpanda.Other = map[string]interface{}{"developer_mod": true}
+			return y.Write(fs)
+		},
 	}}
 
 	for _, tt := range tests {
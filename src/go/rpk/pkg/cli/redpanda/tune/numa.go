@@ -0,0 +1,244 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build linux
+
+package tune
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	vos "github.com/redpanda-data/redpanda/src/go/rpk/pkg/os"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/disk"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/factory"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/hwloc"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/irq"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/numa"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// The start flags that a NUMA placement owns; any previous value for these
+// in rpk.additional_start_flags is replaced when writing a placement.
+var numaPlacementFlags = []string{"cpuset", "memory", "smp", "reserve-memory"}
+
+func newNUMACommand(fs afero.Fs, p *config.Params) *cobra.Command {
+	var (
+		tunerParams   factory.TunerParams
+		irqCores      uint
+		reserveMemory string
+		write         bool
+		timeout       time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "numa",
+		Short: "Propose a NUMA-local cpuset and memory limit for Redpanda",
+		Long: `Propose a NUMA-local cpuset and memory limit for Redpanda.
+
+This command inspects the NUMA topology of the host using hwloc, finds the NUMA
+node that the network interfaces and data disks used by Redpanda are attached
+to, and proposes a placement that keeps Redpanda on that node:
+
+  * --cpuset: the node's CPUs, minus the first --irq-cores physical cores
+    (and their HT siblings), which are left to handle the devices' IRQs.
+  * --memory: the node's memory, minus --reserve-memory (or, by default,
+    the larger of 1.5GiB and 7% of the node's memory).
+
+NICs and disks default to the ones used by the addresses and data directory in
+your redpanda.yaml, just like 'rpk redpanda tune'.
+
+By default the proposal is only printed. Use --write to save it into
+'rpk.additional_start_flags' in your redpanda.yaml so that 'rpk redpanda start'
+passes it to Redpanda. Any previous --cpuset, --memory, --smp or
+--reserve-memory in 'rpk.additional_start_flags' is replaced.
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(*cobra.Command, []string) {
+			var reserve uint64
+			if reserveMemory != "" {
+				r, err := units.RAMInBytes(reserveMemory)
+				out.MaybeDie(err, "unable to parse --reserve-memory: %v", err)
+				if r < 0 {
+					out.Die("--reserve-memory cannot be negative")
+				}
+				reserve = uint64(r)
+			}
+
+			y, err := p.LoadVirtualRedpandaYaml(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+			params, err := factory.MergeTunerParamsConfig(&tunerParams, y)
+			out.MaybeDie(err, "unable to determine the devices used by Redpanda: %v", err)
+
+			proc := vos.NewProc()
+			hw := hwloc.NewHwLocCmd(proc, timeout)
+			if !hw.IsSupported() {
+				out.Die("unable to find %s and %s, which are required to inspect the NUMA topology", hwloc.CalcBin, hwloc.DistribBin)
+			}
+			topo, err := numa.ReadTopology(fs, hw)
+			out.MaybeDie(err, "unable to read the NUMA topology: %v", err)
+
+			devices, err := devicesNUMANodes(fs, proc, params, timeout)
+			out.MaybeDie(err, "unable to determine the NUMA node of the devices: %v", err)
+
+			placement, err := numa.Propose(topo, devices, irqCores, reserve)
+			out.MaybeDie(err, "unable to propose a placement: %v", err)
+
+			printNUMAPlacement(topo, placement)
+
+			if !write {
+				fmt.Println("\nRun again with --write to save these flags into 'rpk.additional_start_flags'.")
+				return
+			}
+			err = writeNUMAPlacement(fs, p, placement)
+			out.MaybeDie(err, "unable to write the placement: %v", err)
+			fmt.Println("\nPlacement saved to 'rpk.additional_start_flags'; it will be used on the next 'rpk redpanda start'.")
+		},
+	}
+	f := cmd.Flags()
+	f.StringSliceVarP(&tunerParams.Disks, "disks", "d", nil, "Lists of devices to consider f.e. 'sda1'")
+	f.StringSliceVarP(&tunerParams.Nics, "nic", "n", nil, "Network Interface Controllers to consider")
+	f.StringSliceVarP(&tunerParams.Directories, "dirs", "r", nil, "List of *data* directories to consider (e.g. /var/vectorized/redpanda/)")
+	f.UintVar(&irqCores, "irq-cores", 1, "Number of physical cores of the chosen NUMA node to leave out of the cpuset for IRQ handling")
+	f.StringVar(&reserveMemory, "reserve-memory", "", "Memory of the chosen NUMA node to leave for the OS (e.g. 2G); defaults to the larger of 1.5GiB and 7% of the node's memory")
+	f.BoolVar(&write, "write", false, "Write the proposed flags into 'rpk.additional_start_flags' in redpanda.yaml")
+	f.DurationVar(&timeout, "timeout", 10*time.Second, "The maximum time to wait for hwloc and device inspection to complete (e.g. 300ms, 1.5s, 2h45m)")
+	return cmd
+}
+
+// devicesNUMANodes returns the NUMA node of every NIC and disk in params,
+// including the disks backing the data directories.
+func devicesNUMANodes(
+	fs afero.Fs, proc vos.Proc, params *factory.TunerParams, timeout time.Duration,
+) (map[string]int, error) {
+	devices := make(map[string]int)
+	for _, nic := range params.Nics {
+		node, err := numa.NICNode(fs, nic)
+		if err != nil {
+			return nil, err
+		}
+		devices[nic] = node
+	}
+
+	procFile := irq.NewProcFile(fs)
+	blockDevices := disk.NewBlockDevices(fs, irq.NewDeviceInfo(fs, procFile), procFile, proc, timeout)
+	disks := append([]string(nil), params.Disks...)
+	dirDevices, err := blockDevices.GetDirectoriesDevices(params.Directories)
+	if err != nil {
+		return nil, err
+	}
+	for _, devs := range dirDevices {
+		disks = append(disks, devs...)
+	}
+	for _, d := range disks {
+		if _, seen := devices[d]; seen {
+			continue
+		}
+		syspath, err := blockDevices.GetDeviceSystemPath(filepath.Join("/dev", d))
+		if err != nil {
+			return nil, err
+		}
+		node, err := numa.DeviceNode(fs, syspath)
+		if err != nil {
+			return nil, err
+		}
+		devices[d] = node
+	}
+	if len(devices) == 0 {
+		return nil, errors.New("no NICs or disks found; use --nic, --disks or --dirs")
+	}
+	return devices, nil
+}
+
+func printNUMAPlacement(topo *numa.Topology, placement *numa.Placement) {
+	out.Section("NUMA NODES")
+	nodes := out.NewTable("Node", "CPUs", "Cores", "Memory")
+	for _, n := range topo.Nodes {
+		nodes.Print(n.ID, numa.FormatCPUList(n.CPUs()), len(n.Cores), units.BytesSize(float64(n.Memory)))
+	}
+	nodes.Flush()
+	fmt.Println()
+
+	out.Section("DEVICES")
+	devs := out.NewTable("Device", "Node")
+	names := make([]string, 0, len(placement.Devices))
+	for name := range placement.Devices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		node := fmt.Sprint(placement.Devices[name])
+		if placement.Devices[name] < 0 {
+			node = "unknown"
+		}
+		devs.Print(name, node)
+	}
+	devs.Flush()
+	fmt.Println()
+
+	out.Section("PROPOSED PLACEMENT")
+	tw := out.NewTabWriter()
+	tw.Print("NUMA node", placement.Node)
+	tw.Print("IRQ CPUs", numa.FormatCPUList(placement.IRQCPUs))
+	tw.Print("Start flags", strings.Join(numaStartFlags(placement), " "))
+	tw.Flush()
+}
+
+func numaStartFlags(placement *numa.Placement) []string {
+	return []string{
+		"--cpuset=" + placement.CPUSetFlag(),
+		"--memory=" + placement.MemoryFlag(),
+	}
+}
+
+func writeNUMAPlacement(fs afero.Fs, p *config.Params, placement *numa.Placement) error {
+	cfg, err := p.Load(fs)
+	if err != nil {
+		return fmt.Errorf("unable to load config: %v", err)
+	}
+	y := cfg.ActualRedpandaYamlOrDefaults()
+	y.Rpk.AdditionalStartFlags = append(
+		removeStartFlags(y.Rpk.AdditionalStartFlags, numaPlacementFlags...),
+		numaStartFlags(placement)...,
+	)
+	return y.Write(fs)
+}
+
+// removeStartFlags removes the given flags from a list of start flags,
+// supporting both the "--name=value" and "--name value" forms.
+func removeStartFlags(flags []string, names ...string) []string {
+	remove := make(map[string]bool, len(names))
+	for _, n := range names {
+		remove[n] = true
+	}
+	var kept []string
+	for i := 0; i < len(flags); i++ {
+		f := flags[i]
+		if !strings.HasPrefix(f, "-") {
+			kept = append(kept, f)
+			continue
+		}
+		name, _, hasValue := strings.Cut(strings.Trim(f, " -"), "=")
+		if !remove[strings.TrimSpace(name)] {
+			kept = append(kept, f)
+			continue
+		}
+		if !hasValue && i+1 < len(flags) && !strings.HasPrefix(flags[i+1], "-") {
+			i++ // skip the value of a "--name value" flag.
+		}
+	}
+	return kept
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build linux
+
+package tune
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveStartFlags(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		flags []string
+		exp   []string
+	}{
+		{
+			name:  "equals form",
+			flags: []string{"--cpuset=0-3", "--abort-on-seastar-bad-alloc", "--memory=4G"},
+			exp:   []string{"--abort-on-seastar-bad-alloc"},
+		},
+		{
+			name:  "space form",
+			flags: []string{"--smp", "2", "--default-log-level=info", "--reserve-memory", "1G"},
+			exp:   []string{"--default-log-level=info"},
+		},
+		{
+			name:  "nothing to remove",
+			flags: []string{"--overprovisioned", "--logger-log-level=archival=debug"},
+			exp:   []string{"--overprovisioned", "--logger-log-level=archival=debug"},
+		},
+		{
+			name:  "flag without a value at the end",
+			flags: []string{"--overprovisioned", "--memory"},
+			exp:   []string{"--overprovisioned"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.exp, removeStartFlags(test.flags, numaPlacementFlags...))
+		})
+	}
+}
@@ -57,6 +57,9 @@ Available tuners:
   - %s
 
 To learn more about a tuner, run 'rpk redpanda tune help <tuner name>'.
+
+To propose a NUMA-local cpuset and memory limit for Redpanda, run
+'rpk redpanda tune numa'.
 `, strings.Join(factory.AvailableTuners(), "\n  - ")),
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) < 1 {
@@ -115,6 +118,7 @@ To learn more about a tuner, run 'rpk redpanda tune help <tuner name>'.
 	cmd.AddCommand(
 		newHelpCommand(),
 		newListCommand(fs, p),
+		newNUMACommand(fs, p),
 	)
 	return cmd
 }
@@ -0,0 +1,191 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build linux
+
+package redpanda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/adminapi"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config/schema"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// bootstrapFileName is the cluster configuration file that Redpanda reads
+// from the directory of redpanda.yaml when it creates a new cluster.
+const bootstrapFileName = ".bootstrap.yaml"
+
+func validate(fs afero.Fs, p *config.Params) *cobra.Command {
+	var (
+		bootstrapFile string
+		offline       bool
+		timeout       time.Duration
+	)
+	c := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate redpanda.yaml and the bootstrap file against the configuration schema",
+		Long: `Validate redpanda.yaml and the bootstrap file against the configuration schema.
+
+This command checks your redpanda.yaml for unknown or misspelled properties,
+values of the wrong type, values outside of a property's allowed values, and
+deprecated properties. Cluster properties set in the redpanda section are
+reported, since Redpanda only reads them when a new cluster is created.
+
+If a .bootstrap.yaml exists next to your redpanda.yaml (or one is given with
+--bootstrap-file), it is validated against the cluster configuration schema.
+
+The cluster configuration schema is queried from the broker configured in your
+profile, falling back to the schema embedded in rpk if the broker cannot be
+reached or if --offline is used. Node, Pandaproxy, and Schema Registry
+properties are always validated against the schema embedded in rpk.
+
+This command exits with a non-zero status if any error is found; warnings do
+not affect the exit status.
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			schemas, err := schema.Embedded()
+			out.MaybeDie(err, "unable to load the configuration schema: %v", err)
+			if !offline {
+				schemas = withBrokerSchema(cmd.Context(), fs, cfg.VirtualProfile(), schemas, timeout)
+			}
+			fmt.Printf("Using the %s cluster configuration schema.\n\n", schemas.Source)
+
+			y, ok := cfg.ActualRedpandaYaml()
+			if !ok {
+				out.Die("unable to find a redpanda.yaml to validate")
+			}
+			findings, err := schemas.ValidateRedpandaYaml(y.RawFile())
+			out.MaybeDie(err, "unable to validate %s: %v", y.FileLocation(), err)
+			hasErrors := printFindings(y.FileLocation(), findings)
+
+			if bootstrapFile == "" {
+				bootstrapFile = filepath.Join(filepath.Dir(y.FileLocation()), bootstrapFileName)
+				if exists, _ := afero.Exists(fs, bootstrapFile); !exists {
+					bootstrapFile = ""
+				}
+			}
+			if bootstrapFile != "" {
+				raw, err := afero.ReadFile(fs, bootstrapFile)
+				out.MaybeDie(err, "unable to read %s: %v", bootstrapFile, err)
+				findings, err := schemas.ValidateClusterConfig(raw)
+				out.MaybeDie(err, "unable to validate %s: %v", bootstrapFile, err)
+				fmt.Println()
+				hasErrors = printFindings(bootstrapFile, findings) || hasErrors
+			}
+			if hasErrors {
+				os.Exit(1)
+			}
+		},
+	}
+	c.Flags().StringVar(&bootstrapFile, "bootstrap-file", "", "Cluster configuration file to validate (default: the .bootstrap.yaml next to redpanda.yaml, if it exists)")
+	c.Flags().BoolVar(&offline, "offline", false, "Do not query the broker, use the configuration schema embedded in rpk")
+	c.Flags().DurationVar(&timeout, "timeout", 5*time.Second, "The maximum time to wait for the broker's configuration schema (e.g. 300ms, 1.5s)")
+	return c
+}
+
+// withBrokerSchema replaces the embedded cluster schema with the one of the
+// broker in the profile, if it can be queried.
+func withBrokerSchema(
+	ctx context.Context, fs afero.Fs, prof *config.RpkProfile, schemas *schema.Schemas, timeout time.Duration,
+) *schema.Schemas {
+	if err := prof.LoadCredentials(fs); err != nil {
+		zap.L().Sugar().Debugf("Unable to load the profile credentials, using the embedded schema: %v", err)
+		return schemas
+	}
+	cl, err := adminapi.NewClient(fs, prof)
+	if err != nil {
+		zap.L().Sugar().Debugf("Unable to initialize the admin client, using the embedded schema: %v", err)
+		return schemas
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cluster, err := cl.ClusterConfigSchema(ctx)
+	if err != nil {
+		zap.L().Sugar().Debugf("Unable to query the cluster configuration schema, using the embedded schema: %v", err)
+		return schemas
+	}
+	return schemas.WithClusterSchema(cluster, "broker")
+}
+
+// printFindings prints the findings of a file and returns whether any of them
+// is an error.
+func printFindings(file string, findings schema.Findings) bool {
+	out.Section(file)
+	if len(findings) == 0 {
+		fmt.Println("No issues found.")
+		return false
+	}
+	tw := out.NewTable("Severity", "Property", "Message")
+	for _, f := range findings {
+		tw.Print(f.Severity, f.Path, f.Message)
+	}
+	tw.Flush()
+	return findings.HasErrors()
+}
+
+// validateStartConfig validates the redpanda.yaml that 'rpk redpanda start'
+// is about to write, and the bootstrap file next to it, against the embedded
+// schema. Only errors are returned: --mode dev-container itself sets cluster
+// properties in redpanda.yaml, which would be reported on every start. The
+// broker accepts unknown properties, so the caller only fails to start on
+// these errors if --strict-config is used.
+func validateStartConfig(fs afero.Fs, y *config.RedpandaYaml) error {
+	schemas, err := schema.Embedded()
+	if err != nil {
+		return err
+	}
+	raw, err := yaml.Marshal(y)
+	if err != nil {
+		return fmt.Errorf("unable to encode the configuration: %v", err)
+	}
+	findings, err := schemas.ValidateRedpandaYaml(raw)
+	if err != nil {
+		return err
+	}
+	if loc := y.FileLocation(); loc != "" {
+		bootstrap := filepath.Join(filepath.Dir(loc), bootstrapFileName)
+		if b, err := afero.ReadFile(fs, bootstrap); err == nil {
+			bf, err := schemas.ValidateClusterConfig(b)
+			if err != nil {
+				return fmt.Errorf("unable to validate %s: %v", bootstrap, err)
+			}
+			for _, f := range bf {
+				f.Path = bootstrapFileName + ": " + f.Path
+				findings = append(findings, f)
+			}
+		}
+	}
+
+	var errs []error
+	for _, f := range findings {
+		if f.Severity == schema.SeverityError {
+			errs = append(errs, fmt.Errorf("%s: %s", f.Path, f.Message))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
@@ -168,13 +168,18 @@ func (p *Params) LoadActualRedpandaYamlOrDefaults(fs afero.Fs) (*RedpandaYaml, e
 }
 
 // LoadVirtualProfile is a shortcut for p.Load followed by
-// cfg.VirtualProfile.
+// cfg.VirtualProfile. Any secret of the profile that is kept by a credential
+// helper is loaded into the returned profile.
 func (p *Params) LoadVirtualProfile(fs afero.Fs) (*RpkProfile, error) {
 	cfg, err := p.Load(fs)
 	if err != nil {
 		return nil, err
 	}
-	return cfg.VirtualProfile(), nil
+	prof := cfg.VirtualProfile()
+	if err := prof.LoadCredentials(fs); err != nil {
+		return nil, err
+	}
+	return prof, nil
 }
 
 ///////////
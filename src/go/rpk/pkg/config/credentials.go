@@ -0,0 +1,598 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+
+	rpkos "github.com/redpanda-data/redpanda/src/go/rpk/pkg/os"
+)
+
+// CredentialHelperEncrypted is the name of the built-in credential helper,
+// which keeps secrets in a passphrase encrypted file next to rpk.yaml.
+const CredentialHelperEncrypted = "encrypted"
+
+// EnvCredentialsPassphrase is the environment variable the passphrase of the
+// built-in encrypted credential store is read from. If unset, rpk prompts for
+// the passphrase if stdin is a terminal.
+const EnvCredentialsPassphrase = "RPK_CREDENTIALS_PASSPHRASE"
+
+// The yaml paths of the secrets that credential helpers manage.
+var (
+	profileSecretKeys   = []string{"kafka_api.sasl.password", "kafka_api.sasl.oauth.client_secret"}
+	cloudAuthSecretKeys = []string{"client_secret"}
+)
+
+// CredentialRequest identifies a secret to get from, store in, or erase from
+// a credential helper.
+type CredentialRequest struct {
+	Profile   string // the profile the secret belongs to, if any
+	CloudAuth string // the cloud auth the secret belongs to, if any
+	Key       string // the yaml path of the secret, e.g. kafka_api.sasl.password
+	Secret    string // the secret, only set when storing
+}
+
+// CredentialHelper fetches and stores secrets outside of rpk.yaml.
+//
+// External helpers follow a protocol similar to git credential helpers: the
+// helper command is run with one of the "get", "store", or "erase" actions as
+// its last argument, and the request is written to stdin as key=value lines
+// terminated by a blank line:
+//
+//	profile=<name>      (or cloud_auth=<name>)
+//	key=<yaml path of the secret>
+//	secret=<secret>     (store only)
+//
+// For "get", the helper prints secret=<secret> to stdout, or nothing if it
+// does not have the secret. A non-zero exit status is an error. Values that
+// contain newlines cannot be sent to external helpers and are rejected.
+type CredentialHelper interface {
+	Get(req CredentialRequest) (secret string, found bool, err error)
+	Store(req CredentialRequest) error
+	Erase(req CredentialRequest) error
+}
+
+// NewCredentialHelper returns the helper for the given credential_helper
+// value: either the built-in "encrypted" store, or an external command.
+func NewCredentialHelper(fs afero.Fs, helper string) (CredentialHelper, error) {
+	if helper == CredentialHelperEncrypted {
+		path, err := DefaultRpkCredentialsPath()
+		if err != nil {
+			return nil, err
+		}
+		return &encryptedStore{fs: fs, path: path}, nil
+	}
+	args := strings.Fields(helper)
+	if len(args) == 0 {
+		return nil, errors.New("empty credential helper")
+	}
+	return &execHelper{args: args}, nil
+}
+
+// DefaultRpkCredentialsPath returns the OS equivalent of
+// ~/.config/rpk/credentials.enc, the file of the built-in encrypted
+// credential store.
+func DefaultRpkCredentialsPath() (string, error) {
+	path, err := DefaultRpkYamlPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "credentials.enc"), nil
+}
+
+// LoadCredentials fills in any empty secret of the profile from its credential
+// helper. Secrets that are already set, e.g. through flags or environment
+// variables, are left as is.
+func (p *RpkProfile) LoadCredentials(fs afero.Fs) error {
+	if p.CredentialHelper == "" {
+		return nil
+	}
+	if p.KafkaAPI.SASL == nil {
+		return nil // no secret is used without SASL
+	}
+	h, err := NewCredentialHelper(fs, p.CredentialHelper)
+	if err != nil {
+		return err
+	}
+	for _, key := range profileSecretKeys {
+		dst := p.secret(key)
+		if dst == nil || *dst != "" {
+			continue
+		}
+		secret, found, err := h.Get(CredentialRequest{Profile: p.Name, Key: key})
+		if err != nil {
+			return fmt.Errorf("unable to get %s of profile %q from credential helper %q: %v", key, p.Name, p.CredentialHelper, err)
+		}
+		if found {
+			*dst = secret
+		}
+	}
+	return nil
+}
+
+// StoreCredentials moves any secret of the profile into its credential helper,
+// clearing the secret from the profile so that it is not written to rpk.yaml.
+// This is a no-op if the profile has no credential helper.
+func (p *RpkProfile) StoreCredentials(fs afero.Fs) error {
+	if p.CredentialHelper == "" {
+		return nil
+	}
+	h, err := NewCredentialHelper(fs, p.CredentialHelper)
+	if err != nil {
+		return err
+	}
+	for _, key := range profileSecretKeys {
+		dst := p.secret(key)
+		if dst == nil || *dst == "" {
+			continue
+		}
+		if err := h.Store(CredentialRequest{Profile: p.Name, Key: key, Secret: *dst}); err != nil {
+			return fmt.Errorf("unable to store %s of profile %q in credential helper %q: %v", key, p.Name, p.CredentialHelper, err)
+		}
+		*dst = ""
+	}
+	return nil
+}
+
+// RenameCredentials moves the secrets that the profile's credential helper
+// keeps under the profile name "from" to the current name of the profile. This
+// must be called when a profile is renamed, since helpers key secrets by the
+// profile name.
+func (p *RpkProfile) RenameCredentials(fs afero.Fs, from string) error {
+	if p.CredentialHelper == "" || from == p.Name {
+		return nil
+	}
+	h, err := NewCredentialHelper(fs, p.CredentialHelper)
+	if err != nil {
+		return err
+	}
+	return renameCredentials(h, profileSecretKeys,
+		CredentialRequest{Profile: from},
+		CredentialRequest{Profile: p.Name},
+	)
+}
+
+// EraseCredentials erases the secrets of the profile from its credential
+// helper. This must be called when a profile is deleted.
+func (p *RpkProfile) EraseCredentials(fs afero.Fs) error {
+	if p.CredentialHelper == "" {
+		return nil
+	}
+	h, err := NewCredentialHelper(fs, p.CredentialHelper)
+	if err != nil {
+		return err
+	}
+	for _, key := range profileSecretKeys {
+		if err := h.Erase(CredentialRequest{Profile: p.Name, Key: key}); err != nil {
+			return fmt.Errorf("unable to erase %s of profile %q from credential helper %q: %v", key, p.Name, p.CredentialHelper, err)
+		}
+	}
+	return nil
+}
+
+// SwitchCredentialHelper moves the secrets of the profile from the credential
+// helper "from" to the profile's current credential helper, or back into the
+// profile if it no longer has one. This must be called when the credential
+// helper of a profile is changed, so that no secret is left behind in a
+// helper that is no longer used.
+func (p *RpkProfile) SwitchCredentialHelper(fs afero.Fs, from string) error {
+	to := p.CredentialHelper
+	if from == "" || from == to {
+		return nil
+	}
+	p.CredentialHelper = from
+	err := p.LoadCredentials(fs)
+	p.CredentialHelper = to
+	if err != nil {
+		return err
+	}
+	if err := p.StoreCredentials(fs); err != nil {
+		return err
+	}
+	p.CredentialHelper = from
+	err = p.EraseCredentials(fs)
+	p.CredentialHelper = to
+	return err
+}
+
+func (p *RpkProfile) secret(key string) *string {
+	s := p.KafkaAPI.SASL
+	if s == nil {
+		return nil
+	}
+	switch key {
+	case "kafka_api.sasl.password":
+		return &s.Password
+	case "kafka_api.sasl.oauth.client_secret":
+		if s.OAuth == nil {
+			return nil
+		}
+		return &s.OAuth.ClientSecret
+	}
+	return nil
+}
+
+// LoadCredentials fills in the client secret of the cloud auth from its
+// credential helper, if the secret is empty.
+func (a *RpkCloudAuth) LoadCredentials(fs afero.Fs) error {
+	if a.CredentialHelper == "" || a.ClientID == "" || a.ClientSecret != "" {
+		return nil
+	}
+	h, err := NewCredentialHelper(fs, a.CredentialHelper)
+	if err != nil {
+		return err
+	}
+	for _, key := range cloudAuthSecretKeys {
+		secret, found, err := h.Get(CredentialRequest{CloudAuth: a.Name, Key: key})
+		if err != nil {
+			return fmt.Errorf("unable to get %s of cloud auth %q from credential helper %q: %v", key, a.Name, a.CredentialHelper, err)
+		}
+		if found {
+			a.ClientSecret = secret
+		}
+	}
+	return nil
+}
+
+// StoreCredentials moves the client secret of the cloud auth into its
+// credential helper, clearing it so that it is not written to rpk.yaml. This
+// is a no-op if the cloud auth has no credential helper.
+func (a *RpkCloudAuth) StoreCredentials(fs afero.Fs) error {
+	if a.CredentialHelper == "" || a.ClientSecret == "" {
+		return nil
+	}
+	h, err := NewCredentialHelper(fs, a.CredentialHelper)
+	if err != nil {
+		return err
+	}
+	for _, key := range cloudAuthSecretKeys {
+		if err := h.Store(CredentialRequest{CloudAuth: a.Name, Key: key, Secret: a.ClientSecret}); err != nil {
+			return fmt.Errorf("unable to store %s of cloud auth %q in credential helper %q: %v", key, a.Name, a.CredentialHelper, err)
+		}
+	}
+	a.ClientSecret = ""
+	return nil
+}
+
+// RenameCredentials moves the secrets that the cloud auth's credential helper
+// keeps under the cloud auth name "from" to the current name of the cloud auth.
+func (a *RpkCloudAuth) RenameCredentials(fs afero.Fs, from string) error {
+	if a.CredentialHelper == "" || from == a.Name {
+		return nil
+	}
+	h, err := NewCredentialHelper(fs, a.CredentialHelper)
+	if err != nil {
+		return err
+	}
+	return renameCredentials(h, cloudAuthSecretKeys,
+		CredentialRequest{CloudAuth: from},
+		CredentialRequest{CloudAuth: a.Name},
+	)
+}
+
+// EraseCredentials erases the secrets of the cloud auth from its credential
+// helper. This must be called when a cloud auth is deleted.
+func (a *RpkCloudAuth) EraseCredentials(fs afero.Fs) error {
+	if a.CredentialHelper == "" {
+		return nil
+	}
+	h, err := NewCredentialHelper(fs, a.CredentialHelper)
+	if err != nil {
+		return err
+	}
+	for _, key := range cloudAuthSecretKeys {
+		if err := h.Erase(CredentialRequest{CloudAuth: a.Name, Key: key}); err != nil {
+			return fmt.Errorf("unable to erase %s of cloud auth %q from credential helper %q: %v", key, a.Name, a.CredentialHelper, err)
+		}
+	}
+	return nil
+}
+
+// SwitchCredentialHelper moves the client secret of the cloud auth from the
+// credential helper "from" to the cloud auth's current credential helper, or
+// back into the cloud auth if it no longer has one.
+func (a *RpkCloudAuth) SwitchCredentialHelper(fs afero.Fs, from string) error {
+	to := a.CredentialHelper
+	if from == "" || from == to {
+		return nil
+	}
+	a.CredentialHelper = from
+	err := a.LoadCredentials(fs)
+	a.CredentialHelper = to
+	if err != nil {
+		return err
+	}
+	if err := a.StoreCredentials(fs); err != nil {
+		return err
+	}
+	a.CredentialHelper = from
+	err = a.EraseCredentials(fs)
+	a.CredentialHelper = to
+	return err
+}
+
+// renameCredentials moves each key found in the helper from the old owner to
+// the new owner.
+func renameCredentials(h CredentialHelper, keys []string, from, to CredentialRequest) error {
+	for _, key := range keys {
+		from.Key, to.Key = key, key
+		secret, found, err := h.Get(from)
+		if err != nil {
+			return fmt.Errorf("unable to get %s: %v", key, err)
+		}
+		if !found {
+			continue
+		}
+		to.Secret = secret
+		if err := h.Store(to); err != nil {
+			return fmt.Errorf("unable to store %s: %v", key, err)
+		}
+		if err := h.Erase(from); err != nil {
+			return fmt.Errorf("unable to erase %s: %v", key, err)
+		}
+	}
+	return nil
+}
+
+//////////////////////
+// EXTERNAL HELPERS //
+//////////////////////
+
+type execHelper struct {
+	args []string
+}
+
+func (h *execHelper) run(action string, req CredentialRequest) ([]byte, error) {
+	// The protocol is line based: a newline in any value would be read by
+	// the helper as the start of another key=value pair.
+	for _, v := range []string{req.Profile, req.CloudAuth, req.Key, req.Secret} {
+		if strings.ContainsAny(v, "\r\n") {
+			return nil, errors.New("credential helper values cannot contain newlines")
+		}
+	}
+	var in bytes.Buffer
+	if req.Profile != "" {
+		fmt.Fprintf(&in, "profile=%s\n", req.Profile)
+	}
+	if req.CloudAuth != "" {
+		fmt.Fprintf(&in, "cloud_auth=%s\n", req.CloudAuth)
+	}
+	fmt.Fprintf(&in, "key=%s\n", req.Key)
+	if req.Secret != "" {
+		fmt.Fprintf(&in, "secret=%s\n", req.Secret)
+	}
+	in.WriteString("\n")
+
+	args := append(h.args[1:len(h.args):len(h.args)], action)
+	cmd := exec.Command(h.args[0], args...)
+	cmd.Stdin = &in
+	cmd.Stderr = os.Stderr // helpers may prompt or explain failures
+	return cmd.Output()
+}
+
+func (h *execHelper) Get(req CredentialRequest) (string, bool, error) {
+	out, err := h.run("get", req)
+	if err != nil {
+		return "", false, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+		if k, v, ok := strings.Cut(line, "="); ok && k == "secret" {
+			return v, true, nil
+		}
+	}
+	return "", false, scanner.Err()
+}
+
+func (h *execHelper) Store(req CredentialRequest) error {
+	_, err := h.run("store", req)
+	return err
+}
+
+func (h *execHelper) Erase(req CredentialRequest) error {
+	_, err := h.run("erase", req)
+	return err
+}
+
+/////////////////////
+// ENCRYPTED STORE //
+/////////////////////
+
+// encryptedStore keeps secrets in a file encrypted with AES-256-GCM, using a
+// key derived from a passphrase with scrypt.
+type encryptedStore struct {
+	fs         afero.Fs
+	path       string
+	passphrase func() (string, error) // defaults to readPassphrase
+
+	pass string // the passphrase, once read
+}
+
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// The scrypt parameters recommended for interactive logins.
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+)
+
+func (req CredentialRequest) storeKey() string {
+	if req.CloudAuth != "" {
+		return "cloud_auth/" + req.CloudAuth + "/" + req.Key
+	}
+	return "profile/" + req.Profile + "/" + req.Key
+}
+
+func (s *encryptedStore) Get(req CredentialRequest) (string, bool, error) {
+	secrets, err := s.read()
+	if err != nil {
+		return "", false, err
+	}
+	secret, ok := secrets[req.storeKey()]
+	return secret, ok, nil
+}
+
+func (s *encryptedStore) Store(req CredentialRequest) error {
+	secrets, err := s.read()
+	if err != nil {
+		return err
+	}
+	secrets[req.storeKey()] = req.Secret
+	return s.write(secrets)
+}
+
+func (s *encryptedStore) Erase(req CredentialRequest) error {
+	secrets, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[req.storeKey()]; !ok {
+		return nil
+	}
+	delete(secrets, req.storeKey())
+	return s.write(secrets)
+}
+
+func (s *encryptedStore) getPassphrase() (string, error) {
+	if s.pass != "" {
+		return s.pass, nil
+	}
+	read := s.passphrase
+	if read == nil {
+		read = readPassphrase
+	}
+	pass, err := read()
+	if err != nil {
+		return "", err
+	}
+	s.pass = pass
+	return pass, nil
+}
+
+func readPassphrase() (string, error) {
+	if p, ok := os.LookupEnv(EnvCredentialsPassphrase); ok {
+		return p, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("the encrypted credential store requires a passphrase; set %s", EnvCredentialsPassphrase)
+	}
+	fmt.Fprint(os.Stderr, "rpk credentials passphrase: ")
+	p, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("unable to read passphrase: %v", err)
+	}
+	return string(p), nil
+}
+
+func (s *encryptedStore) read() (map[string]string, error) {
+	secrets := make(map[string]string)
+	raw, err := afero.ReadFile(s.fs, s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return secrets, nil
+		}
+		return nil, fmt.Errorf("unable to read %s: %v", s.path, err)
+	}
+	var f encryptedFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %v", s.path, err)
+	}
+	if f.Version != 1 {
+		return nil, fmt.Errorf("unsupported version %d of %s", f.Version, s.path)
+	}
+	passphrase, err := s.getPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, f.Salt)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, f.Nonce, f.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt %s, is the passphrase correct?", s.path)
+	}
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, fmt.Errorf("unable to decode the decrypted %s: %v", s.path, err)
+	}
+	return secrets, nil
+}
+
+func (s *encryptedStore) write(secrets map[string]string) error {
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	passphrase, err := s.getPassphrase()
+	if err != nil {
+		return err
+	}
+	f := encryptedFile{Version: 1, Salt: make([]byte, 16)}
+	if _, err := io.ReadFull(rand.Reader, f.Salt); err != nil {
+		return err
+	}
+	gcm, err := newGCM(passphrase, f.Salt)
+	if err != nil {
+		return err
+	}
+	f.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, f.Nonce); err != nil {
+		return err
+	}
+	f.Ciphertext = gcm.Seal(nil, f.Nonce, plain, nil)
+	raw, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return rpkos.ReplaceFile(s.fs, s.path, raw, 0o600)
+}
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	if passphrase == "" {
+		return nil, errors.New("invalid empty passphrase")
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,183 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedStore(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s := &encryptedStore{fs: fs, path: "/creds.enc", passphrase: func() (string, error) { return "hunter2", nil }}
+
+	req := CredentialRequest{Profile: "prod", Key: "kafka_api.sasl.password"}
+	_, found, err := s.Get(req)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	req.Secret = "s3cret"
+	require.NoError(t, s.Store(req))
+
+	raw, err := afero.ReadFile(fs, "/creds.enc")
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), "s3cret")
+
+	// A new store reads the file back with the same passphrase.
+	s2 := &encryptedStore{fs: fs, path: "/creds.enc", passphrase: func() (string, error) { return "hunter2", nil }}
+	secret, found, err := s2.Get(CredentialRequest{Profile: "prod", Key: "kafka_api.sasl.password"})
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "s3cret", secret)
+
+	// Cloud auth secrets do not collide with profile secrets.
+	_, found, err = s2.Get(CredentialRequest{CloudAuth: "prod", Key: "kafka_api.sasl.password"})
+	require.NoError(t, err)
+	require.False(t, found)
+
+	require.NoError(t, s2.Erase(CredentialRequest{Profile: "prod", Key: "kafka_api.sasl.password"}))
+	_, found, err = s2.Get(CredentialRequest{Profile: "prod", Key: "kafka_api.sasl.password"})
+	require.NoError(t, err)
+	require.False(t, found)
+
+	bad := &encryptedStore{fs: fs, path: "/creds.enc", passphrase: func() (string, error) { return "wrong", nil }}
+	_, _, err = bad.Get(req)
+	require.Error(t, err)
+}
+
+func TestExecHelper(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "log")
+	script := filepath.Join(dir, "helper")
+	err := os.WriteFile(script, []byte(`#!/bin/sh
+cat >> `+log+`
+echo "action=$1" >> `+log+`
+if [ "$1" = get ]; then
+	echo secret=from-helper
+fi
+`), 0o755)
+	require.NoError(t, err)
+
+	fs := afero.NewMemMapFs()
+	p := RpkProfile{
+		Name:             "prod",
+		CredentialHelper: script,
+		KafkaAPI: RpkKafkaAPI{
+			SASL: &SASL{User: "user", Mechanism: "SCRAM-SHA-256"},
+		},
+	}
+	require.NoError(t, p.LoadCredentials(fs))
+	require.Equal(t, "from-helper", p.KafkaAPI.SASL.Password)
+
+	p.KafkaAPI.SASL.Password = "new"
+	require.NoError(t, p.StoreCredentials(fs))
+	require.Empty(t, p.KafkaAPI.SASL.Password)
+
+	raw, err := os.ReadFile(log)
+	require.NoError(t, err)
+	require.Equal(t, strings.Join([]string{
+		"profile=prod",
+		"key=kafka_api.sasl.password",
+		"",
+		"action=get",
+		"profile=prod",
+		"key=kafka_api.sasl.password",
+		"secret=new",
+		"",
+		"action=store",
+		"",
+	}, "\n"), string(raw))
+
+	// Secrets that are already set, e.g. with -X pass, win.
+	p.KafkaAPI.SASL.Password = "from-flag"
+	require.NoError(t, p.LoadCredentials(fs))
+	require.Equal(t, "from-flag", p.KafkaAPI.SASL.Password)
+}
+
+func TestCloudAuthCredentials(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	t.Setenv(EnvCredentialsPassphrase, "hunter2")
+	a := RpkCloudAuth{Name: "org", ClientID: "id", ClientSecret: "secret", CredentialHelper: CredentialHelperEncrypted}
+	require.NoError(t, a.StoreCredentials(fs))
+	require.Empty(t, a.ClientSecret)
+	require.True(t, a.HasClientCredentials())
+
+	require.NoError(t, a.LoadCredentials(fs))
+	require.Equal(t, "secret", a.ClientSecret)
+}
+
+func TestRenameAndEraseCredentials(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	t.Setenv(EnvCredentialsPassphrase, "hunter2")
+	p := RpkProfile{
+		Name:             "prod",
+		CredentialHelper: CredentialHelperEncrypted,
+		KafkaAPI: RpkKafkaAPI{
+			SASL: &SASL{User: "user", Password: "s3cret", Mechanism: "SCRAM-SHA-256"},
+		},
+	}
+	require.NoError(t, p.StoreCredentials(fs))
+
+	p.Name = "staging"
+	require.NoError(t, p.RenameCredentials(fs, "prod"))
+	require.NoError(t, p.LoadCredentials(fs))
+	require.Equal(t, "s3cret", p.KafkaAPI.SASL.Password)
+
+	old := RpkProfile{Name: "prod", CredentialHelper: CredentialHelperEncrypted, KafkaAPI: RpkKafkaAPI{SASL: &SASL{}}}
+	require.NoError(t, old.LoadCredentials(fs))
+	require.Empty(t, old.KafkaAPI.SASL.Password)
+
+	require.NoError(t, p.EraseCredentials(fs))
+	p.KafkaAPI.SASL.Password = ""
+	require.NoError(t, p.LoadCredentials(fs))
+	require.Empty(t, p.KafkaAPI.SASL.Password)
+}
+
+func TestSwitchCredentialHelper(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	t.Setenv(EnvCredentialsPassphrase, "hunter2")
+	p := RpkProfile{
+		Name:             "prod",
+		CredentialHelper: CredentialHelperEncrypted,
+		KafkaAPI: RpkKafkaAPI{
+			SASL: &SASL{User: "user", Password: "s3cret", Mechanism: "SCRAM-SHA-256"},
+		},
+	}
+	require.NoError(t, p.StoreCredentials(fs))
+
+	// Removing the helper moves the secret back into the profile and out
+	// of the old helper.
+	p.CredentialHelper = ""
+	require.NoError(t, p.SwitchCredentialHelper(fs, CredentialHelperEncrypted))
+	require.Equal(t, "s3cret", p.KafkaAPI.SASL.Password)
+	old := RpkProfile{Name: "prod", CredentialHelper: CredentialHelperEncrypted, KafkaAPI: RpkKafkaAPI{SASL: &SASL{}}}
+	require.NoError(t, old.LoadCredentials(fs))
+	require.Empty(t, old.KafkaAPI.SASL.Password)
+
+	a := RpkCloudAuth{Name: "org", ClientID: "id", ClientSecret: "secret", CredentialHelper: CredentialHelperEncrypted}
+	require.NoError(t, a.StoreCredentials(fs))
+	a.CredentialHelper = ""
+	require.NoError(t, a.SwitchCredentialHelper(fs, CredentialHelperEncrypted))
+	require.Equal(t, "secret", a.ClientSecret)
+	oldAuth := RpkCloudAuth{Name: "org", ClientID: "id", CredentialHelper: CredentialHelperEncrypted}
+	require.NoError(t, oldAuth.LoadCredentials(fs))
+	require.Empty(t, oldAuth.ClientSecret)
+}
+
+func TestExecHelperRejectsNewlines(t *testing.T) {
+	h := &execHelper{args: []string{"true"}}
+	err := h.Store(CredentialRequest{Profile: "prod", Key: "kafka_api.sasl.password", Secret: "a\nkey=other"})
+	require.Error(t, err)
+}
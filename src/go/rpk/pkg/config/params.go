@@ -108,6 +108,14 @@ func mkSASL(k *RpkKafkaAPI) *SASL {
 	return k.SASL
 }
 
+func mkSASLOAuth(k *RpkKafkaAPI) *SASLOAuth {
+	s := mkSASL(k)
+	if s.OAuth == nil {
+		s.OAuth = new(SASLOAuth)
+	}
+	return s.OAuth
+}
+
 func mkAdminTLS(a *RpkAdminAPI) *TLS {
 	if a.TLS == nil {
 		a.TLS = new(TLS)
@@ -196,6 +204,55 @@ var xflags = map[string]xflag{
 			return nil
 		},
 	},
+	"sasl.oauth.token_url": {
+		"kafka_api.sasl.oauth.token_url",
+		"https://auth.example.com/oauth/token",
+		xkindProfile,
+		func(v string, y *RpkYaml) error {
+			p := y.Profile(y.CurrentProfile)
+			mkSASLOAuth(&p.KafkaAPI).TokenURL = v
+			return nil
+		},
+	},
+	"sasl.oauth.client_id": {
+		"kafka_api.sasl.oauth.client_id",
+		"anystring",
+		xkindProfile,
+		func(v string, y *RpkYaml) error {
+			p := y.Profile(y.CurrentProfile)
+			mkSASLOAuth(&p.KafkaAPI).ClientID = v
+			return nil
+		},
+	},
+	"sasl.oauth.client_secret": {
+		"kafka_api.sasl.oauth.client_secret",
+		"anysecret",
+		xkindProfile,
+		func(v string, y *RpkYaml) error {
+			p := y.Profile(y.CurrentProfile)
+			mkSASLOAuth(&p.KafkaAPI).ClientSecret = v
+			return nil
+		},
+	},
+	"sasl.oauth.scopes": {
+		"kafka_api.sasl.oauth.scopes",
+		"openid,kafka",
+		xkindProfile,
+		func(v string, y *RpkYaml) error {
+			p := y.Profile(y.CurrentProfile)
+			return splitCommaIntoStrings(v, &mkSASLOAuth(&p.KafkaAPI).Scopes)
+		},
+	},
+	"sasl.oauth.audience": {
+		"kafka_api.sasl.oauth.audience",
+		"redpanda",
+		xkindProfile,
+		func(v string, y *RpkYaml) error {
+			p := y.Profile(y.CurrentProfile)
+			mkSASLOAuth(&p.KafkaAPI).Audience = v
+			return nil
+		},
+	},
 
 	xAdminHosts: {
 		"admin_api.addresses",
@@ -460,10 +517,12 @@ tls.key=/path/to/key.pem
   API listeners with mTLS.
 
 sasl.mechanism=SCRAM-SHA-256
-  The SASL mechanism to use for authentication. This can be either SCRAM-SHA-256
-  or SCRAM-SHA-512. Note that with Redpanda, the Admin API can be configured to
-  require basic authentication with your Kafka API SASL credentials. This
-  defaults to SCRAM-SHA-256 if no mechanism is specified.
+  The SASL mechanism to use for authentication. This can be SCRAM-SHA-256,
+  SCRAM-SHA-512, PLAIN, or OAUTHBEARER. Note that with Redpanda, the Admin API
+  can be configured to require basic authentication with your Kafka API SASL
+  credentials. This defaults to SCRAM-SHA-256 if no mechanism is specified.
+  With OAUTHBEARER, rpk requests a token from sasl.oauth.token_url and also
+  uses it as a bearer token for the Admin API.
 
 user=username
   The SASL username to use for authentication. This is also used for the admin
@@ -473,6 +532,24 @@ pass=password
   The SASL password to use for authentication. This is also used for the admin
   API if you have configured it to require basic authentication.
 
+sasl.oauth.token_url=https://auth.example.com/oauth/token
+  The OIDC token endpoint that rpk requests OAUTHBEARER tokens from, using the
+  OAuth client credentials flow. Tokens are cached and refreshed shortly before
+  they expire.
+
+sasl.oauth.client_id=somestring
+  The OAuth client ID to request OAUTHBEARER tokens with.
+
+sasl.oauth.client_secret=somelongerstring
+  The OAuth client secret to request OAUTHBEARER tokens with.
+
+sasl.oauth.scopes=openid,kafka
+  A comma separated list of scopes to request OAUTHBEARER tokens for.
+
+sasl.oauth.audience=redpanda
+  An optional audience to request OAUTHBEARER tokens for; some identity
+  providers require one.
+
 admin.hosts=localhost:9644,rp.example.com:9644
   A comma separated list of host:ports that rpk talks to for the Admin API.
   By default, this is 127.0.0.1:9644.
@@ -547,9 +624,14 @@ tls.enabled=boolean
 tls.ca=/path/to/ca.pem
 tls.cert=/path/to/cert.pem
 tls.key=/path/to/key.pem
-sasl.mechanism=SCRAM-SHA-256 or SCRAM-SHA-512
+sasl.mechanism=SCRAM-SHA-256, SCRAM-SHA-512, PLAIN, or OAUTHBEARER
 user=username
 pass=password
+sasl.oauth.token_url=https://auth.example.com/oauth/token
+sasl.oauth.client_id=somestring
+sasl.oauth.client_secret=somelongerstring
+sasl.oauth.scopes=comma,delimited,scopes
+sasl.oauth.audience=somestring
 admin.hosts=comma,delimited,host:ports
 admin.tls.enabled=boolean
 admin.tls.ca=/path/to/ca.pem
@@ -595,7 +677,7 @@ func (p *Params) InstallSASLFlags(cmd *cobra.Command) {
 
 	pf.StringVar(&p.user, FlagSASLUser, "", "SASL user to be used for authentication")
 	pf.StringVar(&p.password, "password", "", "SASL password to be used for authentication")
-	pf.StringVar(&p.saslMechanism, "sasl-mechanism", "", "The authentication mechanism to use (SCRAM-SHA-256, SCRAM-SHA-512)")
+	pf.StringVar(&p.saslMechanism, "sasl-mechanism", "", "The authentication mechanism to use (SCRAM-SHA-256, SCRAM-SHA-512, PLAIN, OAUTHBEARER)")
 
 	pf.MarkHidden(FlagSASLUser)
 	pf.MarkHidden("password")
@@ -900,7 +982,7 @@ func (*Params) backcompatOldCloudYaml(fs afero.Fs) error {
 		}
 		if rpkYaml.Version < 1 {
 			return fmt.Errorf("%s is not in the expected rpk.yaml format", def)
-		} else if rpkYaml.Version > 1 {
+		} else if rpkYaml.Version > rpkYamlVersion {
 			return fmt.Errorf("%s is using a newer rpk.yaml format than we understand, please upgrade rpk", def)
 		}
 	}
@@ -973,7 +1055,7 @@ func (p *Params) readRpkConfig(fs afero.Fs, c *Config) error {
 		}
 		c.rpkYaml = before // this config is not an rpk.yaml; preserve our defaults
 		return nil
-	} else if c.rpkYaml.Version > 1 {
+	} else if c.rpkYaml.Version > rpkYamlVersion {
 		return fmt.Errorf("%s is using a newer rpk.yaml format than we understand, please upgrade rpk", def)
 	}
 	yaml.Unmarshal(file, &c.rpkYamlActual)
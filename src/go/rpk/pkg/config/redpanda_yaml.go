@@ -185,9 +185,22 @@ type (
 	}
 
 	SASL struct {
-		User      string `yaml:"user,omitempty" json:"user,omitempty"`
-		Password  string `yaml:"password,omitempty" json:"password,omitempty"`
-		Mechanism string `yaml:"mechanism,omitempty" json:"mechanism,omitempty"`
+		User      string     `yaml:"user,omitempty" json:"user,omitempty"`
+		Password  string     `yaml:"password,omitempty" json:"password,omitempty"`
+		Mechanism string     `yaml:"mechanism,omitempty" json:"mechanism,omitempty"`
+		OAuth     *SASLOAuth `yaml:"oauth,omitempty" json:"oauth,omitempty"`
+	}
+
+	// SASLOAuth configures the OAuth 2.0 client credentials flow used to
+	// get tokens for the OAUTHBEARER mechanism, against any OIDC
+	// compliant token endpoint. The token is used for both the Kafka API
+	// and, as a bearer token, the Admin API.
+	SASLOAuth struct {
+		TokenURL     string   `yaml:"token_url,omitempty" json:"token_url,omitempty"`
+		ClientID     string   `yaml:"client_id,omitempty" json:"client_id,omitempty"`
+		ClientSecret string   `yaml:"client_secret,omitempty" json:"client_secret,omitempty"`
+		Scopes       []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+		Audience     string   `yaml:"audience,omitempty" json:"audience,omitempty"`
 	}
 )
 
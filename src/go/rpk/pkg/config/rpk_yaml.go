@@ -34,6 +34,12 @@ func DefaultRpkYamlPath() (string, error) {
 	return filepath.Join(configDir, "rpk", "rpk.yaml"), nil
 }
 
+// rpkYamlVersion is the newest version of the rpk.yaml format that we
+// understand. Version 2 added the kafka_api.sasl.oauth and credential_helper
+// fields. Files are only written as version 2 if they use these fields, so
+// that older rpk binaries can keep using the file otherwise.
+const rpkYamlVersion = 2
+
 func defaultVirtualRpkYaml() (RpkYaml, error) {
 	path, _ := DefaultRpkYamlPath() // if err is non-nil, we fail in Write
 	y := RpkYaml{
@@ -134,6 +140,11 @@ type (
 		KafkaAPI     RpkKafkaAPI      `yaml:"kafka_api,omitempty"`
 		AdminAPI     RpkAdminAPI      `yaml:"admin_api,omitempty"`
 
+		// CredentialHelper, if set, is the credential helper that
+		// stores the profile's secrets rather than rpk.yaml; see
+		// CredentialHelper.
+		CredentialHelper string `yaml:"credential_helper,omitempty"`
+
 		// We stash the config struct itself so that we can provide
 		// the logger / dev overrides.
 		c *Config
@@ -152,6 +163,10 @@ type (
 		RefreshToken string `yaml:"refresh_token,omitempty"`
 		ClientID     string `yaml:"client_id,omitempty"`
 		ClientSecret string `yaml:"client_secret,omitempty"`
+
+		// CredentialHelper, if set, is the credential helper that
+		// stores the client secret rather than rpk.yaml.
+		CredentialHelper string `yaml:"credential_helper,omitempty"`
 	}
 
 	Duration struct{ time.Duration }
@@ -224,7 +239,7 @@ const (
 // Kind returns either a known auth kind or "uninitialized".
 func (a *RpkCloudAuth) Kind() (CloudAuthKind, bool) {
 	switch {
-	case a.ClientID != "" && a.ClientSecret != "":
+	case a.ClientID != "" && (a.ClientSecret != "" || a.CredentialHelper != ""):
 		return CloudAuthClientCredentials, true
 	case a.ClientID != "":
 		return CloudAuthSSO, true
@@ -297,6 +312,7 @@ func (y *RpkYaml) Write(fs afero.Fs) error {
 	if y.isTheSameAsRawFile() {
 		return nil
 	}
+	y.Version = y.requiredVersion()
 	location := y.fileLocation
 	if location == "" {
 		def, err := DefaultRpkYamlPath()
@@ -308,6 +324,23 @@ func (y *RpkYaml) Write(fs afero.Fs) error {
 	return y.WriteAt(fs, location)
 }
 
+// requiredVersion returns the oldest rpk.yaml version that supports every
+// field in use.
+func (y *RpkYaml) requiredVersion() int {
+	for i := range y.Profiles {
+		p := &y.Profiles[i]
+		if p.CredentialHelper != "" || p.KafkaAPI.SASL != nil && p.KafkaAPI.SASL.OAuth != nil {
+			return 2
+		}
+	}
+	for i := range y.CloudAuths {
+		if y.CloudAuths[i].CredentialHelper != "" {
+			return 2
+		}
+	}
+	return 1
+}
+
 // WriteAt writes the configuration to the given path.
 func (y *RpkYaml) WriteAt(fs afero.Fs, path string) error {
 	b, err := yaml.Marshal(y)
@@ -16,6 +16,9 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRpkYamlVersion(t *testing.T) {
@@ -118,12 +121,32 @@ func TestRpkYamlVersion(t *testing.T) {
 	sha := sha256.Sum256([]byte(sb.String()))
 	shastr := hex.EncodeToString(sha[:])
 
-	const (
-		v1sha = "2ad0d7eee688f2a38a0ca126c919bbc5af2c42b6c848fa40947dfc6dd5a2fce3" // 23-06-08
-	)
+	const v2sha = "628c1da8a066acc90eee86ae38d8257157cf6e57890b6478b71db314baa2ce69" // 26-10-18
 
-	if shastr != v1sha {
-		t.Errorf("rpk.yaml type shape has changed (got sha %s != exp %s, if fields were reordered, update the valid v1 sha, otherwise bump the rpk.yaml version number", shastr, v1sha)
+	if shastr != v2sha {
+		t.Errorf("rpk.yaml type shape has changed (got sha %s != exp %s, if fields were reordered, update the valid v2 sha, otherwise bump the rpk.yaml version number", shastr, v2sha)
 		t.Errorf("current shape:\n%s\n", sb.String())
 	}
 }
+
+func TestRpkYamlWriteVersion(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		y    RpkYaml
+		exp  int
+	}{
+		{"no new fields", RpkYaml{Version: 2, Profiles: []RpkProfile{{Name: "foo", KafkaAPI: RpkKafkaAPI{SASL: &SASL{User: "u", Mechanism: "PLAIN"}}}}}, 1},
+		{"profile credential helper", RpkYaml{Version: 1, Profiles: []RpkProfile{{Name: "foo", CredentialHelper: CredentialHelperEncrypted}}}, 2},
+		{"oauth", RpkYaml{Version: 1, Profiles: []RpkProfile{{Name: "foo", KafkaAPI: RpkKafkaAPI{SASL: &SASL{Mechanism: "OAUTHBEARER", OAuth: &SASLOAuth{ClientID: "id"}}}}}}, 2},
+		{"cloud auth credential helper", RpkYaml{Version: 1, CloudAuths: []RpkCloudAuth{{Name: "foo", CredentialHelper: CredentialHelperEncrypted}}}, 2},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			test.y.fileLocation = "/rpk.yaml"
+			require.NoError(t, test.y.Write(fs))
+			raw, err := afero.ReadFile(fs, "/rpk.yaml")
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(string(raw), fmt.Sprintf("version: %d\n", test.exp)), string(raw))
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package schema validates redpanda.yaml and cluster configuration files
+// against Redpanda's configuration schema.
+package schema
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/adminapi"
+)
+
+// The embedded schemas have the same format as the response of the admin
+// API's /v1/cluster_config/schema endpoint, and are built from the property
+// definitions in src/v (config/configuration.cc, config/node_config.cc,
+// pandaproxy/rest/configuration.cc, pandaproxy/schema_registry/configuration.cc
+// and kafka/client/configuration.cc). Unlike the admin API, they include
+// deprecated properties so that we can report them.
+//
+//go:embed schemas/*.json
+var embedded embed.FS
+
+// Schemas are the configuration schemas of every configuration store of a
+// Redpanda broker.
+type Schemas struct {
+	// Cluster holds the cluster properties, which are set through the admin
+	// API or the bootstrap file.
+	Cluster adminapi.ConfigSchema
+	// Node holds the properties of the redpanda section of redpanda.yaml.
+	Node adminapi.ConfigSchema
+	// Pandaproxy holds the properties of the pandaproxy section.
+	Pandaproxy adminapi.ConfigSchema
+	// SchemaRegistry holds the properties of the schema_registry section.
+	SchemaRegistry adminapi.ConfigSchema
+	// KafkaClient holds the properties of the pandaproxy_client and
+	// schema_registry_client sections.
+	KafkaClient adminapi.ConfigSchema
+
+	// Source describes where the cluster schema came from.
+	Source string
+}
+
+// Embedded returns the schemas embedded in rpk.
+func Embedded() (*Schemas, error) {
+	s := &Schemas{Source: "embedded"}
+	for file, dst := range map[string]*adminapi.ConfigSchema{
+		"cluster":         &s.Cluster,
+		"node":            &s.Node,
+		"pandaproxy":      &s.Pandaproxy,
+		"schema_registry": &s.SchemaRegistry,
+		"kafka_client":    &s.KafkaClient,
+	} {
+		raw, err := embedded.ReadFile("schemas/" + file + ".json")
+		if err != nil {
+			return nil, fmt.Errorf("unable to read embedded %s schema: %v", file, err)
+		}
+		var resp adminapi.ConfigSchemaResponse
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			return nil, fmt.Errorf("unable to decode embedded %s schema: %v", file, err)
+		}
+		*dst = resp.Properties
+	}
+	return s, nil
+}
+
+// WithClusterSchema replaces the cluster schema with one queried from a
+// broker. The broker does not report deprecated properties, so we keep the
+// deprecated properties from the current schema to still report them as such.
+func (s *Schemas) WithClusterSchema(cluster adminapi.ConfigSchema, source string) *Schemas {
+	merged := make(adminapi.ConfigSchema, len(cluster))
+	for k, v := range s.Cluster {
+		if v.Visibility == visibilityDeprecated {
+			merged[k] = v
+		}
+	}
+	for k, v := range cluster {
+		merged[k] = v
+	}
+	cp := *s
+	cp.Cluster = merged
+	cp.Source = source
+	return &cp
+}
@@ -0,0 +1,397 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/adminapi"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+const visibilityDeprecated = "deprecated"
+
+// Severity is the severity of a Finding.
+type Severity string
+
+const (
+	// SeverityError is used for problems that prevent Redpanda from
+	// starting or from using the value as intended.
+	SeverityError Severity = "error"
+	// SeverityWarning is used for values that are accepted but likely not
+	// what the user intended, e.g. deprecated or ignored properties.
+	SeverityWarning Severity = "warning"
+)
+
+// Finding is a single problem found in a configuration file.
+type Finding struct {
+	Path     string
+	Severity Severity
+	Message  string
+}
+
+// Findings are the problems found in a configuration file, sorted by path.
+type Findings []Finding
+
+// HasErrors returns whether any finding is an error.
+func (fs Findings) HasErrors() bool {
+	for _, f := range fs {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func (fs Findings) sorted() Findings {
+	sort.SliceStable(fs, func(i, j int) bool { return fs[i].Path < fs[j].Path })
+	return fs
+}
+
+type validator struct {
+	findings Findings
+}
+
+func (v *validator) errorf(path, msg string, args ...interface{}) {
+	v.findings = append(v.findings, Finding{path, SeverityError, fmt.Sprintf(msg, args...)})
+}
+
+func (v *validator) warnf(path, msg string, args ...interface{}) {
+	v.findings = append(v.findings, Finding{path, SeverityWarning, fmt.Sprintf(msg, args...)})
+}
+
+// ValidateRedpandaYaml validates the raw contents of a redpanda.yaml.
+//
+// Properties in the redpanda section are checked against the node schema.
+// Cluster properties in that section are checked against the cluster schema
+// and reported as warnings, since Redpanda only reads them when creating a
+// new cluster: they should be set with 'rpk cluster config' or in the
+// bootstrap file. The pandaproxy, schema_registry and client sections are
+// checked against their own schemas, and the rpk section against the fields
+// rpk understands.
+func (s *Schemas) ValidateRedpandaYaml(raw []byte) (Findings, error) {
+	var m map[string]interface{}
+	if err := yaml.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("unable to decode yaml: %v", err)
+	}
+	v := new(validator)
+	for key, val := range m {
+		switch key {
+		case "redpanda":
+			v.section(key, val, func(k string, val interface{}) {
+				if meta, ok := s.Node[k]; ok {
+					v.property(key+"."+k, meta, val)
+					return
+				}
+				if name, meta, ok := lookup(s.Cluster, k); ok {
+					path := key + "." + k
+					if meta.Visibility == visibilityDeprecated {
+						v.warnf(path, "%q is a deprecated cluster property and is ignored", name)
+						return
+					}
+					v.warnf(path, "%q is a cluster property, which is only read from redpanda.yaml when a new cluster is created; use 'rpk cluster config set' or the bootstrap file instead", name)
+					v.property(path, meta, val)
+					return
+				}
+				v.unknown(key+"."+k, k, s.Node, s.Cluster)
+			})
+		case "pandaproxy":
+			v.schemaSection(key, val, s.Pandaproxy)
+		case "schema_registry":
+			v.schemaSection(key, val, s.SchemaRegistry)
+		case "pandaproxy_client", "schema_registry_client":
+			v.schemaSection(key, val, s.KafkaClient)
+		case "rpk":
+			v.rpkSection(key, val)
+		default:
+			v.warnf(key, "unknown top level section %q is ignored", key)
+		}
+	}
+	return v.findings.sorted(), nil
+}
+
+// ValidateClusterConfig validates the raw contents of a cluster configuration
+// file, such as the .bootstrap.yaml or a file exported by
+// 'rpk cluster config export', against the cluster schema.
+func (s *Schemas) ValidateClusterConfig(raw []byte) (Findings, error) {
+	var m map[string]interface{}
+	if err := yaml.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("unable to decode yaml: %v", err)
+	}
+	v := new(validator)
+	v.schemaMap("", m, s.Cluster)
+	return v.findings.sorted(), nil
+}
+
+func (v *validator) section(path string, val interface{}, fn func(string, interface{})) {
+	if val == nil {
+		return
+	}
+	m, ok := val.(map[string]interface{})
+	if !ok {
+		v.errorf(path, "expected a map, got %s", describe(val))
+		return
+	}
+	for k, val := range m {
+		fn(k, val)
+	}
+}
+
+func (v *validator) schemaSection(path string, val interface{}, schema adminapi.ConfigSchema) {
+	v.section(path, val, func(k string, val interface{}) {
+		v.schemaProperty(path+"."+k, k, val, schema)
+	})
+}
+
+func (v *validator) schemaMap(prefix string, m map[string]interface{}, schema adminapi.ConfigSchema) {
+	for k, val := range m {
+		v.schemaProperty(prefix+k, k, val, schema)
+	}
+}
+
+func (v *validator) schemaProperty(path, key string, val interface{}, schema adminapi.ConfigSchema) {
+	if _, meta, ok := lookup(schema, key); ok {
+		v.property(path, meta, val)
+		return
+	}
+	v.unknown(path, key, schema)
+}
+
+func (v *validator) unknown(path, key string, schemas ...adminapi.ConfigSchema) {
+	var names []string
+	for _, s := range schemas {
+		for name := range s {
+			names = append(names, name)
+		}
+	}
+	if suggestion := closest(key, names); suggestion != "" {
+		v.errorf(path, "unknown property %q, did you mean %q?", key, suggestion)
+		return
+	}
+	v.errorf(path, "unknown property %q", key)
+}
+
+func (v *validator) rpkSection(path string, val interface{}) {
+	known := yamlKeys(reflect.TypeOf(config.RpkNodeConfig{}))
+	v.section(path, val, func(k string, val interface{}) {
+		p := path + "." + k
+		switch {
+		case k == "tls" || k == "sasl":
+			v.warnf(p, "%q is deprecated, use %q instead", p, path+".kafka_api."+k)
+		case known[k]:
+		default:
+			names := make([]string, 0, len(known))
+			for name := range known {
+				names = append(names, name)
+			}
+			if suggestion := closest(k, names); suggestion != "" {
+				v.errorf(p, "unknown property %q, did you mean %q?", k, suggestion)
+				return
+			}
+			v.errorf(p, "unknown property %q", k)
+		}
+	})
+}
+
+// property checks the value of a single property against its metadata.
+func (v *validator) property(path string, meta adminapi.ConfigPropertyMetadata, val interface{}) {
+	if meta.Visibility == visibilityDeprecated {
+		v.warnf(path, "property is deprecated and is ignored")
+		return
+	}
+	if val == nil {
+		if !meta.Nullable {
+			v.errorf(path, "property cannot be null")
+		}
+		return
+	}
+	if meta.Type == "array" {
+		list, ok := val.([]interface{})
+		if !ok {
+			// A single non-primitive item is accepted in place of a
+			// list, see the yaml decoding of e.g. kafka_api.
+			if _, isMap := val.(map[string]interface{}); isMap && !isPrimitive(meta.Items.Type) {
+				return
+			}
+			v.errorf(path, "expected a list, got %s", describe(val))
+			return
+		}
+		for i, item := range list {
+			if err := checkType(meta.Items.Type, nil, item); err != "" {
+				v.errorf(fmt.Sprintf("%s[%d]", path, i), "%s", err)
+			}
+		}
+		return
+	}
+	if err := checkType(meta.Type, meta.EnumValues, val); err != "" {
+		v.errorf(path, "%s", err)
+	}
+}
+
+func isPrimitive(typ string) bool {
+	switch typ {
+	case "boolean", "integer", "number", "string":
+		return true
+	}
+	return false
+}
+
+// checkType returns a description of why val is not of the given type, or
+// an empty string if it is. Like Redpanda, strings holding a valid value are
+// accepted for booleans and numbers. Non-swagger types are not checked.
+func checkType(typ string, enum []string, val interface{}) string {
+	switch typ {
+	case "boolean":
+		switch t := val.(type) {
+		case bool:
+			return ""
+		case string:
+			switch strings.ToLower(t) {
+			case "true", "false", "yes", "no", "on", "off", "1", "0":
+				return ""
+			}
+		}
+		return fmt.Sprintf("expected a boolean, got %s", describe(val))
+	case "integer":
+		switch t := val.(type) {
+		case int, int64, uint64:
+			return ""
+		case float64:
+			if t == float64(int64(t)) {
+				return ""
+			}
+		case string:
+			if _, err := strconv.ParseInt(t, 10, 64); err == nil {
+				return ""
+			}
+		}
+		return fmt.Sprintf("expected an integer, got %s", describe(val))
+	case "number":
+		switch t := val.(type) {
+		case int, int64, uint64, float64:
+			return ""
+		case string:
+			if _, err := strconv.ParseFloat(t, 64); err == nil {
+				return ""
+			}
+		}
+		return fmt.Sprintf("expected a number, got %s", describe(val))
+	case "string":
+		switch val.(type) {
+		case map[string]interface{}, []interface{}:
+			return fmt.Sprintf("expected a string, got %s", describe(val))
+		}
+		if len(enum) > 0 {
+			s := fmt.Sprint(val)
+			for _, e := range enum {
+				if s == e {
+					return ""
+				}
+			}
+			return fmt.Sprintf("invalid value %q, must be one of: %s", s, strings.Join(enum, ", "))
+		}
+	}
+	return ""
+}
+
+func describe(val interface{}) string {
+	switch t := val.(type) {
+	case map[string]interface{}:
+		return "a map"
+	case []interface{}:
+		return "a list"
+	case string:
+		return fmt.Sprintf("%q", t)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// lookup returns the property with the given name or alias.
+func lookup(schema adminapi.ConfigSchema, key string) (string, adminapi.ConfigPropertyMetadata, bool) {
+	if meta, ok := schema[key]; ok {
+		return key, meta, true
+	}
+	for name, meta := range schema {
+		for _, alias := range meta.Aliases {
+			if alias == key {
+				return name, meta, true
+			}
+		}
+	}
+	return "", adminapi.ConfigPropertyMetadata{}, false
+}
+
+// yamlKeys returns the yaml keys of a struct, including inlined structs.
+func yamlKeys(t reflect.Type) map[string]bool {
+	keys := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if strings.Contains(opts, "inline") && f.Type.Kind() == reflect.Struct {
+			for k := range yamlKeys(f.Type) {
+				keys[k] = true
+			}
+			continue
+		}
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// closest returns the name closest to key, if it is close enough to likely
+// be a typo.
+func closest(key string, names []string) string {
+	sort.Strings(names) // deterministic on ties
+	best, bestDist := "", len(key)/3+1
+	for _, name := range names {
+		if d := levenshtein(key, name); d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
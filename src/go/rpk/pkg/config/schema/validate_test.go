@@ -0,0 +1,173 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/adminapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbedded(t *testing.T) {
+	s, err := Embedded()
+	require.NoError(t, err)
+	require.Contains(t, s.Node, "node_id")
+	require.Contains(t, s.Cluster, "log_segment_size")
+	require.Contains(t, s.Pandaproxy, "pandaproxy_api")
+	require.Contains(t, s.SchemaRegistry, "schema_registry_api")
+	require.Contains(t, s.KafkaClient, "brokers")
+}
+
+func TestValidateRedpandaYaml(t *testing.T) {
+	s, err := Embedded()
+	require.NoError(t, err)
+	for _, test := range []struct {
+		name   string
+		yaml   string
+		exp    Findings
+		expErr bool
+	}{
+		{
+			name: "valid",
+			yaml: `redpanda:
+  data_directory: /var/lib/redpanda/data
+  node_id: 1
+  developer_mode: true
+  seed_servers: []
+  kafka_api:
+    address: 0.0.0.0
+    port: 9092
+rpk:
+  tune_network: true
+  kafka_api:
+    brokers: [127.0.0.1:9092]
+pandaproxy:
+  pandaproxy_api:
+    - address: 0.0.0.0
+      port: 8082
+pandaproxy_client:
+  brokers:
+    - address: 127.0.0.1
+      port: 9092
+`,
+		},
+		{
+			name: "null node_id is allowed",
+			yaml: "redpanda:\n  node_id: null\n",
+		},
+		{
+			name: "wrong types",
+			yaml: `redpanda:
+  developer_mode: maybe
+  node_id: one
+  data_directory: [a, b]
+  seed_servers: foo
+`,
+			exp: Findings{
+				{"redpanda.data_directory", SeverityError, "expected a string, got a list"},
+				{"redpanda.developer_mode", SeverityError, `expected a boolean, got "maybe"`},
+				{"redpanda.node_id", SeverityError, `expected an integer, got "one"`},
+				{"redpanda.seed_servers", SeverityError, `expected a list, got "foo"`},
+			},
+		},
+		{
+			name: "typos and unknown sections",
+			yaml: `redpanda:
+  developer_mod: true
+  totally_unknown_property: 1
+rpk:
+  tune_netwrk: true
+  tls: {}
+foo: bar
+`,
+			exp: Findings{
+				{"foo", SeverityWarning, `unknown top level section "foo" is ignored`},
+				{"redpanda.developer_mod", SeverityError, `unknown property "developer_mod", did you mean "developer_mode"?`},
+				{"redpanda.totally_unknown_property", SeverityError, `unknown property "totally_unknown_property"`},
+				{"rpk.tls", SeverityWarning, `"rpk.tls" is deprecated, use "rpk.kafka_api.tls" instead`},
+				{"rpk.tune_netwrk", SeverityError, `unknown property "tune_netwrk", did you mean "tune_network"?`},
+			},
+		},
+		{
+			name: "cluster properties in the redpanda section",
+			yaml: `redpanda:
+  auto_create_topics_enabled: true
+  log_segment_size: big
+  coproc_max_batch_size: 10
+  cloud_storage_graceful_transfer_timeout: 10
+`,
+			exp: Findings{
+				{"redpanda.auto_create_topics_enabled", SeverityWarning, `"auto_create_topics_enabled" is a cluster property, which is only read from redpanda.yaml when a new cluster is created; use 'rpk cluster config set' or the bootstrap file instead`},
+				{"redpanda.cloud_storage_graceful_transfer_timeout", SeverityWarning, `"cloud_storage_graceful_transfer_timeout_ms" is a cluster property, which is only read from redpanda.yaml when a new cluster is created; use 'rpk cluster config set' or the bootstrap file instead`},
+				{"redpanda.coproc_max_batch_size", SeverityWarning, `"coproc_max_batch_size" is a deprecated cluster property and is ignored`},
+				{"redpanda.log_segment_size", SeverityWarning, `"log_segment_size" is a cluster property, which is only read from redpanda.yaml when a new cluster is created; use 'rpk cluster config set' or the bootstrap file instead`},
+				{"redpanda.log_segment_size", SeverityError, `expected an integer, got "big"`},
+			},
+		},
+		{
+			name: "section is not a map",
+			yaml: "redpanda: foo\n",
+			exp: Findings{
+				{"redpanda", SeverityError, `expected a map, got "foo"`},
+			},
+		},
+		{
+			name:   "invalid yaml",
+			yaml:   "redpanda: [",
+			expErr: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			findings, err := s.ValidateRedpandaYaml([]byte(test.yaml))
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.exp, findings)
+			require.Equal(t, test.exp.HasErrors(), findings.HasErrors())
+		})
+	}
+}
+
+func TestValidateClusterConfig(t *testing.T) {
+	s, err := Embedded()
+	require.NoError(t, err)
+	findings, err := s.ValidateClusterConfig([]byte(`
+auto_create_topics_enabled: "true"
+log_segment_size: 1073741824
+cloud_storage_backend: nope
+coproc_max_batch_size: 10
+superusers: [admin, 1]
+cloud_storage_bucket: null
+`))
+	require.NoError(t, err)
+	require.Equal(t, Findings{
+		{"cloud_storage_backend", SeverityError, `invalid value "nope", must be one of: aws, google_s3_compat, azure, minio, unknown`},
+		{"coproc_max_batch_size", SeverityWarning, "property is deprecated and is ignored"},
+	}, findings)
+	require.True(t, findings.HasErrors())
+
+	// A schema from a broker does not include deprecated properties, which
+	// we keep from the embedded schema.
+	fromBroker := s.WithClusterSchema(adminapi.ConfigSchema{
+		"new_property": {Type: "boolean"},
+	}, "broker")
+	require.Equal(t, "broker", fromBroker.Source)
+	findings, err = fromBroker.ValidateClusterConfig([]byte("new_property: 3\ncoproc_max_batch_size: 1\nlog_segment_size: 1\n"))
+	require.NoError(t, err)
+	require.Equal(t, Findings{
+		{"coproc_max_batch_size", SeverityWarning, "property is deprecated and is ignored"},
+		{"log_segment_size", SeverityError, `unknown property "log_segment_size"`},
+		{"new_property", SeverityError, "expected a boolean, got 3"},
+	}, findings)
+	require.True(t, findings.HasErrors())
+}
@@ -694,6 +694,7 @@ func (s *SASL) UnmarshalYAML(n *yaml.Node) error {
 		Password  weakString `yaml:"password"`
 		Mechanism weakString `yaml:"mechanism"`
 		Type      weakString `yaml:"type"` // BACKCOMPAT 23-05-24 we deserialize type into mechanism
+		OAuth     *SASLOAuth `yaml:"oauth"`
 	}
 	if err := n.Decode(&internal); err != nil {
 		return err
@@ -704,6 +705,26 @@ func (s *SASL) UnmarshalYAML(n *yaml.Node) error {
 	if internal.Mechanism != "" {
 		s.Mechanism = string(internal.Mechanism)
 	}
+	s.OAuth = internal.OAuth
 
 	return nil
 }
+
+func (o *SASLOAuth) UnmarshalYAML(n *yaml.Node) error {
+	var internal struct {
+		TokenURL     weakString      `yaml:"token_url"`
+		ClientID     weakString      `yaml:"client_id"`
+		ClientSecret weakString      `yaml:"client_secret"`
+		Scopes       weakStringArray `yaml:"scopes"`
+		Audience     weakString      `yaml:"audience"`
+	}
+	if err := n.Decode(&internal); err != nil {
+		return err
+	}
+	o.TokenURL = string(internal.TokenURL)
+	o.ClientID = string(internal.ClientID)
+	o.ClientSecret = string(internal.ClientSecret)
+	o.Scopes = internal.Scopes
+	o.Audience = string(internal.Audience)
+	return nil
+}
@@ -10,6 +10,7 @@
 package kafka
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -21,12 +22,14 @@ import (
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/oauth"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/oauth/providers/auth0"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/oauth/providers/oidc"
 	"github.com/spf13/afero"
 	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kgo"
 	"github.com/twmb/franz-go/pkg/kmsg"
 	koauth "github.com/twmb/franz-go/pkg/sasl/oauth"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
 	"github.com/twmb/franz-go/pkg/sasl/scram"
 	"github.com/twmb/franz-go/plugin/kzap"
 )
@@ -129,8 +132,22 @@ func NewFranzClient(fs afero.Fs, p *config.RpkProfile, extraOpts ...kgo.Opt) (*k
 				opts = append(opts, kgo.SASL(a.AsSha256Mechanism()))
 			case "SCRAM-SHA-512":
 				opts = append(opts, kgo.SASL(a.AsSha512Mechanism()))
+			case adminapi.Plain:
+				opts = append(opts, kgo.SASL((plain.Auth{
+					User: k.SASL.User,
+					Pass: k.SASL.Password,
+				}).AsMechanism()))
+			case adminapi.OAuthBearer:
+				source, err := oidc.NewTokenSource(k.SASL.OAuth)
+				if err != nil {
+					return nil, err
+				}
+				opts = append(opts, kgo.SASL(koauth.Oauth(func(ctx context.Context) (koauth.Auth, error) {
+					token, err := source.Token(ctx)
+					return koauth.Auth{Token: token}, err
+				})))
 			default:
-				return nil, fmt.Errorf("unknown SASL mechanism %q, supported: [SCRAM-SHA-256, SCRAM-SHA-512]", name)
+				return nil, fmt.Errorf("unknown SASL mechanism %q, supported: [SCRAM-SHA-256, SCRAM-SHA-512, PLAIN, OAUTHBEARER]", name)
 			}
 		}
 	}
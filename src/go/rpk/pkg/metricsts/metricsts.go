@@ -0,0 +1,132 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package metricsts reads and writes the compact metrics time series files
+// that 'rpk debug bundle' captures and 'rpk debug metrics-replay' serves.
+//
+// A time series file is a gzip stream that starts with a version line,
+// followed by scrapes. Each scrape is a header line followed by the raw
+// Prometheus text exposition that the broker returned:
+//
+//	# rpk metrics time series v1
+//	# scrape <unix millis> <instance> <endpoint> <body length>
+//	<body>
+//
+// Consecutive scrapes of the same broker are nearly identical, so the gzip
+// stream stays small even for long captures.
+package metricsts
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const header = "# rpk metrics time series v1\n"
+
+// Endpoints that scrapes are taken from.
+const (
+	EndpointMetrics       = "metrics"
+	EndpointPublicMetrics = "public_metrics"
+)
+
+// Scrape is a single scrape of a metrics endpoint of a broker.
+type Scrape struct {
+	Time     time.Time
+	Instance string // the broker's admin API address
+	Endpoint string // EndpointMetrics or EndpointPublicMetrics
+	Body     []byte // Prometheus text exposition format
+}
+
+// Writer writes scrapes to a time series file.
+type Writer struct {
+	gz *gzip.Writer
+}
+
+// NewWriter returns a writer that writes a time series file to w. The file is
+// complete once the writer is closed.
+func NewWriter(w io.Writer) (*Writer, error) {
+	gz, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(gz, header); err != nil {
+		return nil, err
+	}
+	return &Writer{gz: gz}, nil
+}
+
+// Write writes a scrape.
+func (w *Writer) Write(s Scrape) error {
+	if strings.ContainsAny(s.Instance, " \n") || strings.ContainsAny(s.Endpoint, " \n") {
+		return fmt.Errorf("invalid instance %q or endpoint %q: must not contain spaces", s.Instance, s.Endpoint)
+	}
+	if _, err := fmt.Fprintf(w.gz, "# scrape %d %s %s %d\n", s.Time.UnixMilli(), s.Instance, s.Endpoint, len(s.Body)); err != nil {
+		return err
+	}
+	_, err := w.gz.Write(s.Body)
+	return err
+}
+
+// Close flushes and finishes the file, but does not close the underlying
+// writer.
+func (w *Writer) Close() error { return w.gz.Close() }
+
+// Read reads every scrape in a time series file, sorted by time.
+func Read(r io.Reader) ([]Scrape, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a metrics time series file: %v", err)
+	}
+	defer gz.Close()
+	br := bufio.NewReader(gz)
+
+	line, err := br.ReadString('\n')
+	if err != nil || line != header {
+		return nil, errors.New("not a metrics time series file: missing version header")
+	}
+
+	var scrapes []Scrape
+	for {
+		line, err := br.ReadString('\n')
+		if err == io.EOF && line == "" {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read scrape header: %v", err)
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 6 || fields[0] != "#" || fields[1] != "scrape" {
+			return nil, fmt.Errorf("invalid scrape header %q", strings.TrimSpace(line))
+		}
+		millis, err1 := strconv.ParseInt(fields[2], 10, 64)
+		size, err2 := strconv.Atoi(fields[5])
+		if err1 != nil || err2 != nil || size < 0 {
+			return nil, fmt.Errorf("invalid scrape header %q", strings.TrimSpace(line))
+		}
+		body := make([]byte, size)
+		if _, err := io.ReadFull(br, body); err != nil {
+			return nil, fmt.Errorf("unable to read scrape body: %v", err)
+		}
+		scrapes = append(scrapes, Scrape{
+			Time:     time.UnixMilli(millis),
+			Instance: fields[3],
+			Endpoint: fields[4],
+			Body:     body,
+		})
+	}
+	sort.SliceStable(scrapes, func(i, j int) bool { return scrapes[i].Time.Before(scrapes[j].Time) })
+	return scrapes, nil
+}
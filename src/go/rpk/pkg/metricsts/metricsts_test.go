@@ -0,0 +1,66 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package metricsts
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRead(t *testing.T) {
+	t0 := time.UnixMilli(1686000000000)
+	scrapes := []Scrape{
+		{Time: t0.Add(time.Second), Instance: "rp-0:9644", Endpoint: EndpointMetrics, Body: []byte("a 1\n")},
+		{Time: t0, Instance: "rp-0:9644", Endpoint: EndpointPublicMetrics, Body: []byte("# HELP b b\n# TYPE b gauge\nb 2\n")},
+		{Time: t0, Instance: "rp-1:9644", Endpoint: EndpointMetrics, Body: nil},
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf)
+	require.NoError(t, err)
+	for _, s := range scrapes {
+		require.NoError(t, w.Write(s))
+	}
+	require.Error(t, w.Write(Scrape{Instance: "has space"}))
+	require.NoError(t, w.Close())
+
+	got, err := Read(&buf)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	// Sorted by time, stable for equal times.
+	require.Equal(t, "rp-0:9644", got[0].Instance)
+	require.Equal(t, EndpointPublicMetrics, got[0].Endpoint)
+	require.Equal(t, "rp-1:9644", got[1].Instance)
+	require.Empty(t, got[1].Body)
+	require.Equal(t, []byte("a 1\n"), got[2].Body)
+	require.True(t, got[2].Time.Equal(t0.Add(time.Second)))
+}
+
+func TestReadInvalid(t *testing.T) {
+	_, err := Read(bytes.NewReader([]byte("not gzip")))
+	require.Error(t, err)
+
+	for _, body := range []string{
+		"wrong header\n",
+		header + "# scrape 1 rp-0 metrics\n",
+		header + "# scrape 1 rp-0 metrics 10\nshort",
+	} {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write([]byte(body))
+		gz.Close()
+		_, err := Read(&buf)
+		require.Error(t, err, "body %q", body)
+	}
+}
@@ -25,6 +25,9 @@ func LoadFlow(ctx context.Context, fs afero.Fs, cfg *config.Config, cl Client) (
 
 	yVir := cfg.VirtualRpkYaml()
 	authVir := yVir.Auth(yVir.CurrentCloudAuth) // must exist
+	if err := authVir.LoadCredentials(fs); err != nil {
+		return "", err
+	}
 
 	var resp Token
 	if authVir.HasClientCredentials() {
@@ -0,0 +1,142 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package oidc implements the OAuth 2.0 client credentials flow against any
+// OIDC compliant token endpoint, which is used to get tokens for the SASL
+// OAUTHBEARER mechanism.
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/httpapi"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/oauth"
+)
+
+const (
+	// refreshBefore is how long before a token expires that we request a
+	// new one, so that a token does not expire while a request is in
+	// flight.
+	refreshBefore = 30 * time.Second
+
+	// unknownLifetime is how long we use a token for if neither the token
+	// response nor the token itself says when it expires.
+	unknownLifetime = time.Minute
+)
+
+// TokenSource requests tokens with the client credentials flow and caches
+// them until shortly before they expire. It is safe for concurrent use.
+type TokenSource struct {
+	cfg    config.SASLOAuth
+	httpCl *httpapi.Client
+	now    func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time
+}
+
+var (
+	sourcesMu sync.Mutex
+	sources   = make(map[string]*TokenSource)
+)
+
+// NewTokenSource returns a token source for the given configuration. Token
+// sources are shared per configuration within the process, so the Kafka API
+// and Admin API clients of a command reuse the same token.
+func NewTokenSource(cfg *config.SASLOAuth) (*TokenSource, error) {
+	if cfg == nil || cfg.TokenURL == "" {
+		return nil, errors.New("OAUTHBEARER requires an OAuth token URL, which can be set with -X sasl.oauth.token_url")
+	}
+	if cfg.ClientID == "" {
+		return nil, errors.New("OAUTHBEARER requires an OAuth client ID, which can be set with -X sasl.oauth.client_id")
+	}
+	key := strings.Join([]string{cfg.TokenURL, cfg.ClientID, cfg.ClientSecret, strings.Join(cfg.Scopes, " "), cfg.Audience}, "\x00")
+
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	if s, ok := sources[key]; ok {
+		return s, nil
+	}
+	s := newTokenSource(cfg)
+	sources[key] = s
+	return s, nil
+}
+
+func newTokenSource(cfg *config.SASLOAuth) *TokenSource {
+	return &TokenSource{
+		cfg: *cfg,
+		httpCl: httpapi.NewClient(
+			httpapi.Err4xx(func(code int) error { return &oauth.TokenResponseError{Code: code} }),
+		),
+		now: time.Now,
+	}
+}
+
+// Token returns a cached token, or requests a new one if there is no cached
+// token or it is about to expire.
+func (s *TokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.token != "" && now.Before(s.refreshAt) {
+		return s.token, nil
+	}
+
+	form := httpapi.Values(
+		"grant_type", "client_credentials",
+		"client_id", s.cfg.ClientID,
+		"client_secret", s.cfg.ClientSecret,
+	)
+	if len(s.cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.cfg.Scopes, " "))
+	}
+	if s.cfg.Audience != "" {
+		form.Set("audience", s.cfg.Audience)
+	}
+
+	var token oauth.Token
+	if err := s.httpCl.PostForm(ctx, s.cfg.TokenURL, nil, form, &token); err != nil {
+		return "", fmt.Errorf("unable to get an OAuth token from %s: %w", s.cfg.TokenURL, err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("the OAuth token response from %s did not contain an access token", s.cfg.TokenURL)
+	}
+
+	s.token = token.AccessToken
+	s.refreshAt = refreshAt(now, token)
+	return s.token, nil
+}
+
+// refreshAt returns when a token received at now should be refreshed, based on
+// the expires_in of the response or, if missing, the exp claim of the token.
+func refreshAt(now time.Time, token oauth.Token) time.Time {
+	var lifetime time.Duration
+	if token.ExpiresIn > 0 {
+		lifetime = time.Duration(token.ExpiresIn) * time.Second
+	} else if parsed, err := jwt.Parse([]byte(token.AccessToken)); err == nil && !parsed.Expiration().IsZero() {
+		lifetime = parsed.Expiration().Sub(now)
+	} else {
+		lifetime = unknownLifetime
+	}
+	// Short lived tokens are refreshed halfway through their lifetime.
+	before := refreshBefore
+	if half := lifetime / 2; half < before {
+		before = half
+	}
+	return now.Add(lifetime - before)
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/oauth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenSource(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		require.Equal(t, "id", r.PostForm.Get("client_id"))
+		require.Equal(t, "secret", r.PostForm.Get("client_secret"))
+		require.Equal(t, "openid kafka", r.PostForm.Get("scope"))
+		require.Equal(t, "redpanda", r.PostForm.Get("audience"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":300}`, requests)
+	}))
+	defer ts.Close()
+
+	s := newTokenSource(&config.SASLOAuth{
+		TokenURL:     ts.URL,
+		ClientID:     "id",
+		ClientSecret: "secret",
+		Scopes:       []string{"openid", "kafka"},
+		Audience:     "redpanda",
+	})
+	now := time.Unix(1000, 0)
+	s.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	tok, err := s.Token(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "token-1", tok)
+
+	// The token is cached until shortly before it expires.
+	now = now.Add(4 * time.Minute)
+	tok, err = s.Token(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "token-1", tok)
+
+	now = now.Add(31 * time.Second)
+	tok, err = s.Token(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "token-2", tok)
+	require.Equal(t, 2, requests)
+}
+
+func TestTokenSourceError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":"invalid_client","error_description":"bad secret"}`)
+	}))
+	defer ts.Close()
+
+	s := newTokenSource(&config.SASLOAuth{TokenURL: ts.URL, ClientID: "id"})
+	_, err := s.Token(context.Background())
+	var tokErr *oauth.TokenResponseError
+	require.ErrorAs(t, err, &tokErr)
+	require.Equal(t, "invalid_client: bad secret", tokErr.Error())
+}
+
+func TestNewTokenSource(t *testing.T) {
+	_, err := NewTokenSource(nil)
+	require.Error(t, err)
+	_, err = NewTokenSource(&config.SASLOAuth{TokenURL: "https://example.com"})
+	require.Error(t, err)
+
+	cfg := &config.SASLOAuth{TokenURL: "https://example.com", ClientID: "id"}
+	s1, err := NewTokenSource(cfg)
+	require.NoError(t, err)
+	s2, err := NewTokenSource(&config.SASLOAuth{TokenURL: "https://example.com", ClientID: "id"})
+	require.NoError(t, err)
+	require.Same(t, s1, s2, "token sources should be shared per configuration")
+}
+
+func TestRefreshAt(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	tok := jwt.New()
+	tok.Set(jwt.ExpirationKey, now.Add(time.Hour).Unix())
+	signed, err := jwt.Sign(tok, jwa.HS256, []byte("key"))
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		name  string
+		token oauth.Token
+		exp   time.Time
+	}{
+		{"expires_in", oauth.Token{AccessToken: "opaque", ExpiresIn: 600}, now.Add(570 * time.Second)},
+		{"short lived", oauth.Token{AccessToken: "opaque", ExpiresIn: 20}, now.Add(10 * time.Second)},
+		{"jwt exp", oauth.Token{AccessToken: string(signed)}, now.Add(time.Hour - 30*time.Second)},
+		{"unknown", oauth.Token{AccessToken: "opaque"}, now.Add(30 * time.Second)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.exp, refreshAt(now, test.token))
+		})
+	}
+}
@@ -11,12 +11,17 @@ package plugin
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"runtime"
 	"sort"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -56,27 +61,32 @@ var client = &http.Client{Timeout: 30 * time.Second}
 // to parse the manifest. For the latter, the user's rpk must be out of date,
 // and the returned error indicates they should update.
 func DownloadManifest(url string) (*Manifest, error) {
-	req, err := http.NewRequestWithContext(
-		context.Background(),
-		http.MethodGet,
-		url,
-		nil,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("unable to create manifest request: %v", err)
-	}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("unable to request manifest: %v", err)
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+	return DownloadSignedManifest(url, nil)
+}
+
+// DownloadSignedManifest downloads a plugin manifest at the given URL, and if
+// any keys are given, verifies the manifest's detached signature against them
+// before parsing it.
+//
+// The signature is an ed25519 signature of the raw manifest, base64 encoded at
+// the manifest URL plus ".sig". Since the manifest contains the sha256 sums of
+// every plugin binary, a verified manifest transitively verifies the plugins
+// downloaded through it.
+func DownloadSignedManifest(url string, keys []ed25519.PublicKey) (*Manifest, error) {
+	body, err := get(url)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read manifest body: %v", err)
+		return nil, fmt.Errorf("unable to download manifest: %v", err)
 	}
-	if resp.StatusCode/100 != 2 {
-		return nil, fmt.Errorf("unsuccessful manifest response %s: %q", http.StatusText(resp.StatusCode), body)
+	if len(keys) > 0 {
+		sig, err := get(url + ".sig")
+		if err != nil {
+			return nil, fmt.Errorf("unable to download manifest signature: %v", err)
+		}
+		if err := VerifyManifest(body, sig, keys); err != nil {
+			return nil, err
+		}
 	}
+
 	var m Manifest
 	if err := yaml.Unmarshal(body, &m); err != nil {
 		return nil, fmt.Errorf("unable to decode manifest body: %v", err)
@@ -94,6 +104,71 @@ func DownloadManifest(url string) (*Manifest, error) {
 	return &m, nil
 }
 
+// VerifyManifest verifies that sig, a base64 encoded ed25519 signature, is a
+// signature of the manifest by any of the keys.
+func VerifyManifest(manifest, sig []byte, keys []ed25519.PublicKey) error {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return fmt.Errorf("unable to decode manifest signature: %v", err)
+	}
+	for _, key := range keys {
+		if ed25519.Verify(key, manifest, raw) {
+			return nil
+		}
+	}
+	return errors.New("the plugin manifest signature does not match any trusted key; refusing to use the manifest")
+}
+
+// ParseManifestKey parses an ed25519 public key used to verify manifests,
+// either PEM encoded in PKIX form, or the base64 encoding of the raw 32 byte
+// key.
+func ParseManifestKey(s string) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode([]byte(s)); block != nil {
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse PEM public key: %v", err)
+		}
+		key, ok := pub.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("public key is a %T, not an ed25519 key", pub)
+		}
+		return key, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("public key is neither PEM nor base64: %v", err)
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid ed25519 public key length %d, expected %d", len(raw), ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+func get(url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(
+		context.Background(),
+		http.MethodGet,
+		url,
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to issue request: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read response body: %v", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("unsuccessful response %s: %q", http.StatusText(resp.StatusCode), body)
+	}
+	return body, nil
+}
+
 // ManifestPlugin is an entry of a plugin available to install.
 type ManifestPlugin struct {
 	// Name is the name of a plugin.
@@ -103,6 +178,10 @@ type ManifestPlugin struct {
 	// for output to a console.
 	Description string `yaml:"description"`
 
+	// Version is an optional, informational version of the plugin. The
+	// sha256 sums in OSArchShas remain what identifies a plugin binary.
+	Version string `yaml:"version,omitempty"`
+
 	// Path is the base request path of the plugin, such as
 	// plugins/vectorized/cloud.
 	Path string `yaml:"path"`
@@ -12,8 +12,13 @@ package plugin
 import (
 	"bytes"
 	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
 	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/hex"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"net/http"
@@ -130,6 +135,91 @@ plugins:
 	}
 }
 
+func TestDownloadSignedManifest(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	const manifest = `
+api_version: 2021-07-27
+plugins:
+  - name: internal
+    version: v1.2.3
+`
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(manifest))) + "\n"
+
+	for _, test := range []struct {
+		name    string
+		serve   string
+		sig     string
+		keys    []ed25519.PublicKey
+		expErr  bool
+		expVers string
+	}{
+		{name: "valid signature", serve: manifest, sig: sig, keys: []ed25519.PublicKey{pub}, expVers: "v1.2.3"},
+		{name: "any key matches", serve: manifest, sig: sig, keys: []ed25519.PublicKey{otherPub, pub}, expVers: "v1.2.3"},
+		{name: "no keys skips verification", serve: manifest, expVers: "v1.2.3"},
+		{name: "untrusted key", serve: manifest, sig: sig, keys: []ed25519.PublicKey{otherPub}, expErr: true},
+		{name: "tampered manifest", serve: manifest + "  - name: evil\n", sig: sig, keys: []ed25519.PublicKey{pub}, expErr: true},
+		{name: "missing signature", serve: manifest, keys: []ed25519.PublicKey{pub}, expErr: true},
+		{name: "garbage signature", serve: manifest, sig: "not base64!", keys: []ed25519.PublicKey{pub}, expErr: true},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/manifest.yaml":
+					io.WriteString(w, test.serve)
+				case "/manifest.yaml.sig":
+					if test.sig == "" {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					io.WriteString(w, test.sig)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer svr.Close()
+
+			m, err := DownloadSignedManifest(svr.URL+"/manifest.yaml", test.keys)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, m.Plugins, 1)
+			require.Equal(t, test.expVers, m.Plugins[0].Version)
+		})
+	}
+}
+
+func TestParseManifestKey(t *testing.T) {
+	t.Parallel()
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	pemKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	for _, in := range []string{pemKey, base64.StdEncoding.EncodeToString(pub)} {
+		got, err := ParseManifestKey(in)
+		require.NoError(t, err)
+		require.Equal(t, pub, got)
+	}
+
+	for _, in := range []string{"", "not a key", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		_, err := ParseManifestKey(in)
+		require.Error(t, err, "input %q", in)
+	}
+}
+
 func TestManifestPluginDownload(t *testing.T) {
 	t.Parallel()
 
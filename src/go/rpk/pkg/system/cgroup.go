@@ -33,15 +33,63 @@ func ReadCgroupMemLimitBytes(fs afero.Fs) (uint64, error) {
 }
 
 func ReadCgroupEffectiveCpusNo(fs afero.Fs) (uint64, error) {
-	cpuList, err := readCgroupFile(
+	cpuList, err := ReadCgroupEffectiveCpus(fs)
+	if err != nil {
+		return 0, err
+	}
+	return calculateEffectiveCpus(cpuList)
+}
+
+// ReadCgroupEffectiveCpus returns the cgroup's effective cpuset, in
+// cpuset(7) list format.
+func ReadCgroupEffectiveCpus(fs afero.Fs) (string, error) {
+	return readCgroupFile(
 		fs,
 		"/cpuset/cpuset.effective_cpus",
 		"/cpuset.cpus.effective",
 	)
+}
+
+// ReadCgroupCPUQuota returns the number of CPUs that the cgroup's CFS
+// bandwidth limit allows (quota / period), or 0 if there is no limit.
+func ReadCgroupCPUQuota(fs afero.Fs) (float64, error) {
+	v2CgroupPath, err := v2CgroupPath(fs)
 	if err != nil {
 		return 0, err
 	}
-	return calculateEffectiveCpus(cpuList)
+	var quota, period string
+	if v2CgroupPath != "" {
+		// cpu.max holds "$MAX $PERIOD", where $MAX may be "max".
+		val, err := readCgroupFile(fs, "", "/cpu.max")
+		if err != nil {
+			return 0, err
+		}
+		fields := strings.Fields(val)
+		if len(fields) != 2 {
+			return 0, fmt.Errorf("invalid cpu.max value %q", val)
+		}
+		quota, period = fields[0], fields[1]
+	} else {
+		if quota, err = readCgroupFile(fs, "/cpu/cpu.cfs_quota_us", ""); err != nil {
+			return 0, err
+		}
+		if period, err = readCgroupFile(fs, "/cpu/cpu.cfs_period_us", ""); err != nil {
+			return 0, err
+		}
+	}
+	quota, period = strings.TrimSpace(quota), strings.TrimSpace(period)
+	if quota == "max" || quota == "-1" {
+		return 0, nil
+	}
+	q, err := strconv.ParseUint(quota, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CPU quota %q: %v", quota, err)
+	}
+	p, err := strconv.ParseUint(period, 10, 64)
+	if err != nil || p == 0 {
+		return 0, fmt.Errorf("invalid CPU period %q", period)
+	}
+	return float64(q) / float64(p), nil
 }
 
 func readUintCgroupsProp(
@@ -271,3 +271,116 @@ func TestReadCgroupEmptyCgroup(t *testing.T) {
 		assert.EqualError(t, err, "no cgroup data found for the current process")
 	}
 }
+
+func TestReadCgroupCPUQuota(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		cgroupsV2   bool
+		expected    float64
+		expectedErr string
+	}{
+		{
+			name: "it should read the quota (v1)",
+			files: map[string]string{
+				"/cpu/cpu.cfs_quota_us":  "250000",
+				"/cpu/cpu.cfs_period_us": "100000",
+			},
+			expected: 2.5,
+		},
+		{
+			name: "it should return 0 if there is no quota (v1)",
+			files: map[string]string{
+				"/cpu/cpu.cfs_quota_us":  "-1",
+				"/cpu/cpu.cfs_period_us": "100000",
+			},
+		},
+		{
+			name:      "it should read the quota (v2)",
+			files:     map[string]string{"/redpanda.slice/redpanda.service/cpu.max": "400000 100000"},
+			cgroupsV2: true,
+			expected:  4,
+		},
+		{
+			name:      "it should return 0 if there is no quota (v2)",
+			files:     map[string]string{"/redpanda.slice/redpanda.service/cpu.max": "max 100000"},
+			cgroupsV2: true,
+		},
+		{
+			name:        "it should fail if cpu.max is malformed (v2)",
+			files:       map[string]string{"/redpanda.slice/redpanda.service/cpu.max": "max"},
+			cgroupsV2:   true,
+			expectedErr: `invalid cpu.max value "max"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			for file, val := range tt.files {
+				err := setUpCgroup(fs, file, val, tt.cgroupsV2)
+				assert.NoError(t, err)
+			}
+			quota, err := system.ReadCgroupCPUQuota(fs)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, quota)
+		})
+	}
+}
+
+func TestResources(t *testing.T) {
+	tests := []struct {
+		name       string
+		resources  system.Resources
+		cpus       uint64
+		cpuLimited bool
+		memory     uint64
+		memLimited bool
+	}{
+		{
+			name:      "no cgroup limits",
+			resources: system.Resources{HostCPUs: 16, HostMemory: 64 << 30, CgroupMemLimit: math.MaxUint64},
+			cpus:      16,
+			memory:    64 << 30,
+		},
+		{
+			name:      "cpuset and memory limit",
+			resources: system.Resources{HostCPUs: 16, CgroupCPUSet: "0-3", CgroupCPUs: 4, HostMemory: 64 << 30, CgroupMemLimit: 8 << 30},
+			cpus:      4,
+			memory:    8 << 30,
+
+			memLimited: true,
+		},
+		{
+			name:       "fractional quota rounds down",
+			resources:  system.Resources{HostCPUs: 16, CgroupCPUs: 8, CPUQuota: 2.5, HostMemory: 64 << 30},
+			cpus:       2,
+			cpuLimited: true,
+			memory:     64 << 30,
+		},
+		{
+			name:       "quota below one CPU",
+			resources:  system.Resources{HostCPUs: 4, CPUQuota: 0.5, HostMemory: 64 << 30},
+			cpus:       1,
+			cpuLimited: true,
+			memory:     64 << 30,
+		},
+		{
+			name:      "quota above the cpuset",
+			resources: system.Resources{HostCPUs: 16, CgroupCPUs: 2, CPUQuota: 4, HostMemory: 64 << 30},
+			cpus:      2,
+			memory:    64 << 30,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.cpus, tt.resources.CPUs())
+			assert.Equal(t, tt.cpuLimited, tt.resources.CPUQuotaLimited())
+			assert.Equal(t, tt.memory, tt.resources.Memory())
+			assert.Equal(t, tt.memLimited, tt.resources.MemoryLimited())
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package system
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/afero"
+	"go.uber.org/zap"
+)
+
+// Resources are the CPUs and memory available to the current process, as
+// seen by the host and as restricted by the process' cgroup (cgroup v1 or v2,
+// e.g. a systemd slice or a Docker container).
+type Resources struct {
+	// HostCPUs is the number of online CPUs in the host.
+	HostCPUs uint64
+	// CgroupCPUSet is the cgroup's effective cpuset, empty if unknown.
+	CgroupCPUSet string
+	// CgroupCPUs is the number of CPUs in CgroupCPUSet, 0 if unknown.
+	CgroupCPUs uint64
+	// CPUQuota is the number of CPUs allowed by the cgroup's CFS bandwidth
+	// limit, 0 if there is no limit.
+	CPUQuota float64
+
+	// HostMemory is the total memory of the host, in bytes.
+	HostMemory uint64
+	// CgroupMemLimit is the cgroup's memory limit in bytes, 0 if unknown.
+	CgroupMemLimit uint64
+}
+
+// ReadResources reads the host and cgroup resources. Cgroup values that can't
+// be read are left empty, since not every system has (every) cgroup
+// controller mounted.
+func ReadResources(fs afero.Fs) (*Resources, error) {
+	r := new(Resources)
+	online, err := afero.ReadFile(fs, "/sys/devices/system/cpu/online")
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the online CPUs: %v", err)
+	}
+	if r.HostCPUs, err = calculateEffectiveCpus(strings.TrimSpace(string(online))); err != nil {
+		return nil, fmt.Errorf("unable to read the online CPUs: %v", err)
+	}
+	mInfo, err := getMemInfo(fs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the host memory: %v", err)
+	}
+	r.HostMemory = mInfo.MemTotal
+	r.CgroupMemLimit = mInfo.CGroupMemLimit
+
+	if cpuset, err := ReadCgroupEffectiveCpus(fs); err != nil {
+		zap.L().Sugar().Debugf("Unable to read the cgroup cpuset: %v", err)
+	} else if n, err := calculateEffectiveCpus(cpuset); err != nil {
+		zap.L().Sugar().Debugf("Unable to parse the cgroup cpuset: %v", err)
+	} else {
+		r.CgroupCPUSet, r.CgroupCPUs = cpuset, n
+	}
+	if r.CPUQuota, err = ReadCgroupCPUQuota(fs); err != nil {
+		zap.L().Sugar().Debugf("Unable to read the cgroup CPU quota: %v", err)
+	}
+	return r, nil
+}
+
+// CPUs returns the number of CPUs the process can fully use: the cgroup
+// cpuset (or host CPUs) capped by the CPU quota, rounded down but at least 1.
+func (r *Resources) CPUs() uint64 {
+	cpus := r.HostCPUs
+	if r.CgroupCPUs > 0 && r.CgroupCPUs < cpus {
+		cpus = r.CgroupCPUs
+	}
+	if r.CPUQuota > 0 {
+		quota := uint64(math.Max(1, math.Floor(r.CPUQuota)))
+		if quota < cpus {
+			cpus = quota
+		}
+	}
+	return cpus
+}
+
+// CPUQuotaLimited returns whether the CPU quota allows fewer CPUs than the
+// cpuset the process can be scheduled on.
+func (r *Resources) CPUQuotaLimited() bool {
+	cpus := r.HostCPUs
+	if r.CgroupCPUs > 0 && r.CgroupCPUs < cpus {
+		cpus = r.CgroupCPUs
+	}
+	return r.CPUs() < cpus
+}
+
+// Memory returns the memory available to the process: the cgroup memory
+// limit if it is lower than the host memory, or the host memory.
+func (r *Resources) Memory() uint64 {
+	if r.MemoryLimited() {
+		return r.CgroupMemLimit
+	}
+	return r.HostMemory
+}
+
+// MemoryLimited returns whether the cgroup memory limit is lower than the
+// host memory. An unlimited cgroup reports either "max" or a huge value.
+func (r *Resources) MemoryLimited() bool {
+	return r.CgroupMemLimit > 0 && r.CgroupMemLimit < r.HostMemory
+}
+
+// String returns a human readable summary of the effective resources.
+func (r *Resources) String() string {
+	cpu := []string{fmt.Sprintf("host: %d", r.HostCPUs)}
+	if r.CgroupCPUSet != "" {
+		cpu = append(cpu, fmt.Sprintf("cgroup cpuset: %s", r.CgroupCPUSet))
+	}
+	if r.CPUQuota > 0 {
+		cpu = append(cpu, fmt.Sprintf("cgroup CPU quota: %.2f", r.CPUQuota))
+	}
+	mem := []string{fmt.Sprintf("host: %s", units.BytesSize(float64(r.HostMemory)))}
+	if r.MemoryLimited() {
+		mem = append(mem, fmt.Sprintf("cgroup limit: %s", units.BytesSize(float64(r.CgroupMemLimit))))
+	}
+	return fmt.Sprintf(
+		"%d CPUs (%s), %s memory (%s)",
+		r.CPUs(), strings.Join(cpu, ", "),
+		units.BytesSize(float64(r.Memory())), strings.Join(mem, ", "),
+	)
+}
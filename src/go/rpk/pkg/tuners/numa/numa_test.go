@@ -0,0 +1,182 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package numa
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/hwloc"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHwLoc struct {
+	hwloc.HwLoc
+	intersections map[string][]uint
+}
+
+func (f *fakeHwLoc) GetPhysIntersection(first, second string) ([]uint, error) {
+	v, ok := f.intersections[first+" "+second]
+	if !ok {
+		return nil, fmt.Errorf("unexpected intersection %s %s", first, second)
+	}
+	return v, nil
+}
+
+func TestCPUList(t *testing.T) {
+	for _, test := range []struct {
+		in     string
+		cpus   []uint
+		out    string
+		expErr bool
+	}{
+		{in: "0", cpus: []uint{0}, out: "0"},
+		{in: "0-3,8,10-11", cpus: []uint{0, 1, 2, 3, 8, 10, 11}, out: "0-3,8,10-11"},
+		{in: "4,0-1", cpus: []uint{4, 0, 1}, out: "0-1,4"},
+		{in: "", cpus: nil, out: ""},
+		{in: "3-1", expErr: true},
+		{in: "a", expErr: true},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			cpus, err := ParseCPUList(test.in)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.cpus, cpus)
+			require.Equal(t, test.out, FormatCPUList(cpus))
+		})
+	}
+}
+
+func TestReadTopology(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	for cpu, siblings := range map[int]string{0: "0,2", 1: "1,3", 2: "0,2", 3: "1,3", 4: "4,6", 5: "5,7", 6: "4,6", 7: "5,7"} {
+		afero.WriteFile(fs, fmt.Sprintf("/sys/devices/system/cpu/cpu%d/topology/thread_siblings_list", cpu), []byte(siblings+"\n"), 0o644)
+	}
+	afero.WriteFile(fs, "/sys/devices/system/node/node0/meminfo", []byte("Node 0 MemTotal:       8388608 kB\nNode 0 MemFree:        1024 kB\n"), 0o644)
+	afero.WriteFile(fs, "/sys/devices/system/node/node1/meminfo", []byte("Node 1 MemTotal:       4194304 kB\n"), 0o644)
+
+	hw := &fakeHwLoc{intersections: map[string][]uint{
+		"numanode all":  {0, 1},
+		"PU numanode:0": {0, 1, 2, 3},
+		"PU numanode:1": {4, 5, 6, 7},
+	}}
+	topo, err := ReadTopology(fs, hw)
+	require.NoError(t, err)
+	require.Equal(t, &Topology{Nodes: []Node{
+		{ID: 0, Cores: [][]uint{{0, 2}, {1, 3}}, Memory: 8 << 30},
+		{ID: 1, Cores: [][]uint{{4, 6}, {5, 7}}, Memory: 4 << 30},
+	}}, topo)
+	require.Equal(t, []uint{0, 1, 2, 3}, topo.Nodes[0].CPUs())
+}
+
+func TestDeviceNode(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/sys/devices/pci0000:00/0000:00:1d.0/0000:3d:00.0/numa_node", []byte("1\n"), 0o644)
+	afero.WriteFile(fs, "/sys/class/net/eth0/device/numa_node", []byte("0\n"), 0o644)
+
+	node, err := DeviceNode(fs, "/sys/devices/pci0000:00/0000:00:1d.0/0000:3d:00.0/nvme/nvme0/nvme0n1")
+	require.NoError(t, err)
+	require.Equal(t, 1, node)
+
+	node, err = DeviceNode(fs, "/sys/devices/virtual/block/loop0")
+	require.NoError(t, err)
+	require.Equal(t, -1, node)
+
+	node, err = NICNode(fs, "eth0")
+	require.NoError(t, err)
+	require.Equal(t, 0, node)
+
+	node, err = NICNode(fs, "lo")
+	require.NoError(t, err)
+	require.Equal(t, -1, node)
+}
+
+func TestPropose(t *testing.T) {
+	topo := &Topology{Nodes: []Node{
+		{ID: 0, Cores: [][]uint{{0, 4}, {1, 5}, {2, 6}, {3, 7}}, Memory: 64 << 30},
+		{ID: 1, Cores: [][]uint{{8, 12}, {9, 13}, {10, 14}, {11, 15}}, Memory: 16 << 30},
+	}}
+	for _, test := range []struct {
+		name     string
+		devices  map[string]int
+		irqCores uint
+		reserve  uint64
+
+		exp    *Placement
+		expErr bool
+	}{
+		{
+			name:     "devices on node 1",
+			devices:  map[string]int{"eth0": 1, "nvme0n1": 1, "nvme1n1": 0},
+			irqCores: 1,
+			exp: &Placement{
+				Node:    1,
+				Devices: map[string]int{"eth0": 1, "nvme0n1": 1, "nvme1n1": 0},
+				CPUSet:  []uint{9, 10, 11, 13, 14, 15},
+				IRQCPUs: []uint{8, 12},
+				Memory:  16<<30 - minReservedMemory,
+			},
+		},
+		{
+			name:     "unknown devices, default to node 0 with percent reservation",
+			devices:  map[string]int{"eth0": -1},
+			irqCores: 2,
+			exp: &Placement{
+				Node:    0,
+				Devices: map[string]int{"eth0": -1},
+				CPUSet:  []uint{2, 3, 6, 7},
+				IRQCPUs: []uint{0, 1, 4, 5},
+				Memory:  64<<30 - 64<<30*7/100,
+			},
+		},
+		{
+			name:    "explicit memory reservation, no IRQ cores",
+			devices: map[string]int{"eth0": 0},
+			reserve: 4 << 30,
+			exp: &Placement{
+				Node:    0,
+				Devices: map[string]int{"eth0": 0},
+				CPUSet:  []uint{0, 1, 2, 3, 4, 5, 6, 7},
+				Memory:  60 << 30,
+			},
+		},
+		{
+			name:     "too many IRQ cores",
+			devices:  map[string]int{"eth0": 0},
+			irqCores: 4,
+			expErr:   true,
+		},
+		{
+			name:    "too much reserved memory",
+			devices: map[string]int{"eth0": 1},
+			reserve: 16 << 30,
+			expErr:  true,
+		},
+		{
+			name:    "unknown node",
+			devices: map[string]int{"eth0": 3},
+			expErr:  true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := Propose(topo, test.devices, test.irqCores, test.reserve)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.exp, p)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package numa
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Seastar reserves max(1.5GiB, 7% of the memory) for the OS when --memory is
+// not given; we follow the same rule when no reservation is requested.
+const (
+	minReservedMemory     = 1536 << 20
+	reservedMemoryPercent = 7
+)
+
+// Placement is a proposed placement of Redpanda in a single NUMA node.
+type Placement struct {
+	// Node is the NUMA node chosen for Redpanda.
+	Node uint
+	// Devices maps every inspected device (NICs and disks) to the NUMA node
+	// it is attached to, -1 if unknown.
+	Devices map[string]int
+	// CPUSet are the PUs that Redpanda should run on.
+	CPUSet []uint
+	// IRQCPUs are the PUs of the node that are left for IRQ handling.
+	IRQCPUs []uint
+	// Memory is the memory Redpanda should use, in bytes.
+	Memory uint64
+}
+
+// CPUSetFlag returns the value for Redpanda's --cpuset flag.
+func (p *Placement) CPUSetFlag() string {
+	return FormatCPUList(p.CPUSet)
+}
+
+// MemoryFlag returns the value for Redpanda's --memory flag, in MiB.
+func (p *Placement) MemoryFlag() string {
+	return fmt.Sprintf("%dM", p.Memory>>20)
+}
+
+// Propose chooses the NUMA node that most of the given devices are attached
+// to (devices with an unknown node are ignored; ties are broken in favor of
+// the node with more cores, then the lowest ID). The first irqCores physical
+// cores of the node are reserved for IRQs and the rest are proposed as the
+// cpuset. The proposed memory is the node memory minus reserveMemory, or
+// minus the Seastar default reservation if reserveMemory is 0.
+func Propose(t *Topology, devices map[string]int, irqCores uint, reserveMemory uint64) (*Placement, error) {
+	if t == nil || len(t.Nodes) == 0 {
+		return nil, errors.New("no NUMA nodes found")
+	}
+	votes := make(map[uint]int)
+	for dev, node := range devices {
+		if node < 0 {
+			continue
+		}
+		if t.Node(uint(node)) == nil {
+			return nil, fmt.Errorf("device %q reports NUMA node %d, which does not exist", dev, node)
+		}
+		votes[uint(node)]++
+	}
+
+	nodes := make([]Node, len(t.Nodes))
+	copy(nodes, t.Nodes)
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if votes[a.ID] != votes[b.ID] {
+			return votes[a.ID] > votes[b.ID]
+		}
+		if len(a.Cores) != len(b.Cores) {
+			return len(a.Cores) > len(b.Cores)
+		}
+		return a.ID < b.ID
+	})
+	node := nodes[0]
+
+	if int(irqCores) >= len(node.Cores) {
+		return nil, fmt.Errorf("NUMA node %d has %d cores, unable to reserve %d of them for IRQs", node.ID, len(node.Cores), irqCores)
+	}
+	p := &Placement{
+		Node:    node.ID,
+		Devices: devices,
+	}
+	for i, core := range node.Cores {
+		if i < int(irqCores) {
+			p.IRQCPUs = append(p.IRQCPUs, core...)
+		} else {
+			p.CPUSet = append(p.CPUSet, core...)
+		}
+	}
+	sort.Slice(p.CPUSet, func(i, j int) bool { return p.CPUSet[i] < p.CPUSet[j] })
+	sort.Slice(p.IRQCPUs, func(i, j int) bool { return p.IRQCPUs[i] < p.IRQCPUs[j] })
+
+	reserve := reserveMemory
+	if reserve == 0 {
+		reserve = node.Memory * reservedMemoryPercent / 100
+		if reserve < minReservedMemory {
+			reserve = minReservedMemory
+		}
+	}
+	if reserve >= node.Memory {
+		return nil, fmt.Errorf("NUMA node %d has %d bytes of memory, unable to reserve %d bytes for the OS", node.ID, node.Memory, reserve)
+	}
+	p.Memory = node.Memory - reserve
+	return p, nil
+}
@@ -0,0 +1,245 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package numa inspects the NUMA topology of the host and proposes a
+// placement (cpuset and memory) for Redpanda that keeps it local to the
+// devices it uses.
+package numa
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/hwloc"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/utils"
+	"github.com/spf13/afero"
+	"go.uber.org/zap"
+)
+
+const (
+	sysNodePath = "/sys/devices/system/node"
+	sysCPUPath  = "/sys/devices/system/cpu"
+	sysNetPath  = "/sys/class/net"
+)
+
+// Node is a single NUMA node.
+type Node struct {
+	ID uint
+	// Cores holds the OS indexes of the PUs of the node, grouped by the
+	// physical core they belong to (i.e. HT siblings are grouped together).
+	Cores [][]uint
+	// Memory is the total memory attached to the node, in bytes.
+	Memory uint64
+}
+
+// CPUs returns the OS indexes of all the PUs of the node.
+func (n *Node) CPUs() []uint {
+	var cpus []uint
+	for _, core := range n.Cores {
+		cpus = append(cpus, core...)
+	}
+	sort.Slice(cpus, func(i, j int) bool { return cpus[i] < cpus[j] })
+	return cpus
+}
+
+// Topology is the list of NUMA nodes of the host, sorted by ID.
+type Topology struct {
+	Nodes []Node
+}
+
+// Node returns the node with the given ID, or nil if it does not exist.
+func (t *Topology) Node(id uint) *Node {
+	for i := range t.Nodes {
+		if t.Nodes[i].ID == id {
+			return &t.Nodes[i]
+		}
+	}
+	return nil
+}
+
+// ReadTopology uses hwloc to discover the NUMA nodes and their PUs, and
+// sysfs to group PUs by core and to read the memory attached to each node.
+func ReadTopology(fs afero.Fs, hw hwloc.HwLoc) (*Topology, error) {
+	nodeIDs, err := hw.GetPhysIntersection("numanode", "all")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list NUMA nodes: %v", err)
+	}
+	t := new(Topology)
+	for _, id := range nodeIDs {
+		cpus, err := hw.GetPhysIntersection("PU", fmt.Sprintf("numanode:%d", id))
+		if err != nil {
+			return nil, fmt.Errorf("unable to list the CPUs of NUMA node %d: %v", id, err)
+		}
+		cores, err := groupByCore(fs, cpus)
+		if err != nil {
+			return nil, err
+		}
+		mem, err := nodeMemory(fs, id)
+		if err != nil {
+			return nil, err
+		}
+		zap.L().Sugar().Debugf("NUMA node %d: cpus %v, memory %d bytes", id, cpus, mem)
+		t.Nodes = append(t.Nodes, Node{ID: id, Cores: cores, Memory: mem})
+	}
+	sort.Slice(t.Nodes, func(i, j int) bool { return t.Nodes[i].ID < t.Nodes[j].ID })
+	return t, nil
+}
+
+// groupByCore groups the given PUs using the thread_siblings_list of each
+// CPU. The resulting cores keep the order of the first PU seen for each one.
+func groupByCore(fs afero.Fs, cpus []uint) ([][]uint, error) {
+	var (
+		cores [][]uint
+		seen  = make(map[uint]bool)
+		inSet = make(map[uint]bool)
+	)
+	for _, cpu := range cpus {
+		inSet[cpu] = true
+	}
+	for _, cpu := range cpus {
+		if seen[cpu] {
+			continue
+		}
+		path := filepath.Join(sysCPUPath, fmt.Sprintf("cpu%d", cpu), "topology", "thread_siblings_list")
+		core := []uint{cpu}
+		if exists, _ := afero.Exists(fs, path); exists {
+			lines, err := utils.ReadFileLines(fs, path)
+			if err != nil {
+				return nil, err
+			}
+			if len(lines) > 0 {
+				siblings, err := ParseCPUList(lines[0])
+				if err != nil {
+					return nil, fmt.Errorf("unable to parse %s: %v", path, err)
+				}
+				core = core[:0]
+				for _, s := range siblings {
+					if inSet[s] {
+						core = append(core, s)
+					}
+				}
+			}
+		}
+		for _, c := range core {
+			seen[c] = true
+		}
+		cores = append(cores, core)
+	}
+	return cores, nil
+}
+
+// nodeMemory reads the MemTotal line of the node's meminfo file, which looks
+// like: "Node 0 MemTotal:       32649096 kB".
+func nodeMemory(fs afero.Fs, node uint) (uint64, error) {
+	path := filepath.Join(sysNodePath, fmt.Sprintf("node%d", node), "meminfo")
+	lines, err := utils.ReadFileLines(fs, path)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read the memory of NUMA node %d: %v", node, err)
+	}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[2] != "MemTotal:" {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[3], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unable to parse %q in %s: %v", line, path, err)
+		}
+		return kb << 10, nil
+	}
+	return 0, fmt.Errorf("MemTotal not found in %s", path)
+}
+
+// NICNode returns the NUMA node the given network interface is attached to,
+// or -1 if the kernel does not report it (e.g. virtual interfaces).
+func NICNode(fs afero.Fs, nic string) (int, error) {
+	return readNUMANode(fs, filepath.Join(sysNetPath, nic, "device", "numa_node"))
+}
+
+// DeviceNode returns the NUMA node of the device found at the given sysfs
+// path, walking up the device hierarchy until a numa_node attribute is found
+// (block devices carry it on their PCI controller). It returns -1 if no
+// ancestor reports a node.
+func DeviceNode(fs afero.Fs, syspath string) (int, error) {
+	for dir := syspath; dir != "/" && dir != "." && dir != "/sys"; dir = filepath.Dir(dir) {
+		path := filepath.Join(dir, "numa_node")
+		if exists, _ := afero.Exists(fs, path); exists {
+			return readNUMANode(fs, path)
+		}
+	}
+	return -1, nil
+}
+
+func readNUMANode(fs afero.Fs, path string) (int, error) {
+	if exists, _ := afero.Exists(fs, path); !exists {
+		return -1, nil
+	}
+	lines, err := utils.ReadFileLines(fs, path)
+	if err != nil {
+		return -1, err
+	}
+	if len(lines) == 0 {
+		return -1, nil
+	}
+	node, err := strconv.Atoi(strings.TrimSpace(lines[0]))
+	if err != nil {
+		return -1, fmt.Errorf("unable to parse %s: %v", path, err)
+	}
+	return node, nil
+}
+
+// ParseCPUList parses a cpuset(7) list, e.g. "0-3,8,10-11".
+func ParseCPUList(list string) ([]uint, error) {
+	var cpus []uint
+	list = strings.TrimSpace(list)
+	if list == "" {
+		return nil, nil
+	}
+	for _, part := range strings.Split(list, ",") {
+		lo, hi, isRange := strings.Cut(part, "-")
+		start, err := strconv.ParseUint(lo, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpu list %q", list)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.ParseUint(hi, 10, 32); err != nil || end < start {
+				return nil, fmt.Errorf("invalid cpu list %q", list)
+			}
+		}
+		for c := start; c <= end; c++ {
+			cpus = append(cpus, uint(c))
+		}
+	}
+	return cpus, nil
+}
+
+// FormatCPUList formats the given PUs as a cpuset(7) list, collapsing
+// consecutive PUs into ranges.
+func FormatCPUList(cpus []uint) string {
+	sorted := append([]uint(nil), cpus...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var parts []string
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] == sorted[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.FormatUint(uint64(sorted[i]), 10))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", sorted[i], sorted[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}